@@ -0,0 +1,150 @@
+// racecheck drives the sequencer the way a busy session does - pads, keys and views
+// from a UI goroutine, notes from a keyboard, a controller taking LED frames, panics,
+// mutes and stops, device type changes and project loads - while the runtime
+// goroutines play it into a stand-in output port.
+// Run it under the race detector (CI does):
+//
+//	go run -race ./cmd/racecheck -duration 10s
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync/atomic"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+	"go-sequence/sequencer"
+)
+
+// fakeController takes LED batches and plays keyboard notes
+type fakeController struct {
+	pads  chan midi.PadEvent
+	notes chan midi.NoteEvent
+}
+
+func (c *fakeController) ID() string                                { return "racecheck" }
+func (c *fakeController) Type() midi.ControllerType                 { return midi.ControllerLaunchpad }
+func (c *fakeController) PadEvents() <-chan midi.PadEvent           { return c.pads }
+func (c *fakeController) NoteEvents() <-chan midi.NoteEvent         { return c.notes }
+func (c *fakeController) SetLEDRGB(int, int, [3]uint8, uint8) error { return nil }
+func (c *fakeController) SetLEDBatch([]midi.LEDUpdate) error        { return nil }
+func (c *fakeController) Close() error                              { return nil }
+
+// uiKeys are keys the devices take without opening prompts or touching files
+var uiKeys = []string{"h", "j", "k", "l", "up", "down", "left", "right", "[", "]"}
+
+func main() {
+	duration := flag.Duration("duration", 5*time.Second, "how long to run")
+	flag.Parse()
+
+	// Every track plays into a stand-in port that counts what it's sent
+	var sent atomic.Int64
+	manager := sequencer.NewManager()
+	manager.SetSender("racecheck", func(gomidi.Message) error {
+		sent.Add(1)
+		return nil
+	})
+	manager.SetDefaultPort("racecheck")
+	manager.SetDevice(0, manager.CreateDrumDevice(0))
+	manager.SetDevice(1, manager.CreatePianoDevice(1))
+	manager.SetDevice(2, manager.CreateMetropolixDevice(2))
+	for i := 3; i < 8; i++ {
+		manager.SetDevice(i, manager.CreateEmptyDevice(i))
+	}
+	manager.SetSession(sequencer.NewSessionDevice(manager))
+	sequencer.S.Tempo = 240 // more events per second
+
+	// A project to reopen, in a scratch home so the real projects are left alone
+	home, err := os.MkdirTemp("", "racecheck")
+	if err != nil {
+		fmt.Printf("racecheck: %v\n", err)
+		os.Exit(1)
+	}
+	os.Setenv("HOME", home)
+	if err := sequencer.SaveProject("racecheck"); err != nil {
+		fmt.Printf("racecheck: %v\n", err)
+		os.Exit(1)
+	}
+	deviceTypes := []sequencer.DeviceType{sequencer.DeviceTypeDrum, sequencer.DeviceTypePiano, sequencer.DeviceTypeMetropolix, sequencer.DeviceTypeNone}
+
+	ctrl := &fakeController{pads: make(chan midi.PadEvent), notes: make(chan midi.NoteEvent, 32)}
+	manager.StartRuntime()
+	manager.SetController(ctrl)
+	manager.SetMIDIInput(ctrl)
+
+	manager.LockDevices()
+	manager.Play()
+	manager.UnlockDevices()
+
+	// Keyboard notes from their own goroutine
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(3 * time.Millisecond):
+				note := uint8(48 + rand.Intn(24))
+				ctrl.notes <- midi.NoteEvent{Type: midi.NoteOn, Note: note, Velocity: 100}
+				ctrl.notes <- midi.NoteEvent{Type: midi.NoteOn, Note: note}
+			}
+		}
+	}()
+
+	// The UI goroutine: focus, press, type and draw, as the TUI does under the lock
+	rng := rand.New(rand.NewSource(1))
+	deadline := time.Now().Add(*duration)
+	actions := 0
+	for time.Now().Before(deadline) {
+		manager.LockDevices()
+		switch rng.Intn(20) {
+		case 0:
+			manager.FocusSession()
+		case 1:
+			manager.FocusDevice(rng.Intn(3))
+		case 2:
+			if _, playing, _ := manager.GetState(); playing {
+				manager.Stop()
+			} else {
+				manager.Play()
+			}
+		case 3:
+			manager.Panic()
+		case 4:
+			manager.ToggleMute(rng.Intn(3))
+		case 5:
+			manager.ChangeDeviceType(rng.Intn(8), deviceTypes[rng.Intn(len(deviceTypes))])
+		case 6:
+			if err := manager.OpenProject("racecheck", ""); err != nil {
+				fmt.Printf("racecheck: %v\n", err)
+				os.Exit(1)
+			}
+			manager.Play()
+		default:
+			row, col := rng.Intn(8), rng.Intn(8)
+			manager.HandlePad(row, col, uint8(1+rng.Intn(127)))
+			manager.HandlePadRelease(row, col)
+			manager.HandleKey(uiKeys[rng.Intn(len(uiKeys))])
+		}
+		if focused := manager.GetFocused(); focused != nil {
+			focused.View()
+		}
+		manager.UnlockDevices()
+		actions++
+		time.Sleep(200 * time.Microsecond)
+	}
+	close(done)
+	os.RemoveAll(home)
+
+	fmt.Printf("racecheck: %d UI actions, %d messages sent in %s\n", actions, sent.Load(), *duration)
+	if sent.Load() == 0 {
+		fmt.Println("racecheck: nothing was dispatched")
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -13,35 +13,100 @@ const (
 	ControllerLaunchpadX    ControllerType = "launchpad-x"
 	ControllerLaunchpadMini ControllerType = "launchpad-mini"
 	ControllerLaunchpadPro  ControllerType = "launchpad-pro"
+	ControllerAPCMini       ControllerType = "apc-mini"
+	ControllerAPCKey        ControllerType = "apc-key"
 	ControllerKeyboard      ControllerType = "keyboard"
 	ControllerGenericGrid   ControllerType = "generic-grid"
 )
 
+// ControllerTypes lists every controller type (in the order the editor cycles them)
+var ControllerTypes = []ControllerType{
+	ControllerLaunchpadX,
+	ControllerLaunchpadMini,
+	ControllerLaunchpadPro,
+	ControllerAPCMini,
+	ControllerAPCKey,
+	ControllerKeyboard,
+	ControllerGenericGrid,
+}
+
 // ControllerConfig defines a saved controller configuration
 type ControllerConfig struct {
 	PortName     string         `json:"portName"`
 	Type         ControllerType `json:"type"`
 	AutoConnect  bool           `json:"autoConnect"`
-	InputChannel int            `json:"inputChannel,omitempty"` // for keyboards
+	InputChannel int            `json:"inputChannel,omitempty"` // for keyboards: 1-16 (0 = all)
+	Mapping      string         `json:"mapping,omitempty"`      // for generic grids: mapping file (relative to the config dir)
+	Hotkeys      []HotkeyConfig `json:"hotkeys,omitempty"`      // for keyboards: notes/CCs that run shortcuts
+}
+
+// HotkeyConfig makes a note or CC from a keyboard run a shortcut - a macro pad action:
+// "play" (play/stop), "record", "tap", "scene" (launch pattern Arg), "mute" or "focus"
+// (track Arg), or "snap" (recall snapshot Arg). A CC runs it when it goes to 64 or above.
+type HotkeyConfig struct {
+	Note    *int   `json:"note,omitempty"`    // note number, or
+	CC      *int   `json:"cc,omitempty"`      // controller number
+	Channel int    `json:"channel,omitempty"` // 1-16 (0 = any)
+	Action  string `json:"action"`
+	Arg     int    `json:"arg,omitempty"` // scene 1-128, track 1-8 or snapshot 1-8
+}
+
+// MappingPath returns the generic grid mapping file, resolved against the config dir
+func (c ControllerConfig) MappingPath() string {
+	if c.Mapping == "" || filepath.IsAbs(c.Mapping) {
+		return c.Mapping
+	}
+	dir, err := ConfigDir()
+	if err != nil {
+		return c.Mapping
+	}
+	return filepath.Join(dir, c.Mapping)
 }
 
 // SynthOutputConfig defines the synth MIDI output
 type SynthOutputConfig struct {
-	PortName string `json:"portName,omitempty"`
+	PortName string `json:"portName,omitempty"` // output for tracks set to (default)
 	Channels []int  `json:"channels,omitempty"`
 }
 
 // UIConfig stores UI preferences
 type UIConfig struct {
-	LastTempo         int `json:"lastTempo,omitempty"`
-	LastFocusedDevice int `json:"lastFocusedDevice,omitempty"`
+	LastTempo         int    `json:"lastTempo,omitempty"`
+	LastFocusedDevice int    `json:"lastFocusedDevice,omitempty"`
+	PlainOutput       bool   `json:"plainOutput,omitempty"`   // screen-reader friendly: no box drawing, state announcements
+	LEDBrightness     int    `json:"ledBrightness,omitempty"` // controller LED brightness in percent (0 = 100)
+	LEDTheme          string `json:"ledTheme,omitempty"`      // palette the LED colors are drawn from ("" = each device's own)
+}
+
+// SyncConfig defines network sync with another go-sequence instance
+type SyncConfig struct {
+	Role    string `json:"role,omitempty"`    // "leader", "follower", or "" (off)
+	Address string `json:"address,omitempty"` // leader: listen address (":7400"), follower: leader's host:port
+}
+
+// NetworkOutputConfig defines an RTP-MIDI endpoint used as an output port
+type NetworkOutputConfig struct {
+	Name    string `json:"name"`    // listed as "<name> (network)"
+	Address string `json:"address"` // host or host:port (AppleMIDI control port, default 5004)
+}
+
+// ThruConfig defines a soft MIDI thru route, played whether or not anything records
+type ThruConfig struct {
+	Input   string `json:"input,omitempty"`   // input port ("" = the note-input keyboard)
+	Output  string `json:"output"`            // output port
+	Channel int    `json:"channel,omitempty"` // output channel 1-16 (0 = keep the input's)
 }
 
 // Config is the main configuration structure
 type Config struct {
-	Controllers []ControllerConfig `json:"controllers,omitempty"`
-	SynthOutput SynthOutputConfig  `json:"synthOutput,omitempty"`
-	UI          UIConfig           `json:"ui,omitempty"`
+	Controllers    []ControllerConfig    `json:"controllers,omitempty"`
+	SynthOutput    SynthOutputConfig     `json:"synthOutput,omitempty"`
+	UI             UIConfig              `json:"ui,omitempty"`
+	Sync           SyncConfig            `json:"sync,omitempty"`
+	NetworkOutputs []NetworkOutputConfig `json:"networkOutputs,omitempty"`
+	Thru           []ThruConfig          `json:"thru,omitempty"`
+	LaunchBars     int                   `json:"launchBars,omitempty"` // clip launch quantization in bars (0 = each track's next pattern boundary)
+	LookAhead      map[string]int        `json:"lookAhead,omitempty"`  // queue fill horizon in ms per device type ("drum", "piano", "metropolix")
 }
 
 // DefaultConfig returns a config with sensible defaults
@@ -147,6 +212,13 @@ func (c *Config) AddController(ctrl ControllerConfig) {
 	c.Controllers = append(c.Controllers, ctrl)
 }
 
+// RemoveController removes the controller config at an index
+func (c *Config) RemoveController(idx int) {
+	if idx >= 0 && idx < len(c.Controllers) {
+		c.Controllers = append(c.Controllers[:idx], c.Controllers[idx+1:]...)
+	}
+}
+
 // AutoConnectControllers returns controllers with autoConnect enabled
 func (c *Config) AutoConnectControllers() []ControllerConfig {
 	var result []ControllerConfig
@@ -1,20 +1,30 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"go-sequence/config"
 	"go-sequence/debug"
 	"go-sequence/midi"
+	"go-sequence/netsync"
+	"go-sequence/rtpmidi"
 	"go-sequence/sequencer"
 	"go-sequence/theme"
 	"go-sequence/tui"
 )
 
 func main() {
+	safeMode := flag.Bool("safe", false, "start without MIDI (edit projects while CoreMIDI is unavailable)")
+	project := flag.String("project", "", "open a project on startup (its latest save unless --save is given)")
+	save := flag.String("save", "", "save file to open with --project")
+	play := flag.Bool("play", false, "start playing once the project is open")
+	flag.Parse()
+
 	fmt.Println("starting...")
 
 	// Enable debug logging
@@ -34,9 +44,19 @@ func main() {
 	palette := theme.MustLoadGPL("palettes/plasma.gpl")
 	th := theme.New(palette)
 
+	// Every palette can also be an LED theme
+	var ledThemes []*theme.Palette
+	gpls, _ := filepath.Glob("palettes/*.gpl")
+	for _, path := range gpls {
+		if p, err := theme.LoadGPL(path); err == nil {
+			ledThemes = append(ledThemes, p)
+		}
+	}
+
 	// Create sequencer manager
 	fmt.Println("creating sequencer...")
 	manager := sequencer.NewManager()
+	manager.SetConfig(cfg)
 
 	// Assign devices to slots
 	manager.SetDevice(0, manager.CreateDrumDevice(0))
@@ -59,9 +79,85 @@ func main() {
 	saveDevice := sequencer.NewSaveDevice(manager)
 	manager.SetSave(saveDevice)
 
+	// Create macro pad bank
+	manager.SetMacro(sequencer.NewMacroDevice(manager))
+
+	// Create set list device
+	manager.SetSetList(sequencer.NewSetListDevice(manager))
+
+	// Create routing matrix
+	manager.SetRouting(sequencer.NewRoutingDevice(manager))
+
+	// Create MIDI monitor
+	manager.SetMonitor(sequencer.NewMonitorDevice(manager))
+
+	// Create MIDI learn view
+	manager.SetLearn(sequencer.NewLearnDevice(manager))
+
+	// Create SysEx librarian
+	manager.SetSysEx(sequencer.NewSysExDevice(manager))
+
+	// Create controller profile editor
+	manager.SetControllerProfiles(sequencer.NewControllersDevice(manager, cfg))
+
+	// Create mixer page
+	manager.SetMixer(sequencer.NewMixerDevice(manager))
+
+	// LED look from the config
+	manager.SetLEDThemes(ledThemes)
+	manager.SetLEDTheme(cfg.UI.LEDTheme)
+	manager.SetLEDBrightness(cfg.UI.LEDBrightness)
+
+	// Open a project from the command line (for fast recovery on stage)
+	if *project != "" {
+		fmt.Printf("opening project %s...\n", *project)
+		if err := manager.OpenProject(*project, *save); err != nil {
+			fmt.Printf("Could not open project: %v\n", err)
+			*play = false
+		}
+	} else if *save != "" {
+		fmt.Println("--save needs --project, ignoring it")
+	}
+
+	// Safe mode - skip MIDI when asked to, or when CoreMIDI doesn't answer (before
+	// anything starts that could open a port)
+	if !*safeMode {
+		if err := midi.Probe(); err != nil {
+			fmt.Printf("%v\n", err)
+			*safeMode = true
+		}
+	}
+	if *safeMode {
+		manager.SetMIDIEnabled(false)
+	}
+
 	// Start all runtime goroutines
 	manager.StartRuntime()
 
+	// Network sync with another instance (optional, from config)
+	var syncLink *netsync.Link
+	if cfg.Sync.Role != "" {
+		fmt.Printf("starting sync (%s)...\n", cfg.Sync.Role)
+		syncLink, err = netsync.Open(netsync.Role(cfg.Sync.Role), cfg.Sync.Address)
+		if err != nil {
+			fmt.Printf("Sync disabled: %v\n", err)
+		} else {
+			manager.SetSyncLink(syncLink)
+		}
+	}
+
+	// Network MIDI outputs (RTP-MIDI sessions, from config) - they connect in the background
+	var sessions []*rtpmidi.Session
+	for _, out := range cfg.NetworkOutputs {
+		session, err := rtpmidi.Open(out.Name, out.Address)
+		if err != nil {
+			fmt.Printf("Network output %s disabled: %v\n", out.Name, err)
+			continue
+		}
+		sessions = append(sessions, session)
+		fmt.Printf("network output: %s -> %s\n", rtpmidi.PortName(out.Name), session.Addr())
+	}
+
 	// Create MIDI device manager
 	fmt.Println("initializing MIDI...")
 	deviceMgr := midi.NewDeviceManager()
@@ -70,9 +166,13 @@ func main() {
 	fmt.Println("connecting controller...")
 	fmt.Println("")
 	fmt.Println("go-sequence")
-	if err := deviceMgr.Connect(cfg); err != nil {
+	if *safeMode {
+		deviceMgr.SetSafeMode(true)
+		fmt.Println("Safe mode: MIDI disabled")
+		fmt.Println("Press 'r' in settings to retry MIDI")
+	} else if err := deviceMgr.Connect(cfg); err != nil {
 		fmt.Printf("No controller: %v\n", err)
-		fmt.Println("Press 'r' in the app to scan for devices")
+		fmt.Println("Plug it in any time - devices are detected automatically")
 	} else {
 		ctrl := deviceMgr.GetController()
 		if ctrl != nil {
@@ -85,8 +185,25 @@ func main() {
 	if noteInput := deviceMgr.GetNoteInput(); noteInput != nil {
 		manager.SetMIDIInput(noteInput)
 	}
+	manager.SetCCInput(deviceMgr.CCEvents())
+	thru := make([]sequencer.ThruRoute, len(cfg.Thru))
+	for i, route := range cfg.Thru {
+		thru[i] = sequencer.ThruRoute{Input: route.Input, Output: route.Output, Channel: route.Channel}
+	}
+	manager.SetThru(thru)
+	manager.SetThruInput(deviceMgr.ThruEvents())
 	fmt.Println("")
 
+	// Nothing to save until something changes
+	sequencer.S.MarkSaved()
+
+	// Auto-play once the outputs are wired
+	if *play {
+		manager.LockDevices()
+		manager.Play()
+		manager.UnlockDevices()
+	}
+
 	// Create and run TUI
 	m := tui.NewModel(manager, deviceMgr, cfg, th)
 	p := tea.NewProgram(m, tea.WithAltScreen(), tea.WithMouseCellMotion())
@@ -98,4 +215,11 @@ func main() {
 
 	// Cleanup
 	deviceMgr.Disconnect()
+	deviceMgr.ListenInputs(nil)
+	if syncLink != nil {
+		syncLink.Close()
+	}
+	for _, session := range sessions {
+		session.Close()
+	}
 }
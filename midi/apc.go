@@ -0,0 +1,295 @@
+package midi
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go-sequence/debug"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// FaderEvent is sent when a fader or knob moves on a controller that has them
+type FaderEvent struct {
+	Index int   // 0-7 = track faders, 8 = master
+	Value uint8 // 0-127
+}
+
+// FaderController is a controller with faders or knobs (APC family)
+type FaderController interface {
+	FaderEvents() <-chan FaderEvent
+}
+
+// APC single-color LED values (velocity of a note-on to the pad)
+const (
+	apcOff         uint8 = 0
+	apcGreen       uint8 = 1
+	apcGreenBlink  uint8 = 2
+	apcRed         uint8 = 3
+	apcRedBlink    uint8 = 4
+	apcYellow      uint8 = 5
+	apcYellowBlink uint8 = 6
+
+	apcButtonOn    uint8 = 1 // track and scene buttons have one color
+	apcButtonBlink uint8 = 2
+)
+
+// APC note and CC layout
+const (
+	apcTrackButtonBase = 64 // buttons under the grid, left to right (the app's top row)
+	apcSceneButtonTop  = 82 // right column, top to bottom
+	apcFaderBase       = 48 // CC of the first fader/knob
+	apcKeyChannel      = 1  // APC Key 25 keys play on channel 2 (pads are on 1)
+)
+
+// APCController handles an Akai APC Mini (8x8 grid, 9 faders) or APC Key 25 (5x8 grid,
+// 8 knobs, keys). The grid is laid over the app's rows from the top, the buttons under
+// it stand in for the Launchpad's top row, and the single-color pads show the nearest
+// of green, red and yellow.
+type APCController struct {
+	id       string
+	outPort  drivers.Out
+	inPort   drivers.In
+	send     func(msg gomidi.Message) error
+	stopFunc func()
+	rows     int // grid rows (8 on the Mini, 5 on the Key 25)
+	faders   int // faders/knobs (9 on the Mini, 8 on the Key 25)
+
+	padChan   chan PadEvent
+	noteChan  chan NoteEvent
+	faderChan chan FaderEvent
+}
+
+// NewAPCMiniController creates an APC Mini controller
+func NewAPCMiniController(id string, inPort drivers.In, outPort drivers.Out) (*APCController, error) {
+	return newAPCController(id, inPort, outPort, 8, 9)
+}
+
+// NewAPCKeyController creates an APC Key 25 controller
+func NewAPCKeyController(id string, inPort drivers.In, outPort drivers.Out) (*APCController, error) {
+	return newAPCController(id, inPort, outPort, 5, 8)
+}
+
+func newAPCController(id string, inPort drivers.In, outPort drivers.Out, rows, faders int) (*APCController, error) {
+	apc := &APCController{
+		id:        id,
+		inPort:    inPort,
+		outPort:   outPort,
+		rows:      rows,
+		faders:    faders,
+		padChan:   make(chan PadEvent, 32),
+		noteChan:  make(chan NoteEvent, 32),
+		faderChan: make(chan FaderEvent, 64),
+	}
+
+	// Open output (no mode switch needed - the APC lights whatever it's sent)
+	if outPort != nil {
+		send, err := gomidi.SendTo(outPort)
+		if err != nil {
+			return nil, fmt.Errorf("open output: %w", err)
+		}
+		apc.send = send
+	}
+
+	// Open input
+	if inPort != nil {
+		stop, err := gomidi.ListenTo(inPort, func(msg gomidi.Message, timestampms int32) {
+			var channel, note, velocity uint8
+			var cc, value uint8
+
+			switch {
+			case msg.GetNoteOn(&channel, &note, &velocity) && velocity > 0:
+				if channel == apcKeyChannel {
+					apc.emitNote(NoteEvent{Type: NoteOn, Note: note, Velocity: velocity, Channel: channel})
+					return
+				}
+				row, col := apc.noteToRowCol(note)
+				debug.Log("apc-in", "NoteOn note=%d -> row=%d col=%d", note, row, col)
+				if row >= 0 {
+					// Pads have no velocity - they all send 127
+					apc.emitPad(PadEvent{Row: row, Col: col, Velocity: velocity})
+				}
+			case msg.GetNoteEnd(&channel, &note):
+				if channel == apcKeyChannel {
+					apc.emitNote(NoteEvent{Type: NoteOn, Note: note, Channel: channel})
+					return
+				}
+				if row, col := apc.noteToRowCol(note); row >= 0 {
+					apc.emitPad(PadEvent{Row: row, Col: col, Released: true})
+				}
+			case msg.GetControlChange(&channel, &cc, &value):
+				idx := int(cc) - apcFaderBase
+				if idx >= 0 && idx < apc.faders {
+					select {
+					case apc.faderChan <- FaderEvent{Index: idx, Value: value}:
+					default:
+					}
+				}
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("open input: %w", err)
+		}
+		apc.stopFunc = stop
+	}
+
+	return apc, nil
+}
+
+func (apc *APCController) emitPad(evt PadEvent) {
+	select {
+	case apc.padChan <- evt:
+	default:
+	}
+}
+
+func (apc *APCController) emitNote(evt NoteEvent) {
+	select {
+	case apc.noteChan <- evt:
+	default:
+	}
+}
+
+func (apc *APCController) ID() string {
+	return apc.id
+}
+
+func (apc *APCController) Type() ControllerType {
+	return ControllerAPC
+}
+
+func (apc *APCController) PadEvents() <-chan PadEvent {
+	return apc.padChan
+}
+
+// NoteEvents returns the APC Key 25's keys (the Mini has none)
+func (apc *APCController) NoteEvents() <-chan NoteEvent {
+	return apc.noteChan
+}
+
+// FaderEvents returns fader (Mini) or knob (Key 25) moves
+func (apc *APCController) FaderEvents() <-chan FaderEvent {
+	return apc.faderChan
+}
+
+func (apc *APCController) SetLEDRGB(row, col int, rgb [3]uint8, channel uint8) error {
+	if apc.send == nil {
+		return nil
+	}
+	note, ok := apc.rowColToNote(row, col)
+	if !ok {
+		return nil
+	}
+	atomic.AddUint64(&ledSendCount, 1)
+	return apc.send(gomidi.NoteOn(0, note, apc.ledValue(row, col, rgb, channel)))
+}
+
+// SetLEDBatch sends multiple LED updates as individual NoteOn messages
+func (apc *APCController) SetLEDBatch(updates []LEDUpdate) error {
+	if apc.send == nil || len(updates) == 0 {
+		return nil
+	}
+	sent := 0
+	for _, u := range updates {
+		note, ok := apc.rowColToNote(u.Row, u.Col)
+		if !ok {
+			continue // no pad there on this model
+		}
+		apc.send(gomidi.NoteOn(0, note, apc.ledValue(u.Row, u.Col, u.Color, u.Channel)))
+		sent++
+	}
+	atomic.AddUint64(&ledSendCount, uint64(sent))
+	return nil
+}
+
+// ledValue picks the LED value for a color - grid pads get the nearest of their three
+// colors, buttons are lit or not; flashing and pulsing both blink
+func (apc *APCController) ledValue(row, col int, rgb [3]uint8, channel uint8) uint8 {
+	blink := channel == ChannelFlash || channel == ChannelPulse
+	if row == 8 || col == 8 {
+		switch {
+		case rgb == [3]uint8{}:
+			return apcOff
+		case blink:
+			return apcButtonBlink
+		}
+		return apcButtonOn
+	}
+	color := mapRGBToAPC(rgb)
+	if blink && color != apcOff {
+		color++ // each color's blinking value follows it
+	}
+	return color
+}
+
+// mapRGBToAPC finds the nearest APC pad color for an RGB value (dim colors are off -
+// the pads have no brightness levels)
+func mapRGBToAPC(rgb [3]uint8) uint8 {
+	r, g, b := int(rgb[0]), int(rgb[1]), int(rgb[2])
+	if max(r, g, b) < 48 {
+		return apcOff
+	}
+	switch {
+	case r > 2*g && r >= b:
+		return apcRed
+	case g > 2*r || b > r:
+		return apcGreen
+	}
+	return apcYellow
+}
+
+func (apc *APCController) Close() error {
+	// Clear all LEDs on close
+	if apc.send != nil {
+		var updates []LEDUpdate
+		for row := 0; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				updates = append(updates, LEDUpdate{Row: row, Col: col})
+			}
+		}
+		apc.SetLEDBatch(updates)
+	}
+	if apc.stopFunc != nil {
+		apc.stopFunc()
+	}
+	close(apc.padChan)
+	close(apc.noteChan)
+	close(apc.faderChan)
+	return nil
+}
+
+// APC note mapping (app rows are bottom-up, the grid covers the top apc.rows of them)
+// Grid:          note = gridRow*8 + col, gridRow 0 = bottom
+// Scene buttons: col 8, notes 82 (top) down to 82+rows-1
+// Track buttons: row 8, notes 64-71
+
+// rowOffset is the app row of the grid's bottom row
+func (apc *APCController) rowOffset() int {
+	return 8 - apc.rows
+}
+
+func (apc *APCController) rowColToNote(row, col int) (uint8, bool) {
+	switch {
+	case row == 8 && col < 8:
+		return uint8(apcTrackButtonBase + col), true
+	case col == 8 && row >= apc.rowOffset() && row < 8:
+		return uint8(apcSceneButtonTop + 7 - row), true
+	case row >= apc.rowOffset() && row < 8 && col >= 0 && col < 8:
+		return uint8((row-apc.rowOffset())*8 + col), true
+	}
+	return 0, false
+}
+
+func (apc *APCController) noteToRowCol(note uint8) (row, col int) {
+	n := int(note)
+	switch {
+	case n < apc.rows*8:
+		return n/8 + apc.rowOffset(), n % 8
+	case n >= apcTrackButtonBase && n < apcTrackButtonBase+8:
+		return 8, n - apcTrackButtonBase
+	case n >= apcSceneButtonTop && n < apcSceneButtonTop+apc.rows:
+		return 7 - (n - apcSceneButtonTop), 8
+	}
+	return -1, -1
+}
@@ -6,20 +6,42 @@ type ControllerType int
 const (
 	ControllerUnknown ControllerType = iota
 	ControllerLaunchpad
+	ControllerLaunchpadPro
+	ControllerAPC
+	ControllerGenericGrid
 	ControllerKeyboard
 )
 
-// PadEvent is sent when a pad/button is pressed on a grid controller
+// PadEvent is sent when a pad/button is pressed or released on a grid controller
 type PadEvent struct {
 	Row, Col int
 	Velocity uint8
+	Released bool // true on release (note-off / CC value 0)
+	Pressure bool // aftertouch on a held pad (Velocity is the pressure)
 }
 
-// NoteEvent is sent when a note is played on a keyboard
+// NoteEvent is sent when a note is played on a keyboard (or it sends aftertouch or
+// pitch bend)
 type NoteEvent struct {
+	Type     uint8 // NoteOn (velocity 0 = off), PolyPressure, Pressure or PitchBend
 	Note     uint8
-	Velocity uint8
+	Velocity uint8 // pressure for aftertouch
 	Channel  uint8
+	Bend     int16 // -8192 to +8191 for PitchBend
+}
+
+// CCEvent is a control change from any input port (see DeviceManager.ListenInputs)
+type CCEvent struct {
+	Port       string
+	Channel    uint8
+	Controller uint8
+	Value      uint8
+}
+
+// ThruEvent is a channel message from any input port (see DeviceManager.ListenInputs)
+type ThruEvent struct {
+	Port string
+	Msg  []byte
 }
 
 // LEDUpdate represents a single LED change for batch updates
@@ -46,6 +68,11 @@ type Controller interface {
 	Close() error
 }
 
+// BrightnessController is a controller whose LED brightness can be set in hardware
+type BrightnessController interface {
+	SetBrightness(level uint8) error // 0-127
+}
+
 // Launchpad X color palette (velocity values 0-127)
 // See Programmer's Reference Manual for full palette
 const (
@@ -0,0 +1,311 @@
+package midi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// Generic grid - grid hardware without its own controller type is wired up with a JSON
+// mapping file (config type "generic-grid", "mapping" path):
+//
+//	{
+//	  "name": "My Grid",
+//	  "grid": {"rows": 8, "cols": 8, "firstNote": 36, "rowStride": 8},
+//	  "pads": [{"note": 100, "row": 8, "col": 0}],
+//	  "ccs":  [{"cc": 89, "row": 7, "col": 8}],
+//	  "led":  ["0x90+{mode}", "{note}", "{color}"],
+//	  "colors": [{"value": 0, "rgb": [0, 0, 0]}, {"value": 1, "rgb": [0, 255, 0]}]
+//	}
+//
+// "grid" lays out a block of notes (row 0 at the bottom, firstNote bottom-left);
+// "pads" and "ccs" add single notes or CCs anywhere (row 8 = top row, col 8 = scene
+// column). "led" is the message that lights one pad: each byte is a number or a sum of
+// numbers and placeholders - {note} (the pad's note or CC), {color} (nearest of
+// "colors", or the Launchpad palette without them), {r} {g} {b} (0-127) and {mode}
+// (0 static, 1 flash, 2 pulse).
+
+// GridMapping describes a generic grid controller
+type GridMapping struct {
+	Name   string      `json:"name"`
+	Grid   *GridBlock  `json:"grid,omitempty"`
+	Pads   []GridPad   `json:"pads,omitempty"`
+	CCs    []GridCC    `json:"ccs,omitempty"`
+	LED    []string    `json:"led"`
+	Colors []GridColor `json:"colors,omitempty"`
+}
+
+// GridBlock is a rectangular block of consecutive notes
+type GridBlock struct {
+	Rows      int `json:"rows"`
+	Cols      int `json:"cols"`
+	FirstNote int `json:"firstNote"`
+	RowStride int `json:"rowStride"` // notes between rows (0 = cols)
+}
+
+// GridPad maps one note to a pad
+type GridPad struct {
+	Note uint8 `json:"note"`
+	Row  int   `json:"row"`
+	Col  int   `json:"col"`
+}
+
+// GridCC maps one CC (a button) to a pad
+type GridCC struct {
+	CC  uint8 `json:"cc"`
+	Row int   `json:"row"`
+	Col int   `json:"col"`
+}
+
+// GridColor is one entry of the controller's LED palette
+type GridColor struct {
+	Value uint8    `json:"value"`
+	RGB   [3]uint8 `json:"rgb"`
+}
+
+// gridAddr is how a pad is addressed - by note or by CC
+type gridAddr struct {
+	num  uint8
+	isCC bool
+}
+
+// LoadGridMapping reads and checks a mapping file
+func LoadGridMapping(path string) (*GridMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var gm GridMapping
+	if err := json.Unmarshal(data, &gm); err != nil {
+		return nil, fmt.Errorf("mapping %s: %w", path, err)
+	}
+	if gm.Grid == nil && len(gm.Pads) == 0 && len(gm.CCs) == 0 {
+		return nil, fmt.Errorf("mapping %s: no grid, pads or ccs", path)
+	}
+	if _, err := gm.ledMessage(gridAddr{}, [3]uint8{}, 0); err != nil {
+		return nil, fmt.Errorf("mapping %s: %w", path, err)
+	}
+	return &gm, nil
+}
+
+// ledMessage fills in the LED template for one pad
+func (gm *GridMapping) ledMessage(addr gridAddr, rgb [3]uint8, mode uint8) ([]byte, error) {
+	if len(gm.LED) == 0 {
+		return nil, fmt.Errorf("no led message")
+	}
+	vars := map[string]int{
+		"note":  int(addr.num),
+		"color": int(gm.colorValue(rgb)),
+		"r":     int(rgb[0]) >> 1,
+		"g":     int(rgb[1]) >> 1,
+		"b":     int(rgb[2]) >> 1,
+		"mode":  int(mode),
+	}
+	msg := make([]byte, 0, len(gm.LED))
+	for _, field := range gm.LED {
+		sum := 0
+		for _, term := range strings.Split(field, "+") {
+			term = strings.TrimSpace(term)
+			if name, ok := strings.CutPrefix(term, "{"); ok {
+				v, known := vars[strings.TrimSuffix(name, "}")]
+				if !known {
+					return nil, fmt.Errorf("led: unknown placeholder %s", term)
+				}
+				sum += v
+				continue
+			}
+			v, err := strconv.ParseInt(term, 0, 16)
+			if err != nil {
+				return nil, fmt.Errorf("led: bad byte %q", field)
+			}
+			sum += int(v)
+		}
+		if sum < 0 || sum > 0xFF {
+			return nil, fmt.Errorf("led: %q is out of byte range", field)
+		}
+		msg = append(msg, byte(sum))
+	}
+	return msg, nil
+}
+
+// colorValue finds the nearest palette color (the Launchpad's without a palette)
+func (gm *GridMapping) colorValue(rgb [3]uint8) uint8 {
+	if len(gm.Colors) == 0 {
+		return mapRGBToLaunchpad(rgb)
+	}
+	best, bestDist := gm.Colors[0].Value, -1
+	r, g, b := int(rgb[0]), int(rgb[1]), int(rgb[2])
+	for _, c := range gm.Colors {
+		pr, pg, pb := int(c.RGB[0]), int(c.RGB[1]), int(c.RGB[2])
+		dist := (r-pr)*(r-pr) + (g-pg)*(g-pg) + (b-pb)*(b-pb)
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = c.Value, dist
+		}
+	}
+	return best
+}
+
+// GenericGridController handles grid hardware described by a GridMapping
+type GenericGridController struct {
+	id       string
+	mapping  *GridMapping
+	send     func(msg gomidi.Message) error
+	stopFunc func()
+
+	toPad  map[gridAddr][2]int // note/CC → row, col
+	toAddr map[[2]int]gridAddr // row, col → note/CC
+
+	padChan  chan PadEvent
+	noteChan chan NoteEvent
+}
+
+// NewGenericGridController creates a controller from a mapping
+func NewGenericGridController(id string, mapping *GridMapping, inPort drivers.In, outPort drivers.Out) (*GenericGridController, error) {
+	gc := &GenericGridController{
+		id:       id,
+		mapping:  mapping,
+		toPad:    make(map[gridAddr][2]int),
+		toAddr:   make(map[[2]int]gridAddr),
+		padChan:  make(chan PadEvent, 32),
+		noteChan: make(chan NoteEvent, 32),
+	}
+	add := func(addr gridAddr, row, col int) {
+		if row < 0 || row > 8 || col < 0 || col > 8 {
+			return
+		}
+		gc.toPad[addr] = [2]int{row, col}
+		gc.toAddr[[2]int{row, col}] = addr
+	}
+	if g := mapping.Grid; g != nil {
+		stride := g.RowStride
+		if stride == 0 {
+			stride = g.Cols
+		}
+		for row := 0; row < g.Rows; row++ {
+			for col := 0; col < g.Cols; col++ {
+				if note := g.FirstNote + row*stride + col; note >= 0 && note < 128 {
+					add(gridAddr{num: uint8(note)}, row, col)
+				}
+			}
+		}
+	}
+	for _, p := range mapping.Pads {
+		add(gridAddr{num: p.Note}, p.Row, p.Col)
+	}
+	for _, c := range mapping.CCs {
+		add(gridAddr{num: c.CC, isCC: true}, c.Row, c.Col)
+	}
+
+	// Open output
+	if outPort != nil {
+		send, err := gomidi.SendTo(outPort)
+		if err != nil {
+			return nil, fmt.Errorf("open output: %w", err)
+		}
+		gc.send = send
+	}
+
+	// Open input
+	if inPort != nil {
+		stop, err := gomidi.ListenTo(inPort, func(msg gomidi.Message, timestampms int32) {
+			var channel, note, velocity uint8
+			var cc, value uint8
+			switch {
+			case msg.GetNoteOn(&channel, &note, &velocity) && velocity > 0:
+				if pad, ok := gc.toPad[gridAddr{num: note}]; ok {
+					gc.emit(PadEvent{Row: pad[0], Col: pad[1], Velocity: velocity})
+				}
+			case msg.GetNoteEnd(&channel, &note):
+				if pad, ok := gc.toPad[gridAddr{num: note}]; ok {
+					gc.emit(PadEvent{Row: pad[0], Col: pad[1], Released: true})
+				}
+			case msg.GetControlChange(&channel, &cc, &value):
+				if pad, ok := gc.toPad[gridAddr{num: cc, isCC: true}]; ok {
+					gc.emit(PadEvent{Row: pad[0], Col: pad[1], Velocity: value, Released: value == 0})
+				}
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("open input: %w", err)
+		}
+		gc.stopFunc = stop
+	}
+
+	return gc, nil
+}
+
+func (gc *GenericGridController) emit(evt PadEvent) {
+	select {
+	case gc.padChan <- evt:
+	default:
+	}
+}
+
+func (gc *GenericGridController) ID() string {
+	return gc.id
+}
+
+func (gc *GenericGridController) Type() ControllerType {
+	return ControllerGenericGrid
+}
+
+// Name returns the mapping's name for the controller
+func (gc *GenericGridController) Name() string {
+	return gc.mapping.Name
+}
+
+func (gc *GenericGridController) PadEvents() <-chan PadEvent {
+	return gc.padChan
+}
+
+func (gc *GenericGridController) NoteEvents() <-chan NoteEvent {
+	return gc.noteChan
+}
+
+func (gc *GenericGridController) SetLEDRGB(row, col int, rgb [3]uint8, channel uint8) error {
+	return gc.SetLEDBatch([]LEDUpdate{{Row: row, Col: col, Color: rgb, Channel: channel}})
+}
+
+// SetLEDBatch sends one templated message per LED (pads the mapping doesn't have are
+// skipped)
+func (gc *GenericGridController) SetLEDBatch(updates []LEDUpdate) error {
+	if gc.send == nil {
+		return nil
+	}
+	sent := 0
+	for _, u := range updates {
+		addr, ok := gc.toAddr[[2]int{u.Row, u.Col}]
+		if !ok {
+			continue
+		}
+		msg, err := gc.mapping.ledMessage(addr, u.Color, u.Channel)
+		if err != nil {
+			return err
+		}
+		gc.send(gomidi.Message(msg))
+		sent++
+	}
+	atomic.AddUint64(&ledSendCount, uint64(sent))
+	return nil
+}
+
+func (gc *GenericGridController) Close() error {
+	// Clear every mapped LED on close
+	var updates []LEDUpdate
+	for pad := range gc.toAddr {
+		updates = append(updates, LEDUpdate{Row: pad[0], Col: pad[1]})
+	}
+	gc.SetLEDBatch(updates)
+	if gc.stopFunc != nil {
+		gc.stopFunc()
+	}
+	close(gc.padChan)
+	close(gc.noteChan)
+	return nil
+}
@@ -17,8 +17,9 @@ type KeyboardController struct {
 	noteChan chan NoteEvent
 }
 
-// NewKeyboardController creates a keyboard controller (input only)
-func NewKeyboardController(id string, inPort drivers.In) (*KeyboardController, error) {
+// NewKeyboardController creates a keyboard controller (input only) that listens on one
+// channel (1-16, 0 = all)
+func NewKeyboardController(id string, inPort drivers.In, inputChannel int) (*KeyboardController, error) {
 	kb := &KeyboardController{
 		id:       id,
 		inPort:   inPort,
@@ -30,11 +31,29 @@ func NewKeyboardController(id string, inPort drivers.In) (*KeyboardController, e
 	if inPort != nil {
 		stop, err := gomidi.ListenTo(inPort, func(msg gomidi.Message, timestampms int32) {
 			var channel, note, velocity uint8
-			if msg.GetNoteOn(&channel, &note, &velocity) && velocity > 0 {
-				select {
-				case kb.noteChan <- NoteEvent{Note: note, Velocity: velocity, Channel: channel}:
-				default:
-				}
+			var rel int16
+			var abs uint16
+			evt := NoteEvent{Type: NoteOn}
+			switch {
+			case msg.GetNoteOn(&channel, &note, &velocity):
+			case msg.GetNoteOff(&channel, &note, &velocity):
+				velocity = 0 // note-offs are sent on as velocity 0
+			case msg.GetPolyAfterTouch(&channel, &note, &velocity):
+				evt.Type = PolyPressure
+			case msg.GetAfterTouch(&channel, &velocity):
+				evt.Type = Pressure
+			case msg.GetPitchBend(&channel, &rel, &abs):
+				evt.Type, evt.Bend = PitchBend, rel
+			default:
+				return
+			}
+			if inputChannel > 0 && int(channel) != inputChannel-1 {
+				return
+			}
+			evt.Note, evt.Velocity, evt.Channel = note, velocity, channel
+			select {
+			case kb.noteChan <- evt:
+			default:
 			}
 		})
 		if err != nil {
@@ -20,18 +20,38 @@ type LaunchpadController struct {
 	send     func(msg gomidi.Message) error
 	stopFunc func()
 
-	padChan  chan PadEvent
-	noteChan chan NoteEvent
+	padChan   chan PadEvent
+	noteChan  chan NoteEvent
+	faderChan chan FaderEvent
 }
 
+// FaderPageController is a controller that can turn its grid into faders (Launchpad X
+// DAW faders layout)
+type FaderPageController interface {
+	FaderController
+	ShowFaders(colors [8][3]uint8, values [8]uint8) error
+	SetFader(index int, value uint8) error
+	HideFaders() error
+}
+
+// Launchpad X DAW faders - the grid becomes 8 vertical faders that send, and are moved
+// by, CCs on channel 5
+const (
+	lpFaderChannel     = 4  // channel 5
+	lpFaderCCBase      = 21 // CC of the first fader
+	lpLayoutFaders     = 0x0D
+	lpLayoutProgrammer = 0x7F
+)
+
 // NewLaunchpadController creates and configures a Launchpad
 func NewLaunchpadController(id string, inPort drivers.In, outPort drivers.Out) (*LaunchpadController, error) {
 	lp := &LaunchpadController{
-		id:       id,
-		inPort:   inPort,
-		outPort:  outPort,
-		padChan:  make(chan PadEvent, 32),
-		noteChan: make(chan NoteEvent, 32),
+		id:        id,
+		inPort:    inPort,
+		outPort:   outPort,
+		padChan:   make(chan PadEvent, 32),
+		noteChan:  make(chan NoteEvent, 32),
+		faderChan: make(chan FaderEvent, 64),
 	}
 
 	// Open output
@@ -71,15 +91,35 @@ func NewLaunchpadController(id string, inPort drivers.In, outPort drivers.Out) (
 					default:
 					}
 				}
+			} else if msg.GetNoteEnd(&channel, &note) {
+				// Note-off (or note-on with velocity 0) - pad released
+				row, col := noteToRowCol(note)
+				if row >= 0 {
+					select {
+					case lp.padChan <- PadEvent{Row: row, Col: col, Released: true}:
+					default:
+					}
+				}
+			}
+
+			// Fader moves (DAW faders layout)
+			if msg.GetControlChange(&channel, &cc, &value) && channel == lpFaderChannel {
+				if idx := int(cc) - lpFaderCCBase; idx >= 0 && idx < 8 {
+					select {
+					case lp.faderChan <- FaderEvent{Index: idx, Value: value}:
+					default:
+					}
+				}
+				return
 			}
 
 			// Handle CC messages (top row buttons CC 91-98)
-			if msg.GetControlChange(&channel, &cc, &value) && value > 0 {
+			if msg.GetControlChange(&channel, &cc, &value) {
 				debug.Log("lp-in", "CC cc=%d value=%d", cc, value)
 				row, col := ccToRowCol(cc)
 				if row >= 0 {
 					select {
-					case lp.padChan <- PadEvent{Row: row, Col: col, Velocity: value}:
+					case lp.padChan <- PadEvent{Row: row, Col: col, Velocity: value, Released: value == 0}:
 					default:
 					}
 				}
@@ -144,6 +184,59 @@ func (lp *LaunchpadController) SetLEDBatch(updates []LEDUpdate) error {
 	return nil
 }
 
+// FaderEvents returns fader moves while the DAW faders layout is shown
+func (lp *LaunchpadController) FaderEvents() <-chan FaderEvent {
+	return lp.faderChan
+}
+
+// ShowFaders sets up 8 vertical faders in the track colors and switches to them
+// F0 00 20 29 02 0C 01 00 <orientation> [<fader> <polarity> <CC> <color>]x8 F7
+// F0 00 20 29 02 0C 00 <layout> F7
+func (lp *LaunchpadController) ShowFaders(colors [8][3]uint8, values [8]uint8) error {
+	if lp.send == nil {
+		return nil
+	}
+	setup := []byte{0x00, 0x20, 0x29, 0x02, 0x0C, 0x01, 0x00, 0x00} // vertical
+	for i, c := range colors {
+		setup = append(setup, byte(i), 0x00, byte(lpFaderCCBase+i), mapRGBToLaunchpad(c)) // unipolar
+	}
+	if err := lp.send(gomidi.SysEx(setup)); err != nil {
+		return err
+	}
+	if err := lp.send(gomidi.SysEx([]byte{0x00, 0x20, 0x29, 0x02, 0x0C, 0x00, lpLayoutFaders})); err != nil {
+		return err
+	}
+	for i, v := range values {
+		lp.SetFader(i, v)
+	}
+	return nil
+}
+
+// SetFader moves a fader to a value
+func (lp *LaunchpadController) SetFader(index int, value uint8) error {
+	if lp.send == nil || index < 0 || index >= 8 {
+		return nil
+	}
+	return lp.send(gomidi.ControlChange(lpFaderChannel, uint8(lpFaderCCBase+index), value&0x7F))
+}
+
+// HideFaders switches back to Programmer mode
+func (lp *LaunchpadController) HideFaders() error {
+	if lp.send == nil {
+		return nil
+	}
+	return lp.send(gomidi.SysEx([]byte{0x00, 0x20, 0x29, 0x02, 0x0C, 0x00, lpLayoutProgrammer}))
+}
+
+// SetBrightness sets the LED brightness (0-127)
+// F0 00 20 29 02 0C 08 <brightness> F7
+func (lp *LaunchpadController) SetBrightness(level uint8) error {
+	if lp.send == nil {
+		return nil
+	}
+	return lp.send(gomidi.SysEx([]byte{0x00, 0x20, 0x29, 0x02, 0x0C, 0x08, level & 0x7F}))
+}
+
 // mapRGBToLaunchpad finds the nearest Launchpad X palette color for an RGB value
 func mapRGBToLaunchpad(rgb [3]uint8) uint8 {
 	// Launchpad X palette - approximate RGB values for key colors
@@ -209,6 +302,7 @@ func (lp *LaunchpadController) Close() error {
 	}
 	close(lp.padChan)
 	close(lp.noteChan)
+	close(lp.faderChan)
 	return nil
 }
 
@@ -0,0 +1,230 @@
+package midi
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"go-sequence/debug"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/drivers"
+)
+
+// Rows outside the 8x8 grid + top row that only the Launchpad Pro Mk3 has
+const (
+	RowTrackButtons = -1 // track select buttons under the grid (CC 101-108), col 0-7
+	RowModeButtons  = -2 // bottom row (Record Arm, Mute, Solo, Volume, Pan, Sends, Device, Stop Clip - CC 1-8)
+)
+
+// Bottom row mode buttons (cols of RowModeButtons)
+const (
+	ProModeRecordArm = 0
+	ProModeMute      = 1
+	ProModeSolo      = 2
+	ProModeStopClip  = 7
+)
+
+// LaunchpadProController handles a Novation Launchpad Pro Mk3. Same Programmer mode
+// grid as the X, plus velocity and polyphonic (or channel) pressure from the pads and
+// two rows of buttons under the grid.
+type LaunchpadProController struct {
+	id       string
+	outPort  drivers.Out
+	inPort   drivers.In
+	send     func(msg gomidi.Message) error
+	stopFunc func()
+
+	padChan  chan PadEvent
+	noteChan chan NoteEvent
+
+	// Last pad pressed - channel pressure is applied to it
+	lastMu  sync.Mutex
+	lastPad [2]int
+	lastOn  bool
+}
+
+// NewLaunchpadProController creates and configures a Launchpad Pro Mk3
+func NewLaunchpadProController(id string, inPort drivers.In, outPort drivers.Out) (*LaunchpadProController, error) {
+	lp := &LaunchpadProController{
+		id:       id,
+		inPort:   inPort,
+		outPort:  outPort,
+		padChan:  make(chan PadEvent, 64),
+		noteChan: make(chan NoteEvent, 32),
+	}
+
+	// Open output
+	if outPort != nil {
+		send, err := gomidi.SendTo(outPort)
+		if err != nil {
+			return nil, fmt.Errorf("open output: %w", err)
+		}
+		lp.send = send
+
+		// Send SysEx to switch to Programmer mode
+		// F0 00 20 29 02 0E 0E 01 F7
+		lp.send(gomidi.SysEx([]byte{0x00, 0x20, 0x29, 0x02, 0x0E, 0x0E, 0x01}))
+	}
+
+	// Open input
+	if inPort != nil {
+		stop, err := gomidi.ListenTo(inPort, func(msg gomidi.Message, timestampms int32) {
+			var channel, note, velocity, pressure uint8
+			var cc, value uint8
+
+			switch {
+			case msg.GetNoteOn(&channel, &note, &velocity) && velocity > 0:
+				row, col := noteToRowCol(note)
+				debug.Log("lp-in", "pro NoteOn note=%d vel=%d -> row=%d col=%d", note, velocity, row, col)
+				if row >= 0 {
+					lp.setLastPad(row, col, true)
+					lp.emit(PadEvent{Row: row, Col: col, Velocity: velocity})
+				}
+			case msg.GetNoteEnd(&channel, &note):
+				row, col := noteToRowCol(note)
+				if row >= 0 {
+					lp.setLastPad(row, col, false)
+					lp.emit(PadEvent{Row: row, Col: col, Released: true})
+				}
+			case msg.GetPolyAfterTouch(&channel, &note, &pressure):
+				row, col := noteToRowCol(note)
+				if row >= 0 {
+					lp.emit(PadEvent{Row: row, Col: col, Velocity: pressure, Pressure: true})
+				}
+			case msg.GetAfterTouch(&channel, &pressure):
+				if row, col, ok := lp.heldPad(); ok {
+					lp.emit(PadEvent{Row: row, Col: col, Velocity: pressure, Pressure: true})
+				}
+			case msg.GetControlChange(&channel, &cc, &value):
+				debug.Log("lp-in", "pro CC cc=%d value=%d", cc, value)
+				row, col := proCCToRowCol(cc)
+				if row != noPad {
+					lp.emit(PadEvent{Row: row, Col: col, Velocity: value, Released: value == 0})
+				}
+			}
+		})
+		if err != nil {
+			return nil, fmt.Errorf("open input: %w", err)
+		}
+		lp.stopFunc = stop
+	}
+
+	return lp, nil
+}
+
+// emit sends a pad event on, dropping it if nobody is keeping up
+func (lp *LaunchpadProController) emit(evt PadEvent) {
+	select {
+	case lp.padChan <- evt:
+	default:
+	}
+}
+
+func (lp *LaunchpadProController) setLastPad(row, col int, down bool) {
+	lp.lastMu.Lock()
+	defer lp.lastMu.Unlock()
+	if down {
+		lp.lastPad, lp.lastOn = [2]int{row, col}, true
+	} else if lp.lastPad == [2]int{row, col} {
+		lp.lastOn = false
+	}
+}
+
+// heldPad returns the last pad pressed, if it is still down
+func (lp *LaunchpadProController) heldPad() (row, col int, ok bool) {
+	lp.lastMu.Lock()
+	defer lp.lastMu.Unlock()
+	return lp.lastPad[0], lp.lastPad[1], lp.lastOn
+}
+
+func (lp *LaunchpadProController) ID() string {
+	return lp.id
+}
+
+func (lp *LaunchpadProController) Type() ControllerType {
+	return ControllerLaunchpadPro
+}
+
+func (lp *LaunchpadProController) PadEvents() <-chan PadEvent {
+	return lp.padChan
+}
+
+func (lp *LaunchpadProController) NoteEvents() <-chan NoteEvent {
+	return lp.noteChan
+}
+
+func (lp *LaunchpadProController) SetLEDRGB(row, col int, rgb [3]uint8, channel uint8) error {
+	if lp.send == nil {
+		return nil
+	}
+	atomic.AddUint64(&ledSendCount, 1)
+	return lp.send(proLEDMessage(row, col, mapRGBToLaunchpad(rgb), channel))
+}
+
+// SetLEDBatch sends multiple LED updates as individual messages (like the X)
+func (lp *LaunchpadProController) SetLEDBatch(updates []LEDUpdate) error {
+	if lp.send == nil || len(updates) == 0 {
+		return nil
+	}
+	for _, u := range updates {
+		lp.send(proLEDMessage(u.Row, u.Col, mapRGBToLaunchpad(u.Color), u.Channel))
+	}
+	atomic.AddUint64(&ledSendCount, uint64(len(updates)))
+	return nil
+}
+
+func (lp *LaunchpadProController) Close() error {
+	// Clear all LEDs on close, including the rows under the grid
+	if lp.send != nil {
+		var updates []LEDUpdate
+		for row := RowModeButtons; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				if (row < 0 || row == 8) && col == 8 {
+					continue
+				}
+				updates = append(updates, LEDUpdate{Row: row, Col: col})
+			}
+		}
+		lp.SetLEDBatch(updates)
+	}
+	if lp.stopFunc != nil {
+		lp.stopFunc()
+	}
+	close(lp.padChan)
+	close(lp.noteChan)
+	return nil
+}
+
+// Launchpad Pro Mk3 Programmer mode mapping - the grid, side column and top row are
+// the same as the X (see rowColToNote). Under the grid:
+// Track buttons: RowTrackButtons = CC 101-108
+// Bottom row:    RowModeButtons  = CC 1-8
+
+// noPad marks a CC that isn't a pad (rows can be negative on the Pro)
+const noPad = -99
+
+// proCCToRowCol converts a Pro Mk3 button CC to row/col (noPad if it isn't one)
+func proCCToRowCol(cc uint8) (row, col int) {
+	switch {
+	case cc >= 101 && cc <= 108:
+		return RowTrackButtons, int(cc - 101)
+	case cc >= 1 && cc <= 8:
+		return RowModeButtons, int(cc - 1)
+	}
+	if row, col := ccToRowCol(cc); row >= 0 {
+		return row, col
+	}
+	return noPad, noPad
+}
+
+// proLEDMessage lights one LED - grid pads by note, buttons by CC
+func proLEDMessage(row, col int, color, channel uint8) gomidi.Message {
+	switch row {
+	case RowTrackButtons:
+		return gomidi.ControlChange(channel, uint8(101+col), color)
+	case RowModeButtons:
+		return gomidi.ControlChange(channel, uint8(1+col), color)
+	}
+	return gomidi.NoteOn(channel, rowColToNote(row, col), color)
+}
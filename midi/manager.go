@@ -2,12 +2,15 @@ package midi
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"go-sequence/config"
+	"go-sequence/rtpmidi"
 
 	gomidi "gitlab.com/gomidi/midi/v2"
 	"gitlab.com/gomidi/midi/v2/drivers"
@@ -30,19 +33,91 @@ const (
 	DeviceError
 )
 
-// DeviceManager handles MIDI controller connections (no polling - user-initiated only)
+// ProbeTimeout is how long port enumeration may take before CoreMIDI is considered hung
+const ProbeTimeout = 3 * time.Second
+
+// ErrSafeMode is returned by port operations while MIDI is disabled
+var ErrSafeMode = errors.New("MIDI disabled (safe mode)")
+
+// probing is the enumeration a probe is waiting on (nil when none is running)
+var (
+	probeMu sync.Mutex
+	probing chan struct{}
+)
+
+// Probe checks that MIDI port enumeration answers within ProbeTimeout.
+// A hung CoreMIDI blocks forever, so the enumeration goroutine is abandoned on timeout -
+// and a retry while it is still stuck waits on that one instead of starting another.
+func Probe() error {
+	probeMu.Lock()
+	if probing == nil {
+		probing = make(chan struct{})
+		go func(done chan struct{}) {
+			gomidi.GetInPorts()
+			gomidi.GetOutPorts()
+			probeMu.Lock()
+			probing = nil
+			probeMu.Unlock()
+			close(done)
+		}(probing)
+	}
+	done := probing
+	probeMu.Unlock()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(ProbeTimeout):
+		return fmt.Errorf("MIDI timeout - CoreMIDI may be hung. Try: sudo killall coreaudiod midiserver")
+	}
+}
+
+// DeviceManager handles MIDI controller connections (the TUI polls ScanPorts for hot-plug)
 type DeviceManager struct {
 	controller Controller // Launchpad (special control surface)
 	noteInput  Controller // MIDI keyboard for recording
 	mu         sync.RWMutex
 	timeout    time.Duration
+	safeMode   bool // MIDI disabled - port operations fail fast with ErrSafeMode
+
+	// Listeners on every input but the controller (CCs for MIDI learn, thru)
+	inputMu        sync.Mutex
+	inputListeners map[string]func() // port → stop
+	ccChan         chan CCEvent
+	thruChan       chan ThruEvent
 }
 
 // NewDeviceManager creates a new device manager
 func NewDeviceManager() *DeviceManager {
 	return &DeviceManager{
-		timeout: 5 * time.Second,
+		timeout:        5 * time.Second,
+		inputListeners: make(map[string]func()),
+		ccChan:         make(chan CCEvent, 64),
+		thruChan:       make(chan ThruEvent, 256),
+	}
+}
+
+// SetSafeMode enables or disables safe mode (no MIDI port access)
+func (dm *DeviceManager) SetSafeMode(on bool) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+	dm.safeMode = on
+}
+
+// SafeMode returns true while MIDI is disabled
+func (dm *DeviceManager) SafeMode() bool {
+	dm.mu.RLock()
+	defer dm.mu.RUnlock()
+	return dm.safeMode
+}
+
+// RetryMIDI probes MIDI again and leaves safe mode if it responds
+func (dm *DeviceManager) RetryMIDI() error {
+	if err := Probe(); err != nil {
+		return err
 	}
+	dm.SetSafeMode(false)
+	return nil
 }
 
 // GetController returns the currently connected controller (or nil)
@@ -59,8 +134,9 @@ func (dm *DeviceManager) GetNoteInput() Controller {
 	return dm.noteInput
 }
 
-// ConnectNoteInput connects to a MIDI keyboard for recording
-func (dm *DeviceManager) ConnectNoteInput(portName string) error {
+// ConnectNoteInput connects to a MIDI keyboard for recording (inputChannel 1-16 listens
+// on one channel, 0 on all)
+func (dm *DeviceManager) ConnectNoteInput(portName string, inputChannel int) error {
 	dm.mu.Lock()
 	defer dm.mu.Unlock()
 
@@ -73,6 +149,9 @@ func (dm *DeviceManager) ConnectNoteInput(portName string) error {
 	if portName == "" {
 		return nil // Just disconnect
 	}
+	if dm.safeMode {
+		return ErrSafeMode
+	}
 
 	// Find the port
 	inPorts := gomidi.GetInPorts()
@@ -89,7 +168,7 @@ func (dm *DeviceManager) ConnectNoteInput(portName string) error {
 	}
 
 	// Create keyboard controller for note input
-	ctrl, err := NewKeyboardController(portName, inPort)
+	ctrl, err := NewKeyboardController(portName, inPort, inputChannel)
 	if err != nil {
 		return err
 	}
@@ -121,6 +200,10 @@ func (dm *DeviceManager) Connect(cfg *config.Config) error {
 		dm.controller = nil
 	}
 
+	if dm.safeMode {
+		return ErrSafeMode
+	}
+
 	// Timeout wrapper for all CoreMIDI operations
 	ctx, cancel := context.WithTimeout(context.Background(), dm.timeout)
 	defer cancel()
@@ -168,23 +251,23 @@ func (dm *DeviceManager) tryConnect(cfg *config.Config) (Controller, error) {
 		outName := strings.Replace(ctrlCfg.PortName, "In", "Out", 1)
 		outPort := findPortByName(outPorts, outName)
 
-		ctrl, err := dm.createController(ctrlCfg.Type, inPort, outPort)
+		ctrl, err := dm.createController(ctrlCfg, inPort, outPort)
 		if err == nil {
 			return ctrl, nil
 		}
 	}
 
-	// Fallback: try to find any Launchpad
+	// Fallback: try to find any Launchpad or APC
 	for _, inPort := range inPorts {
 		name := strings.ToLower(inPort.String())
-		if strings.Contains(name, "launchpad") && strings.Contains(name, "midi") {
+		if (strings.Contains(name, "launchpad") && strings.Contains(name, "midi")) || strings.Contains(name, "apc") {
 			// Find matching output
 			outPort := findPortByName(outPorts, inPort.String())
 
 			// Detect type from name
-			ctrlType := detectControllerType(inPort.String())
+			ctrlType := DetectControllerType(inPort.String())
 
-			ctrl, err := dm.createController(ctrlType, inPort, outPort)
+			ctrl, err := dm.createController(config.ControllerConfig{Type: ctrlType}, inPort, outPort)
 			if err == nil {
 				return ctrl, nil
 			}
@@ -195,16 +278,26 @@ func (dm *DeviceManager) tryConnect(cfg *config.Config) (Controller, error) {
 }
 
 // createController creates the appropriate controller based on type
-func (dm *DeviceManager) createController(ctrlType config.ControllerType, inPort drivers.In, outPort drivers.Out) (Controller, error) {
-	switch ctrlType {
+func (dm *DeviceManager) createController(ctrlCfg config.ControllerConfig, inPort drivers.In, outPort drivers.Out) (Controller, error) {
+	switch ctrlType := ctrlCfg.Type; ctrlType {
 	case config.ControllerLaunchpadX:
 		return NewLaunchpadController(inPort.String(), inPort, outPort)
 	case config.ControllerLaunchpadMini:
 		return NewLaunchpadController(inPort.String(), inPort, outPort) // Same for now
 	case config.ControllerLaunchpadPro:
-		return NewLaunchpadController(inPort.String(), inPort, outPort) // Same for now
+		return NewLaunchpadProController(inPort.String(), inPort, outPort)
+	case config.ControllerAPCMini:
+		return NewAPCMiniController(inPort.String(), inPort, outPort)
+	case config.ControllerAPCKey:
+		return NewAPCKeyController(inPort.String(), inPort, outPort)
+	case config.ControllerGenericGrid:
+		mapping, err := LoadGridMapping(ctrlCfg.MappingPath())
+		if err != nil {
+			return nil, err
+		}
+		return NewGenericGridController(inPort.String(), mapping, inPort, outPort)
 	case config.ControllerKeyboard:
-		return NewKeyboardController(inPort.String(), inPort)
+		return NewKeyboardController(inPort.String(), inPort, ctrlCfg.InputChannel)
 	default:
 		return nil, fmt.Errorf("unknown controller type: %s", ctrlType)
 	}
@@ -221,8 +314,79 @@ func (dm *DeviceManager) Disconnect() {
 	}
 }
 
-// ScanPorts returns available MIDI ports (with timeout)
+// CCEvents returns control changes from the ports ListenInputs listens on
+func (dm *DeviceManager) CCEvents() <-chan CCEvent {
+	return dm.ccChan
+}
+
+// ThruEvents returns every channel message (notes, CCs, bends, pressure) from the
+// ports ListenInputs listens on
+func (dm *DeviceManager) ThruEvents() <-chan ThruEvent {
+	return dm.thruChan
+}
+
+// ListenInputs listens on the given input ports (the controller's port is left to the
+// controller), and stops listening on ports no longer listed. Called after every port
+// scan, so only new ports are opened.
+func (dm *DeviceManager) ListenInputs(inputs []string) {
+	if dm.SafeMode() {
+		inputs = nil
+	}
+	exclude := ""
+	if ctrl := dm.GetController(); ctrl != nil {
+		exclude = ctrl.ID()
+	}
+
+	dm.inputMu.Lock()
+	defer dm.inputMu.Unlock()
+	for name, stop := range dm.inputListeners {
+		if name == exclude || !slices.Contains(inputs, name) {
+			stop()
+			delete(dm.inputListeners, name)
+		}
+	}
+	var inPorts []drivers.In
+	for _, name := range inputs {
+		if _, ok := dm.inputListeners[name]; ok || name == exclude {
+			continue
+		}
+		if inPorts == nil {
+			inPorts = gomidi.GetInPorts()
+		}
+		for _, p := range inPorts {
+			if p.String() != name {
+				continue
+			}
+			stop, err := gomidi.ListenTo(p, func(msg gomidi.Message, timestampms int32) {
+				var ch, cc, val uint8
+				if msg.GetControlChange(&ch, &cc, &val) {
+					select {
+					case dm.ccChan <- CCEvent{Port: name, Channel: ch, Controller: cc, Value: val}:
+					default:
+					}
+				}
+				if len(msg) > 0 && msg[0] >= 0x80 && msg[0] < 0xF0 {
+					select {
+					case dm.thruChan <- ThruEvent{Port: name, Msg: slices.Clone(msg)}:
+					default:
+					}
+				}
+			})
+			if err == nil {
+				dm.inputListeners[name] = stop
+			}
+			break
+		}
+	}
+}
+
+// ScanPorts returns available MIDI ports (with timeout). Network sessions are listed
+// after the outputs.
 func (dm *DeviceManager) ScanPorts() ([]string, []string, error) {
+	if dm.SafeMode() {
+		return nil, nil, ErrSafeMode
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), dm.timeout)
 	defer cancel()
 
@@ -244,6 +408,7 @@ func (dm *DeviceManager) ScanPorts() ([]string, []string, error) {
 		for _, p := range outPorts {
 			outNames = append(outNames, p.String())
 		}
+		outNames = append(outNames, rtpmidi.PortNames()...)
 		ch <- result{inNames: inNames, outNames: outNames}
 	}()
 
@@ -268,15 +433,20 @@ func findPortByName[T interface{ String() string }](ports []T, name string) T {
 	return zero
 }
 
-func detectControllerType(portName string) config.ControllerType {
+// DetectControllerType guesses a controller's type from its port name
+func DetectControllerType(portName string) config.ControllerType {
 	name := strings.ToLower(portName)
 	switch {
 	case strings.Contains(name, "launchpad x"):
 		return config.ControllerLaunchpadX
 	case strings.Contains(name, "launchpad mini"):
 		return config.ControllerLaunchpadMini
-	case strings.Contains(name, "launchpad pro"):
+	case strings.Contains(name, "launchpad pro"), strings.Contains(name, "lppromk3"):
 		return config.ControllerLaunchpadPro
+	case strings.Contains(name, "apc mini"):
+		return config.ControllerAPCMini
+	case strings.Contains(name, "apc key"):
+		return config.ControllerAPCKey
 	default:
 		return config.ControllerLaunchpadX // Default assumption
 	}
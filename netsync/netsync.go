@@ -0,0 +1,248 @@
+package netsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Role is which side of the link this instance is
+type Role string
+
+const (
+	RoleOff      Role = ""
+	RoleLeader   Role = "leader"   // owns transport, accepts followers
+	RoleFollower Role = "follower" // connects to a leader, follows its transport
+)
+
+// DefaultAddr is the leader's listen address when none is configured
+const DefaultAddr = ":7400"
+
+// MsgType identifies a sync message
+type MsgType string
+
+const (
+	MsgHello MsgType = "hello" // follower joined - leader replies with current state
+	MsgPlay  MsgType = "play"  // start playback at Tick
+	MsgStop  MsgType = "stop"
+	MsgTempo MsgType = "tempo"
+	MsgSync  MsgType = "sync"  // leader's current tick (drift correction)
+	MsgQueue MsgType = "queue" // clip/scene launch: Track plays Pattern
+)
+
+// Message is one line of JSON on the wire
+type Message struct {
+	Type    MsgType `json:"type"`
+	Tempo   int     `json:"tempo,omitempty"`
+	Tick    int64   `json:"tick,omitempty"`
+	Track   int     `json:"track,omitempty"`
+	Pattern int     `json:"pattern,omitempty"`
+
+	from *peer // the peer it came from (nil for our own messages)
+}
+
+// Link is a TCP connection between a leader and its followers
+type Link struct {
+	role     Role
+	addr     string
+	listener net.Listener // leader only
+
+	mu    sync.Mutex
+	peers []*peer
+
+	msgs      chan Message
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type peer struct {
+	conn net.Conn
+	enc  *json.Encoder
+}
+
+// Dial/write timeouts
+const (
+	dialTimeout  = 5 * time.Second
+	writeTimeout = time.Second
+)
+
+// Open starts a link in the given role. Leaders listen on addr (DefaultAddr if empty),
+// followers connect to the leader at addr.
+func Open(role Role, addr string) (*Link, error) {
+	switch role {
+	case RoleLeader:
+		return Listen(addr)
+	case RoleFollower:
+		return Dial(addr)
+	}
+	return nil, fmt.Errorf("unknown sync role %q", role)
+}
+
+// Listen starts a leader link accepting followers on addr
+func Listen(addr string) (*Link, error) {
+	if addr == "" {
+		addr = DefaultAddr
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	l := newLink(RoleLeader, addr)
+	l.listener = ln
+	go l.acceptLoop()
+	return l, nil
+}
+
+// Dial connects a follower link to the leader at addr
+func Dial(addr string) (*Link, error) {
+	if addr == "" {
+		return nil, fmt.Errorf("follower needs a leader address")
+	}
+	conn, err := net.DialTimeout("tcp", addr, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	l := newLink(RoleFollower, addr)
+	l.addPeer(conn)
+	l.Send(Message{Type: MsgHello})
+	return l, nil
+}
+
+func newLink(role Role, addr string) *Link {
+	return &Link{
+		role: role,
+		addr: addr,
+		msgs: make(chan Message, 32),
+		done: make(chan struct{}),
+	}
+}
+
+// Role returns which side of the link this is
+func (l *Link) Role() Role {
+	return l.role
+}
+
+// Addr returns the listen (leader) or remote (follower) address
+func (l *Link) Addr() string {
+	return l.addr
+}
+
+// Peers returns the number of connected peers
+func (l *Link) Peers() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.peers)
+}
+
+// Messages returns incoming messages from all peers
+func (l *Link) Messages() <-chan Message {
+	return l.msgs
+}
+
+// Done is closed when the link is closed
+func (l *Link) Done() <-chan struct{} {
+	return l.done
+}
+
+// Send writes a message to every connected peer. Peers that fail are dropped.
+func (l *Link) Send(msg Message) error {
+	return l.sendExcept(msg, nil)
+}
+
+// Relay passes a received message on to every other peer (a leader forwarding one
+// follower's launch to the rest)
+func (l *Link) Relay(msg Message) error {
+	return l.sendExcept(msg, msg.from)
+}
+
+// sendExcept writes a message to every connected peer but skip
+func (l *Link) sendExcept(msg Message, skip *peer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var firstErr error
+	alive := l.peers[:0]
+	for _, p := range l.peers {
+		if p == skip {
+			alive = append(alive, p)
+			continue
+		}
+		p.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := p.enc.Encode(msg); err != nil {
+			p.conn.Close()
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		alive = append(alive, p)
+	}
+	l.peers = alive
+	return firstErr
+}
+
+// Close shuts down the listener and all peer connections
+func (l *Link) Close() {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		if l.listener != nil {
+			l.listener.Close()
+		}
+		l.mu.Lock()
+		for _, p := range l.peers {
+			p.conn.Close()
+		}
+		l.peers = nil
+		l.mu.Unlock()
+	})
+}
+
+func (l *Link) acceptLoop() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			return // listener closed
+		}
+		l.addPeer(conn)
+	}
+}
+
+func (l *Link) addPeer(conn net.Conn) {
+	p := &peer{conn: conn, enc: json.NewEncoder(conn)}
+	l.mu.Lock()
+	l.peers = append(l.peers, p)
+	l.mu.Unlock()
+	go l.readLoop(p)
+}
+
+func (l *Link) removePeer(p *peer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, other := range l.peers {
+		if other == p {
+			l.peers = append(l.peers[:i], l.peers[i+1:]...)
+			break
+		}
+	}
+	p.conn.Close()
+}
+
+// readLoop decodes newline-delimited JSON from a peer until it disconnects
+func (l *Link) readLoop(p *peer) {
+	dec := json.NewDecoder(p.conn)
+	for {
+		var msg Message
+		if err := dec.Decode(&msg); err != nil {
+			l.removePeer(p)
+			return
+		}
+		msg.from = p
+		select {
+		case l.msgs <- msg:
+		case <-l.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package netsync
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRelaySkipsSender(t *testing.T) {
+	l := newLink(RoleLeader, "")
+	defer l.Close()
+	var remotes []net.Conn
+	for range 3 {
+		local, remote := net.Pipe()
+		l.addPeer(local)
+		remotes = append(remotes, remote)
+	}
+
+	sent := Message{Type: MsgQueue, Track: 2, Pattern: 5, Tick: 7680}
+	go json.NewEncoder(remotes[0]).Encode(sent)
+	var got Message
+	select {
+	case got = <-l.Messages():
+	case <-time.After(time.Second):
+		t.Fatal("leader didn't receive the launch")
+	}
+
+	received := make(chan int, len(remotes))
+	var wg sync.WaitGroup
+	for i, r := range remotes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var msg Message
+			r.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+			if json.NewDecoder(r).Decode(&msg) == nil && msg == sent {
+				received <- i
+			}
+		}()
+	}
+	if err := l.Relay(got); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	close(received)
+	var peers []int
+	for i := range received {
+		peers = append(peers, i)
+	}
+	if len(peers) != 2 || peers[0] == 0 || peers[1] == 0 {
+		t.Errorf("relayed to peers %v, want 1 and 2", peers)
+	}
+}
@@ -0,0 +1,354 @@
+// Package rtpmidi sends MIDI to network endpoints over RTP-MIDI (RFC 6295), using the
+// AppleMIDI session protocol that macOS Network MIDI, rtpMIDI on Windows and iOS synths
+// speak. This side is always the session initiator and only sends - incoming MIDI is
+// ignored. Sessions connect (and reconnect) in the background; Send fails while a
+// session isn't up.
+package rtpmidi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultPort is the AppleMIDI control port when an address doesn't give one (the data
+// port is always the one after it)
+const DefaultPort = 5004
+
+// ErrNotConnected is returned by Send while the session isn't established
+var ErrNotConnected = errors.New("network session not connected")
+
+// Timing
+const (
+	inviteInterval = time.Second      // invitations are repeated this often until accepted
+	syncInterval   = 10 * time.Second // clock sync while connected
+	syncTimeout    = 30 * time.Second // no sync answer for this long drops the session
+)
+
+// localName is what the remote sees the session as
+const localName = "go-sequence"
+
+// AppleMIDI exchange commands
+var (
+	cmdInvite = [2]byte{'I', 'N'}
+	cmdAccept = [2]byte{'O', 'K'}
+	cmdReject = [2]byte{'N', 'O'}
+	cmdBye    = [2]byte{'B', 'Y'}
+	cmdSync   = [2]byte{'C', 'K'}
+)
+
+const protocolVersion = 2
+
+// Session is an output session with one remote endpoint
+type Session struct {
+	name     string
+	ctrl     *net.UDPConn
+	data     *net.UDPConn
+	ctrlAddr *net.UDPAddr
+	dataAddr *net.UDPAddr
+	ssrc     uint32
+	start    time.Time
+
+	mu        sync.Mutex
+	token     uint32 // current invitation
+	ctrlOK    bool   // control port accepted
+	connected bool   // both ports accepted
+	lastSync  time.Time
+	seq       uint16
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Open starts a session with the endpoint at addr ("host" or "host:port") and registers
+// it as an output port (see PortName)
+func Open(name, addr string) (*Session, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, strconv.Itoa(DefaultPort))
+	}
+	ctrlAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	dataAddr := *ctrlAddr
+	dataAddr.Port++
+
+	ctrl, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+	s := &Session{
+		name:     name,
+		ctrl:     ctrl,
+		data:     data,
+		ctrlAddr: ctrlAddr,
+		dataAddr: &dataAddr,
+		ssrc:     rand.Uint32(),
+		start:    time.Now(),
+		done:     make(chan struct{}),
+	}
+	go s.readLoop(ctrl, false)
+	go s.readLoop(data, true)
+	go s.run()
+	register(s)
+	return s, nil
+}
+
+// Name returns the session's configured name
+func (s *Session) Name() string {
+	return s.name
+}
+
+// Addr returns the remote control address
+func (s *Session) Addr() string {
+	return s.ctrlAddr.String()
+}
+
+// Connected reports whether the remote has accepted the session
+func (s *Session) Connected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connected
+}
+
+// Send sends one MIDI message (or several back to back) to the endpoint
+func (s *Session) Send(msg []byte) error {
+	if len(msg) == 0 || len(msg) > 0x0FFF {
+		return fmt.Errorf("rtpmidi: bad message length %d", len(msg))
+	}
+	s.mu.Lock()
+	if !s.connected {
+		s.mu.Unlock()
+		return ErrNotConnected
+	}
+	s.seq++
+	seq := s.seq
+	s.mu.Unlock()
+
+	// RTP header: version 2, payload type 97, sequence, timestamp, SSRC
+	pkt := make([]byte, 12, 14+len(msg))
+	pkt[0] = 0x80
+	pkt[1] = 0x61
+	binary.BigEndian.PutUint16(pkt[2:], seq)
+	binary.BigEndian.PutUint32(pkt[4:], uint32(s.now()))
+	binary.BigEndian.PutUint32(pkt[8:], s.ssrc)
+
+	// MIDI command section: no journal, no delta time on the first command
+	if len(msg) <= 0x0F {
+		pkt = append(pkt, byte(len(msg)))
+	} else {
+		pkt = append(pkt, 0x80|byte(len(msg)>>8), byte(len(msg)))
+	}
+	pkt = append(pkt, msg...)
+	_, err := s.data.WriteToUDP(pkt, s.dataAddr)
+	return err
+}
+
+// Close ends the session (telling the remote) and unregisters it
+func (s *Session) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+		unregister(s)
+		s.mu.Lock()
+		token, connected := s.token, s.connected
+		s.connected = false
+		s.mu.Unlock()
+		if connected {
+			bye := s.exchange(cmdBye, token, false)
+			s.ctrl.WriteToUDP(bye, s.ctrlAddr)
+			s.data.WriteToUDP(bye, s.dataAddr)
+		}
+		s.ctrl.Close()
+		s.data.Close()
+	})
+}
+
+// now is the session clock in 100 microsecond units
+func (s *Session) now() uint64 {
+	return uint64(time.Since(s.start) / (100 * time.Microsecond))
+}
+
+// run invites the remote until it accepts, then keeps the clocks synced, starting
+// over when the session drops
+func (s *Session) run() {
+	ticker := time.NewTicker(inviteInterval)
+	defer ticker.Stop()
+	var lastSyncSent time.Time
+	for {
+		s.mu.Lock()
+		connected, ctrlOK, token, lastSync := s.connected, s.ctrlOK, s.token, s.lastSync
+		if connected && time.Since(lastSync) > syncTimeout {
+			s.connected, s.ctrlOK = false, false
+			connected, ctrlOK = false, false
+		}
+		if !connected && !ctrlOK && token == 0 {
+			s.token = rand.Uint32() | 1
+			token = s.token
+		}
+		s.mu.Unlock()
+
+		switch {
+		case !connected && !ctrlOK:
+			s.ctrl.WriteToUDP(s.exchange(cmdInvite, token, true), s.ctrlAddr)
+		case !connected:
+			s.data.WriteToUDP(s.exchange(cmdInvite, token, true), s.dataAddr)
+		case time.Since(lastSyncSent) >= syncInterval:
+			s.sendSync(0, s.now(), 0, 0)
+			lastSyncSent = time.Now()
+		}
+
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// exchange builds an IN/OK/NO/BY packet
+func (s *Session) exchange(cmd [2]byte, token uint32, withName bool) []byte {
+	pkt := make([]byte, 16, 16+len(localName)+1)
+	pkt[0], pkt[1] = 0xFF, 0xFF
+	pkt[2], pkt[3] = cmd[0], cmd[1]
+	binary.BigEndian.PutUint32(pkt[4:], protocolVersion)
+	binary.BigEndian.PutUint32(pkt[8:], token)
+	binary.BigEndian.PutUint32(pkt[12:], s.ssrc)
+	if withName {
+		pkt = append(pkt, localName...)
+		pkt = append(pkt, 0)
+	}
+	return pkt
+}
+
+// sendSync sends a CK packet on the data port
+func (s *Session) sendSync(count uint8, ts1, ts2, ts3 uint64) {
+	pkt := make([]byte, 36)
+	pkt[0], pkt[1] = 0xFF, 0xFF
+	pkt[2], pkt[3] = cmdSync[0], cmdSync[1]
+	binary.BigEndian.PutUint32(pkt[4:], s.ssrc)
+	pkt[8] = count
+	binary.BigEndian.PutUint64(pkt[12:], ts1)
+	binary.BigEndian.PutUint64(pkt[20:], ts2)
+	binary.BigEndian.PutUint64(pkt[28:], ts3)
+	s.data.WriteToUDP(pkt, s.dataAddr)
+}
+
+// readLoop handles session packets from the remote until the socket closes
+func (s *Session) readLoop(conn *net.UDPConn, dataPort bool) {
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			return // closed
+		}
+		pkt := buf[:n]
+		if n < 4 || pkt[0] != 0xFF || pkt[1] != 0xFF {
+			continue // RTP-MIDI from the remote - not used
+		}
+		cmd := [2]byte{pkt[2], pkt[3]}
+		switch cmd {
+		case cmdAccept, cmdReject, cmdBye:
+			if n < 16 {
+				continue
+			}
+			s.handleExchange(cmd, binary.BigEndian.Uint32(pkt[8:]), dataPort)
+		case cmdSync:
+			if n < 36 {
+				continue
+			}
+			s.handleSync(pkt[8], binary.BigEndian.Uint64(pkt[12:]), binary.BigEndian.Uint64(pkt[20:]))
+		}
+	}
+}
+
+func (s *Session) handleExchange(cmd [2]byte, token uint32, dataPort bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cmd == cmdBye {
+		s.connected, s.ctrlOK, s.token = false, false, 0
+		return
+	}
+	if token != s.token {
+		return // answer to an old invitation
+	}
+	switch {
+	case cmd == cmdReject:
+		s.connected, s.ctrlOK, s.token = false, false, 0
+	case !dataPort:
+		s.ctrlOK = true
+	case s.ctrlOK:
+		s.connected = true
+		s.lastSync = time.Now()
+	}
+}
+
+// handleSync answers the remote's half of a clock sync (or finishes ours)
+func (s *Session) handleSync(count uint8, ts1, ts2 uint64) {
+	switch count {
+	case 0:
+		s.sendSync(1, ts1, s.now(), 0)
+	case 1:
+		s.sendSync(2, ts1, ts2, s.now())
+	}
+	s.mu.Lock()
+	s.lastSync = time.Now()
+	s.mu.Unlock()
+}
+
+// --- Output ports ---
+
+var (
+	sessionsMu sync.RWMutex
+	sessions   []*Session
+)
+
+// PortName is how a session is listed among the output ports
+func PortName(name string) string {
+	return name + " (network)"
+}
+
+// PortNames lists the open sessions as output port names
+func PortNames() []string {
+	sessionsMu.RLock()
+	defer sessionsMu.RUnlock()
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = PortName(s.name)
+	}
+	return names
+}
+
+// Lookup finds the session behind an output port name (nil if it isn't one)
+func Lookup(portName string) *Session {
+	sessionsMu.RLock()
+	defer sessionsMu.RUnlock()
+	for _, s := range sessions {
+		if PortName(s.name) == portName {
+			return s
+		}
+	}
+	return nil
+}
+
+func register(s *Session) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	sessions = append(sessions, s)
+}
+
+func unregister(s *Session) {
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	sessions = slices.DeleteFunc(sessions, func(other *Session) bool { return other == s })
+}
@@ -0,0 +1,91 @@
+package sequencer
+
+import (
+	"fmt"
+
+	"go-sequence/midi"
+)
+
+// Live accumulator view - the Accumulator page's fourth sub-page shows each stage's
+// current accumulated offset as a bar (up from the middle for positive offsets, down
+// for negative), so the drift is visible while playing. Pressing a column resets that
+// stage's accumulator.
+
+// AccumSubLive is the live offset sub-page (after value, reset and mode)
+const AccumSubLive = 3
+
+// Live bar scale - each pad is accumLiveStep semitones, four pads either way
+const (
+	accumLiveStep = 3
+	accumLiveRows = 4
+)
+
+// accumBarRows returns how many pads a stage's offset lights (0-4) and whether it
+// runs past the end of the bar
+func accumBarRows(offset int) (int, bool) {
+	mag := max(offset, -offset)
+	rows := (mag + accumLiveStep - 1) / accumLiveStep
+	return min(rows, accumLiveRows), mag > accumLiveRows*accumLiveStep
+}
+
+// renderAccumLivePage draws the live offset bars (rows 4-7 up, rows 3-0 down)
+func (d *MetropolixDevice) renderAccumLivePage() []LEDState {
+	s := d.state
+	pat := &s.Patterns[s.Pattern]
+
+	upColor := [3]uint8{255, 100, 50}
+	downColor := [3]uint8{50, 150, 255}
+	overColor := [3]uint8{255, 255, 255}
+	centerColor := [3]uint8{40, 40, 40}
+	offColor := [3]uint8{0, 0, 0}
+
+	var leds []LEDState
+	for col := 0; col < 8; col++ {
+		offset := s.Accum[col]
+		rows, over := accumBarRows(offset)
+		for row := 0; row < 8; row++ {
+			color := offColor
+			if col < pat.Length {
+				// Distance from the middle: rows 4 and 3 are the first pad either way
+				up := row - 3
+				down := 4 - row
+				switch {
+				case offset > 0 && up >= 1 && up <= rows:
+					color = upColor
+					if over && up == accumLiveRows {
+						color = overColor
+					}
+				case offset < 0 && down >= 1 && down <= rows:
+					color = downColor
+					if over && down == accumLiveRows {
+						color = overColor
+					}
+				case offset == 0 && (row == 3 || row == 4):
+					color = centerColor
+				}
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+// resetStageAccum zeroes one stage's accumulator while playing
+func (s *MetropolixState) resetStageAccum(stage int) {
+	s.Accum[stage] = 0
+	s.AccumCount[stage] = 0
+	s.AccumDir[stage] = 1
+}
+
+// accumLiveRow renders the live offsets as a TUI stage row
+func (d *MetropolixDevice) accumLiveRow(length int) string {
+	out := "   │"
+	for i := 0; i < length; i++ {
+		if offset := d.state.Accum[i]; offset != 0 {
+			out += fmt.Sprintf(" %+3d │", offset)
+		} else {
+			out += "     │"
+		}
+	}
+	return out + " Offset (live)\n"
+}
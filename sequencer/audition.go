@@ -0,0 +1,91 @@
+package sequencer
+
+import (
+	"time"
+
+	"go-sequence/midi"
+)
+
+// Audition - with preview on (P), the piano roll plays a short note whenever the
+// selection lands on a note, or the selected note moves or changes pitch, so edits
+// can be made by ear. Previews (these and the drum pads') go out right away through
+// the same path as played notes - track transpose, output profile and note tracking -
+// so Panic and the note flushes silence them too.
+
+// auditionLength is how long an auditioned note sounds
+const auditionLength = 150 * time.Millisecond
+
+// PreviewChan returns the channel for audition events
+func (p *PianoRollDevice) PreviewChan() <-chan NoteEventState {
+	return p.previewChan
+}
+
+// audition queues the selected note for preview (dropped if the channel is full)
+func (p *PianoRollDevice) audition() {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if !s.Preview || s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	select {
+	case p.previewChan <- pat.Notes[s.SelectedNote]:
+	default:
+	}
+}
+
+// trackAudition auditions the selected note if an edit selected, moved or re-pitched it.
+// Use as: defer p.trackAudition()()
+func (p *PianoRollDevice) trackAudition() func() {
+	s := p.state
+	idx := s.SelectedNote
+	var before NoteEventState
+	if pat := &s.Patterns[s.Editing]; idx >= 0 && idx < len(pat.Notes) {
+		before = pat.Notes[idx]
+	}
+	editing := s.Editing
+	return func() {
+		pat := &s.Patterns[s.Editing]
+		if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+			return
+		}
+		n := pat.Notes[s.SelectedNote]
+		if s.SelectedNote != idx || s.Editing != editing || n.Pitch != before.Pitch || n.Start != before.Start {
+			p.audition()
+		}
+	}
+}
+
+// playAudition plays an auditioned piano note on its track (with its channel override)
+func (m *Manager) playAudition(trackIdx int, n NoteEventState) {
+	m.sendPreview(trackIdx, midi.Event{Type: midi.NoteOn, Note: n.Pitch, Velocity: n.Velocity, OutChannel: n.Channel}, auditionLength)
+}
+
+// sendPreview plays a note on a track now and ends it after length (hold the devices
+// - see devicelock.go; the note-off takes them itself)
+func (m *Manager) sendPreview(trackIdx int, on midi.Event, length time.Duration) {
+	off := midi.Event{Type: midi.NoteOff, Note: on.Note, OutChannel: on.OutChannel}
+	if m.sendNow(trackIdx, on) {
+		time.AfterFunc(length, func() {
+			m.withDevices(func() { m.sendNow(trackIdx, off) })
+		})
+	}
+}
+
+// sendNow sends an event on a track right away, the way dispatch sends it; false if it
+// didn't go out (transposed out of range, or no port)
+func (m *Manager) sendNow(trackIdx int, evt midi.Event) bool {
+	ts := S.Tracks[trackIdx]
+	portName := m.TrackPort(trackIdx)
+	m.voiceMu.Lock()
+	defer m.voiceMu.Unlock()
+	if !m.transposeEvent(trackIdx, ts, &evt) {
+		return false
+	}
+	sender := m.getSender(portName)
+	if sender == nil {
+		return false
+	}
+	ch := m.sendEvent(sender, trackIdx, ts, &evt)
+	m.trackNote(trackIdx, portName, ch, &evt)
+	return true
+}
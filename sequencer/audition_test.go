@@ -0,0 +1,64 @@
+package sequencer
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+)
+
+func TestAuditionIsTracked(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+	S.Tracks[0].Transpose = 5
+
+	m := NewManager()
+	var mu sync.Mutex
+	var sent []string
+	sentCopy := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(sent)
+	}
+	m.SetSender("test", func(msg gomidi.Message) error {
+		mu.Lock()
+		sent = append(sent, msg.String())
+		mu.Unlock()
+		return nil
+	})
+	m.SetDefaultPort("test")
+
+	m.playAudition(0, NoteEventState{Pitch: 60, Velocity: 90})
+	mu.Lock()
+	if len(sent) != 1 || sent[0] != gomidi.NoteOn(0, 65, 90).String() {
+		t.Errorf("audition sent %v, want one transposed note on", sent)
+	}
+	mu.Unlock()
+	m.activeMu.Lock()
+	held := len(m.active[0])
+	m.activeMu.Unlock()
+	if held != 1 {
+		t.Fatalf("%d notes tracked as sounding, want the audition", held)
+	}
+
+	m.Panic()
+	m.activeMu.Lock()
+	held = len(m.active[0])
+	m.activeMu.Unlock()
+	if held != 0 {
+		t.Errorf("%d notes still tracked after a panic", held)
+	}
+
+	// The note-off still goes out on time (and is done with S before it's restored)
+	off := gomidi.NoteOff(0, 65).String()
+	deadline := time.Now().Add(time.Second)
+	for !slices.Contains(sentCopy(), off) {
+		if time.Now().After(deadline) {
+			t.Fatal("the audition's note-off never went out")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,255 @@
+package sequencer
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+
+	"go-sequence/midi"
+)
+
+// Automation - piano patterns carry CC / pitch-bend / pressure lanes. GeneratePattern
+// turns each lane into events, interpolating between breakpoints on a fixed grid.
+// Aftertouch and bend played while recording are written into lanes (see
+// recordExpression).
+
+// Lane "CC numbers" past the 128 controllers
+const (
+	AutomationPitchBend = 128
+	AutomationPressure  = 129 // channel pressure (aftertouch)
+)
+
+// Automation defaults
+const (
+	DefaultAutomationCC = 74       // filter cutoff on most synths
+	automationTicks     = PPQ / 32 // interpolation resolution (1/128 note)
+	automationCoarse    = 8        // value change per key press (fine = 1)
+	automationNumCCs    = AutomationPressure + 1
+	automationRecStep   = 1.0 / 16 // recorded breakpoint spacing (beats - a 1/64 note)
+)
+
+// Name labels a lane for the grid ("cc74", "bend")
+func (l *AutomationLane) Name() string {
+	switch l.CC {
+	case AutomationPitchBend:
+		return "bend"
+	case AutomationPressure:
+		return "press"
+	}
+	return fmt.Sprintf("cc%d", l.CC)
+}
+
+// ValueAt returns the lane's value at a beat (holds the first/last point outside them, -1 if no points)
+func (l *AutomationLane) ValueAt(beat float64) int {
+	if len(l.Points) == 0 {
+		return -1
+	}
+	if beat <= l.Points[0].Beat {
+		return l.Points[0].Value
+	}
+	for i := 1; i < len(l.Points); i++ {
+		a, b := l.Points[i-1], l.Points[i]
+		if beat < b.Beat {
+			t := (beat - a.Beat) / (b.Beat - a.Beat)
+			return a.Value + int(float64(b.Value-a.Value)*t+0.5)
+		}
+	}
+	return l.Points[len(l.Points)-1].Value
+}
+
+// PointAt returns the index of the point at beat (-1 if none)
+func (l *AutomationLane) PointAt(beat float64) int {
+	for i, pt := range l.Points {
+		if pt.Beat > beat-1e-9 && pt.Beat < beat+1e-9 {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetPoint adds or replaces the point at beat, keeping points sorted
+func (l *AutomationLane) SetPoint(beat float64, value int) {
+	value = clamp(value, 0, 127)
+	if i := l.PointAt(beat); i >= 0 {
+		l.Points[i].Value = value
+		return
+	}
+	l.Points = append(l.Points, AutomationPoint{Beat: beat, Value: value})
+	sort.Slice(l.Points, func(i, j int) bool { return l.Points[i].Beat < l.Points[j].Beat })
+}
+
+// automationEvents renders a lane's [from, to) beats as events from startTick, sending only value changes
+func automationEvents(l *AutomationLane, from, to float64, startTick int64) []midi.Event {
+	var events []midi.Event
+	last := -1
+	lengthTicks := int64((to - from) * float64(PPQ))
+	for t := int64(0); t < lengthTicks; t += automationTicks {
+		v := l.ValueAt(from + float64(t)/float64(PPQ))
+		if v < 0 || v == last {
+			continue
+		}
+		last = v
+		evt := midi.Event{Tick: startTick + t}
+		switch l.CC {
+		case AutomationPitchBend:
+			evt.Type = midi.PitchBend
+			evt.BendValue = int16(clamp((v-64)*128, -8192, 8191))
+		case AutomationPressure:
+			evt.Type = midi.Pressure
+			evt.Velocity = uint8(v)
+		default:
+			evt.Type = midi.CC
+			evt.Note = uint8(l.CC) // CC events carry the controller in Note, the value in Velocity
+			evt.Velocity = uint8(v)
+		}
+		events = append(events, evt)
+	}
+	return events
+}
+
+// hasAutomationPoint reports whether the lane has a breakpoint in [from, to)
+func hasAutomationPoint(l *AutomationLane, from, to float64) bool {
+	for _, pt := range l.Points {
+		if pt.Beat >= from && pt.Beat < to {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAutomationKey edits the selected lane at the automation cursor
+func (p *PianoRollDevice) handleAutomationKey(key string) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	editH := EditHorizSteps[s.EditHoriz]
+
+	var lane *AutomationLane
+	if p.autoLane >= 0 && p.autoLane < len(pat.Automation) {
+		lane = &pat.Automation[p.autoLane]
+	}
+	nudge := func(delta int) {
+		if lane == nil {
+			return
+		}
+		v := lane.ValueAt(p.autoBeat)
+		if v < 0 {
+			v = 64
+		}
+		lane.SetPoint(p.autoBeat, v+delta)
+	}
+
+	switch key {
+	case "esc", "A":
+		p.autoMode = false
+	case "h", "left":
+		p.autoBeat = max(0, p.autoBeat-editH)
+	case "l", "right":
+		if p.autoBeat+editH < pat.Length {
+			p.autoBeat += editH
+		}
+	case "j", "down":
+		if p.autoLane < len(pat.Automation)-1 {
+			p.autoLane++
+		}
+	case "k", "up":
+		if p.autoLane > 0 {
+			p.autoLane--
+		}
+	case "[":
+		nudge(-automationCoarse)
+	case "]":
+		nudge(automationCoarse)
+	case "{":
+		nudge(-1)
+	case "}":
+		nudge(1)
+	case " ":
+		nudge(0)
+	case "x":
+		if lane != nil {
+			if i := lane.PointAt(p.autoBeat); i >= 0 {
+				lane.Points = slices.Delete(lane.Points, i, i+1)
+			}
+		}
+	case "n":
+		pat.Automation = append(pat.Automation, AutomationLane{CC: DefaultAutomationCC})
+		p.autoLane = len(pat.Automation) - 1
+	case "c":
+		if lane != nil {
+			lane.CC = (lane.CC + automationNumCCs - 1) % automationNumCCs
+		}
+	case "C":
+		if lane != nil {
+			lane.CC = (lane.CC + 1) % automationNumCCs
+		}
+	case "d":
+		if lane != nil {
+			pat.Automation = slices.Delete(pat.Automation, p.autoLane, p.autoLane+1)
+			p.autoLane = max(0, p.autoLane-1)
+		}
+	}
+}
+
+// --- Recording ---
+
+// recordExpression writes played (or resampled) aftertouch and pitch bend into the
+// pattern: channel pressure and bend become breakpoints on their lanes (added if
+// missing), poly pressure the peak pressure of the held note it belongs to
+func (p *PianoRollDevice) recordExpression(pat *PianoPatternState, event midi.Event, beat float64) {
+	switch {
+	case event.Type == midi.PolyPressure || (event.Type == midi.Pressure && event.PerNote):
+		if pending, ok := p.pendingNotes[event.Note]; ok {
+			pending.Pressure = max(pending.Pressure, event.Velocity)
+		}
+	case event.Type == midi.Pressure:
+		p.recordPoint(pat, AutomationPressure, beat, int(event.Velocity))
+	case event.Type == midi.PitchBend && !event.PerNote:
+		p.recordPoint(pat, AutomationPitchBend, beat, int(event.BendValue)/128+64)
+	}
+}
+
+// recordPoint sets a recorded value on a lane, replacing the points the take has
+// passed over since its last one on that lane
+func (p *PianoRollDevice) recordPoint(pat *PianoPatternState, cc int, beat float64, value int) {
+	idx := slices.IndexFunc(pat.Automation, func(l AutomationLane) bool { return l.CC == cc })
+	if idx < 0 {
+		pat.Automation = append(pat.Automation, AutomationLane{CC: cc})
+		idx = len(pat.Automation) - 1
+	}
+	lane := &pat.Automation[idx]
+
+	beat = math.Floor(beat/automationRecStep) * automationRecStep
+	if beat < 0 || beat >= pat.Length {
+		return
+	}
+	if p.autoRecLast == nil {
+		p.autoRecLast = make(map[int]float64)
+	}
+	if last, ok := p.autoRecLast[cc]; ok && last < beat {
+		lane.Points = slices.DeleteFunc(lane.Points, func(pt AutomationPoint) bool {
+			return pt.Beat > last && pt.Beat < beat
+		})
+	}
+	lane.SetPoint(beat, value)
+	p.autoRecLast[cc] = beat
+}
+
+// cloneAutomation deep-copies automation lanes (for undo snapshots and the clipboard)
+func cloneAutomation(lanes []AutomationLane) []AutomationLane {
+	if lanes == nil {
+		return nil
+	}
+	out := make([]AutomationLane, len(lanes))
+	for i, l := range lanes {
+		out[i] = AutomationLane{CC: l.CC, Points: slices.Clone(l.Points)}
+	}
+	return out
+}
+
+// automationEqual reports whether two sets of lanes are identical
+func automationEqual(a, b []AutomationLane) bool {
+	return slices.EqualFunc(a, b, func(x, y AutomationLane) bool {
+		return x.CC == y.CC && slices.Equal(x.Points, y.Points)
+	})
+}
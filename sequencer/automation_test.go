@@ -0,0 +1,29 @@
+package sequencer
+
+import "testing"
+
+func TestAutomationValueAt(t *testing.T) {
+	lane := AutomationLane{Points: []AutomationPoint{{Beat: 1, Value: 0}, {Beat: 3, Value: 100}, {Beat: 4, Value: 50}}}
+	tests := []struct {
+		name string
+		lane AutomationLane
+		beat float64
+		want int
+	}{
+		{"no points", AutomationLane{}, 1, -1},
+		{"before the first point", lane, 0, 0},
+		{"on a point", lane, 3, 100},
+		{"halfway", lane, 2, 50},
+		{"rounds", lane, 1.01, 1},
+		{"falling", lane, 3.25, 88},
+		{"after the last point", lane, 8, 50},
+		{"single point", AutomationLane{Points: []AutomationPoint{{Beat: 2, Value: 64}}}, 5, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lane.ValueAt(tt.beat); got != tt.want {
+				t.Errorf("ValueAt(%v) = %d, want %d", tt.beat, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go-sequence/debug"
+)
+
+// Auto-save - before a pattern clear, a device type change, a scene delete or loading
+// a project, unsaved changes are written to a backup save in the current project
+// (named "auto-before-..." in the save list), so the confirmations have a real safety
+// net behind them. Loading a project's latest save skips its backups, and only the
+// newest maxBackups are kept.
+
+// Backup saves
+const (
+	backupPrefix = "auto-before-" // save name prefix marking a backup
+	maxBackups   = 10             // per project, oldest deleted first
+)
+
+// IsBackup reports whether a save is an automatic backup
+func (s SaveInfo) IsBackup() bool {
+	return strings.HasPrefix(s.Name, backupPrefix)
+}
+
+// autoBackup writes the state to a backup save before a destructive operation
+// (nothing when there are no unsaved changes - the last save already has it)
+func autoBackup(operation string) {
+	if !S.Unsaved() {
+		return
+	}
+	projectName := S.ProjectName
+	if projectName == "" {
+		projectName = "untitled"
+	}
+	if err := writeSave(projectName, backupPrefix+operation); err != nil {
+		debug.Log("autosave", "backup before %s failed: %v", operation, err)
+		return
+	}
+	pruneBackups(projectName)
+}
+
+// writeSave writes the state to a new timestamped save, named if name isn't empty
+func writeSave(projectName, name string) error {
+	dir, err := ProjectDir(projectName)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(S, "", "  ")
+	if err != nil {
+		return err
+	}
+	filename := time.Now().Format("2006-01-02_15-04-05")
+	if name != "" {
+		filename += "_" + sanitizeFilename(name)
+	}
+	return os.WriteFile(filepath.Join(dir, filename+".json"), data, 0644)
+}
+
+// pruneBackups deletes a project's oldest backups past maxBackups
+func pruneBackups(projectName string) {
+	saves, err := ListSaves(projectName)
+	if err != nil {
+		return
+	}
+	kept := 0
+	for _, save := range saves { // newest first
+		if !save.IsBackup() {
+			continue
+		}
+		if kept++; kept > maxBackups {
+			DeleteSave(projectName, save.Filename)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package sequencer
+
+import (
+	"math"
+
+	"go-sequence/midi"
+)
+
+// Session capture - records the keyboard into the next empty pattern slot of a track
+// for a fixed number of bars, starting at the next bar, then launches the new clip.
+// The manager collects the take itself and hands it to the device in one go when it
+// ends, so it doesn't matter what the track plays meanwhile.
+
+// captureBarOptions are the take lengths the session cycles through
+var captureBarOptions = []int{1, 2, 4, 8}
+
+// DefaultCaptureBars is the take length before the user picks one
+const DefaultCaptureBars = 4
+
+// capturedNote is one note of a take, in ticks from the start of the take
+type capturedNote struct {
+	Offset   int64
+	Length   int64 // 0 while the note is still held
+	Note     uint8
+	Velocity uint8
+}
+
+// captureTake is a capture in progress (guarded by Manager.captureMu)
+type captureTake struct {
+	track   int
+	pattern int
+	bars    int
+	from    int64 // take starts at this tick
+	until   int64 // and ends here
+	notes   []capturedNote
+	held    map[uint8]int // sounding notes → index in notes
+}
+
+// captureDevice is a device that can write a take into a pattern
+type captureDevice interface {
+	captureClip(pattern, bars int, notes []capturedNote)
+	maxCaptureBars() int // longest take a pattern holds
+}
+
+// captureEarly is how early a note may come in and still land on the take's downbeat
+const captureEarly = PPQ / 4
+
+// StartCapture arms a take on a track: the first empty pattern from startPattern on
+// records for bars bars (cut to the most the device's pattern holds) from the next
+// bar. Calling it again while a take is armed or recording cancels the take.
+func (m *Manager) StartCapture(trackIdx, startPattern, bars int) {
+	m.captureMu.Lock()
+	defer m.captureMu.Unlock()
+	if m.capture != nil {
+		m.capture = nil
+		m.announce("capture cancelled")
+		return
+	}
+	if !S.Playing {
+		m.announce("capture needs the transport running")
+		return
+	}
+	dev := m.GetDevice(trackIdx)
+	cd, ok := dev.(captureDevice)
+	if !ok {
+		m.announce("T%d can't capture (drum and piano tracks only)", trackIdx+1)
+		return
+	}
+	pattern := nextEmptyPattern(dev.ContentMask(), startPattern)
+	if pattern < 0 {
+		m.announce("T%d has no empty pattern to capture into", trackIdx+1)
+		return
+	}
+	clamped := bars > cd.maxCaptureBars()
+	bars = min(bars, cd.maxCaptureBars())
+	from := nextBarTick(dev)
+	m.capture = &captureTake{
+		track:   trackIdx,
+		pattern: pattern,
+		bars:    bars,
+		from:    from,
+		until:   from + int64(bars)*4*PPQ,
+		held:    make(map[uint8]int),
+	}
+	if clamped {
+		m.announce("capturing T%d pattern %d for %d bars (the most it holds) from the next bar", trackIdx+1, pattern+1, bars)
+		return
+	}
+	m.announce("capturing T%d pattern %d for %d bars from the next bar", trackIdx+1, pattern+1, bars)
+}
+
+// nextEmptyPattern returns the first empty pattern at or after start, wrapping (-1 if full)
+func nextEmptyPattern(mask []bool, start int) int {
+	for i := 0; i < len(mask); i++ {
+		p := (start + i) % len(mask)
+		if !mask[p] {
+			return p
+		}
+	}
+	return -1
+}
+
+// Capturing returns the take in progress: its track and pattern, and whether it has
+// started recording (ok false if no take is armed)
+func (m *Manager) Capturing() (track, pattern int, recording, ok bool) {
+	m.captureMu.Lock()
+	defer m.captureMu.Unlock()
+	c := m.capture
+	if c == nil {
+		return 0, 0, false, false
+	}
+	return c.track, c.pattern, S.Tick >= c.from, true
+}
+
+// captureTrack returns the track being captured into (-1 if none)
+func (m *Manager) captureTrack() int {
+	m.captureMu.Lock()
+	defer m.captureMu.Unlock()
+	if m.capture == nil {
+		return -1
+	}
+	return m.capture.track
+}
+
+// captureInput adds a keyboard event to the take; false when no take is armed
+func (m *Manager) captureInput(evt midi.Event) bool {
+	m.captureMu.Lock()
+	defer m.captureMu.Unlock()
+	c := m.capture
+	if c == nil {
+		return false
+	}
+	offset := max(evt.Tick-c.from, 0)
+	switch {
+	case evt.Type == midi.NoteOn && evt.Velocity > 0:
+		if evt.Tick < c.from-captureEarly || evt.Tick >= c.until {
+			return true
+		}
+		c.held[evt.Note] = len(c.notes)
+		c.notes = append(c.notes, capturedNote{Offset: offset, Note: evt.Note, Velocity: evt.Velocity})
+	default:
+		if i, ok := c.held[evt.Note]; ok {
+			c.notes[i].Length = max(offset-c.notes[i].Offset, 1)
+			delete(c.held, evt.Note)
+		}
+	}
+	return true
+}
+
+// checkCapture ends a take once playback passes its last bar: the notes go into the
+// pattern and the pattern is launched. Stopping the transport drops the take.
+func (m *Manager) checkCapture() {
+	m.captureMu.Lock()
+	c := m.capture
+	if c == nil {
+		m.captureMu.Unlock()
+		return
+	}
+	if !S.Playing {
+		m.capture = nil
+		m.captureMu.Unlock()
+		m.announce("capture dropped (transport stopped)")
+		return
+	}
+	if S.Tick < c.until {
+		m.captureMu.Unlock()
+		return
+	}
+	m.capture = nil
+	m.captureMu.Unlock()
+
+	// Notes still held run to the end of the take
+	for _, i := range c.held {
+		c.notes[i].Length = max(c.until-c.from-c.notes[i].Offset, 1)
+	}
+	if dev, ok := m.GetDevice(c.track).(captureDevice); ok {
+		dev.captureClip(c.pattern, c.bars, c.notes)
+	}
+	m.QueuePattern(c.track, c.pattern)
+	m.announce("captured %d notes into T%d pattern %d", len(c.notes), c.track+1, c.pattern+1)
+}
+
+// cycleCaptureBars steps the session's take length through captureBarOptions
+func (s *SessionDevice) cycleCaptureBars() {
+	i := 0
+	for j, bars := range captureBarOptions {
+		if bars == s.captureBars {
+			i = (j + 1) % len(captureBarOptions)
+		}
+	}
+	s.captureBars = captureBarOptions[i]
+	s.manager.announce("capture length %d bars", s.captureBars)
+}
+
+// --- Piano roll ---
+
+func (p *PianoRollDevice) maxCaptureBars() int { return int(maxPianoLength / 4) }
+
+// captureClip writes a take into a pattern (undoable), quantized to the record grid
+func (p *PianoRollDevice) captureClip(pattern, bars int, notes []capturedNote) {
+	pat := p.patternData(pattern)
+	p.history.push(pattern, clonePianoPattern(pat))
+	pat.Length = float64(bars * 4)
+
+	grid := p.state.recordGrid()
+	minDuration := grid
+	if grid == 0 {
+		minDuration = EditHorizSteps[0]
+	}
+	pat.Notes = nil
+	for _, n := range notes {
+		start := float64(n.Offset) / PPQ
+		duration := float64(n.Length) / PPQ
+		if grid > 0 {
+			start = quantizeStart(start, grid, 100, pat.Length)
+			duration = math.Round(duration/grid) * grid
+		}
+		pat.Notes = append(pat.Notes, NoteEventState{
+			Start:    start,
+			Pitch:    n.Note,
+			Velocity: n.Velocity,
+			Duration: max(duration, minDuration),
+		})
+	}
+	p.state.Patterns[pattern] = pat
+	p.regeneratePatternInQueue(pattern)
+}
+
+// --- Drum ---
+
+// maxCaptureBars is what a drum lane holds - 32 steps, two bars of 16ths
+func (d *DrumDevice) maxCaptureBars() int {
+	return len(d.state.Patterns[0].Notes[0].Steps) / 16
+}
+
+// captureClip writes a take into a pattern (undoable), one step per 16th
+func (d *DrumDevice) captureClip(pattern, bars int, notes []capturedNote) {
+	pat := d.state.Patterns[pattern]
+	d.pushUndo(pattern, pat)
+
+	steps := min(bars*16, len(pat.Notes[0].Steps))
+	pat.Length = 0
+	for lane := range pat.Notes {
+		pat.Notes[lane].Length = steps
+	}
+	kit := GetKit(S.Tracks[d.track].Kit)
+	ticksPerStep := int64(PPQ / 4)
+	for _, n := range notes {
+		lane := kitSlot(kit, n.Note)
+		step := int((n.Offset + ticksPerStep/2) / ticksPerStep)
+		if lane < 0 || step >= steps {
+			continue
+		}
+		pat.Notes[lane].Steps[step] = DrumStepState{Active: true, Velocity: n.Velocity}
+	}
+	d.state.Patterns[pattern] = pat
+	d.patternDirty[pattern] = true
+	d.syncQueueToSchedule()
+}
+
+// kitSlot returns the drum slot a note plays in a kit, falling back to the note itself
+// for notes 0-15 like live recording does (-1 if neither)
+func kitSlot(kit DrumKit, note uint8) int {
+	for slot, n := range kit.Notes {
+		if n == note {
+			return slot
+		}
+	}
+	if note < 16 {
+		return int(note)
+	}
+	return -1
+}
@@ -0,0 +1,37 @@
+package sequencer
+
+import "testing"
+
+func TestStartCaptureClampsBars(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name     string
+		dev      func() Device
+		bars     int
+		wantBars int
+	}{
+		{"drum default", func() Device { return NewDrumDevice(NewDrumState()) }, DefaultCaptureBars, 2},
+		{"drum one bar", func() Device { return NewDrumDevice(NewDrumState()) }, 1, 1},
+		{"piano eight bars", func() Device { return NewPianoRollDevice(NewPianoState()) }, 8, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			S.Playing = true
+			m := NewManager()
+			m.SetDevice(0, tt.dev())
+			m.StartCapture(0, 0, tt.bars)
+			if m.capture == nil {
+				t.Fatal("no take armed")
+			}
+			if m.capture.bars != tt.wantBars {
+				t.Errorf("take is %d bars, want %d", m.capture.bars, tt.wantBars)
+			}
+			if got := m.capture.until - m.capture.from; got != int64(tt.wantBars)*4*PPQ {
+				t.Errorf("take lasts %d ticks, want %d bars", got, tt.wantBars)
+			}
+		})
+	}
+}
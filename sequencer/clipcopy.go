@@ -0,0 +1,170 @@
+package sequencer
+
+import "fmt"
+
+// Clip copy - the session copies a pattern from one track/slot to another track of
+// the same device type (or moves it, leaving the source empty). Pastes and clears are
+// undoable on the receiving device.
+
+// clipDevice is a device whose patterns the session can copy
+type clipDevice interface {
+	clipData(pattern int) any             // snapshot of a pattern
+	pasteClip(pattern int, data any) bool // false if data is from another device type
+	clearClip(pattern int)                // empty a pattern
+}
+
+// sessionClip is a copied clip waiting to be pasted
+type sessionClip struct {
+	track   int
+	pattern int
+	data    any
+	label   ClipLabel
+	move    bool // clear the source once pasted
+}
+
+// copyHeldClip handles a grid press while another clip pad is held: it copies the
+// held clip onto the pressed slot (returns false when no clip pad is held)
+func (s *SessionDevice) copyHeldClip(track, pattern int) bool {
+	if s.holdTrack < 0 {
+		return false
+	}
+	s.CopyClip(s.holdTrack, s.holdPattern, false)
+	s.PasteClip(track, pattern)
+	s.holdCopied = true
+	return true
+}
+
+// CopyClip snapshots a track's pattern for pasting; move clears the source on paste
+func (s *SessionDevice) CopyClip(track, pattern int, move bool) {
+	dev, ok := s.manager.GetDevice(track).(clipDevice)
+	if !ok {
+		s.manager.announce("track %d has no clips to copy", track+1)
+		return
+	}
+	s.clip = &sessionClip{track: track, pattern: pattern, data: dev.clipData(pattern), label: S.Tracks[track].Clips[pattern], move: move}
+	verb := "copied"
+	if move {
+		verb = "cut"
+	}
+	s.manager.announce("T%d pattern %d %s", track+1, pattern+1, verb)
+}
+
+// PasteClip puts the copied clip in a track's pattern slot
+func (s *SessionDevice) PasteClip(track, pattern int) {
+	c := s.clip
+	if c == nil {
+		s.manager.announce("nothing copied")
+		return
+	}
+	if c.track == track && c.pattern == pattern {
+		return
+	}
+	dev, ok := s.manager.GetDevice(track).(clipDevice)
+	if !ok || !dev.pasteClip(pattern, c.data) {
+		s.manager.announce("T%d is a %s track - clips only paste onto the same device type", track+1, S.Tracks[track].Type)
+		return
+	}
+	S.Tracks[track].Clips.set(pattern, c.label)
+	if c.move {
+		if src, ok := s.manager.GetDevice(c.track).(clipDevice); ok {
+			src.clearClip(c.pattern)
+		}
+		S.Tracks[c.track].Clips.set(c.pattern, ClipLabel{})
+		s.clip = nil
+	}
+	s.manager.announce("pasted to T%d pattern %d", track+1, pattern+1)
+}
+
+// String describes the copied clip for the view
+func (c *sessionClip) String() string {
+	verb := "copied"
+	if c.move {
+		verb = "cut"
+	}
+	return fmt.Sprintf("T%d pattern %d %s", c.track+1, c.pattern+1, verb)
+}
+
+// --- Drum ---
+
+func (d *DrumDevice) clipData(pattern int) any { return d.state.Patterns[pattern] }
+
+func (d *DrumDevice) pasteClip(pattern int, data any) bool {
+	pat, ok := data.(DrumPatternState)
+	if !ok {
+		return false
+	}
+	d.setClip(pattern, pat)
+	return true
+}
+
+func (d *DrumDevice) clearClip(pattern int) {
+	pat := d.state.Patterns[pattern]
+	for n := range pat.Notes {
+		for step := range pat.Notes[n].Steps {
+			pat.Notes[n].Steps[step].Active = false
+		}
+	}
+	d.setClip(pattern, pat)
+}
+
+// setClip replaces a pattern from the session (undoable)
+func (d *DrumDevice) setClip(pattern int, pat DrumPatternState) {
+	d.pushUndo(pattern, d.state.Patterns[pattern])
+	d.state.Patterns[pattern] = pat
+	d.patternDirty[pattern] = true
+	d.syncQueueToSchedule()
+}
+
+// --- Piano roll ---
+
+func (p *PianoRollDevice) clipData(pattern int) any { return p.patternData(pattern) }
+
+func (p *PianoRollDevice) pasteClip(pattern int, data any) bool {
+	pat, ok := data.(PianoPatternState)
+	if !ok {
+		return false
+	}
+	p.setClip(pattern, clonePianoPattern(pat))
+	return true
+}
+
+func (p *PianoRollDevice) clearClip(pattern int) {
+	pat := p.patternData(pattern)
+	pat.Notes = nil
+	pat.Automation = nil
+	p.setClip(pattern, pat)
+}
+
+// setClip replaces a pattern from the session (undoable)
+func (p *PianoRollDevice) setClip(pattern int, pat PianoPatternState) {
+	p.history.push(pattern, p.patternData(pattern))
+	p.state.Patterns[pattern] = pat
+	if pattern == p.state.Editing && p.state.SelectedNote >= len(pat.Notes) {
+		p.state.SelectedNote = len(pat.Notes) - 1
+	}
+	p.regeneratePatternInQueue(pattern)
+}
+
+// --- Metropolix ---
+
+func (d *MetropolixDevice) clipData(pattern int) any { return d.state.Patterns[pattern] }
+
+func (d *MetropolixDevice) pasteClip(pattern int, data any) bool {
+	pat, ok := data.(MetropolixPatternState)
+	if !ok {
+		return false
+	}
+	d.setClip(pattern, pat)
+	return true
+}
+
+func (d *MetropolixDevice) clearClip(pattern int) {
+	d.setClip(pattern, NewMetropolixState().Patterns[pattern])
+}
+
+// setClip replaces a pattern from the session (undoable)
+func (d *MetropolixDevice) setClip(pattern int, pat MetropolixPatternState) {
+	d.history.push(pattern, d.state.Patterns[pattern])
+	d.state.Patterns[pattern] = pat
+	d.regeneratePatternInQueue(pattern)
+}
@@ -0,0 +1,272 @@
+package sequencer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+	"gitlab.com/gomidi/midi/v2/smf"
+
+	"go-sequence/midi"
+)
+
+// Clip export - renders one pattern (or a whole scene row) offline to a standard MIDI
+// file in the project's exports folder, with tempo, meter, track names and each
+// track's output channel. Rendering works on the pattern data, so playback isn't
+// disturbed. `e`/`E` in the session export the cursor clip/scene; ctrl+e exports the
+// pattern being edited in a device view.
+
+// triggerTicks is how long drum hits sound in an exported file
+const triggerTicks = PPQ / 16
+
+// clipRenderer is a device whose patterns can be rendered to a file
+type clipRenderer interface {
+	clipTicks(pattern int) int64                      // length of one pass
+	renderClip(pattern int, until int64) []midi.Event // passes back to back from tick 0
+	editingPattern() int
+}
+
+// ExportClip writes one track's pattern to a MIDI file
+func (m *Manager) ExportClip(track, pattern int) {
+	dev, ok := m.GetDevice(track).(clipRenderer)
+	if !ok || !m.hasContent(track, pattern) {
+		m.announce("track %d pattern %d is empty - nothing to export", track+1, pattern+1)
+		return
+	}
+	length := dev.clipTicks(pattern)
+	file := smf.New()
+	file.TimeFormat = smf.MetricTicks(PPQ)
+	file.Add(clipTrack(track, dev.renderClip(pattern, length), length, clipTimeSig(dev, pattern)))
+
+	name := fmt.Sprintf("T%d-pattern%d", track+1, pattern+1)
+	if label := S.Tracks[track].Clips[pattern].Name; label != "" {
+		name += "-" + label
+	}
+	m.writeExport(file, name)
+}
+
+// ExportScene writes every track's clip in a scene row to one multi-track MIDI file,
+// each looped to the length (and written in the meter) of the longest
+func (m *Manager) ExportScene(row int) {
+	var length int64
+	var tracks []int
+	sig := &TimeSigs[0]
+	for i := range m.devices {
+		if dev, ok := m.GetDevice(i).(clipRenderer); ok && m.hasContent(i, row) {
+			tracks = append(tracks, i)
+			if ticks := dev.clipTicks(row); ticks > length {
+				// The longest clip sets the scene's length and meter
+				length = ticks
+				sig = clipTimeSig(dev, row)
+			}
+		}
+	}
+	if len(tracks) == 0 {
+		m.announce("scene %d is empty - nothing to export", row+1)
+		return
+	}
+
+	file := smf.NewSMF1()
+	file.TimeFormat = smf.MetricTicks(PPQ)
+	var conductor smf.Track
+	conductor.Add(0, smf.MetaTrackSequenceName(fmt.Sprintf("scene %d", row+1)))
+	conductor.Add(0, smf.MetaTempo(float64(S.Tempo)))
+	conductor.Add(0, smf.MetaMeter(uint8(sig.Beats), uint8(sig.Unit)))
+	conductor.Close(uint32(length))
+	file.Add(conductor)
+	for _, i := range tracks {
+		file.Add(clipTrack(i, m.GetDevice(i).(clipRenderer).renderClip(row, length), length, nil))
+	}
+
+	name := fmt.Sprintf("scene%d", row+1)
+	if label := rowName(row); label != "" {
+		name += "-" + label
+	}
+	m.writeExport(file, name)
+}
+
+// ExportFocused exports the pattern being edited on the focused track
+func (m *Manager) ExportFocused() {
+	for i, dev := range m.devices {
+		if dev != nil && dev == m.focused {
+			if cr, ok := dev.(clipRenderer); ok {
+				m.ExportClip(i, cr.editingPattern())
+				return
+			}
+		}
+	}
+	m.announce("nothing to export here (focus a track, or use e/E in the session)")
+}
+
+// hasContent reports whether a track's pattern has anything in it
+func (m *Manager) hasContent(track, pattern int) bool {
+	dev := m.GetDevice(track)
+	if dev == nil {
+		return false
+	}
+	mask := dev.ContentMask()
+	return pattern >= 0 && pattern < len(mask) && mask[pattern]
+}
+
+// writeExport saves a file to <project>/exports/<name>.mid and announces where
+func (m *Manager) writeExport(file *smf.SMF, name string) {
+	projectName := S.ProjectName
+	if projectName == "" {
+		projectName = "untitled"
+	}
+	dir, err := ProjectDir(projectName)
+	if err == nil {
+		dir = filepath.Join(dir, "exports")
+		err = os.MkdirAll(dir, 0755)
+	}
+	if err != nil {
+		m.announce("export failed: %v", err)
+		return
+	}
+	path := filepath.Join(dir, sanitizeFilename(name)+".mid")
+	if err := file.WriteFile(path); err != nil {
+		m.announce("export failed: %v", err)
+		return
+	}
+	m.announce("exported %s", path)
+}
+
+// clipTimeSig returns the time signature a device pattern is written in (4/4 for
+// devices without one)
+func clipTimeSig(dev clipRenderer, pattern int) *TimeSig {
+	idx := 0
+	switch d := dev.(type) {
+	case *DrumDevice:
+		idx = d.state.Patterns[pattern].TimeSig
+	case *PianoRollDevice:
+		idx = d.state.Patterns[pattern].TimeSig
+	}
+	if idx < 0 || idx >= len(TimeSigs) {
+		idx = 0
+	}
+	return &TimeSigs[idx]
+}
+
+// clipTrack turns rendered events into a file track the way dispatch would send them:
+// drum slots through the kit, track transpose applied, on the track's channel. A
+// time signature adds tempo and meter (scene files keep those on their own track).
+func clipTrack(track int, events []midi.Event, length int64, sig *TimeSig) smf.Track {
+	ts := S.Tracks[track]
+	type timed struct {
+		tick int64
+		msg  gomidi.Message
+	}
+	var msgs []timed
+	for _, evt := range events {
+		ch := ts.Channel - 1
+		if evt.OutChannel > 0 {
+			ch = evt.OutChannel - 1
+		}
+		switch evt.Type {
+		case midi.PitchBend:
+			bend := evt.BendValue
+			if evt.PerNote {
+				bend = bendValue(bend, GetProfile(ts.Profile).BendRange)
+			}
+			msgs = append(msgs, timed{evt.Tick, gomidi.Pitchbend(ch, bend)})
+			continue
+		case midi.Pressure:
+			msgs = append(msgs, timed{evt.Tick, gomidi.AfterTouch(ch, evt.Velocity)})
+			continue
+		case midi.CC:
+			msgs = append(msgs, timed{evt.Tick, gomidi.ControlChange(ch, evt.Note, evt.Velocity)})
+			continue
+		}
+
+		note := int(evt.Note)
+		if ts.Type == DeviceTypeDrum && note < 16 {
+			note = int(GetKit(ts.Kit).Notes[note])
+		}
+		note += ts.Transpose
+		if note < 0 || note > 127 {
+			continue
+		}
+		switch evt.Type {
+		case midi.NoteOn:
+			msgs = append(msgs, timed{evt.Tick, gomidi.NoteOn(ch, uint8(note), evt.Velocity)})
+		case midi.NoteOff:
+			msgs = append(msgs, timed{evt.Tick, gomidi.NoteOff(ch, uint8(note))})
+		case midi.Trigger:
+			msgs = append(msgs, timed{evt.Tick, gomidi.NoteOn(ch, uint8(note), evt.Velocity)})
+			msgs = append(msgs, timed{min(evt.Tick+triggerTicks, length), gomidi.NoteOff(ch, uint8(note))})
+		}
+	}
+	sort.SliceStable(msgs, func(i, j int) bool { return msgs[i].tick < msgs[j].tick })
+
+	name := ts.Name
+	if name == "" {
+		name = fmt.Sprintf("Track %d", track+1)
+	}
+	var tr smf.Track
+	tr.Add(0, smf.MetaTrackSequenceName(name))
+	if sig != nil {
+		tr.Add(0, smf.MetaTempo(float64(S.Tempo)))
+		tr.Add(0, smf.MetaMeter(uint8(sig.Beats), uint8(sig.Unit)))
+	}
+	var last int64
+	for _, m := range msgs {
+		tr.Add(uint32(m.tick-last), m.msg)
+		last = m.tick
+	}
+	tr.Close(uint32(length - last))
+	return tr
+}
+
+// renderPasses lays passes of a pattern back to back up to until, cutting the last one
+func renderPasses(until, passTicks int64, generate func(startTick int64) []midi.Event) []midi.Event {
+	var events []midi.Event
+	for start := int64(0); passTicks > 0 && start < until; start += passTicks {
+		events = append(events, generate(start)...)
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Tick < events[j].Tick })
+	return cutEvents(events, until)
+}
+
+// --- Drum ---
+
+func (d *DrumDevice) clipTicks(pattern int) int64 { return d.patternLengthTicks(pattern) }
+func (d *DrumDevice) editingPattern() int         { return d.state.EditingPatternIdx }
+
+func (d *DrumDevice) renderClip(pattern int, until int64) []midi.Event {
+	return renderPasses(until, d.patternLengthTicks(pattern), func(start int64) []midi.Event {
+		return d.GeneratePattern(pattern, start)
+	})
+}
+
+// --- Piano roll ---
+
+func (p *PianoRollDevice) clipTicks(pattern int) int64 { return p.patternLengthTicks(pattern) }
+func (p *PianoRollDevice) editingPattern() int         { return p.state.Editing }
+
+func (p *PianoRollDevice) renderClip(pattern int, until int64) []midi.Event {
+	return renderPasses(until, p.patternLengthTicks(pattern), func(start int64) []midi.Event {
+		return p.GeneratePattern(pattern, start)
+	})
+}
+
+// --- Metropolix ---
+
+func (d *MetropolixDevice) clipTicks(pattern int) int64 { return d.fauxPatternTicks(pattern) }
+func (d *MetropolixDevice) editingPattern() int         { return d.state.Editing }
+
+// renderClip runs the pattern on a copy of the state, from a fresh start (playing that
+// pattern from its first stage, accumulators cleared, first cycle), so the live
+// device's position is left alone
+func (d *MetropolixDevice) renderClip(pattern int, until int64) []midi.Event {
+	state := *d.state
+	state.Pattern = pattern
+	state.Next = -1
+	state.ResetPlayback()
+	state.ResetAccumulators()
+	r := NewMetropolixDevice(&state)
+	return renderPasses(until, r.fauxPatternTicks(pattern), func(start int64) []midi.Event {
+		return r.GeneratePattern(pattern, start)
+	})
+}
@@ -0,0 +1,50 @@
+package sequencer
+
+import (
+	"reflect"
+	"testing"
+
+	"go-sequence/midi"
+)
+
+func TestMetropolixRenderClip(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name    string
+		pattern int
+	}{
+		{"playing pattern", 0},
+		{"other pattern", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewMetropolixState()
+			st.Patterns[0].RootNote = 48
+			st.Patterns[3].RootNote = 67
+			fresh := *st
+			fresh.Pattern = tt.pattern
+			want := NewMetropolixDevice(&fresh).renderClip(tt.pattern, 4*PPQ)
+
+			// The live device is mid-pattern on pattern 0
+			st.Stage, st.Cycle, st.Direction = 5, 3, -1
+			st.Accum[2], st.AccumCount[2] = 4, 2
+			d := NewMetropolixDevice(st)
+			got := d.renderClip(tt.pattern, 4*PPQ)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("render depends on the live position:\n got %v\nwant %v", got, want)
+			}
+			if st.Pattern != 0 || st.Stage != 5 || st.Cycle != 3 {
+				t.Error("rendering moved the live device")
+			}
+			for _, e := range got {
+				if e.Type == midi.NoteOn && e.Note < st.Patterns[tt.pattern].RootNote {
+					t.Errorf("note %d is below pattern %d's root", e.Note, tt.pattern+1)
+					break
+				}
+			}
+		})
+	}
+}
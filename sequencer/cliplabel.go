@@ -0,0 +1,180 @@
+package sequencer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"go-sequence/widgets"
+)
+
+// Clip labels - each track's pattern slots can carry a name and a color. The session
+// shows a row's name in place of "Pat 7" and lights colored clips in their color on
+// the Launchpad (`n` names the cursor clip, `N` cycles its color). Only labeled slots
+// are stored, so saves don't carry 128 empty labels per track.
+
+// ClipLabel names and colors one pattern slot on a track (zero value = unnamed, device color)
+type ClipLabel struct {
+	Name  string `json:"name,omitempty"`
+	Color int    `json:"color,omitempty"` // index into clipColors (0 = default)
+}
+
+// ClipLabels holds a track's labeled pattern slots by pattern index
+type ClipLabels map[int]ClipLabel
+
+// set labels a pattern slot, dropping the entry when the label is empty
+func (c *ClipLabels) set(pattern int, label ClipLabel) {
+	if label == (ClipLabel{}) {
+		delete(*c, pattern)
+		return
+	}
+	if *c == nil {
+		*c = ClipLabels{}
+	}
+	(*c)[pattern] = label
+}
+
+// shift moves labels along with their patterns, filling the opened slot on insert
+func (c *ClipLabels) shift(rs rowShift, fill ClipLabel) {
+	var shifted ClipLabels
+	for p, label := range *c {
+		if to, ok := rs.move(p); ok {
+			shifted.set(to, label)
+		}
+	}
+	if rs.insert {
+		shifted.set(rs.row, fill)
+	}
+	*c = shifted
+}
+
+// UnmarshalJSON reads labels by pattern index, or the one-label-per-slot list older
+// saves wrote
+func (c *ClipLabels) UnmarshalJSON(data []byte) error {
+	var labels map[int]ClipLabel
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var list []ClipLabel
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		labels = make(map[int]ClipLabel, len(list))
+		for p, label := range list {
+			labels[p] = label
+		}
+	} else if err := json.Unmarshal(data, &labels); err != nil {
+		return err
+	}
+	*c = nil
+	for p, label := range labels {
+		if p >= 0 && p < NumPatterns {
+			c.set(p, label)
+		}
+	}
+	return nil
+}
+
+// clipColors is the clip palette (index 0 keeps the session's default purple)
+var clipColors = []struct {
+	Name string
+	RGB  [3]uint8
+}{
+	{"default", [3]uint8{140, 26, 242}},
+	{"red", [3]uint8{255, 30, 30}},
+	{"orange", [3]uint8{255, 110, 0}},
+	{"yellow", [3]uint8{255, 220, 0}},
+	{"green", [3]uint8{40, 220, 40}},
+	{"cyan", [3]uint8{0, 200, 200}},
+	{"blue", [3]uint8{30, 80, 255}},
+	{"pink", [3]uint8{255, 60, 160}},
+	{"white", [3]uint8{200, 200, 200}},
+}
+
+// maxClipName keeps names short enough for the session's row labels
+const maxClipName = 12
+
+// clipColor returns a clip's LED color, or fallback when it has none
+func clipColor(track, pattern int, fallback [3]uint8) [3]uint8 {
+	c := S.Tracks[track].Clips[pattern].Color
+	if c <= 0 || c >= len(clipColors) {
+		return fallback
+	}
+	return clipColors[c].RGB
+}
+
+// rowName returns the first clip name in a pattern row, scanning tracks left to right
+func rowName(pattern int) string {
+	for _, ts := range S.Tracks {
+		if name := ts.Clips[pattern].Name; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// cycleClipColor steps the cursor clip through the palette
+func (s *SessionDevice) cycleClipColor() {
+	ts := S.Tracks[s.cursorCol]
+	label := ts.Clips[s.cursorRow]
+	label.Color = (label.Color + 1) % len(clipColors)
+	ts.Clips.set(s.cursorRow, label)
+	s.manager.announce("T%d pattern %d color %s", s.cursorCol+1, s.cursorRow+1, clipColors[label.Color].Name)
+}
+
+// --- Clip naming ---
+
+// startNaming opens text entry for the cursor clip's name
+func (s *SessionDevice) startNaming() {
+	s.nameMode = true
+	s.nameBuffer = S.Tracks[s.cursorCol].Clips[s.cursorRow].Name
+}
+
+// handleNameKey processes keys while naming a clip (empty name clears it)
+func (s *SessionDevice) handleNameKey(key string) {
+	switch key {
+	case "enter":
+		ts := S.Tracks[s.cursorCol]
+		label := ts.Clips[s.cursorRow]
+		label.Name = s.nameBuffer
+		ts.Clips.set(s.cursorRow, label)
+		s.nameMode = false
+		s.nameBuffer = ""
+	case "esc":
+		s.nameMode = false
+		s.nameBuffer = ""
+	case "backspace":
+		if len(s.nameBuffer) > 0 {
+			s.nameBuffer = s.nameBuffer[:len(s.nameBuffer)-1]
+		}
+	case " ":
+		if len(s.nameBuffer) < maxClipName {
+			s.nameBuffer += " "
+		}
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 && len(s.nameBuffer) < maxClipName {
+			s.nameBuffer += key
+		}
+	}
+}
+
+// IsInputMode returns true while naming a clip, confirming a scene delete or using the
+// snapshot slots
+func (s *SessionDevice) IsInputMode() bool {
+	return s.nameMode || s.confirmDelete || s.snapMode
+}
+
+// clipLabelLine describes the cursor clip's name and color for the view
+func (s *SessionDevice) clipLabelLine() string {
+	label := S.Tracks[s.cursorCol].Clips[s.cursorRow]
+	if label.Name == "" && label.Color == 0 {
+		return ""
+	}
+	out := fmt.Sprintf("Clip T%d pattern %d:", s.cursorCol+1, s.cursorRow+1)
+	if label.Name != "" {
+		out += fmt.Sprintf(" %q", label.Name)
+	}
+	if label.Color > 0 && label.Color < len(clipColors) {
+		out += fmt.Sprintf(" %s %s", widgets.RenderPad(clipColors[label.Color].RGB), clipColors[label.Color].Name)
+	}
+	return out + "\n"
+}
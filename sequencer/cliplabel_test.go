@@ -0,0 +1,69 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClipLabelsJSON(t *testing.T) {
+	legacy := make([]ClipLabel, NumPatterns)
+	legacy[3] = ClipLabel{Name: "verse"}
+	legacy[70] = ClipLabel{Color: 4}
+	legacyJSON, _ := json.Marshal(legacy)
+
+	tests := []struct {
+		name string
+		data string
+		want ClipLabels
+	}{
+		{"labels by pattern", `{"3":{"name":"verse"},"70":{"color":4}}`, ClipLabels{3: {Name: "verse"}, 70: {Color: 4}}},
+		{"older list", string(legacyJSON), ClipLabels{3: {Name: "verse"}, 70: {Color: 4}}},
+		{"empty labels dropped", `{"3":{},"5":{"name":"x"}}`, ClipLabels{5: {Name: "x"}}},
+		{"out of range dropped", `{"-1":{"name":"a"},"128":{"name":"b"}}`, nil},
+		{"empty list", `[]`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ClipLabels
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	data, _ := json.Marshal(&TrackState{})
+	if strings.Contains(string(data), `"clips"`) {
+		t.Errorf("a track without labels writes them: %s", data)
+	}
+}
+
+func TestClipLabelsShift(t *testing.T) {
+	base := func() ClipLabels {
+		return ClipLabels{0: {Name: "a"}, 4: {Name: "b"}, NumPatterns - 1: {Name: "last"}}
+	}
+	tests := []struct {
+		name string
+		rs   rowShift
+		fill ClipLabel
+		want ClipLabels
+	}{
+		{"insert", rowShift{row: 2, insert: true}, ClipLabel{}, ClipLabels{0: {Name: "a"}, 5: {Name: "b"}}},
+		{"insert with fill", rowShift{row: 1, insert: true}, ClipLabel{Name: "a"}, ClipLabels{0: {Name: "a"}, 1: {Name: "a"}, 5: {Name: "b"}}},
+		{"delete", rowShift{row: 0}, ClipLabel{}, ClipLabels{3: {Name: "b"}, NumPatterns - 2: {Name: "last"}}},
+		{"delete labeled row", rowShift{row: 4}, ClipLabel{}, ClipLabels{0: {Name: "a"}, NumPatterns - 2: {Name: "last"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := base()
+			got.shift(tt.rs, tt.fill)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package sequencer
+
+import (
+	"go-sequence/netsync"
+)
+
+// Group launch - several clips across tracks launched as one action switch on the
+// same tick: the latest of the tracks' own next boundaries. Tracks with shorter
+// patterns keep looping until then and switch there, even mid-pattern. Select clips
+// with `f` (or hold a scene pad and tap clips on the Launchpad), then launch them
+// with `F` (or by letting go of the scene pad).
+
+// boundaryDevice reports where a pattern queued after a tick would start playing, and
+// can switch to a pattern at any tick (cutting the playing one short)
+type boundaryDevice interface {
+	nextBoundary(atTick int64) int64
+	switchAt(p int, tick int64)
+	barGrid() (start, barTicks int64) // where the playing pattern started and its bar length
+}
+
+// LaunchClips queues one pattern per track (-1 = leave the track alone) so they all
+// switch together
+func (m *Manager) LaunchClips(patterns [8]int) {
+	at := int64(0)
+	if S.Playing {
+		for i, p := range patterns {
+			dev, ok := m.GetDevice(i).(boundaryDevice)
+			if !ok || p < 0 {
+				continue
+			}
+			if line, ok := m.launchLine(i); ok {
+				at = max(at, line)
+			} else {
+				at = max(at, dev.nextBoundary(S.Tick))
+			}
+		}
+	}
+
+	queued := 0
+	for i, p := range patterns {
+		if p < 0 || m.GetDevice(i) == nil {
+			continue
+		}
+		if at > 0 {
+			// Every track switches on the common boundary, even mid-pattern
+			m.switchPatternAt(i, p, at)
+		} else {
+			m.queuePatternAt(i, p, S.Tick)
+		}
+		m.sendSync(netsync.Message{Type: netsync.MsgQueue, Track: i, Pattern: p, Tick: at})
+		queued++
+	}
+	m.announce("%d clips queued together", queued)
+}
+
+// switchPatternAt switches a track to a pattern at exactly tick (falling back to its
+// next boundary when the device can't switch or the tick has passed)
+func (m *Manager) switchPatternAt(trackIdx, patternIdx int, tick int64) {
+	bd, ok := m.GetDevice(trackIdx).(boundaryDevice)
+	if !ok || !S.Playing || tick < S.Tick {
+		m.queuePatternAt(trackIdx, patternIdx, min(tick, S.Tick))
+		return
+	}
+	bd.switchAt(patternIdx, tick)
+	m.scheduleTransport(trackIdx, tick, true)
+	m.resumeClip(trackIdx, tick)
+	m.announce("track %d pattern %d queued", trackIdx+1, patternIdx+1)
+}
+
+// --- Session selection ---
+
+// clearSelection empties the group launch selection
+func (s *SessionDevice) clearSelection() {
+	for i := range s.selected {
+		s.selected[i] = -1
+	}
+}
+
+// toggleSelected adds a clip to the selection (replacing the track's other pick) or
+// takes it out
+func (s *SessionDevice) toggleSelected(track, pattern int) {
+	if s.selected[track] == pattern {
+		s.selected[track] = -1
+		return
+	}
+	s.selected[track] = pattern
+}
+
+// hasSelection reports whether any clip is selected
+func (s *SessionDevice) hasSelection() bool {
+	for _, p := range s.selected {
+		if p >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// launchSelection launches the selected clips together and clears the selection
+func (s *SessionDevice) launchSelection() {
+	if !s.hasSelection() {
+		s.manager.announce("no clips selected (f selects)")
+		return
+	}
+	s.manager.LaunchClips(s.selected)
+	s.clearSelection()
+}
+
+// pressScenePad starts a scene pad hold - clip pads pressed while it's held are selected
+func (s *SessionDevice) pressScenePad(patternRow int) {
+	s.sceneHeld = patternRow
+	s.scenePicked = false
+}
+
+// releaseScenePad launches what the hold picked, or the scene if it picked nothing
+func (s *SessionDevice) releaseScenePad(patternRow int) {
+	if s.sceneHeld != patternRow {
+		return
+	}
+	s.sceneHeld = -1
+	if s.scenePicked {
+		s.launchSelection()
+		return
+	}
+	s.manager.LaunchScene(patternRow)
+}
+
+// --- Device boundaries ---
+
+func (d *DrumDevice) nextBoundary(atTick int64) int64 {
+	d.extendSchedule(atTick)
+	tick := d.schedule.StartTick
+	for i := range d.schedule.Patterns {
+		tick += d.slotTicks(i)
+		if tick > atTick {
+			break
+		}
+	}
+	return tick
+}
+
+func (p *PianoRollDevice) nextBoundary(atTick int64) int64 {
+	p.queueMu.RLock()
+	patternStart := p.patternStartTick
+	p.queueMu.RUnlock()
+	return boundaryAfter(atTick, patternStart, p.patternLengthTicks(p.state.Pattern))
+}
+
+func (d *MetropolixDevice) nextBoundary(atTick int64) int64 {
+	d.queueMu.RLock()
+	patternStart := d.patternStartTick
+	d.queueMu.RUnlock()
+	return boundaryAfter(atTick, patternStart, d.fauxPatternTicks(d.state.Pattern))
+}
+
+func (d *DrumDevice) barGrid() (int64, int64) {
+	pat := &d.state.Patterns[d.state.PlayingPatternIdx]
+	return d.schedule.StartTick, int64(pat.Sig().BarSteps()) * (PPQ / 4)
+}
+
+func (p *PianoRollDevice) barGrid() (int64, int64) {
+	p.queueMu.RLock()
+	defer p.queueMu.RUnlock()
+	return p.patternStartTick, int64(p.state.Patterns[p.state.Pattern].Sig().BarBeats() * PPQ)
+}
+
+func (d *MetropolixDevice) barGrid() (int64, int64) {
+	d.queueMu.RLock()
+	defer d.queueMu.RUnlock()
+	return d.patternStartTick, 4 * PPQ // Metropolix patterns are always 4/4
+}
+
+// lineAfter returns the first grid line after atTick for a grid that starts at start
+// (which can be ahead of atTick when the look-ahead already started a pattern)
+func lineAfter(atTick, start, grid int64) int64 {
+	n := (atTick - start) / grid
+	if atTick < start && (atTick-start)%grid != 0 {
+		n-- // round toward the past
+	}
+	return start + (n+1)*grid
+}
+
+// boundaryAfter returns the first pattern boundary after atTick for a pattern that
+// started at patternStart and loops every patternTicks (patternStart itself when the
+// look-ahead has already started the pattern ahead of atTick)
+func boundaryAfter(atTick, patternStart, patternTicks int64) int64 {
+	if atTick < patternStart {
+		return patternStart
+	}
+	ticksIntoPattern := (atTick - patternStart) % patternTicks
+	return atTick + patternTicks - ticksIntoPattern
+}
@@ -0,0 +1,101 @@
+package sequencer
+
+import (
+	"slices"
+	"testing"
+
+	"go-sequence/midi"
+)
+
+func TestBoundaryAfter(t *testing.T) {
+	bar := int64(4 * PPQ)
+	tests := []struct {
+		name                 string
+		atTick, start, ticks int64
+		want                 int64
+	}{
+		{"from the start", 0, 0, bar, bar},
+		{"mid pattern", bar / 2, 0, bar, bar},
+		{"on a boundary", bar - 1, 0, bar, bar},
+		{"later pass", 2*bar + 5, 0, bar, 3 * bar},
+		{"offset start", bar + 10, bar / 2, bar, bar + bar/2},
+		{"pattern started ahead", 100, bar, bar, bar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := boundaryAfter(tt.atTick, tt.start, tt.ticks); got != tt.want {
+				t.Errorf("boundaryAfter(%d, %d, %d) = %d, want %d", tt.atTick, tt.start, tt.ticks, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDrumSwitchAt(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+
+	step := int64(PPQ / 4)
+	bar := 16 * step
+	tests := []struct {
+		name       string
+		at         int64
+		patterns   []int
+		cuts       []int64
+		triggersAt []int64
+	}{
+		{"mid pattern", 8 * step, []int{0, 1}, []int64{8 * step}, []int64{0, 8 * step}},
+		{"on the boundary", bar, []int{0, 1}, nil, []int64{0, 12 * step, bar}},
+		{"second pass", bar + 4*step, []int{0, 0, 1}, []int64{0, 4 * step}, []int64{0, 12 * step, bar, bar + 4*step}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDrumState()
+			st.Patterns[0].Notes[0].Steps[0] = DrumStepState{Active: true, Velocity: 100}
+			st.Patterns[0].Notes[0].Steps[12] = DrumStepState{Active: true, Velocity: 100}
+			st.Patterns[1].Notes[1].Steps[0] = DrumStepState{Active: true, Velocity: 100}
+			d := NewDrumDevice(st)
+			d.FillUntil(bar / 2)
+			d.switchAt(1, tt.at)
+
+			if !slices.Equal(d.schedule.Patterns, tt.patterns) || !slices.Equal(d.schedule.Cuts, tt.cuts) {
+				t.Errorf("schedule = %v cuts %v, want %v cuts %v", d.schedule.Patterns, d.schedule.Cuts, tt.patterns, tt.cuts)
+			}
+			var ons []int64
+			for _, e := range d.queue {
+				if e.Type == midi.Trigger && e.Tick < tt.at+bar {
+					ons = append(ons, e.Tick)
+				}
+			}
+			if !slices.Equal(ons, tt.triggersAt) {
+				t.Errorf("triggers at %v, want %v", ons, tt.triggersAt)
+			}
+		})
+	}
+}
+
+func TestLineAfter(t *testing.T) {
+	bar := int64(4 * PPQ)
+	waltz := int64(3 * PPQ)
+	tests := []struct {
+		name                string
+		atTick, start, grid int64
+		want                int64
+	}{
+		{"first bar", 10, 0, bar, bar},
+		{"on a line", bar, 0, bar, 2 * bar},
+		{"four bars", 5 * bar, 0, 4 * bar, 8 * bar},
+		{"3/4 from its start", bar + 1, bar, waltz, bar + waltz},
+		{"3/4 four bars", bar + 5*waltz, bar, 4 * waltz, bar + 8*waltz},
+		{"start ahead", 10, bar, bar, bar},
+		{"start a grid ahead", 0, bar, bar, bar},
+		{"start two lines ahead", 1, 3 * waltz, waltz, waltz},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lineAfter(tt.atTick, tt.start, tt.grid); got != tt.want {
+				t.Errorf("lineAfter(%d, %d, %d) = %d, want %d", tt.atTick, tt.start, tt.grid, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package sequencer
+
+import "go-sequence/midi"
+
+// Clip stop - a stopped track goes silent at the next bar. Its device keeps running
+// (the queue drains as usual so it stays in time), but from the stop tick on only the
+// note-offs for notes already sounding get through. Launching a clip on the track
+// brings it back at the next bar.
+
+// clipStop is one track's stop window (guarded by Manager.mu)
+type clipStop struct {
+	stopped bool
+	from    int64 // silent from this tick
+	until   int64 // back on at this tick (-1 = until relaunched)
+}
+
+// silentAt reports whether the window covers a tick
+func (c clipStop) silentAt(tick int64) bool {
+	return c.stopped && tick >= c.from && (c.until < 0 || tick < c.until)
+}
+
+// nextBarTick returns where a stop or relaunch on a device lands: the next bar of its
+// playing pattern, in that pattern's time signature (now when the transport is stopped)
+func nextBarTick(dev Device) int64 {
+	if !S.Playing {
+		return 0
+	}
+	start, barTicks := int64(0), int64(4*PPQ)
+	if bd, ok := dev.(boundaryDevice); ok {
+		start, barTicks = bd.barGrid()
+	}
+	return lineAfter(S.Tick, start, barTicks)
+}
+
+// StopClip silences a track at the next bar
+func (m *Manager) StopClip(trackIdx int) {
+	dev := m.GetDevice(trackIdx)
+	if dev == nil {
+		return
+	}
+	m.mu.Lock()
+	at := nextBarTick(dev)
+	m.clipStops[trackIdx] = clipStop{stopped: true, from: at, until: -1}
+	m.mu.Unlock()
+	if S.Playing {
+		m.scheduleTransport(trackIdx, at, false)
+	}
+	m.announce("track %d stops at the next bar", trackIdx+1)
+}
+
+// StopAllClips silences every track at the next bar
+func (m *Manager) StopAllClips() {
+	m.mu.Lock()
+	for i, dev := range m.devices {
+		if dev != nil {
+			at := nextBarTick(dev)
+			m.clipStops[i] = clipStop{stopped: true, from: at, until: -1}
+			if S.Playing {
+				m.scheduleTransport(i, at, false)
+			}
+		}
+	}
+	m.mu.Unlock()
+	m.announce("all clips stop at the next bar")
+}
+
+// resumeClip ends a track's stop at tick, where a clip launched on it starts
+func (m *Manager) resumeClip(trackIdx int, at int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := &m.clipStops[trackIdx]
+	if !c.stopped {
+		return
+	}
+	if at <= c.from {
+		// Stop hadn't landed yet - cancel it
+		*c = clipStop{}
+		return
+	}
+	c.until = at
+}
+
+// settleClipStops lands pending stops and drops relaunched ones when the transport
+// stops, so the next play starts from tick 0 with the right tracks silent (hold m.mu)
+func (m *Manager) settleClipStops() {
+	for i := range m.clipStops {
+		c := &m.clipStops[i]
+		if c.stopped && c.until < 0 {
+			c.from = 0
+		} else {
+			*c = clipStop{}
+		}
+	}
+}
+
+// ClipStopped reports whether a track is stopped (or stopping) and not yet relaunched
+func (m *Manager) ClipStopped(trackIdx int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	c := m.clipStops[trackIdx]
+	return c.stopped && (c.until < 0 || S.Tick < c.until)
+}
+
+// trackSilent reports whether a track's events at a tick are held back - its clip is
+// stopped, or it's muted or left out of a solo (hold m.mu for reading)
+func (m *Manager) trackSilent(trackIdx int, tick int64) bool {
+	return m.clipStops[trackIdx].silentAt(tick) || !trackAudible(trackIdx)
+}
+
+// silenced reports whether a silent track drops an event, tracking which notes are
+// sounding so their note-offs still go out (hold m.voiceMu)
+func (m *Manager) silenced(trackIdx int, evt *midi.Event, silent bool) bool {
+	sounding := &m.sounding[trackIdx]
+	switch evt.Type {
+	case midi.NoteOn:
+		if silent {
+			return true
+		}
+		sounding[evt.Note] = true
+	case midi.NoteOff:
+		if silent && !sounding[evt.Note] {
+			return true
+		}
+		sounding[evt.Note] = false
+	default:
+		return silent
+	}
+	return false
+}
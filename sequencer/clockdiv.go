@@ -0,0 +1,29 @@
+package sequencer
+
+// Clock division - each pattern sets how long one pulse lasts (1/16, 1/8 or 1/4 note),
+// so slow sequences don't need maxed-out pulse counts. Pulse counts, gate lengths,
+// ratchets and swing all work in the pattern's steps.
+
+// Clock divisions
+const (
+	ClockDiv16 = 0
+	ClockDiv8  = 1
+	ClockDiv4  = 2
+)
+
+var clockDivNames = []string{"1/16", "1/8", "1/4"}
+
+// clockDivPadCol is the first Settings page pad (row 0) for the clock divisions
+const clockDivPadCol = 4
+
+// stepTicks returns the length of one pulse in ticks
+func (pat *MetropolixPatternState) stepTicks() int64 {
+	return int64(PPQ/4) << pat.ClockDiv
+}
+
+// cycleClockDiv steps the editing pattern through the clock divisions
+func (d *MetropolixDevice) cycleClockDiv() {
+	pat := &d.state.Patterns[d.state.Editing]
+	pat.ClockDiv = (pat.ClockDiv + 1) % len(clockDivNames)
+	d.regeneratePatternInQueue(d.state.Editing)
+}
@@ -0,0 +1,394 @@
+package sequencer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-sequence/config"
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// Controller profiles - edits the config's controller entries (`ctrl+k`) instead of
+// config.json by hand: add one per scanned input, pick its type, auto-connect, a
+// keyboard's input channel and a generic grid's mapping file. Every change is saved to
+// the config straight away; rescan (`r`) connects with the new profiles.
+
+// Profile columns
+const (
+	profilePort = iota
+	profileType
+	profileAuto
+	profileChannel
+	profileMapping
+	numProfileCols
+)
+
+var profileColNames = [numProfileCols]string{"Port", "Type", "Auto", "Channel", "Mapping"}
+
+// ControllersDevice is the controller profile editor
+type ControllersDevice struct {
+	manager *Manager
+	cfg     *config.Config
+
+	cursor int // controller entry
+	col    int // profile column
+
+	editing     bool // typing a mapping file name
+	inputBuffer string
+
+	err string
+}
+
+// NewControllersDevice creates the controller profile editor for a config
+func NewControllersDevice(manager *Manager, cfg *config.Config) *ControllersDevice {
+	return &ControllersDevice{manager: manager, cfg: cfg}
+}
+
+// IsInputMode returns true while typing a mapping file name
+func (d *ControllersDevice) IsInputMode() bool {
+	return d.editing
+}
+
+// inputs returns the scanned MIDI inputs (cached by settings on rescan)
+func (d *ControllersDevice) inputs() []string {
+	if s := d.manager.GetSettings(); s != nil {
+		return s.midiInputs
+	}
+	return nil
+}
+
+// selected returns the entry under the cursor (nil when there are none)
+func (d *ControllersDevice) selected() *config.ControllerConfig {
+	if d.cursor < 0 || d.cursor >= len(d.cfg.Controllers) {
+		return nil
+	}
+	return &d.cfg.Controllers[d.cursor]
+}
+
+// save writes the config after a change
+func (d *ControllersDevice) save() {
+	if err := d.cfg.Save(); err != nil {
+		d.err = err.Error()
+		return
+	}
+	d.err = ""
+}
+
+// add adds a profile for the first scanned input that doesn't have one
+func (d *ControllersDevice) add() {
+	for _, port := range d.inputs() {
+		if d.cfg.FindController(port) != nil {
+			continue
+		}
+		d.cfg.AddController(config.ControllerConfig{
+			PortName:    port,
+			Type:        midi.DetectControllerType(port),
+			AutoConnect: true,
+		})
+		d.cursor = len(d.cfg.Controllers) - 1
+		d.save()
+		d.manager.announce("added controller %s", port)
+		return
+	}
+	d.err = "every scanned input has a profile (r rescans)"
+}
+
+// remove deletes the selected profile
+func (d *ControllersDevice) remove() {
+	ctrl := d.selected()
+	if ctrl == nil {
+		return
+	}
+	port := ctrl.PortName
+	d.cfg.RemoveController(d.cursor)
+	d.cursor = clamp(d.cursor, 0, max(0, len(d.cfg.Controllers)-1))
+	d.save()
+	d.manager.announce("removed controller %s", port)
+}
+
+// nudge steps the selected column's value (ports and types wrap)
+func (d *ControllersDevice) nudge(delta int) {
+	ctrl := d.selected()
+	if ctrl == nil {
+		return
+	}
+	switch d.col {
+	case profilePort:
+		inputs := d.inputs()
+		if len(inputs) == 0 {
+			d.err = "no inputs found (r rescans)"
+			return
+		}
+		idx := slices.Index(inputs, ctrl.PortName)
+		if idx < 0 && delta < 0 {
+			idx = 0
+		}
+		ctrl.PortName = inputs[(idx+delta+len(inputs))%len(inputs)]
+	case profileType:
+		n := len(config.ControllerTypes)
+		idx := slices.Index(config.ControllerTypes, ctrl.Type)
+		if idx < 0 && delta < 0 {
+			idx = 0
+		}
+		ctrl.Type = config.ControllerTypes[(idx+delta+n)%n]
+	case profileAuto:
+		ctrl.AutoConnect = !ctrl.AutoConnect
+	case profileChannel:
+		if ctrl.Type != config.ControllerKeyboard {
+			return
+		}
+		ctrl.InputChannel = (ctrl.InputChannel + delta + 17) % 17
+	case profileMapping:
+		return
+	}
+	d.save()
+}
+
+// activate runs enter/space on the selected column
+func (d *ControllersDevice) activate() {
+	ctrl := d.selected()
+	if ctrl == nil {
+		return
+	}
+	if d.col == profileMapping {
+		if ctrl.Type != config.ControllerGenericGrid {
+			return
+		}
+		d.editing = true
+		d.inputBuffer = ctrl.Mapping
+		return
+	}
+	d.nudge(1)
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (d *ControllersDevice) FillUntil(tick int64)                           {}
+func (d *ControllersDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (d *ControllersDevice) PopNextEvent() *midi.Event                      { return nil }
+func (d *ControllersDevice) ClearQueue()                                    {}
+func (d *ControllersDevice) QueuePattern(p int, atTick int64)               {}
+func (d *ControllersDevice) CurrentPattern() int                            { return 0 }
+func (d *ControllersDevice) NextPattern() int                               { return -1 }
+func (d *ControllersDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (d *ControllersDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (d *ControllersDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (d *ControllersDevice) HandleMIDI(event midi.Event)                    {}
+func (d *ControllersDevice) ToggleRecording()                               {}
+func (d *ControllersDevice) TogglePreview()                                 {}
+func (d *ControllersDevice) IsRecording() bool                              { return false }
+func (d *ControllersDevice) IsPreviewing() bool                             { return false }
+func (d *ControllersDevice) HandlePadRelease(row, col int)                  {}
+func (d *ControllersDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+// profileCell formats one column of a profile ("-" where the column doesn't apply)
+func profileCell(ctrl config.ControllerConfig, col int) string {
+	switch col {
+	case profilePort:
+		return ctrl.PortName
+	case profileType:
+		return string(ctrl.Type)
+	case profileAuto:
+		if ctrl.AutoConnect {
+			return "on"
+		}
+		return "off"
+	case profileChannel:
+		switch {
+		case ctrl.Type != config.ControllerKeyboard:
+			return "-"
+		case ctrl.InputChannel == 0:
+			return "all"
+		}
+		return fmt.Sprintf("%d", ctrl.InputChannel)
+	case profileMapping:
+		switch {
+		case ctrl.Type != config.ControllerGenericGrid:
+			return "-"
+		case ctrl.Mapping == "":
+			return "(none)"
+		}
+		return ctrl.Mapping
+	}
+	return ""
+}
+
+func (d *ControllersDevice) View() string {
+	var out strings.Builder
+	out.WriteString("CONTROLLERS\n\n")
+
+	widths := [numProfileCols]int{28, 14, 5, 8, 20}
+	for col, name := range profileColNames {
+		out.WriteString(fmt.Sprintf("  %-*s", widths[col], name))
+	}
+	out.WriteString("\n─────────────────────────────────────────────────────────────────────────────────\n")
+	inputs := d.inputs()
+	for i, ctrl := range d.cfg.Controllers {
+		for col := range profileColNames {
+			cell := truncateName(profileCell(ctrl, col), widths[col])
+			if col == profilePort && len(inputs) > 0 && !slices.Contains(inputs, ctrl.PortName) {
+				cell = truncateName(cell, widths[col]-2) + " ?"
+			}
+			prefix := "  "
+			if i == d.cursor && col == d.col {
+				prefix = "> "
+			}
+			out.WriteString(fmt.Sprintf("%s%-*s", prefix, widths[col], cell))
+		}
+		out.WriteString("\n")
+	}
+	if len(d.cfg.Controllers) == 0 {
+		out.WriteString("  (no profiles - a to add one for a scanned input)\n")
+	}
+
+	if d.editing {
+		out.WriteString(fmt.Sprintf("\nMapping file: %s_\n", d.inputBuffer))
+		out.WriteString("(relative to the config folder)  [enter] set  [esc] cancel\n")
+	}
+	if d.err != "" {
+		out.WriteString(fmt.Sprintf("\nError: %s\n", d.err))
+	}
+	if len(inputs) > 0 {
+		out.WriteString("\n? = port not connected now\n")
+	}
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "j / k", Desc: "select controller"},
+			{Key: "h / l", Desc: "select column"},
+			{Key: "[ / ]", Desc: "previous/next value"},
+			{Key: "enter", Desc: "next value / edit mapping file"},
+			{Key: "a", Desc: "add a scanned input"},
+			{Key: "X", Desc: "remove controller"},
+			{Key: "r", Desc: "rescan and connect"},
+			{Key: "T", Desc: "test the connected controller's LEDs"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(d.HelpLayout()))
+	return out.String()
+}
+
+// Controller profile pad colors
+var (
+	profileAutoColor   = [3]uint8{0, 200, 0}
+	profileManualColor = [3]uint8{0, 60, 120}
+	profileDimColor    = [3]uint8{30, 30, 30}
+)
+
+func (d *ControllersDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	layout := d.HelpLayout()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: row, Col: col, Color: layout.Grid[row][col].Color, Channel: midi.ChannelStatic})
+		}
+		leds = append(leds, LEDState{Row: row, Col: 8, Color: layout.RightCol[row].Color, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+func (d *ControllersDevice) HandleKey(key string) {
+	if d.editing {
+		d.handleMappingKey(key)
+		return
+	}
+	switch key {
+	case "j", "down":
+		if d.cursor < len(d.cfg.Controllers)-1 {
+			d.cursor++
+		}
+	case "k", "up":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "h", "left":
+		if d.col > 0 {
+			d.col--
+		}
+	case "l", "right":
+		if d.col < numProfileCols-1 {
+			d.col++
+		}
+	case "[":
+		d.nudge(-1)
+	case "]":
+		d.nudge(1)
+	case "enter", " ":
+		d.activate()
+	case "a":
+		d.add()
+	case "X":
+		d.remove()
+	case "T":
+		d.manager.TestLEDs()
+	}
+}
+
+// handleMappingKey edits the selected generic grid's mapping file name
+func (d *ControllersDevice) handleMappingKey(key string) {
+	switch key {
+	case "enter":
+		if ctrl := d.selected(); ctrl != nil {
+			ctrl.Mapping = strings.TrimSpace(d.inputBuffer)
+			d.save()
+		}
+		d.editing = false
+	case "esc":
+		d.editing = false
+	case "backspace":
+		if len(d.inputBuffer) > 0 {
+			d.inputBuffer = d.inputBuffer[:len(d.inputBuffer)-1]
+		}
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			d.inputBuffer += key
+		}
+	}
+}
+
+// HandlePad toggles a controller's auto-connect (one pad per profile, top-left first)
+func (d *ControllersDevice) HandlePad(row, col int, velocity uint8) {
+	if d.editing || row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	if idx := (7-row)*8 + col; idx < len(d.cfg.Controllers) {
+		d.cursor = idx
+		d.cfg.Controllers[idx].AutoConnect = !d.cfg.Controllers[idx].AutoConnect
+		d.save()
+	}
+}
+
+// HelpLayout shows one pad per controller profile, lit by auto-connect
+func (d *ControllersDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
+
+	for i := 0; i < 8; i++ {
+		l.TopRow[i] = widgets.Pad{Color: profileDimColor}
+		rightCol[i] = widgets.Pad{Color: profileDimColor}
+	}
+	for idx := 0; idx < 64; idx++ {
+		pad := widgets.Pad{Color: profileDimColor}
+		if idx < len(d.cfg.Controllers) {
+			ctrl := d.cfg.Controllers[idx]
+			pad = widgets.Pad{Color: profileManualColor, Tooltip: "auto-connect " + ctrl.PortName + " (off)"}
+			if ctrl.AutoConnect {
+				pad = widgets.Pad{Color: profileAutoColor, Tooltip: "auto-connect " + ctrl.PortName + " (on)"}
+			}
+		}
+		l.Grid[7-idx/8][idx%8] = pad
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: profileAutoColor, Name: "Auto", Desc: "connects on start and rescan - tap to toggle"},
+		{Color: profileManualColor, Name: "Manual", Desc: "saved, not auto-connected"},
+	}
+	return l
+}
@@ -0,0 +1,34 @@
+package sequencer
+
+// Cycle conditions - besides its probability, a stage can play only every Nth pass
+// through the pattern (on the 2nd, 4th, ... pass for N = 2), so long sequences can
+// evolve. Passes are counted from when the pattern started; set per stage with `C`
+// or on the Probability page's second sub-page.
+
+// Probability page sub-pages (navigated with the top row up/down arrows)
+const (
+	ProbSubPercent = 0
+	ProbSubEvery   = 1
+)
+
+// maxEvery is the longest cycle condition (one pad row per value)
+const maxEvery = 8
+
+// every returns how many passes the stage waits between plays (0 = every pass)
+func (st *MetropolixStageState) every() int {
+	if st.Every == 0 {
+		return 1
+	}
+	return st.Every
+}
+
+// playsOnCycle reports whether the stage's cycle condition lets it play on a pass
+// (cycle counts from 0)
+func (st *MetropolixStageState) playsOnCycle(cycle int) bool {
+	return (cycle+1)%st.every() == 0
+}
+
+// cycleEvery steps a stage's cycle condition 1, 2, ... maxEvery and back to 1
+func (st *MetropolixStageState) cycleEvery() {
+	st.Every = st.every()%maxEvery + 1
+}
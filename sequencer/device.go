@@ -2,6 +2,7 @@ package sequencer
 
 import (
 	"go-sequence/midi"
+	"go-sequence/widgets"
 )
 
 const NumPatterns = 128
@@ -37,6 +38,8 @@ type Device interface {
 	CurrentPattern() int              // Currently playing pattern
 	NextPattern() int                 // Queued pattern (-1 if none)
 	ContentMask() []bool              // Which patterns have content
+	Density() []float64               // Notes per bar for each pattern (0 = empty)
+	PatternBars() []float64           // Length of each pattern in bars
 
 	// Live input (bypasses queue - immediate echo + record)
 	HandleMIDI(event midi.Event)
@@ -50,8 +53,19 @@ type Device interface {
 	// UI - device returns render data, Manager handles output
 	View() string
 	RenderLEDs() []LEDState
+	HelpLayout() widgets.LaunchpadLayout // pad colors, tooltips and legend for the help view
 	HandleKey(key string)
-	HandlePad(row, col int)
+	HandlePad(row, col int, velocity uint8) // velocity 1-127 (127 for buttons without velocity)
+	HandlePadRelease(row, col int) // pad let go (for hold gestures)
+	HandlePadPressure(row, col int, pressure uint8) // aftertouch on a held pad (0-127)
+}
+
+// ClipInfo describes one pattern slot of a track, for the clip launcher
+type ClipInfo struct {
+	HasContent bool
+	Bars       float64 // pattern length in bars
+	Playing    bool
+	Queued     bool
 }
 
 // LEDState describes the state of a single LED
@@ -0,0 +1,39 @@
+package sequencer
+
+// Device state ownership - a device's pattern data and playback state (the drum,
+// piano roll and Metropolix states in S.Tracks, and the device structs around them)
+// belong to whoever holds the manager's device lock. The UI edits them from keys and
+// pads and reads them for its views, the MIDI, CC and sync inputs edit them, the queue
+// loop generates from them (and advances them) on every fill, and the LED loop
+// renders them. Each of those takes the lock once, where it enters the sequencer -
+// the TUI around Update, View and each pad (LockDevices), the manager's loops around
+// each unit of work (withDevices) - and everything below runs as the lock's owner, so
+// nothing under it takes it again. Lock order is devMu, then mu, then a device's
+// queueMu. The output loop never waits for it - events other tracks resample go to the
+// queue loop, which hands them over under the lock. What the output loop does read -
+// the device slots, a track's type, S itself - is only replaced with mu held as well
+// (SetDevice, ChangeDeviceType, OpenProject).
+//
+// Voice state - what the output loop has sounding per track (mono voices, transposed
+// notes, notes let through a mute, MPE channels) - belongs to whoever holds voiceMu.
+// The output loop holds it from transposing an event to recording the note it sent,
+// and Panic and the note flushes (stop, mute, clear) hold it to reset a track's voices
+// and send their note-offs, so a reset can't land halfway through a send. It comes
+// after mu in the lock order, and before activeMu and the senders' lock.
+
+// LockDevices takes the device lock for a caller outside the sequencer (see above)
+func (m *Manager) LockDevices() {
+	m.devMu.Lock()
+}
+
+// UnlockDevices releases the device lock
+func (m *Manager) UnlockDevices() {
+	m.devMu.Unlock()
+}
+
+// withDevices runs fn as the device lock's owner
+func (m *Manager) withDevices(fn func()) {
+	m.devMu.Lock()
+	defer m.devMu.Unlock()
+	fn()
+}
@@ -0,0 +1,193 @@
+package sequencer
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+)
+
+// streamDevice is always due to play - alternating note ons and offs at tick 0
+type streamDevice struct {
+	*EmptyDevice
+	mu   sync.Mutex
+	next midi.Event
+}
+
+func newStreamDevice() *streamDevice {
+	return &streamDevice{EmptyDevice: NewEmptyDevice(1), next: midi.Event{Type: midi.NoteOn, Note: 60, Velocity: 100}}
+}
+
+func (d *streamDevice) PeekNextEvent() *midi.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	evt := d.next
+	return &evt
+}
+
+func (d *streamDevice) PopNextEvent() *midi.Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	evt := d.next
+	if d.next.Type == midi.NoteOn {
+		d.next.Type = midi.NoteOff
+	} else {
+		d.next = midi.Event{Type: midi.NoteOn, Note: 48 + (d.next.Note-47)%24, Velocity: 100}
+	}
+	return &evt
+}
+
+// TestVoiceResetsDuringDispatch resets voices from other goroutines while the output
+// loop sends - run with -race
+func TestVoiceResetsDuringDispatch(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+	S.Tracks[0].Profile = "mono"
+	S.Tracks[1].Profile = "mpe"
+	S.Tracks[2].Transpose = 5
+	S.Playing = true
+	S.T0 = time.Now()
+
+	m := NewManager()
+	m.stopChan = make(chan struct{})
+	var sent sync.Map
+	m.SetSender("test", func(msg gomidi.Message) error {
+		sent.Store(msg.String(), true)
+		return nil
+	})
+	m.SetDefaultPort("test")
+	for i := range 3 {
+		m.SetDevice(i, newStreamDevice())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.midiOutputLoop()
+		close(done)
+	}()
+
+	var wg sync.WaitGroup
+	deadline := time.Now().Add(200 * time.Millisecond)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for time.Now().Before(deadline) {
+			m.Panic()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for time.Now().Before(deadline) {
+			m.mu.Lock()
+			m.flushNotes(0)
+			m.flushNotes(1)
+			m.mu.Unlock()
+		}
+	}()
+	wg.Wait()
+	close(m.stopChan)
+	<-done
+
+	count := 0
+	sent.Range(func(any, any) bool { count++; return true })
+	if count == 0 {
+		t.Fatal("the output loop sent nothing")
+	}
+}
+
+// TestDeviceSwapsDuringDispatch swaps devices and changes device types under the
+// device lock while the output loop sends - run with -race
+func TestDeviceSwapsDuringDispatch(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+	S.Playing = true
+	S.T0 = time.Now()
+
+	m := NewManager()
+	m.stopChan = make(chan struct{})
+	m.SetSender("test", func(gomidi.Message) error { return nil })
+	m.SetDefaultPort("test")
+	for i := range 2 {
+		m.SetDevice(i, newStreamDevice())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.midiOutputLoop()
+		close(done)
+	}()
+
+	types := []DeviceType{DeviceTypeDrum, DeviceTypePiano, DeviceTypeMetropolix, DeviceTypeNone}
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for i := 0; time.Now().Before(deadline); i++ {
+		m.withDevices(func() {
+			m.SetDevice(0, newStreamDevice())
+			m.ChangeDeviceType(1, types[i%len(types)])
+		})
+	}
+	close(m.stopChan)
+	<-done
+}
+
+// recordDevice keeps the events handed to it
+type recordDevice struct {
+	*EmptyDevice
+	events []midi.Event
+}
+
+func (d *recordDevice) HandleMIDI(event midi.Event) { d.events = append(d.events, event) }
+
+// TestResampleOffDispatch checks that sent events reach a recording track through the
+// queue loop's channel, as the source device played them
+func TestResampleOffDispatch(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+	S.Tracks[0].Transpose = 7
+	S.Tracks[1].RecordFrom = 1
+	S.Playing = true
+	S.T0 = time.Now()
+
+	m := NewManager()
+	m.stopChan = make(chan struct{})
+	m.SetSender("test", func(gomidi.Message) error { return nil })
+	m.SetDefaultPort("test")
+	m.SetDevice(0, newStreamDevice())
+	rec := &recordDevice{EmptyDevice: NewEmptyDevice(1)}
+	m.SetDevice(1, rec)
+
+	done := make(chan struct{})
+	go func() {
+		m.midiOutputLoop()
+		close(done)
+	}()
+	var got []resampled
+	for len(got) < 4 {
+		select {
+		case r := <-m.resampleChan:
+			got = append(got, r)
+		case <-time.After(time.Second):
+			t.Fatal("nothing came through to resample")
+		}
+	}
+	close(m.stopChan)
+	<-done
+
+	if len(rec.events) != 0 {
+		t.Error("the output loop handed events to the recording device itself")
+	}
+	for _, r := range got {
+		m.resample(r)
+	}
+	if len(rec.events) != len(got) {
+		t.Fatalf("recording track got %d events, want %d", len(rec.events), len(got))
+	}
+	if first := rec.events[0]; first.Type != midi.NoteOn || first.Note != 60 {
+		t.Errorf("first recorded event %+v, want note on 60 (before the track transpose)", first)
+	}
+}
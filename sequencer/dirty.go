@@ -0,0 +1,42 @@
+package sequencer
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+)
+
+// Unsaved changes - pattern edits mark the state dirty as they happen (they all go
+// through the undo history). Everything else that is saved - tempo, track settings,
+// mutes, macros, mappings - is caught by comparing a fingerprint of it against the one
+// taken at the last save or load, so moving a cursor or launching a clip doesn't count.
+// Quitting with unsaved changes asks first (see tui).
+
+// settingsSum fingerprints the saved state outside the device patterns
+func (s *State) settingsSum() [sha256.Size]byte {
+	shallow := *s
+	shallow.Thumbnail = nil
+	for i, ts := range s.Tracks {
+		if ts == nil {
+			continue
+		}
+		t := *ts
+		t.Drum, t.Piano, t.Metropolix = nil, nil, nil
+		shallow.Tracks[i] = &t
+	}
+	data, _ := json.Marshal(&shallow)
+	return sha256.Sum256(data)
+}
+
+// MarkSaved records the state as matching what is on disk
+func (s *State) MarkSaved() {
+	s.Dirty = false
+	s.savedSum = s.settingsSum()
+}
+
+// Unsaved reports whether the project has changed since the last save or load
+func (s *State) Unsaved() bool {
+	if !s.Dirty && s.settingsSum() != s.savedSum {
+		s.Dirty = true
+	}
+	return s.Dirty
+}
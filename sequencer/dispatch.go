@@ -0,0 +1,132 @@
+package sequencer
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Event dispatch - the output loop keeps each track's next event in a min-heap by
+// send time (latency-compensated tracks go early), so finding the next event to send
+// is a look at the top instead of peeking every device. After a send only that
+// track's new head goes back in. Anything else that changes the queues - a fill, an
+// edit swapping a queue, a restart, a clear - marks the heap stale and it's rebuilt
+// from the devices' heads before the next send.
+
+// dispatchHead is a track's next queued event
+type dispatchHead struct {
+	track int
+	tick  int64
+	at    time.Time // send time
+}
+
+// dispatchQueue is a min-heap of track heads, only touched by the output loop
+type dispatchQueue struct {
+	heads []dispatchHead
+	stale atomic.Bool // a queue changed outside the output loop
+}
+
+// invalidate makes the output loop rebuild the heap before its next send
+func (q *dispatchQueue) invalidate() {
+	q.stale.Store(true)
+}
+
+// top returns the earliest head (false if no track has an event queued)
+func (q *dispatchQueue) top() (dispatchHead, bool) {
+	if len(q.heads) == 0 {
+		return dispatchHead{}, false
+	}
+	return q.heads[0], true
+}
+
+// push adds a head
+func (q *dispatchQueue) push(h dispatchHead) {
+	q.heads = append(q.heads, h)
+	q.up(len(q.heads) - 1)
+}
+
+// popTop removes the earliest head
+func (q *dispatchQueue) popTop() {
+	last := len(q.heads) - 1
+	q.heads[0] = q.heads[last]
+	q.heads = q.heads[:last]
+	q.down(0)
+}
+
+func (q *dispatchQueue) less(i, j int) bool {
+	if q.heads[i].at.Equal(q.heads[j].at) {
+		return q.heads[i].track < q.heads[j].track // lower tracks first, as before
+	}
+	return q.heads[i].at.Before(q.heads[j].at)
+}
+
+func (q *dispatchQueue) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(i, parent) {
+			return
+		}
+		q.heads[i], q.heads[parent] = q.heads[parent], q.heads[i]
+		i = parent
+	}
+}
+
+func (q *dispatchQueue) down(i int) {
+	n := len(q.heads)
+	for {
+		least := i
+		if l := 2*i + 1; l < n && q.less(l, least) {
+			least = l
+		}
+		if r := 2*i + 2; r < n && q.less(r, least) {
+			least = r
+		}
+		if least == i {
+			return
+		}
+		q.heads[i], q.heads[least] = q.heads[least], q.heads[i]
+		i = least
+	}
+}
+
+// trackHead reads a track's next event from its device (hold m.mu for reading)
+func (m *Manager) trackHead(track int) (dispatchHead, bool) {
+	dev := m.devices[track]
+	if dev == nil {
+		return dispatchHead{}, false
+	}
+	evt := dev.PeekNextEvent()
+	if evt == nil {
+		return dispatchHead{}, false
+	}
+	at := S.TickToTime(evt.Tick).Add(-S.Tracks[track].Latency())
+	return dispatchHead{track: track, tick: evt.Tick, at: at}, true
+}
+
+// nextDispatch returns the earliest event head, rebuilding the heap first if a queue
+// changed (hold m.mu for reading)
+func (m *Manager) nextDispatch() (dispatchHead, bool) {
+	q := &m.dispatch
+	if q.stale.Swap(false) {
+		q.heads = q.heads[:0]
+		for i := range m.devices {
+			if h, ok := m.trackHead(i); ok {
+				q.push(h)
+			}
+		}
+	}
+	return q.top()
+}
+
+// advanceDispatch replaces the top head (just popped from its device) with that
+// track's next event (hold m.mu for reading)
+func (m *Manager) advanceDispatch(track int) {
+	q := &m.dispatch
+	if h, ok := q.top(); !ok || h.track != track {
+		q.invalidate()
+		return
+	}
+	q.popTop()
+	if h, ok := m.trackHead(track); ok {
+		q.push(h)
+	}
+}
@@ -0,0 +1,42 @@
+package sequencer
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDispatchQueueOrder(t *testing.T) {
+	t0 := time.Now()
+	at := func(ms int) time.Time { return t0.Add(time.Duration(ms) * time.Millisecond) }
+	tests := []struct {
+		name  string
+		heads []dispatchHead
+		want  []int // tracks in send order
+	}{
+		{"empty", nil, nil},
+		{"by time", []dispatchHead{{track: 0, at: at(30)}, {track: 1, at: at(10)}, {track: 2, at: at(20)}}, []int{1, 2, 0}},
+		{"ties go to lower tracks", []dispatchHead{{track: 5, at: at(10)}, {track: 2, at: at(10)}, {track: 7, at: at(10)}}, []int{2, 5, 7}},
+		{"latency sends early", []dispatchHead{{track: 0, tick: 10, at: at(10)}, {track: 1, tick: 20, at: at(5)}}, []int{1, 0}},
+		{"many", []dispatchHead{
+			{track: 0, at: at(7)}, {track: 1, at: at(3)}, {track: 2, at: at(9)}, {track: 3, at: at(1)},
+			{track: 4, at: at(8)}, {track: 5, at: at(2)}, {track: 6, at: at(6)}, {track: 7, at: at(3)},
+		}, []int{3, 5, 1, 7, 6, 0, 4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q dispatchQueue
+			for _, h := range tt.heads {
+				q.push(h)
+			}
+			var got []int
+			for h, ok := q.top(); ok; h, ok = q.top() {
+				got = append(got, h.track)
+				q.popTop()
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sent tracks %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package sequencer
 
 import (
 	"fmt"
+	"math/rand"
+	"strings"
 	"sync"
 
 	"go-sequence/midi"
@@ -10,13 +12,15 @@ import (
 
 // DrumSchedule tracks what patterns play at what ticks (source of truth for playback)
 type DrumSchedule struct {
-	StartTick int64 // when patterns[0] starts
-	Patterns  []int // pattern indices in order
+	StartTick int64   // when patterns[0] starts
+	Patterns  []int   // pattern indices in order
+	Cuts      []int64 // slot lengths cut short by a switch (0 or missing = the whole pattern)
 }
 
 // DrumDevice reads/writes from central DrumState
 type DrumDevice struct {
 	state       *DrumState
+	track       int      // track index (for kit lookup)
 	previewChan chan int // sends slot index when preview sound should play
 
 	// Schedule - source of truth for what plays when
@@ -26,19 +30,55 @@ type DrumDevice struct {
 	// Queue - derived from schedule + pattern data
 	queueMu       sync.RWMutex
 	queue         []midi.Event // events sorted by tick
+	popped        poppedMark   // how far dispatch has popped it (see queuetrim.go)
 	onQueueChange func()       // callback to wake manager when queue needs recalc
 
 	// Confirmation dialog
 	confirmMode   bool
 	confirmMsg    string
 	confirmAction func()
+
+	// Lane rename text entry
+	renameMode   bool
+	renameBuffer string
+
+	// Humanize preview - pattern is modified live, base is restored on cancel
+	humanizeMode    bool
+	humanizeAll     bool             // false = selected lane, true = whole pattern
+	humanizePattern int              // pattern being humanized
+	humanizeBase    DrumPatternState // pattern data before humanize
+
+	// Randomize preview - same snapshot/restore approach as humanize
+	randomMode    bool
+	randomMutate  bool             // false = generate from scratch, true = mutate existing
+	randomAll     bool             // false = selected lane, true = whole pattern
+	randomPattern int              // pattern being randomized
+	randomBase    DrumPatternState // pattern data before randomize
+
+	// Step-hold gesture - hold a step pad, press another to fill the range
+	holdStep     int  // step pad being held (-1 = none)
+	holdActive   bool // state the held step was toggled to (applied to the range)
+	holdPressure bool // the hold's pressure has recorded its undo step
+
+	// Undo/redo - snapshots of patterns before edits
+	history undoHistory[DrumPatternState]
 }
 
+// Humanize limits
+const (
+	DefaultHumanizeRange = 20
+	maxHumanizeRange     = 64
+)
+
+// laneNameWidth is the column width for lane names in the grid
+const laneNameWidth = 9
+
 // NewDrumDevice creates a device that operates on the given state
 func NewDrumDevice(state *DrumState) *DrumDevice {
 	return &DrumDevice{
 		state:       state,
 		previewChan: make(chan int, 16),
+		holdStep:    -1,
 		schedule: DrumSchedule{
 			StartTick: 0,
 			Patterns:  []int{0}, // start with pattern 0
@@ -51,6 +91,34 @@ func (d *DrumDevice) SetOnQueueChange(fn func()) {
 	d.onQueueChange = fn
 }
 
+// SetTrack sets the track index this device plays on
+func (d *DrumDevice) SetTrack(idx int) {
+	d.track = idx
+}
+
+// LaneName returns the user's name for a lane, or the kit's slot name
+func (d *DrumDevice) LaneName(lane int) string {
+	if lane < 0 || lane >= 16 {
+		return ""
+	}
+	if name := d.state.LaneNames[lane]; name != "" {
+		return name
+	}
+	kit := DefaultKit
+	if d.track >= 0 && d.track < 8 && S.Tracks[d.track] != nil && S.Tracks[d.track].Kit != "" {
+		kit = S.Tracks[d.track].Kit
+	}
+	return GetKit(kit).SlotName(lane)
+}
+
+// SetLaneName overrides a lane's name (empty reverts to the kit name)
+func (d *DrumDevice) SetLaneName(lane int, name string) {
+	if lane < 0 || lane >= 16 {
+		return
+	}
+	d.state.LaneNames[lane] = strings.TrimSpace(name)
+}
+
 // PreviewChan returns the channel for preview events (slot indices)
 func (d *DrumDevice) PreviewChan() <-chan int {
 	return d.previewChan
@@ -66,6 +134,11 @@ func (d *DrumDevice) currentStep() int {
 	return int(ticksSinceStart / ticksPerStep)
 }
 
+// laneStep returns the step a lane is on - lanes restart every master length
+func (d *DrumDevice) laneStep(pat *DrumPatternState, note int) int {
+	return d.currentStep() % pat.MasterLength() % pat.Notes[note].Length
+}
+
 // GeneratePattern generates all MIDI events for a pattern starting at startTick.
 // This is the ONLY place pattern data → events conversion happens.
 func (d *DrumDevice) GeneratePattern(patternNum int, startTick int64) []midi.Event {
@@ -107,11 +180,19 @@ func (d *DrumDevice) patternLengthTicks(patternNum int) int64 {
 
 // --- Schedule helpers ---
 
+// slotTicks returns how long a schedule slot plays
+func (d *DrumDevice) slotTicks(slot int) int64 {
+	if slot < len(d.schedule.Cuts) && d.schedule.Cuts[slot] > 0 {
+		return d.schedule.Cuts[slot]
+	}
+	return d.patternLengthTicks(d.schedule.Patterns[slot])
+}
+
 // scheduleEndTick returns the tick where the current schedule ends
 func (d *DrumDevice) scheduleEndTick() int64 {
 	tick := d.schedule.StartTick
-	for _, patIdx := range d.schedule.Patterns {
-		tick += d.patternLengthTicks(patIdx)
+	for i := range d.schedule.Patterns {
+		tick += d.slotTicks(i)
 	}
 	return tick
 }
@@ -131,11 +212,14 @@ func (d *DrumDevice) extendSchedule(targetTick int64) {
 // trimSchedule drops patterns that are entirely behind the playhead
 func (d *DrumDevice) trimSchedule(currentTick int64) {
 	for len(d.schedule.Patterns) > 1 {
-		firstPatLen := d.patternLengthTicks(d.schedule.Patterns[0])
+		firstPatLen := d.slotTicks(0)
 		if d.schedule.StartTick+firstPatLen <= currentTick {
 			// First pattern is entirely in the past - drop it
 			d.schedule.StartTick += firstPatLen
 			d.schedule.Patterns = d.schedule.Patterns[1:]
+			if len(d.schedule.Cuts) > 0 {
+				d.schedule.Cuts = d.schedule.Cuts[1:]
+			}
 		} else {
 			break
 		}
@@ -170,10 +254,14 @@ func (d *DrumDevice) syncQueueToSchedule() {
 	// Generate all events from schedule
 	var newQueue []midi.Event
 	tick := d.schedule.StartTick
-	for _, patIdx := range d.schedule.Patterns {
+	for i, patIdx := range d.schedule.Patterns {
+		slotLen := d.slotTicks(i)
 		events := d.GeneratePattern(patIdx, tick)
+		if slotLen < d.patternLengthTicks(patIdx) {
+			events = cutEvents(events, tick+slotLen) // a switch cuts this slot short
+		}
 		newQueue = append(newQueue, events...)
-		tick += d.patternLengthTicks(patIdx)
+		tick += slotLen
 	}
 
 	// Update playing pattern index to match schedule
@@ -183,7 +271,7 @@ func (d *DrumDevice) syncQueueToSchedule() {
 
 	// Swap in new queue
 	d.queueMu.Lock()
-	d.queue = newQueue
+	d.queue = d.popped.trim(newQueue) // already sent events stay sent
 	d.queueMu.Unlock()
 
 	// Clear dirty flags
@@ -234,20 +322,21 @@ func (d *DrumDevice) PopNextEvent() *midi.Event {
 	if len(d.queue) == 0 {
 		return nil
 	}
-	event := d.queue[0]
+	event := &d.queue[0] // no copy - queues are replaced, never written in place
 	d.queue = d.queue[1:]
-	return &event
+	d.popped.pop(event.Tick)
+	return event
 }
 
 // ClearQueue clears all queued events (for stop/restart)
 func (d *DrumDevice) ClearQueue() {
 	d.queueMu.Lock()
 	d.queue = nil
+	d.popped = poppedMark{}
 	d.queueMu.Unlock()
 
 	// Reset schedule to start fresh
-	d.schedule.StartTick = 0
-	d.schedule.Patterns = []int{d.state.PlayingPatternIdx}
+	d.schedule = DrumSchedule{Patterns: []int{d.state.PlayingPatternIdx}}
 	d.clearDirtyFlags()
 }
 
@@ -264,11 +353,12 @@ func (d *DrumDevice) QueuePattern(p int, atTick int64) {
 	// Find which schedule slot contains atTick, then replace everything after with new pattern
 	tick := d.schedule.StartTick
 	foundSlot := false
-	for i, patIdx := range d.schedule.Patterns {
-		patLen := d.patternLengthTicks(patIdx)
+	for i := range d.schedule.Patterns {
+		patLen := d.slotTicks(i)
 		if tick+patLen > atTick {
 			// atTick is within this pattern - replace from next slot onward
 			nextSlot := i + 1
+			d.schedule.Cuts = d.schedule.Cuts[:min(nextSlot, len(d.schedule.Cuts))]
 			if nextSlot < len(d.schedule.Patterns) {
 				// Replace remaining slots with new pattern
 				for j := nextSlot; j < len(d.schedule.Patterns); j++ {
@@ -327,6 +417,35 @@ func (d *DrumDevice) ContentMask() []bool {
 	return mask
 }
 
+// PatternBars returns each pattern's master length in bars of its time signature
+func (d *DrumDevice) PatternBars() []float64 {
+	bars := make([]float64, NumPatterns)
+	for i := range d.state.Patterns {
+		pat := &d.state.Patterns[i]
+		bars[i] = float64(pat.MasterLength()) / float64(pat.Sig().BarSteps())
+	}
+	return bars
+}
+
+// Density returns triggers per bar for each pattern (polymeter lanes counted over the master length)
+func (d *DrumDevice) Density() []float64 {
+	density := make([]float64, NumPatterns)
+	for i := range d.state.Patterns {
+		pat := &d.state.Patterns[i]
+		masterLen := pat.MasterLength()
+		hits := 0
+		for step := 0; step < masterLen; step++ {
+			for n := 0; n < 16; n++ {
+				if pat.Notes[n].Steps[step%pat.Notes[n].Length].Active {
+					hits++
+				}
+			}
+		}
+		density[i] = float64(hits) * float64(pat.Sig().BarSteps()) / float64(masterLen)
+	}
+	return density
+}
+
 // HandleMIDI handles incoming MIDI for recording
 func (d *DrumDevice) HandleMIDI(event midi.Event) {
 	if !d.state.Recording || !S.Playing {
@@ -349,7 +468,7 @@ func (d *DrumDevice) HandleMIDI(event midi.Event) {
 	ticksSinceStart := event.Tick - d.schedule.StartTick
 	ticksPerStep := int64(PPQ / 4)
 	pat := &d.state.Patterns[d.state.EditingPatternIdx]
-	step := int((ticksSinceStart / ticksPerStep) % int64(pat.Notes[noteIdx].Length))
+	step := int((ticksSinceStart/ticksPerStep)%int64(pat.MasterLength())) % pat.Notes[noteIdx].Length
 
 	// Use SetStep to write to the editing pattern
 	d.SetStep(noteIdx, step, event.Velocity)
@@ -394,6 +513,7 @@ func (d *DrumDevice) SetStep(note, step int, velocity uint8) {
 	pat.Notes[note].Steps[step].Active = true
 	pat.Notes[note].Steps[step].Velocity = velocity
 	d.patternDirty[d.state.EditingPatternIdx] = true
+	S.Dirty = true
 	d.syncQueueToSchedule()
 }
 
@@ -408,6 +528,53 @@ func (d *DrumDevice) SetNoteLaneLength(note, length int) {
 	d.syncQueueToSchedule()
 }
 
+// SetPatternLength sets the editing pattern's master length in steps (0 = longest lane)
+func (d *DrumDevice) SetPatternLength(length int) {
+	if length < 0 || length > MaxDrumPatternLength {
+		return
+	}
+	d.state.Patterns[d.state.EditingPatternIdx].Length = length
+	d.patternDirty[d.state.EditingPatternIdx] = true
+	d.syncQueueToSchedule()
+}
+
+// nudgePatternLength grows/shrinks the master length by one bar of the time signature.
+// Shrinking below one bar returns to automatic (longest lane).
+func (d *DrumDevice) nudgePatternLength(bars int) {
+	pat := &d.state.Patterns[d.state.EditingPatternIdx]
+	barSteps := pat.Sig().BarSteps()
+	current := pat.Length
+	if current == 0 {
+		// Start from the automatic length, rounded to whole bars
+		current = (pat.MasterLength() + barSteps/2) / barSteps * barSteps
+		if bars > 0 && current > 0 {
+			bars-- // first press locks in the current length
+		}
+	}
+	length := current + bars*barSteps
+	if length < barSteps {
+		length = 0
+	}
+	if length > MaxDrumPatternLength {
+		return
+	}
+	d.SetPatternLength(length)
+}
+
+// CycleTimeSig moves to the next time signature, keeping an explicit length's bar count.
+// An automatic length stays automatic - the lanes already set it.
+func (d *DrumDevice) CycleTimeSig() {
+	pat := &d.state.Patterns[d.state.EditingPatternIdx]
+	bars := max(pat.Length/pat.Sig().BarSteps(), 1)
+	pat.TimeSig = (pat.TimeSig + 1) % len(TimeSigs)
+	if pat.Length == 0 {
+		d.patternDirty[d.state.EditingPatternIdx] = true
+		d.syncQueueToSchedule()
+		return
+	}
+	d.SetPatternLength(min(bars*pat.Sig().BarSteps(), MaxDrumPatternLength))
+}
+
 // ClearNote clears all steps in a note lane
 func (d *DrumDevice) ClearNote(note int) {
 	pat := &d.state.Patterns[d.state.EditingPatternIdx]
@@ -433,6 +600,262 @@ func (d *DrumDevice) ClearEditingPattern() {
 	d.syncQueueToSchedule()
 }
 
+// --- Humanize ---
+
+// StartHumanize snapshots the editing pattern and enters humanize preview
+func (d *DrumDevice) StartHumanize() {
+	s := d.state
+	if s.HumanizeRange < 1 || s.HumanizeRange > maxHumanizeRange {
+		s.HumanizeRange = DefaultHumanizeRange
+	}
+	d.humanizePattern = s.EditingPatternIdx
+	d.humanizeBase = s.Patterns[s.EditingPatternIdx]
+	d.humanizeMode = true
+	d.rollHumanize()
+}
+
+// rollHumanize re-randomizes velocities from the snapshot (so range changes don't compound)
+func (d *DrumDevice) rollHumanize() {
+	s := d.state
+	pat := &s.Patterns[d.humanizePattern]
+	*pat = d.humanizeBase
+
+	for n := 0; n < 16; n++ {
+		if !d.humanizeAll && n != s.SelectedNoteIdx {
+			continue
+		}
+		note := &pat.Notes[n]
+		for step := 0; step < note.Length; step++ {
+			st := &note.Steps[step]
+			if !st.Active {
+				continue
+			}
+			vel := int(st.Velocity) + rand.Intn(2*s.HumanizeRange+1) - s.HumanizeRange
+			st.Velocity = uint8(clamp(vel, 1, 127))
+		}
+	}
+
+	d.patternDirty[d.humanizePattern] = true
+	d.syncQueueToSchedule()
+}
+
+// applyHumanize keeps the previewed velocities (undoable)
+func (d *DrumDevice) applyHumanize() {
+	d.pushUndo(d.humanizePattern, d.humanizeBase)
+	d.humanizeMode = false
+}
+
+// cancelHumanize restores the pattern as it was before the preview
+func (d *DrumDevice) cancelHumanize() {
+	d.state.Patterns[d.humanizePattern] = d.humanizeBase
+	d.patternDirty[d.humanizePattern] = true
+	d.syncQueueToSchedule()
+	d.humanizeMode = false
+}
+
+func (d *DrumDevice) handleHumanizeKey(key string) {
+	s := d.state
+	switch key {
+	case "h", "left":
+		if s.HumanizeRange > 1 {
+			s.HumanizeRange--
+			d.rollHumanize()
+		}
+	case "l", "right":
+		if s.HumanizeRange < maxHumanizeRange {
+			s.HumanizeRange++
+			d.rollHumanize()
+		}
+	case "tab":
+		d.humanizeAll = !d.humanizeAll
+		d.rollHumanize()
+	case " ":
+		d.rollHumanize()
+	case "y", "Y", "enter":
+		d.applyHumanize()
+	case "n", "N", "esc", "q":
+		d.cancelHumanize()
+	}
+}
+
+// --- Randomize ---
+
+// StartRandomize snapshots the editing pattern and enters generate/mutate preview
+func (d *DrumDevice) StartRandomize(mutate bool) {
+	s := d.state
+	if s.RandomDensity < randomDensityStep || s.RandomDensity > 100 {
+		s.RandomDensity = DefaultRandomDensity
+	}
+	d.randomMutate = mutate
+	d.randomPattern = s.EditingPatternIdx
+	d.randomBase = s.Patterns[s.EditingPatternIdx]
+	d.randomMode = true
+	d.rollRandomize()
+}
+
+// rollRandomize regenerates from the snapshot using each lane's style hint
+func (d *DrumDevice) rollRandomize() {
+	s := d.state
+	pat := &s.Patterns[d.randomPattern]
+	*pat = d.randomBase
+
+	for n := 0; n < 16; n++ {
+		if !d.randomAll && n != s.SelectedNoteIdx {
+			continue
+		}
+		style := laneStyleFor(d.LaneName(n))
+		if d.randomMutate {
+			mutateLane(&pat.Notes[n], style, s.RandomDensity)
+		} else {
+			generateLane(&pat.Notes[n], style, s.RandomDensity)
+		}
+	}
+
+	d.patternDirty[d.randomPattern] = true
+	d.syncQueueToSchedule()
+}
+
+// applyRandomize keeps the previewed pattern (undoable)
+func (d *DrumDevice) applyRandomize() {
+	d.pushUndo(d.randomPattern, d.randomBase)
+	d.randomMode = false
+}
+
+// cancelRandomize restores the pattern as it was before the preview
+func (d *DrumDevice) cancelRandomize() {
+	d.state.Patterns[d.randomPattern] = d.randomBase
+	d.patternDirty[d.randomPattern] = true
+	d.syncQueueToSchedule()
+	d.randomMode = false
+}
+
+func (d *DrumDevice) handleRandomizeKey(key string) {
+	s := d.state
+	switch key {
+	case "h", "left":
+		if s.RandomDensity > randomDensityStep {
+			s.RandomDensity -= randomDensityStep
+			d.rollRandomize()
+		}
+	case "l", "right":
+		if s.RandomDensity < 100 {
+			s.RandomDensity += randomDensityStep
+			d.rollRandomize()
+		}
+	case "tab":
+		d.randomAll = !d.randomAll
+		d.rollRandomize()
+	case "g":
+		d.randomMutate = false
+		d.rollRandomize()
+	case "m":
+		d.randomMutate = true
+		d.rollRandomize()
+	case " ":
+		d.rollRandomize()
+	case "y", "Y", "enter":
+		d.applyRandomize()
+	case "n", "N", "esc", "q":
+		d.cancelRandomize()
+	}
+}
+
+// --- Undo ---
+
+// pushUndo records a pattern snapshot before an edit
+func (d *DrumDevice) pushUndo(pattern int, data DrumPatternState) {
+	d.history.push(pattern, data)
+}
+
+// trackEdit snapshots the editing pattern; the returned func records an undo step
+// if the pattern changed since. Previews record their own step when applied.
+func (d *DrumDevice) trackEdit() func() {
+	idx := d.state.EditingPatternIdx
+	before := d.state.Patterns[idx]
+	previewing := d.humanizeMode || d.randomMode
+	return func() {
+		if previewing || d.humanizeMode || d.randomMode {
+			return
+		}
+		if d.state.Patterns[idx] != before {
+			d.pushUndo(idx, before)
+		}
+	}
+}
+
+// Undo restores the most recent snapshot
+func (d *DrumDevice) Undo() {
+	if e, ok := d.history.stepBack(d.patternData); ok {
+		d.restorePattern(e.pattern, e.data)
+	}
+}
+
+// Redo re-applies the most recently undone edit
+func (d *DrumDevice) Redo() {
+	if e, ok := d.history.stepForward(d.patternData); ok {
+		d.restorePattern(e.pattern, e.data)
+	}
+}
+
+func (d *DrumDevice) patternData(pattern int) DrumPatternState {
+	return d.state.Patterns[pattern]
+}
+
+// restorePattern swaps in a snapshot and shows it
+func (d *DrumDevice) restorePattern(pattern int, data DrumPatternState) {
+	d.state.Patterns[pattern] = data
+	d.state.EditingPatternIdx = pattern
+	d.patternDirty[pattern] = true
+	d.syncQueueToSchedule()
+}
+
+// --- Lane rename ---
+
+// startRename enters text entry for the selected lane's name
+func (d *DrumDevice) startRename() {
+	d.renameMode = true
+	d.renameBuffer = d.LaneName(d.state.SelectedNoteIdx)
+}
+
+func (d *DrumDevice) handleRenameKey(key string) {
+	switch key {
+	case "enter":
+		d.SetLaneName(d.state.SelectedNoteIdx, d.renameBuffer)
+		d.renameMode = false
+		d.renameBuffer = ""
+	case "esc":
+		d.renameMode = false
+		d.renameBuffer = ""
+	case "backspace":
+		if len(d.renameBuffer) > 0 {
+			d.renameBuffer = d.renameBuffer[:len(d.renameBuffer)-1]
+		}
+	case " ":
+		d.renameBuffer += " "
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 {
+			d.renameBuffer += key
+		}
+	}
+}
+
+// velocityGlyph maps a velocity (1-127) to a bar glyph
+func velocityGlyph(vel uint8) string {
+	bars := []string{"▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+	idx := int(vel) * len(bars) / 128
+	return bars[clamp(idx, 0, len(bars)-1)]
+}
+
+// truncateName cuts a name to fit a fixed-width column, by runes so multi-byte
+// port names aren't split mid-character
+func truncateName(name string, width int) string {
+	if runes := []rune(name); len(runes) > width {
+		return string(runes[:width])
+	}
+	return name
+}
+
 func (d *DrumDevice) View() string {
 	s := d.state
 	pat := &s.Patterns[s.EditingPatternIdx]
@@ -443,9 +866,20 @@ func (d *DrumDevice) View() string {
 		playInfo = fmt.Sprintf(" (playing:%d)", s.PlayingPatternIdx)
 	}
 	selectedNote := &pat.Notes[s.SelectedNoteIdx]
-	currentStep := d.currentStep()
-	selectedStep := currentStep % selectedNote.Length
-	out := fmt.Sprintf("DRUM  Pattern %d%s  Step %d/%d  Note %d\n\n", s.EditingPatternIdx+1, playInfo, selectedStep+1, selectedNote.Length, s.SelectedNoteIdx+1)
+	selectedStep := d.laneStep(pat, s.SelectedNoteIdx)
+	masterLen := pat.MasterLength()
+	lengthInfo := fmt.Sprintf("%d steps", masterLen)
+	if pat.Length == 0 {
+		lengthInfo += " (auto)"
+	}
+	if s.Recording {
+		if s.FixedVelocity {
+			playInfo += fmt.Sprintf("  REC vel %d", DefaultVelocity)
+		} else {
+			playInfo += "  REC vel pad"
+		}
+	}
+	out := fmt.Sprintf("DRUM  Pattern %d%s  %s  %s  Step %d/%d  Note %d %s\n\n", s.EditingPatternIdx+1, playInfo, pat.Sig(), lengthInfo, selectedStep+1, selectedNote.Length, s.SelectedNoteIdx+1, d.LaneName(s.SelectedNoteIdx))
 
 	// Confirmation dialog takes over
 	if d.confirmMode {
@@ -456,17 +890,43 @@ func (d *DrumDevice) View() string {
 		return out
 	}
 
+	// Lane rename takes over
+	if d.renameMode {
+		out += "─────────────────────────────────────────────────\n"
+		out += fmt.Sprintf("\nName for note %d: %s_\n", s.SelectedNoteIdx+1, d.renameBuffer)
+		out += "\n[enter] confirm (empty = kit name)  [esc] cancel\n"
+		out += "\n─────────────────────────────────────────────────\n"
+		return out
+	}
+
+	// Ruler - bar starts and beats for the time signature, up to the master length
+	barSteps := pat.Sig().BarSteps()
+	beatSteps := 16 / pat.Sig().Unit
+	out += strings.Repeat(" ", 3+laneNameWidth+1)
+	for step := 0; step < 32; step++ {
+		if step >= masterLen {
+			out += " "
+		} else if step%barSteps == 0 {
+			out += "|"
+		} else if step%beatSteps == 0 {
+			out += "'"
+		} else {
+			out += " "
+		}
+	}
+	out += "\n"
+
 	// 16x32 grid - single char per cell
 	for n := 0; n < 16; n++ {
 		note := &pat.Notes[n]
-		noteStep := currentStep % note.Length
-		out += fmt.Sprintf("%2d ", n+1)
+		noteStep := d.laneStep(pat, n)
+		out += fmt.Sprintf("%2d %-*s ", n+1, laneNameWidth, truncateName(d.LaneName(n), laneNameWidth))
 
 		for step := 0; step < 32; step++ {
 			isCursor := n == s.SelectedNoteIdx && step == s.Cursor
 
 			var char string
-			if step >= note.Length {
+			if step >= note.Length || step >= masterLen {
 				if isCursor {
 					char = "□"
 				} else {
@@ -497,6 +957,66 @@ func (d *DrumDevice) View() string {
 		out += "\n"
 	}
 
+	// Velocity row for the selected note
+	out += strings.Repeat(" ", 3+laneNameWidth+1)
+	for step := 0; step < 32; step++ {
+		if step >= selectedNote.Length || step >= masterLen {
+			out += "-"
+		} else if selectedNote.Steps[step].Active {
+			out += velocityGlyph(selectedNote.Steps[step].Velocity)
+		} else {
+			out += " "
+		}
+	}
+	out += "  vel\n"
+
+	// Humanize preview replaces key help
+	if d.humanizeMode {
+		scope := fmt.Sprintf("note %d (%s)", s.SelectedNoteIdx+1, d.LaneName(s.SelectedNoteIdx))
+		if d.humanizeAll {
+			scope = "whole pattern"
+		}
+		out += "\n─────────────────────────────────────────────────\n"
+		out += fmt.Sprintf("HUMANIZE  range ±%d  scope: %s  (previewing)\n\n", s.HumanizeRange, scope)
+		out += widgets.RenderKeyHelp([]widgets.KeySection{
+			{Keys: []widgets.KeyBinding{
+				{Key: "h / l", Desc: "range -/+"},
+				{Key: "tab", Desc: "toggle note/pattern scope"},
+				{Key: "space", Desc: "re-roll"},
+				{Key: "y / enter", Desc: "apply"},
+				{Key: "n / esc", Desc: "cancel"},
+			}},
+		})
+		out += "\n─────────────────────────────────────────────────\n"
+		return out
+	}
+
+	// Randomize preview replaces key help
+	if d.randomMode {
+		action := "GENERATE"
+		if d.randomMutate {
+			action = "MUTATE"
+		}
+		scope := fmt.Sprintf("note %d (%s, %s)", s.SelectedNoteIdx+1, d.LaneName(s.SelectedNoteIdx), laneStyleFor(d.LaneName(s.SelectedNoteIdx)))
+		if d.randomAll {
+			scope = "whole pattern"
+		}
+		out += "\n─────────────────────────────────────────────────\n"
+		out += fmt.Sprintf("%s  density %d%%  scope: %s  (previewing)\n\n", action, s.RandomDensity, scope)
+		out += widgets.RenderKeyHelp([]widgets.KeySection{
+			{Keys: []widgets.KeyBinding{
+				{Key: "h / l", Desc: "density -/+"},
+				{Key: "tab", Desc: "toggle note/pattern scope"},
+				{Key: "g / m", Desc: "generate / mutate"},
+				{Key: "space", Desc: "re-roll"},
+				{Key: "y / enter", Desc: "apply"},
+				{Key: "n / esc", Desc: "cancel"},
+			}},
+		})
+		out += "\n─────────────────────────────────────────────────\n"
+		return out
+	}
+
 	// Key help
 	out += "\n"
 	out += widgets.RenderKeyHelp([]widgets.KeySection{
@@ -505,14 +1025,21 @@ func (d *DrumDevice) View() string {
 			{Key: "j / k", Desc: "select note up/down"},
 			{Key: "space", Desc: "toggle step on/off"},
 			{Key: "[ / ]", Desc: "shorten/lengthen note lane"},
+			{Key: "{ / }", Desc: "pattern length -/+ one bar (below 1 bar = auto)"},
+			{Key: "t", Desc: "cycle time signature"},
 			{Key: "c", Desc: "clear current note"},
+			{Key: "N", Desc: "rename current note"},
 			{Key: "< / >", Desc: "previous/next pattern"},
+			{Key: "V", Desc: "pad recording velocity: pressure/fixed"},
+			{Key: "H", Desc: "humanize velocities (preview)"},
+			{Key: "g / m", Desc: "generate / mutate pattern (preview)"},
+			{Key: "u / ctrl+r", Desc: "undo / redo"},
 		}},
 	})
 
 	// Launchpad
 	out += "\n\n"
-	out += d.renderLaunchpadHelp()
+	out += widgets.RenderLayout(d.HelpLayout())
 
 	return out
 }
@@ -534,8 +1061,8 @@ func (d *DrumDevice) RenderLEDs() []LEDState {
 	offColor := [3]uint8{0, 0, 0}
 
 	// Top 4 rows (rows 4-7): steps for selected note
-	currentStep := d.currentStep()
-	noteStep := currentStep % selectedNote.Length
+	noteStep := d.laneStep(pat, s.SelectedNoteIdx)
+	masterLen := pat.MasterLength()
 	for stepIdx := 0; stepIdx < 32; stepIdx++ {
 		row := 7 - (stepIdx / 8)
 		col := stepIdx % 8
@@ -543,7 +1070,7 @@ func (d *DrumDevice) RenderLEDs() []LEDState {
 		var color [3]uint8 = offColor
 		var channel uint8 = midi.ChannelStatic
 
-		if stepIdx >= selectedNote.Length {
+		if stepIdx >= selectedNote.Length || stepIdx >= masterLen {
 			color = offColor
 		} else if stepIdx == noteStep {
 			color = playheadColor
@@ -603,12 +1130,16 @@ func (d *DrumDevice) RenderLEDs() []LEDState {
 	return leds
 }
 
-// IsInputMode returns true if in confirm mode
+// IsInputMode returns true if in confirm, rename, humanize or randomize mode
 func (d *DrumDevice) IsInputMode() bool {
-	return d.confirmMode
+	return d.confirmMode || d.renameMode || d.humanizeMode || d.randomMode
 }
 
 func (d *DrumDevice) HandleKey(key string) {
+	if key != "u" && key != "ctrl+r" {
+		defer d.trackEdit()()
+	}
+
 	// Confirmation mode
 	if d.confirmMode {
 		switch key {
@@ -625,6 +1156,24 @@ func (d *DrumDevice) HandleKey(key string) {
 		return
 	}
 
+	// Lane rename
+	if d.renameMode {
+		d.handleRenameKey(key)
+		return
+	}
+
+	// Humanize preview
+	if d.humanizeMode {
+		d.handleHumanizeKey(key)
+		return
+	}
+
+	// Randomize preview
+	if d.randomMode {
+		d.handleRandomizeKey(key)
+		return
+	}
+
 	s := d.state
 	pat := &s.Patterns[s.EditingPatternIdx]
 	note := &pat.Notes[s.SelectedNoteIdx]
@@ -666,6 +1215,12 @@ func (d *DrumDevice) HandleKey(key string) {
 		if note.Length < 32 {
 			d.SetNoteLaneLength(s.SelectedNoteIdx, note.Length+1)
 		}
+	case "{":
+		d.nudgePatternLength(-1)
+	case "}":
+		d.nudgePatternLength(1)
+	case "t":
+		d.CycleTimeSig()
 	case "c":
 		d.confirmClearNote()
 	case "C":
@@ -678,6 +1233,20 @@ func (d *DrumDevice) HandleKey(key string) {
 		if s.EditingPatternIdx < NumPatterns-1 {
 			s.EditingPatternIdx++
 		}
+	case "N":
+		d.startRename()
+	case "V":
+		s.FixedVelocity = !s.FixedVelocity
+	case "H":
+		d.StartHumanize()
+	case "g":
+		d.StartRandomize(false)
+	case "m":
+		d.StartRandomize(true)
+	case "u":
+		d.Undo()
+	case "ctrl+r":
+		d.Redo()
 	}
 }
 
@@ -699,8 +1268,9 @@ func (d *DrumDevice) confirmClearNote() {
 		return // nothing to clear
 	}
 
-	d.confirmMsg = fmt.Sprintf("Clear note %d?", noteIdx+1)
+	d.confirmMsg = fmt.Sprintf("Clear note %d (%s)?", noteIdx+1, d.LaneName(noteIdx))
 	d.confirmAction = func() {
+		autoBackup("clear")
 		d.ClearNote(noteIdx)
 	}
 	d.confirmMode = true
@@ -726,23 +1296,42 @@ func (d *DrumDevice) confirmClearPattern() {
 
 	d.confirmMsg = fmt.Sprintf("Clear pattern %d?", s.EditingPatternIdx+1)
 	d.confirmAction = func() {
+		autoBackup("clear")
 		d.ClearEditingPattern()
 	}
 	d.confirmMode = true
 }
 
-func (d *DrumDevice) HandlePad(row, col int) {
+func (d *DrumDevice) HandlePad(row, col int, velocity uint8) {
+	// A range fill joins the held press's undo step, so the whole gesture undoes at once
+	filling := row >= 4 && row <= 7 && d.holdStep >= 0 && d.holdStep != (7-row)*8+col
+	if !filling {
+		defer d.trackEdit()()
+	}
+
 	s := d.state
 	pat := &s.Patterns[s.EditingPatternIdx]
 
-	// Top 4 rows: step toggle
+	// Top 4 rows: step toggle (hold one step and press another to fill the range)
 	if row >= 4 && row <= 7 {
 		stepIdx := (7-row)*8 + col
 		note := &pat.Notes[s.SelectedNoteIdx]
-		if stepIdx < note.Length {
+		if stepIdx >= note.Length {
+			return
+		}
+		if d.holdStep >= 0 && d.holdStep != stepIdx { // same pad again = missed release
+			d.fillHeldRange(stepIdx)
+		} else {
 			d.ToggleStep(s.SelectedNoteIdx, stepIdx)
-			s.Cursor = stepIdx
+			d.holdStep = stepIdx
+			d.holdActive = note.Steps[stepIdx].Active
+			d.holdPressure = false
+			// A step turned on takes the pad's velocity unless fixed velocity is on
+			if d.holdActive && !s.FixedVelocity && velocity > 0 {
+				note.Steps[stepIdx].Velocity = velocity
+			}
 		}
+		s.Cursor = stepIdx
 		return
 	}
 
@@ -765,10 +1354,17 @@ func (d *DrumDevice) HandlePad(row, col int) {
 			}
 
 			// If recording while playing, toggle step at current position
+			// (new hits keep the pad's velocity unless fixed velocity is on)
 			if s.Recording && S.Playing {
-				note := &pat.Notes[noteIdx]
-				stepIdx := d.currentStep() % note.Length
-				d.ToggleStep(noteIdx, stepIdx)
+				stepIdx := d.laneStep(pat, noteIdx)
+				if pat.Notes[noteIdx].Steps[stepIdx].Active {
+					d.ToggleStep(noteIdx, stepIdx)
+				} else {
+					if s.FixedVelocity || velocity == 0 {
+						velocity = DefaultVelocity
+					}
+					d.SetStep(noteIdx, stepIdx, velocity)
+				}
 			}
 		}
 		return
@@ -806,7 +1402,54 @@ func (d *DrumDevice) HandlePad(row, col int) {
 	}
 }
 
-func (d *DrumDevice) renderLaunchpadHelp() string {
+// HandlePadRelease ends a step-hold gesture when the held step pad is let go
+func (d *DrumDevice) HandlePadRelease(row, col int) {
+	if row >= 4 && row <= 7 && (7-row)*8+col == d.holdStep {
+		d.holdStep = -1
+	}
+}
+
+// HandlePadPressure raises the held step's velocity as the pad is pressed harder
+// (pads with aftertouch - Launchpad Pro Mk3)
+func (d *DrumDevice) HandlePadPressure(row, col int, pressure uint8) {
+	s := d.state
+	if row < 4 || row > 7 || (7-row)*8+col != d.holdStep || !d.holdActive || s.FixedVelocity {
+		return
+	}
+	pat := &s.Patterns[s.EditingPatternIdx]
+	step := &pat.Notes[s.SelectedNoteIdx].Steps[d.holdStep]
+	if pressure <= step.Velocity {
+		return
+	}
+	if !d.holdPressure {
+		// One undo step for the hold's pressure - later changes join it
+		d.holdPressure = true
+		defer d.trackEdit()()
+	}
+	step.Velocity = pressure
+	d.patternDirty[s.EditingPatternIdx] = true
+	d.syncQueueToSchedule()
+}
+
+// fillHeldRange sets every step between the held pad and stepIdx to the held pad's state
+func (d *DrumDevice) fillHeldRange(stepIdx int) {
+	s := d.state
+	pat := &s.Patterns[s.EditingPatternIdx]
+	note := &pat.Notes[s.SelectedNoteIdx]
+
+	lo, hi := d.holdStep, stepIdx
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	for step := lo; step <= hi && step < note.Length; step++ {
+		note.Steps[step].Active = d.holdActive
+	}
+	d.patternDirty[s.EditingPatternIdx] = true
+	d.syncQueueToSchedule()
+}
+
+// HelpLayout describes the drum Launchpad page with a tooltip for every active pad
+func (d *DrumDevice) HelpLayout() widgets.LaunchpadLayout {
 	// Colors
 	topRowColor := [3]uint8{111, 10, 126}
 	stepsColor := [3]uint8{234, 73, 116}
@@ -814,55 +1457,64 @@ func (d *DrumDevice) renderLaunchpadHelp() string {
 	commandsColor := [3]uint8{253, 157, 110}
 	sceneColor := [3]uint8{71, 13, 121}
 
-	// Build the grid
-	var grid [8][8][3]uint8
-	var rightCol [8][3]uint8
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
 
 	// Top 4 rows: steps
 	for row := 4; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			grid[row][col] = stepsColor
+			l.Grid[row][col] = widgets.Pad{Color: stepsColor, Tooltip: fmt.Sprintf("step %d", (7-row)*8+col+1)}
 		}
 	}
 
 	// Bottom-left 4x4: note select
 	for row := 0; row < 4; row++ {
 		for col := 0; col < 4; col++ {
-			grid[row][col] = noteColor
+			noteIdx := row*4 + col
+			l.Grid[row][col] = widgets.Pad{Color: noteColor, Tooltip: fmt.Sprintf("note %d (%s)", noteIdx+1, d.LaneName(noteIdx))}
 		}
 	}
 
-	// Bottom-right 4x4: commands
+	// Bottom-right 4x4: commands (only implemented ones get a tooltip)
+	commands := map[[2]int]string{
+		{3, 4}: "toggle preview",
+		{3, 5}: "toggle record",
+		{1, 6}: "shorten note lane",
+		{1, 7}: "lengthen note lane",
+		{0, 4}: "clear note",
+		{0, 5}: "clear pattern",
+	}
 	for row := 0; row < 4; row++ {
 		for col := 4; col < 8; col++ {
-			grid[row][col] = commandsColor
+			l.Grid[row][col] = widgets.Pad{Color: commandsColor, Tooltip: commands[[2]int{row, col}]}
 		}
 	}
 
 	// Right column: scenes
 	for i := 0; i < 8; i++ {
-		rightCol[i] = sceneColor
-	}
-
-	// Top row
-	topRow := make([][3]uint8, 8)
-	for i := range topRow {
-		topRow[i] = topRowColor
+		rightCol[i] = widgets.Pad{Color: sceneColor}
+		l.TopRow[i] = widgets.Pad{Color: topRowColor}
 	}
 
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, &rightCol) + "\n\n"
-
 	// Legend
-	out += widgets.RenderLegendItem(stepsColor, "Steps", "tap to toggle steps 1-32") + "\n"
-	out += widgets.RenderLegendItem(noteColor, "Note", "select note 1-16 (plays sound in preview mode)") + "\n"
-	out += widgets.RenderLegendItem(commandsColor, "Commands", "") + "\n"
-	out += `    Row 3: [Preview] [Record]  (Mute)   (Solo)
+	var lanes strings.Builder
+	for row := 3; row >= 0; row-- {
+		lanes.WriteString(fmt.Sprintf("    Row %d:", row))
+		for col := 0; col < 4; col++ {
+			lanes.WriteString(fmt.Sprintf(" %-*s", laneNameWidth, truncateName(d.LaneName(row*4+col), laneNameWidth)))
+		}
+		lanes.WriteString("\n")
+	}
+	l.Legend = []widgets.LegendItem{
+		{Color: stepsColor, Name: "Steps", Desc: "tap to toggle steps 1-32, hold one and press another to fill the range"},
+		{Color: noteColor, Name: "Note", Desc: "select note 1-16 (plays sound in preview mode)", Detail: lanes.String()},
+		{Color: commandsColor, Name: "Commands", Detail: `    Row 3: [Preview] [Record]  (Mute)   (Solo)
     Row 2: (Vel -)   (Vel +)   (-)      (-)
     Row 1: (Nudge<)  (Nudge>)  [Len -]  [Len +]
     Row 0: [ClrNote] [ClrPat]  (Copy)   (Paste)
-    [ ] = implemented, ( ) = not yet` + "\n"
-	out += widgets.RenderLegendItem(sceneColor, "Scene", "launch scenes")
-
-	return out
+    [ ] = implemented, ( ) = not yet`},
+		{Color: sceneColor, Name: "Scene", Desc: "launch scenes"},
+	}
+	return l
 }
@@ -0,0 +1,110 @@
+package sequencer
+
+import (
+	"slices"
+	"testing"
+
+	"go-sequence/midi"
+)
+
+func TestDrumGeneratePattern(t *testing.T) {
+	step := int64(PPQ / 4)
+	type hit struct {
+		note, step int
+	}
+	tests := []struct {
+		name    string
+		length  int   // master length (0 = longest note)
+		lengths []int // note lengths for notes 0 and 1
+		hits    []hit
+		start   int64
+		want    []int64 // trigger ticks
+		notes   []uint8
+	}{
+		{"empty", 0, []int{16, 16}, nil, 0, nil, nil},
+		{"one hit", 0, []int{16, 16}, []hit{{0, 4}}, 0, []int64{4 * step}, []uint8{0}},
+		{"from a start tick", 0, []int{16, 16}, []hit{{0, 0}}, 100, []int64{100}, []uint8{0}},
+		{"same step, note order", 0, []int{16, 16}, []hit{{1, 2}, {0, 2}}, 0, []int64{2 * step, 2 * step}, []uint8{0, 1}},
+		{"polymeter repeats a short note", 0, []int{16, 6}, []hit{{1, 0}}, 0, []int64{0, 6 * step, 12 * step}, []uint8{1, 1, 1}},
+		{"master length cuts off", 8, []int{16, 16}, []hit{{0, 2}, {0, 10}}, 0, []int64{2 * step}, []uint8{0}},
+		{"master length repeats", 32, []int{16, 16}, []hit{{0, 0}}, 0, []int64{0, 16 * step}, []uint8{0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDrumState()
+			pat := &st.Patterns[0]
+			pat.Length = tt.length
+			for i := range pat.Notes {
+				pat.Notes[i].Length = 1
+			}
+			pat.Notes[0].Length, pat.Notes[1].Length = tt.lengths[0], tt.lengths[1]
+			for _, h := range tt.hits {
+				pat.Notes[h.note].Steps[h.step] = DrumStepState{Active: true, Velocity: 100}
+			}
+			d := NewDrumDevice(st)
+
+			var ticks []int64
+			var notes []uint8
+			for _, e := range d.GeneratePattern(0, tt.start) {
+				if e.Type != midi.Trigger {
+					t.Fatalf("generated a %v event, want only triggers", e.Type)
+				}
+				ticks = append(ticks, e.Tick)
+				notes = append(notes, e.Note)
+			}
+			if !slices.Equal(ticks, tt.want) || !slices.Equal(notes, tt.notes) {
+				t.Errorf("triggers at %v notes %v, want %v notes %v", ticks, notes, tt.want, tt.notes)
+			}
+		})
+	}
+}
+
+func TestDrumCycleTimeSig(t *testing.T) {
+	tests := []struct {
+		name       string
+		length     int // master length (0 = longest note)
+		laneLength int // length of note 0
+		wantLength int
+		wantMaster int
+	}{
+		{"two bar auto length stays auto", 0, 32, 0, 32},
+		{"one bar auto length stays auto", 0, 16, 0, 16},
+		{"explicit bar count kept", 32, 16, 24, 24}, // 2 bars of 3/4
+		{"explicit length under a bar", 8, 16, 12, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := NewDrumState()
+			pat := &st.Patterns[0]
+			pat.Length = tt.length
+			pat.Notes[0].Length = tt.laneLength
+			d := NewDrumDevice(st)
+
+			d.CycleTimeSig() // 4/4 -> 3/4
+			if pat.Sig() != (TimeSig{3, 4}) {
+				t.Fatalf("time signature %v, want 3/4", pat.Sig())
+			}
+			if pat.Length != tt.wantLength || pat.MasterLength() != tt.wantMaster {
+				t.Errorf("length %d (master %d), want %d (master %d)", pat.Length, pat.MasterLength(), tt.wantLength, tt.wantMaster)
+			}
+		})
+	}
+}
+
+func TestTruncateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		width int
+		want  string
+	}{
+		{"Kick", 6, "Kick"},
+		{"Open Hat", 4, "Open"},
+		{"Größe Trommel", 4, "Größ"},
+		{"シンセサイザー", 3, "シンセ"},
+	}
+	for _, tt := range tests {
+		if got := truncateName(tt.name, tt.width); got != tt.want {
+			t.Errorf("truncateName(%q, %d) = %q, want %q", tt.name, tt.width, got, tt.want)
+		}
+	}
+}
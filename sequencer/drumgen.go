@@ -0,0 +1,121 @@
+package sequencer
+
+import (
+	"math/rand"
+	"strings"
+)
+
+// LaneStyle is a rhythmic hint used when generating drum patterns
+type LaneStyle int
+
+const (
+	StylePerc   LaneStyle = iota // sparse, anywhere
+	StyleKick                    // downbeats
+	StyleSnare                   // backbeats (2 and 4)
+	StyleHat                     // eighths, some sixteenths
+	StyleCymbal                  // bar starts only
+)
+
+var laneStyleNames = []string{"perc", "kick", "snare", "hat", "cymbal"}
+
+func (s LaneStyle) String() string {
+	if int(s) < len(laneStyleNames) {
+		return laneStyleNames[s]
+	}
+	return "?"
+}
+
+// Randomize limits
+const (
+	DefaultRandomDensity = 50 // percent
+	randomDensityStep    = 5
+)
+
+// laneStyleFor guesses a style from a lane's name (kit slot name or user name)
+func laneStyleFor(name string) LaneStyle {
+	n := strings.ToLower(name)
+	switch {
+	case strings.Contains(n, "kick"), strings.Contains(n, "bd"):
+		return StyleKick
+	case strings.Contains(n, "snare"), strings.Contains(n, "clap"), strings.Contains(n, "rim"):
+		return StyleSnare
+	case strings.Contains(n, "hh"), strings.Contains(n, "hat"), strings.Contains(n, "ride"),
+		strings.Contains(n, "shaker"), strings.Contains(n, "maracas"):
+		return StyleHat
+	case strings.Contains(n, "crash"), strings.Contains(n, "cymbal"):
+		return StyleCymbal
+	}
+	return StylePerc
+}
+
+// stepWeight returns how likely (0-1) a style is to hit on a step at density 50
+func stepWeight(style LaneStyle, step int) float64 {
+	switch style {
+	case StyleKick:
+		if step%8 == 0 {
+			return 1.0
+		} else if step%4 == 0 {
+			return 0.6
+		} else if step%2 == 0 {
+			return 0.15
+		}
+		return 0.05
+	case StyleSnare:
+		if step%8 == 4 {
+			return 1.0
+		} else if step%2 == 1 {
+			return 0.08
+		}
+		return 0.04
+	case StyleHat:
+		if step%2 == 0 {
+			return 0.9
+		}
+		return 0.3
+	case StyleCymbal:
+		if step%16 == 0 {
+			return 0.5
+		}
+		return 0
+	}
+	return 0.15
+}
+
+// rollStep decides whether a step hits and with what velocity
+func rollStep(style LaneStyle, step, density int) (bool, uint8) {
+	w := stepWeight(style, step)
+	if rand.Float64() >= w*float64(density)/50 {
+		return false, 0
+	}
+	// Accent strong positions
+	vel := 70 + rand.Intn(30)
+	if w >= 0.9 {
+		vel = 100 + rand.Intn(20)
+	}
+	return true, uint8(vel)
+}
+
+// generateLane fills a lane from scratch
+func generateLane(note *DrumNoteState, style LaneStyle, density int) {
+	for step := 0; step < note.Length; step++ {
+		active, vel := rollStep(style, step, density)
+		note.Steps[step].Active = active
+		if active {
+			note.Steps[step].Velocity = vel
+		}
+	}
+}
+
+// mutateLane re-rolls a fraction of a lane's steps (density/4 percent of them)
+func mutateLane(note *DrumNoteState, style LaneStyle, density int) {
+	for step := 0; step < note.Length; step++ {
+		if rand.Intn(400) >= density {
+			continue
+		}
+		active, vel := rollStep(style, step, density)
+		note.Steps[step].Active = active
+		if active {
+			note.Steps[step].Velocity = vel
+		}
+	}
+}
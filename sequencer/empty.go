@@ -27,6 +27,8 @@ func (e *EmptyDevice) QueuePattern(p int, atTick int64) {}
 func (e *EmptyDevice) CurrentPattern() int            { return 0 }
 func (e *EmptyDevice) NextPattern() int               { return -1 }
 func (e *EmptyDevice) ContentMask() []bool            { return make([]bool, NumPatterns) }
+func (e *EmptyDevice) Density() []float64             { return make([]float64, NumPatterns) }
+func (e *EmptyDevice) PatternBars() []float64            { return make([]float64, NumPatterns) }
 
 func (e *EmptyDevice) HandleMIDI(event midi.Event) {}
 
@@ -52,33 +54,33 @@ func (e *EmptyDevice) View() string {
 
 	// Launchpad
 	out += "\n\n"
-	out += e.renderLaunchpadHelp()
+	out += widgets.RenderLayout(e.HelpLayout())
 
 	return out
 }
 
-func (e *EmptyDevice) renderLaunchpadHelp() string {
+// HelpLayout shows an all-dim Launchpad with no active pads
+func (e *EmptyDevice) HelpLayout() widgets.LaunchpadLayout {
 	dimColor := [3]uint8{40, 40, 40}
 
-	var grid [8][8][3]uint8
-	var rightCol [8][3]uint8
-	topRow := make([][3]uint8, 8)
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
 
 	for i := 0; i < 8; i++ {
-		topRow[i] = dimColor
-		rightCol[i] = dimColor
+		l.TopRow[i] = widgets.Pad{Color: dimColor}
+		rightCol[i] = widgets.Pad{Color: dimColor}
 	}
 	for row := 0; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			grid[row][col] = dimColor
+			l.Grid[row][col] = widgets.Pad{Color: dimColor}
 		}
 	}
 
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, &rightCol) + "\n\n"
-	out += widgets.RenderLegendItem(dimColor, "Empty", "no device assigned")
-
-	return out
+	l.Legend = []widgets.LegendItem{
+		{Color: dimColor, Name: "Empty", Desc: "no device assigned"},
+	}
+	return l
 }
 
 func (e *EmptyDevice) RenderLEDs() []LEDState {
@@ -99,6 +101,9 @@ func (e *EmptyDevice) HandleKey(key string) {
 	// Nothing to do - empty device has no controls
 }
 
-func (e *EmptyDevice) HandlePad(row, col int) {
+func (e *EmptyDevice) HandlePadRelease(row, col int) {}
+func (e *EmptyDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (e *EmptyDevice) HandlePad(row, col int, velocity uint8) {
 	// Nothing to do
 }
@@ -0,0 +1,265 @@
+package sequencer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+)
+
+// Exploded saves - a project can save as a tree of small JSON files instead of
+// timestamped snapshots, for version control with readable diffs (`e` in the save
+// device). Each save rewrites <project>/exploded/:
+//
+//	project.json                       tempo, macros, mappings, ... (everything but the tracks)
+//	track-N/track.json                 the track's settings and clip labels
+//	track-N/<device>.json              device state without its patterns (drum, piano or metropolix)
+//	track-N/patterns/<device>/NNN.json each pattern that isn't empty
+//
+// A track keeps the state of every device it has held, so each device's patterns get
+// their own folder. Keys are sorted, devices go in a fixed order and files end in a
+// newline, so an edit shows up as a small diff in the one pattern it touched. The tree
+// is listed with the saves as "exploded". Trees from before the per-device folders
+// (patterns/NNN.json) load their patterns into the track's current device.
+
+// explodedDir is the tree's folder in the project (and its filename in the save list)
+const explodedDir = "exploded"
+
+// explodedDevices are the device keys in a track's JSON with their fresh states, in
+// the order they're written
+var explodedDevices = []struct {
+	key   string
+	typ   DeviceType
+	fresh func() any
+}{
+	{"drum", DeviceTypeDrum, func() any { return NewDrumState() }},
+	{"piano", DeviceTypePiano, func() any { return NewPianoState() }},
+	{"metropolix", DeviceTypeMetropolix, func() any { return NewMetropolixState() }},
+}
+
+// jsonObject converts a value to a generic JSON object (numbers kept exact)
+func jsonObject(v any) (map[string]any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// writeJSONFile writes a value indented with sorted keys and a trailing newline
+func writeJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// readJSONFile reads a generic JSON object (nil if the file doesn't exist)
+func readJSONFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return obj, nil
+}
+
+// patternFile is a device pattern's path in a track's folder (numbered from 1)
+func patternFile(dir, key string, p int) string {
+	return filepath.Join(dir, "patterns", key, fmt.Sprintf("%03d.json", p+1))
+}
+
+// writeExploded rewrites the project's exploded tree from the state
+func writeExploded(projectName string) error {
+	dir, err := ProjectDir(projectName)
+	if err != nil {
+		return err
+	}
+	// Build the tree next to the old one, then swap it in
+	tmp := filepath.Join(dir, explodedDir+".tmp")
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return err
+	}
+
+	project, err := jsonObject(S)
+	if err != nil {
+		return err
+	}
+	delete(project, "tracks")
+	if err := writeJSONFile(filepath.Join(tmp, "project.json"), project); err != nil {
+		return err
+	}
+	for i, ts := range S.Tracks {
+		if err := writeExplodedTrack(filepath.Join(tmp, fmt.Sprintf("track-%d", i+1)), ts); err != nil {
+			return err
+		}
+	}
+
+	final := filepath.Join(dir, explodedDir)
+	if err := os.RemoveAll(final); err != nil {
+		return err
+	}
+	return os.Rename(tmp, final)
+}
+
+// writeExplodedTrack writes one track's folder
+func writeExplodedTrack(dir string, ts *TrackState) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	track, err := jsonObject(ts)
+	if err != nil {
+		return err
+	}
+
+	// Clip labels by pattern number, leaving out unlabeled slots
+	labels := map[string]ClipLabel{}
+	for p, label := range ts.Clips {
+		if label != (ClipLabel{}) {
+			labels[fmt.Sprintf("%03d", p+1)] = label
+		}
+	}
+	delete(track, "clips")
+	if len(labels) > 0 {
+		track["clipLabels"] = labels
+	}
+
+	// Device state, with only the patterns that differ from a fresh device's
+	for _, ed := range explodedDevices {
+		dev, ok := track[ed.key].(map[string]any)
+		if !ok {
+			continue
+		}
+		delete(track, ed.key)
+		defaults, err := jsonObject(ed.fresh())
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Join(dir, "patterns", ed.key), 0755); err != nil {
+			return err
+		}
+		patterns, _ := dev["patterns"].([]any)
+		empty, _ := defaults["patterns"].([]any)
+		delete(dev, "patterns")
+		for p, pat := range patterns {
+			if p < len(empty) && reflect.DeepEqual(pat, empty[p]) {
+				continue
+			}
+			if err := writeJSONFile(patternFile(dir, ed.key, p), pat); err != nil {
+				return err
+			}
+		}
+		if err := writeJSONFile(filepath.Join(dir, ed.key+".json"), dev); err != nil {
+			return err
+		}
+	}
+	return writeJSONFile(filepath.Join(dir, "track.json"), track)
+}
+
+// readExploded assembles a State from an exploded tree
+func readExploded(dir string) (*State, error) {
+	project, err := readJSONFile(filepath.Join(dir, "project.json"))
+	if err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, fmt.Errorf("no project.json in %s", dir)
+	}
+
+	tracks := make([]any, 8)
+	for i := range tracks {
+		track, err := readExplodedTrack(filepath.Join(dir, fmt.Sprintf("track-%d", i+1)))
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", i+1, err)
+		}
+		tracks[i] = track
+	}
+	project["tracks"] = tracks
+
+	data, err := json.Marshal(project)
+	if err != nil {
+		return nil, err
+	}
+	st := NewState()
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// readExplodedTrack reassembles one track's JSON from its folder
+func readExplodedTrack(dir string) (map[string]any, error) {
+	track, err := readJSONFile(filepath.Join(dir, "track.json"))
+	if err != nil {
+		return nil, err
+	}
+	if track == nil {
+		track = map[string]any{}
+	}
+
+	clips := map[string]any{}
+	labels, _ := track["clipLabels"].(map[string]any)
+	for p := range NumPatterns {
+		if label, ok := labels[fmt.Sprintf("%03d", p+1)]; ok {
+			clips[strconv.Itoa(p)] = label
+		}
+	}
+	delete(track, "clipLabels")
+	if len(clips) > 0 {
+		track["clips"] = clips
+	}
+
+	for _, ed := range explodedDevices {
+		dev, err := readJSONFile(filepath.Join(dir, ed.key+".json"))
+		if err != nil {
+			return nil, err
+		}
+		if dev == nil {
+			continue
+		}
+		defaults, err := jsonObject(ed.fresh())
+		if err != nil {
+			return nil, err
+		}
+		patterns, _ := defaults["patterns"].([]any)
+		legacy := false
+		if _, err := os.Stat(filepath.Join(dir, "patterns", ed.key)); os.IsNotExist(err) {
+			legacy = track["type"] == string(ed.typ)
+		}
+		for p := range patterns {
+			path := patternFile(dir, ed.key, p)
+			if legacy {
+				path = filepath.Join(dir, "patterns", filepath.Base(path))
+			}
+			pat, err := readJSONFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if pat != nil {
+				patterns[p] = pat
+			}
+		}
+		dev["patterns"] = patterns
+		track[ed.key] = dev
+	}
+	return track, nil
+}
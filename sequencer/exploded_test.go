@@ -0,0 +1,105 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// explodedTrack builds a track that held a Metropolix and then a drum device, with an
+// edited first pattern on each
+func explodedTrack() *TrackState {
+	ts := &TrackState{Channel: 3, Type: DeviceTypeDrum, Drum: NewDrumState(), Metropolix: NewMetropolixState()}
+	ts.Drum.Patterns[0].Notes[2].Steps[4] = DrumStepState{Active: true, Velocity: 90}
+	ts.Metropolix.Patterns[0].RootNote = 50
+	ts.Metropolix.Patterns[5].Length = 3
+	ts.Clips = ClipLabels{7: {Name: "verse"}, 9: {Color: 2}}
+	return ts
+}
+
+// roundTrip writes a track to an exploded folder and reads it back
+func roundTrip(t *testing.T, dir string, ts *TrackState) *TrackState {
+	t.Helper()
+	if err := writeExplodedTrack(dir, ts); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := readExplodedTrack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got TrackState
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	return &got
+}
+
+func TestExplodedRoundTrip(t *testing.T) {
+	ts := explodedTrack()
+	got := roundTrip(t, t.TempDir(), ts)
+	if !reflect.DeepEqual(got, ts) {
+		t.Errorf("track changed in the round trip:\n got %+v\nwant %+v", got, ts)
+	}
+}
+
+func TestExplodedDeterministic(t *testing.T) {
+	ts := explodedTrack()
+	first, second := t.TempDir(), t.TempDir()
+	for _, dir := range []string{first, second} {
+		if err := writeExplodedTrack(dir, ts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := filepath.Walk(first, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, _ := filepath.Rel(first, path)
+		want, _ := os.ReadFile(path)
+		got, err := os.ReadFile(filepath.Join(second, rel))
+		if err != nil {
+			return err
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s differs between saves", rel)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExplodedLegacyPatterns(t *testing.T) {
+	// Trees from before the per-device folders kept patterns/NNN.json
+	ts := &TrackState{Type: DeviceTypeDrum, Drum: NewDrumState()}
+	ts.Drum.Patterns[1].Notes[0].Steps[0] = DrumStepState{Active: true, Velocity: 100}
+	dir := t.TempDir()
+	if err := writeExplodedTrack(dir, ts); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(patternFile(dir, "drum", 1), filepath.Join(dir, "patterns", "002.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "patterns", "drum")); err != nil {
+		t.Fatal(err)
+	}
+	obj, err := readExplodedTrack(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(obj)
+	var got TrackState
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Drum == nil || got.Drum.Patterns[1] != ts.Drum.Patterns[1] {
+		t.Error("legacy pattern file wasn't loaded into the track's device")
+	}
+}
@@ -0,0 +1,74 @@
+package sequencer
+
+import (
+	"go-sequence/midi"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+)
+
+// Faders - a controller's faders or knobs (APC Mini, APC Key 25, the Launchpad X's DAW
+// faders on the mixer page - see mixer.go) send a CC on each track's port and channel:
+// track volume unless the track's Fader column in Settings picks another CC. The master
+// fader (APC Mini) moves the focused track's.
+
+// DefaultFaderCC is what a fader sends when the track doesn't pick a CC (channel volume)
+const DefaultFaderCC = 7
+
+// masterFader is the index of the fader right of the eight track faders
+const masterFader = 8
+
+// FaderCC returns the CC a track's fader sends
+func (ts *TrackState) FaderCC() uint8 {
+	if ts.SendCC > 0 {
+		return uint8(ts.SendCC)
+	}
+	return DefaultFaderCC
+}
+
+// NudgeSendCC changes the CC a track's fader sends (from its current one, 1-119 -
+// channel mode messages start at 120)
+func (s *State) NudgeSendCC(track, delta int) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	s.Tracks[track].SendCC = clamp(int(s.Tracks[track].FaderCC())+delta, 1, 119)
+}
+
+// listenFaders consumes a controller's fader moves until it is closed
+func (m *Manager) listenFaders(fc midi.FaderController) {
+	for evt := range fc.FaderEvents() {
+		m.HandleFader(evt)
+	}
+}
+
+// HandleFader sends a fader's value on its track
+func (m *Manager) HandleFader(evt midi.FaderEvent) {
+	track := evt.Index
+	if track == masterFader {
+		track = m.getFocusedTrackIdx()
+	}
+	if track < 0 || track >= 8 {
+		return
+	}
+	m.SetTrackLevel(track, evt.Value)
+}
+
+// SetTrackLevel sends a track's fader CC with a value
+func (m *Manager) SetTrackLevel(track int, value uint8) {
+	m.mu.RLock()
+	ts := S.Tracks[track]
+	cc, ch := ts.FaderCC(), ts.Channel-1
+	m.mu.RUnlock()
+
+	sender := m.getSender(m.TrackPort(track))
+	if sender == nil {
+		return
+	}
+	if err := sender(gomidi.ControlChange(ch, cc, value)); err != nil {
+		return
+	}
+	if m.mixer != nil {
+		m.mixer.setLevel(track, value, m.controller)
+	}
+	m.announce("track %d cc %d: %d", track+1, cc, value)
+}
@@ -0,0 +1,50 @@
+package sequencer
+
+// Feel - pattern swing delays notes on the off-beat steps by a share of a step, and the
+// device-wide gate scale stretches or shortens every stage's gate length, so the feel
+// can change without editing each stage.
+
+// Swing and gate scale limits (percent)
+const (
+	maxSwing      = 50
+	swingStep     = 5
+	minGateScale  = 25
+	maxGateScale  = 200
+	gateScaleStep = 25
+)
+
+// Settings page pad values (row 7 and row 6, cols 4-7)
+var (
+	swingPadValues     = []int{0, 16, 33, 50}
+	gateScalePadValues = []int{50, 75, 100, 150}
+)
+
+// gateScale returns the gate length multiplier in percent
+func (s *MetropolixState) gateScale() int {
+	if s.GateScale == 0 {
+		return 100
+	}
+	return s.GateScale
+}
+
+// swingDelay returns how late a hit at tick plays: off-beat steps (of step ticks) are
+// pushed by swing percent of a step, everything else stays put
+func swingDelay(tick int64, swing int, step int64) int64 {
+	if swing <= 0 || tick%(2*step) != step {
+		return 0
+	}
+	return step * int64(swing) / 100
+}
+
+// nudgeSwing changes the editing pattern's swing
+func (d *MetropolixDevice) nudgeSwing(delta int) {
+	pat := &d.state.Patterns[d.state.Editing]
+	pat.Swing = clamp(pat.Swing+delta, 0, maxSwing)
+	d.regeneratePatternInQueue(d.state.Editing)
+}
+
+// nudgeGateScale changes the gate length multiplier
+func (d *MetropolixDevice) nudgeGateScale(delta int) {
+	d.state.GateScale = clamp(d.state.gateScale()+delta, minGateScale, maxGateScale)
+	d.regeneratePatternInQueue(d.state.Pattern)
+}
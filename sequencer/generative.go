@@ -0,0 +1,82 @@
+package sequencer
+
+import "math/rand"
+
+// Generative set mode - at every boundary of GenerativeBars bars, each track rolls
+// its Relaunch chance and, on a hit, launches one of its non-empty patterns at random
+
+// GenerativeBarOptions are the boundary lengths the session cycles through
+var GenerativeBarOptions = []int{1, 2, 4, 8, 16}
+
+// Generative defaults
+const (
+	DefaultGenerativeBars = 4
+	relaunchStep          = 10 // percent per key press
+)
+
+// GenBars returns the generative boundary length in bars
+func (s *State) GenBars() int {
+	if s.GenerativeBars <= 0 {
+		return DefaultGenerativeBars
+	}
+	return s.GenerativeBars
+}
+
+// CycleGenerativeBars steps to the next boundary length
+func (s *State) CycleGenerativeBars() {
+	cur := s.GenBars()
+	for i, bars := range GenerativeBarOptions {
+		if bars == cur {
+			s.GenerativeBars = GenerativeBarOptions[(i+1)%len(GenerativeBarOptions)]
+			return
+		}
+	}
+	s.GenerativeBars = DefaultGenerativeBars
+}
+
+// NudgeRelaunch changes a track's relaunch chance by delta percent (0-100)
+func (s *State) NudgeRelaunch(track, delta int) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	s.Tracks[track].Relaunch = clamp(s.Tracks[track].Relaunch+delta, 0, 100)
+}
+
+// checkGenerative relaunches clips when playback crosses a generative boundary.
+// Followers leave this to the leader, whose launches reach them over sync.
+func (m *Manager) checkGenerative() {
+	m.mu.RLock()
+	active := S.Playing && S.Generative
+	boundary := S.Tick / (int64(S.GenBars()) * 4 * PPQ)
+	m.mu.RUnlock()
+
+	if !active || m.IsFollower() {
+		m.genBoundary = -1
+		return
+	}
+	if boundary == m.genBoundary {
+		return
+	}
+	first := m.genBoundary < 0 // just started - don't relaunch on the downbeat of play
+	m.genBoundary = boundary
+	if first {
+		return
+	}
+
+	for i := 0; i < 8; i++ {
+		chance := S.Tracks[i].Relaunch
+		dev := m.GetDevice(i)
+		if dev == nil || chance <= 0 || rand.Intn(100) >= chance {
+			continue
+		}
+		var candidates []int
+		for p, has := range dev.ContentMask() {
+			if has {
+				candidates = append(candidates, p)
+			}
+		}
+		if len(candidates) > 0 {
+			m.QueuePattern(i, candidates[rand.Intn(len(candidates))])
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package sequencer
+
+import (
+	"fmt"
+	"math"
+)
+
+// Ghost notes - another piano roll track's playing pattern drawn dimmed under the
+// editing one (e.g. the bass while writing a lead). Shorter ghost patterns repeat
+// across the editing pattern, the way they'd sound together - only their loop region,
+// when one is set, since that's all that plays.
+
+// ghostPattern returns the ghost track's playing pattern, or nil when off
+func (p *PianoRollDevice) ghostPattern() *PianoPatternState {
+	g := p.state.Ghost - 1
+	if g < 0 || g >= len(S.Tracks) || S.Tracks[g] == nil {
+		return nil
+	}
+	ps := S.Tracks[g].Piano
+	if ps == nil || ps == p.state {
+		return nil
+	}
+	return &ps.Patterns[ps.Pattern]
+}
+
+// ghostAt reports whether a ghost note of this pitch sounds anywhere in [from, to)
+func (p *PianoRollDevice) ghostAt(ghost *PianoPatternState, pitch uint8, from, to float64) bool {
+	if ghost == nil {
+		return false
+	}
+	loopStart, loopEnd := ghost.Loop()
+	span := loopEnd - loopStart
+	if span <= 0 {
+		return false
+	}
+	// Check each repeat of the ghost loop the range touches
+	for rep := math.Floor(from / span); rep*span < to; rep++ {
+		offset := rep*span - loopStart
+		for _, n := range ghost.Notes {
+			if n.Pitch != pitch || n.Start < loopStart || n.Start >= loopEnd {
+				continue
+			}
+			if offset+n.Start < to && offset+min(n.Start+n.Duration, loopEnd) > from {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cycleGhost steps the ghost source through the other piano roll tracks, then off
+func (p *PianoRollDevice) cycleGhost() {
+	s := p.state
+	for g := s.Ghost + 1; g <= len(S.Tracks); g++ {
+		if ts := S.Tracks[g-1]; ts != nil && ts.Piano != nil && ts.Piano != s {
+			s.Ghost = g
+			return
+		}
+	}
+	s.Ghost = 0
+}
+
+// ghostName describes the ghost source for the header
+func (p *PianoRollDevice) ghostName() string {
+	if p.ghostPattern() == nil {
+		return "off"
+	}
+	return fmt.Sprintf("T%d", p.state.Ghost)
+}
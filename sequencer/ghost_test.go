@@ -0,0 +1,42 @@
+package sequencer
+
+import "testing"
+
+func TestGhostAt(t *testing.T) {
+	ghost := &PianoPatternState{
+		Length: 8,
+		Notes: []NoteEventState{
+			{Start: 1, Duration: 1, Pitch: 60},
+			{Start: 4, Duration: 3, Pitch: 62},
+			{Start: 6, Duration: 1, Pitch: 64},
+		},
+	}
+	looped := *ghost
+	looped.LoopStart, looped.LoopEnd = 4, 6
+
+	tests := []struct {
+		name     string
+		ghost    *PianoPatternState
+		pitch    uint8
+		from, to float64
+		want     bool
+	}{
+		{"whole pattern", ghost, 60, 1, 1.5, true},
+		{"second repeat", ghost, 60, 9, 9.5, true},
+		{"between notes", ghost, 60, 2, 4, false},
+		{"loop starts at its start", &looped, 62, 0, 0.5, true},
+		{"loop repeats on its span", &looped, 62, 2, 2.5, true},
+		{"note clipped at the loop end", &looped, 62, 1.5, 2, true},
+		{"outside the loop is silent", &looped, 60, 0, 8, false},
+		{"past the loop end is silent", &looped, 64, 0, 8, false},
+		{"off", nil, 60, 0, 8, false},
+	}
+	p := NewPianoRollDevice(NewPianoState())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.ghostAt(tt.ghost, tt.pitch, tt.from, tt.to); got != tt.want {
+				t.Errorf("ghostAt(%d, %v, %v) = %v, want %v", tt.pitch, tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package sequencer
+
+import (
+	"go-sequence/midi"
+)
+
+// Keyboard hotkeys - notes or CCs from the note input keyboard can run the macro pad
+// actions (play/stop, record, tap tempo, scene launch, mute, focus, snapshot recall), so
+// a small controller's pads drive the session without a Launchpad. They're set per keyboard in
+// the config (controller "hotkeys"); a hotkey note is swallowed rather than played.
+
+// Hotkey makes a note or CC from the note input run a macro action
+type Hotkey struct {
+	Note    int // note number (-1 = a CC hotkey)
+	CC      int // controller number (-1 = a note hotkey)
+	Channel int // 1-16 (0 = any)
+	Macro   MacroBinding
+}
+
+// matchesChannel reports whether a hotkey listens on a channel (0-15)
+func (h Hotkey) matchesChannel(channel uint8) bool {
+	return h.Channel == 0 || h.Channel == int(channel)+1
+}
+
+// SetHotkeys sets the note input's hotkeys (port is the keyboard's, for its CCs)
+func (m *Manager) SetHotkeys(port string, hotkeys []Hotkey) {
+	m.hotkeyMu.Lock()
+	m.hotkeyPort, m.hotkeys = port, hotkeys
+	m.hotkeyMu.Unlock()
+}
+
+// noteHotkey runs the hotkey on a note (false if the note isn't one - it plays)
+func (m *Manager) noteHotkey(evt midi.NoteEvent) bool {
+	if evt.Type != midi.NoteOn {
+		return false
+	}
+	m.hotkeyMu.Lock()
+	var hits []MacroBinding
+	for _, h := range m.hotkeys {
+		if h.Note == int(evt.Note) && h.matchesChannel(evt.Channel) {
+			hits = append(hits, h.Macro)
+		}
+	}
+	m.hotkeyMu.Unlock()
+	if len(hits) == 0 {
+		return false
+	}
+	if evt.Velocity > 0 { // note-offs are swallowed too
+		for _, b := range hits {
+			m.RunMacro(b)
+		}
+		m.notifyUpdate()
+	}
+	return true
+}
+
+// ccHotkey runs the hotkeys on a CC from the note input when it's pressed (false if
+// the CC isn't one)
+func (m *Manager) ccHotkey(evt midi.CCEvent, pressed bool) bool {
+	m.hotkeyMu.Lock()
+	var hits []MacroBinding
+	if evt.Port == m.hotkeyPort {
+		for _, h := range m.hotkeys {
+			if h.CC == int(evt.Controller) && h.matchesChannel(evt.Channel) {
+				hits = append(hits, h.Macro)
+			}
+		}
+	}
+	m.hotkeyMu.Unlock()
+	if len(hits) == 0 {
+		return false
+	}
+	if pressed {
+		for _, b := range hits {
+			m.RunMacro(b)
+		}
+		m.notifyUpdate()
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package sequencer
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Dispatch timing - the output loop sleeps on a timer until a millisecond before an
+// event is due, then spins on the clock for the rest, since timer wake-ups alone land
+// late by a scheduler tick or so (audible as uneven ratchets at high tempos). How late
+// each event actually went out against its scheduled send time is kept in a ring
+// buffer, summed up at the bottom of the MIDI monitor (`c` there clears it).
+
+// spinWindow is how long before an event the wait stops sleeping and spins
+const spinWindow = time.Millisecond
+
+// jitterSize is how many send times the stats cover
+const jitterSize = 1024
+
+// jitterStats is the ring buffer of dispatch lateness (written by the output loop,
+// read by the monitor view)
+type jitterStats struct {
+	mu    sync.Mutex
+	late  [jitterSize]time.Duration // actual minus scheduled send time (negative = early)
+	next  int                       // slot the next sample goes in
+	count int                       // samples held (up to jitterSize)
+}
+
+// record logs how late an event went out
+func (js *jitterStats) record(late time.Duration) {
+	js.mu.Lock()
+	js.late[js.next] = late
+	js.next = (js.next + 1) % jitterSize
+	js.count = min(js.count+1, jitterSize)
+	js.mu.Unlock()
+}
+
+// clear empties the buffer
+func (js *jitterStats) clear() {
+	js.mu.Lock()
+	js.count = 0
+	js.next = 0 // summary reads the first count slots
+	js.mu.Unlock()
+}
+
+// jitterSummary sums up the recorded lateness
+type jitterSummary struct {
+	Count          int
+	Mean, P50, P99 time.Duration
+	Max            time.Duration
+	Early          int // events sent before their time
+}
+
+// summary sums up the samples held
+func (js *jitterStats) summary() jitterSummary {
+	js.mu.Lock()
+	samples := make([]time.Duration, js.count)
+	copy(samples, js.late[:js.count]) // order doesn't matter here
+	js.mu.Unlock()
+
+	sum := jitterSummary{Count: len(samples)}
+	if len(samples) == 0 {
+		return sum
+	}
+	slices.Sort(samples)
+	var total time.Duration
+	for _, late := range samples {
+		total += late
+		if late < 0 {
+			sum.Early++
+		}
+	}
+	sum.Mean = total / time.Duration(len(samples))
+	sum.P50 = samples[len(samples)/2]
+	sum.P99 = samples[len(samples)*99/100]
+	sum.Max = samples[len(samples)-1]
+	return sum
+}
+
+// String shows the summary as one line
+func (sum jitterSummary) String() string {
+	if sum.Count == 0 {
+		return "no events sent yet"
+	}
+	ms := func(d time.Duration) string {
+		return fmt.Sprintf("%+.2fms", float64(d)/float64(time.Millisecond))
+	}
+	return fmt.Sprintf("late by median %s  p99 %s  max %s  (mean %s, %d early, last %d events)",
+		ms(sum.P50), ms(sum.P99), ms(sum.Max), ms(sum.Mean), sum.Early, sum.Count)
+}
+
+// Outcomes of waitPrecise
+type waitResult int
+
+const (
+	waitDone    waitResult = iota // the time came
+	waitWoken                     // a Start/Stop was scheduled - look again
+	waitStopped                   // the manager shut down
+)
+
+// waitPrecise sleeps until spinWindow before a time, then spins until it (the output
+// loop has its OS thread to itself)
+func (m *Manager) waitPrecise(at time.Time) waitResult {
+	if sleep := time.Until(at) - spinWindow; sleep > 0 {
+		timer := time.NewTimer(sleep)
+		select {
+		case <-m.stopChan:
+			timer.Stop()
+			return waitStopped
+		case <-m.transportWake:
+			timer.Stop()
+			return waitWoken
+		case <-timer.C:
+		}
+	}
+	for time.Now().Before(at) {
+	}
+	return waitDone
+}
@@ -0,0 +1,50 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJitterSummary(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		record func(js *jitterStats)
+		count  int
+		max    time.Duration
+		early  int
+		p50    time.Duration
+	}{
+		{"empty", func(*jitterStats) {}, 0, 0, 0, 0},
+		{"samples", func(js *jitterStats) {
+			for _, d := range []time.Duration{3 * ms, -ms, 2 * ms} {
+				js.record(d)
+			}
+		}, 3, 3 * ms, 1, 2 * ms},
+		{"cleared", func(js *jitterStats) {
+			for range 10 {
+				js.record(50 * ms)
+			}
+			js.clear()
+			js.record(ms)
+		}, 1, ms, 0, ms},
+		{"wrapped", func(js *jitterStats) {
+			for range jitterSize {
+				js.record(50 * ms)
+			}
+			for range jitterSize {
+				js.record(ms)
+			}
+		}, jitterSize, ms, 0, ms},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var js jitterStats
+			tt.record(&js)
+			sum := js.summary()
+			if sum.Count != tt.count || sum.Max != tt.max || sum.Early != tt.early || sum.P50 != tt.p50 {
+				t.Errorf("summary %+v, want count %d max %v early %d median %v", sum, tt.count, tt.max, tt.early, tt.p50)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package sequencer
+
+import (
+	"fmt"
+
+	"go-sequence/midi"
+)
+
+// Keyboard guide - the scene column mirrors the 8 pitch rows as a keyboard strip so the
+// window is navigable from the Launchpad alone: the pattern's root (C when chromatic)
+// is bright, other Cs mark octaves, white keys glow dimly and black keys stay dark.
+// Tapping the upper half scrolls up an octave, the lower half down.
+
+var (
+	guideRootColor   = [3]uint8{255, 140, 0}
+	guideOctaveColor = [3]uint8{120, 60, 255}
+	guideWhiteColor  = [3]uint8{40, 40, 40}
+	guideBlackColor  = [3]uint8{0, 0, 0}
+)
+
+// isBlackKey reports whether a pitch is a black key
+func isBlackKey(pitch int) bool {
+	switch pitch % 12 {
+	case 1, 3, 6, 8, 10:
+		return true
+	}
+	return false
+}
+
+// guideColor returns the scene column color for a pitch row
+func (pat *PianoPatternState) guideColor(pitch int) [3]uint8 {
+	switch {
+	case pitch < 0 || pitch > 127:
+		return guideBlackColor
+	case pitch%12 == int(pat.Root%12):
+		return guideRootColor
+	case pitch%12 == 0:
+		return guideOctaveColor
+	case isBlackKey(pitch):
+		return guideBlackColor
+	}
+	return guideWhiteColor
+}
+
+// guideLEDs renders the keyboard guide in the scene column
+func (p *PianoRollDevice) guideLEDs() []LEDState {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	basePitch := int(s.CenterPitch) - 4
+	leds := make([]LEDState, 0, 8)
+	for row := range 8 {
+		leds = append(leds, LEDState{Row: row, Col: 8, Color: pat.guideColor(basePitch + row), Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+// guideTooltip names the pitch on a guide row
+func (p *PianoRollDevice) guideTooltip(row int) string {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	pitch := int(s.CenterPitch) - 4 + row
+	if pitch < 0 || pitch > 127 {
+		return ""
+	}
+	noteNames := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	name := fmt.Sprintf("%s%d", noteNames[pitch%12], pitch/12)
+	if pitch%12 == int(pat.Root%12) {
+		name += " (root)"
+	}
+	if row >= 4 {
+		return name + " - tap for octave up"
+	}
+	return name + " - tap for octave down"
+}
+
+// scrollOctave moves the pitch window an octave up or down
+func (p *PianoRollDevice) scrollOctave(up bool) {
+	s := p.state
+	delta := -12.0
+	if up {
+		delta = 12
+	}
+	s.CenterPitch = max(0, min(127, s.CenterPitch+delta))
+}
@@ -0,0 +1,252 @@
+package sequencer
+
+import (
+	"fmt"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+)
+
+// Key zones - a track can filter the keyboard by channel, note range and velocity, so
+// one split keyboard plays several tracks at once (lower octaves to the bass, upper to
+// the lead). Zoned tracks take their notes whether or not they're focused; notes no zone
+// takes go to the focused track as before. A note-off follows its note-on, so moving a
+// zone or the focus while a key is held doesn't leave the note hanging.
+
+// KeyZone is a track's keyboard input filter
+type KeyZone struct {
+	Channel     int   `json:"channel,omitempty"`     // input channel 1-16 (0 = any)
+	Low         uint8 `json:"low"`                   // lowest note taken
+	High        uint8 `json:"high"`                  // highest note taken
+	MinVelocity uint8 `json:"minVelocity,omitempty"` // softer notes are ignored (0 = all)
+}
+
+// zoneVelocityStep is how far one key press moves a zone's velocity threshold
+const zoneVelocityStep = 8
+
+// keyRouteFocused marks a held note that went to the focused track (bits 0-7 are the
+// zoned tracks it went to)
+const keyRouteFocused = 1 << 8
+
+// Takes reports whether a note-on passes the zone (ch is the 0-based input channel)
+func (z *KeyZone) Takes(ch, note, velocity uint8) bool {
+	if z.Channel > 0 && int(ch) != z.Channel-1 {
+		return false
+	}
+	return note >= z.Low && note <= z.High && velocity >= z.MinVelocity
+}
+
+// String describes the zone ("C2-B3 ch1 v>=20")
+func (z *KeyZone) String() string {
+	s := pitchName(int(z.Low)) + "-" + pitchName(int(z.High))
+	if z.Channel > 0 {
+		s += fmt.Sprintf(" ch%d", z.Channel)
+	}
+	if z.MinVelocity > 0 {
+		s += fmt.Sprintf(" v>=%d", z.MinVelocity)
+	}
+	return s
+}
+
+// keyRoute picks where a keyboard note-on goes: a bit per zoned track that takes it,
+// plus keyRouteFocused when no zone does
+func keyRoute(ch, note, velocity uint8) uint16 {
+	var route uint16
+	for i, ts := range S.Tracks {
+		if ts.Zone != nil && ts.Zone.Takes(ch, note, velocity) {
+			route |= 1 << i
+		}
+	}
+	if route == 0 {
+		route = keyRouteFocused
+	}
+	return route
+}
+
+// HandleNote handles live MIDI input (ch is the 0-based input channel): echo
+// immediately, then record. Velocity 0 is a note-off.
+func (m *Manager) HandleNote(ch, note, velocity uint8) {
+	eventType := midi.NoteOn
+	if velocity == 0 {
+		eventType = midi.NoteOff
+	}
+
+	// Note-offs go wherever their note-on went (nowhere if it was filtered out)
+	ch &= 0x0F
+	var route uint16
+	if eventType == midi.NoteOn {
+		route = keyRoute(ch, note, velocity)
+		m.keyRoutes[ch][note] = route
+	} else {
+		route = m.keyRoutes[ch][note]
+		m.keyRoutes[ch][note] = 0
+	}
+	if route == 0 {
+		return
+	}
+
+	// Calculate tick from wall clock
+	tick := int64(0)
+	if S.Playing {
+		tick = S.TimeToTick(time.Now())
+	}
+	evt := midi.Event{
+		Tick:     tick,
+		Type:     eventType,
+		Note:     note,
+		Velocity: velocity,
+	}
+	capture := m.captureTrack()
+
+	for i := 0; i < 8; i++ {
+		if route&(1<<i) == 0 {
+			continue
+		}
+		m.echoEvent(i, evt)
+		// Send to the capture take or the device for recording (with tick)
+		if i == capture && m.captureInput(evt) {
+			continue
+		}
+		if dev := m.devices[i]; dev != nil {
+			dev.HandleMIDI(evt)
+		}
+	}
+
+	if route&keyRouteFocused != 0 {
+		// Find which track is focused (or being captured into) and use its output settings
+		focusedIdx := m.getFocusedTrackIdx()
+		if capture >= 0 {
+			focusedIdx = capture
+		}
+		if focusedIdx >= 0 {
+			m.echoEvent(focusedIdx, evt)
+		}
+		if !m.captureInput(evt) && m.focused != nil {
+			m.focused.HandleMIDI(evt)
+		}
+	}
+	m.notifyUpdate()
+}
+
+// HandleExpression handles live aftertouch and pitch bend: poly pressure follows its
+// note to the tracks it went to, channel pressure and bend go to every track holding a
+// note from that channel (the focused track when none is)
+func (m *Manager) HandleExpression(in midi.NoteEvent) {
+	ch := in.Channel & 0x0F
+	var route uint16
+	if in.Type == midi.PolyPressure {
+		route = m.keyRoutes[ch][in.Note&0x7F]
+	} else {
+		for _, r := range m.keyRoutes[ch] {
+			route |= r
+		}
+		if route == 0 {
+			route = keyRouteFocused
+		}
+	}
+	if route == 0 {
+		return
+	}
+
+	tick := int64(0)
+	if S.Playing {
+		tick = S.TimeToTick(time.Now())
+	}
+	evt := midi.Event{
+		Tick:      tick,
+		Type:      in.Type,
+		Note:      in.Note,
+		Velocity:  in.Velocity,
+		BendValue: in.Bend,
+	}
+
+	for i := 0; i < 8; i++ {
+		if route&(1<<i) == 0 {
+			continue
+		}
+		m.echoEvent(i, evt)
+		if dev := m.devices[i]; dev != nil {
+			dev.HandleMIDI(evt)
+		}
+	}
+	if route&keyRouteFocused != 0 {
+		focusedIdx := m.getFocusedTrackIdx()
+		if c := m.captureTrack(); c >= 0 {
+			focusedIdx = c
+		}
+		if focusedIdx >= 0 {
+			m.echoEvent(focusedIdx, evt)
+		}
+		if m.focused != nil {
+			m.focused.HandleMIDI(evt)
+		}
+	}
+}
+
+// echoEvent plays a keyboard note, aftertouch or bend on a track's output right away
+// (bypassing the queue for low latency)
+func (m *Manager) echoEvent(trackIdx int, evt midi.Event) {
+	ts := S.Tracks[trackIdx]
+	portName := ts.PortName
+	if portName == "" {
+		portName = m.defaultPort
+	}
+	sender := m.getSender(portName)
+	if sender == nil {
+		return
+	}
+	midiCh := ts.Channel - 1
+	switch evt.Type {
+	case midi.NoteOn:
+		sender(gomidi.NoteOn(midiCh, evt.Note, evt.Velocity))
+	case midi.NoteOff:
+		sender(gomidi.NoteOff(midiCh, evt.Note))
+	case midi.PolyPressure:
+		sender(gomidi.PolyAfterTouch(midiCh, evt.Note, evt.Velocity))
+	case midi.Pressure:
+		sender(gomidi.AfterTouch(midiCh, evt.Velocity))
+	case midi.PitchBend:
+		sender(gomidi.Pitchbend(midiCh, evt.BendValue))
+	}
+}
+
+// --- Routing matrix editing ---
+
+// toggleZone gives the cursor track a zone (the whole keyboard on any channel, to be
+// narrowed) or takes it away
+func (r *RoutingDevice) toggleZone() {
+	if r.cursorRow == routingKeyboardRow {
+		return
+	}
+	ts := S.Tracks[r.cursorRow]
+	if ts.Zone != nil {
+		ts.Zone = nil
+		return
+	}
+	ts.Zone = &KeyZone{Low: 0, High: 127}
+}
+
+// nudgeZone steps the cursor track's zone: its low and high notes, input channel
+// (0 = any) and velocity threshold
+func (r *RoutingDevice) nudgeZone(low, high, channel, velocity int) {
+	if r.cursorRow == routingKeyboardRow {
+		return
+	}
+	z := S.Tracks[r.cursorRow].Zone
+	if z == nil {
+		return
+	}
+	z.Low = uint8(clamp(int(z.Low)+low, 0, 127))
+	z.High = uint8(clamp(int(z.High)+high, 0, 127))
+	// Moving one end past the other drags it along
+	if low != 0 && z.High < z.Low {
+		z.High = z.Low
+	}
+	if high != 0 && z.Low > z.High {
+		z.Low = z.High
+	}
+	z.Channel = (z.Channel + channel + 17) % 17
+	z.MinVelocity = uint8(clamp(int(z.MinVelocity)+velocity, 0, 127))
+}
@@ -2,27 +2,30 @@ package sequencer
 
 // DrumKit maps 16 drum slots to MIDI notes
 type DrumKit struct {
-	Name  string
-	Notes [16]uint8
+	Name      string
+	Notes     [16]uint8
+	SlotNames [16]string // optional per-kit slot names (falls back to DefaultSlotNames)
 }
 
-// Slot names for reference (not used in code, just documentation)
-// 0: Kick
-// 1: Snare
-// 2: Closed HH
-// 3: Open HH
-// 4: Low Tom
-// 5: Mid Tom
-// 6: High Tom
-// 7: Crash
-// 8: Ride
-// 9: Clap
-// 10: Rimshot
-// 11: Cowbell
-// 12: Clave
-// 13: Maracas
-// 14: Low Conga
-// 15: High Conga
+// DefaultSlotNames names the 16 drum slots (GM-style layout)
+var DefaultSlotNames = [16]string{
+	"Kick",
+	"Snare",
+	"Closed HH",
+	"Open HH",
+	"Low Tom",
+	"Mid Tom",
+	"High Tom",
+	"Crash",
+	"Ride",
+	"Clap",
+	"Rimshot",
+	"Cowbell",
+	"Clave",
+	"Maracas",
+	"Low Conga",
+	"High Conga",
+}
 
 // Kits contains all available drum kit mappings
 var Kits = map[string]DrumKit{
@@ -109,6 +112,12 @@ var Kits = map[string]DrumKit{
 			64, // (unused)
 			63, // (unused)
 		},
+		SlotNames: [16]string{
+			"Perc 1", "Perc 2", "Closed HH", "Open HH",
+			"Perc 3", "Perc 4", "Audio 1", "Crash",
+			"Audio 2", "Clap", "(unused)", "(unused)",
+			"(unused)", "(unused)", "(unused)", "(unused)",
+		},
 	},
 }
 
@@ -125,5 +134,16 @@ func GetKit(name string) DrumKit {
 	return Kits["gm"]
 }
 
+// SlotName returns the display name for a drum slot
+func (k DrumKit) SlotName(slot int) string {
+	if slot < 0 || slot >= 16 {
+		return ""
+	}
+	if k.SlotNames[slot] != "" {
+		return k.SlotNames[slot]
+	}
+	return DefaultSlotNames[slot]
+}
+
 // DefaultKit is the default kit name
 const DefaultKit = "gm"
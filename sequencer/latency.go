@@ -0,0 +1,102 @@
+package sequencer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"go-sequence/midi"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+)
+
+// Latency test - send probe notes out a port and time their return on the note input.
+// Wire the port back to the note input (loopback cable or a thru on the interface).
+const (
+	latencyProbeNote    = 127 // highest note - unlikely to be played during a test
+	latencyProbeCount   = 5
+	latencyProbeTimeout = 500 * time.Millisecond
+	latencyProbeGap     = 100 * time.Millisecond
+)
+
+// TrackPort returns the output port a track actually uses (its own or the default)
+func (m *Manager) TrackPort(idx int) string {
+	if idx < 0 || idx >= 8 {
+		return ""
+	}
+	if port := S.Tracks[idx].PortName; port != "" {
+		return port
+	}
+	return m.defaultPort
+}
+
+// MeasureLatency sends probe notes out portName and returns half the median round
+// trip (the estimated one-way output latency). Blocks for up to a few seconds.
+func (m *Manager) MeasureLatency(portName string, channel uint8) (time.Duration, error) {
+	sender := m.getSender(portName)
+	if sender == nil {
+		return 0, fmt.Errorf("can't open output %q", portName)
+	}
+
+	probe := make(chan time.Time, 1)
+	m.latencyMu.Lock()
+	if m.latencyProbe != nil {
+		m.latencyMu.Unlock()
+		return 0, fmt.Errorf("latency test already running")
+	}
+	m.latencyProbe = probe
+	m.latencyMu.Unlock()
+	defer func() {
+		m.latencyMu.Lock()
+		m.latencyProbe = nil
+		m.latencyMu.Unlock()
+	}()
+
+	ch := channel - 1
+	var trips []time.Duration
+	for i := 0; i < latencyProbeCount; i++ {
+		sent := time.Now()
+		sender(gomidi.NoteOn(ch, latencyProbeNote, 1))
+		select {
+		case got := <-probe:
+			trips = append(trips, got.Sub(sent))
+		case <-time.After(latencyProbeTimeout):
+		}
+		sender(gomidi.NoteOff(ch, latencyProbeNote))
+		time.Sleep(latencyProbeGap)
+	}
+
+	if len(trips) == 0 {
+		return 0, fmt.Errorf("no echo on note input - loop %s back to it", portName)
+	}
+	sort.Slice(trips, func(i, j int) bool { return trips[i] < trips[j] })
+	return trips[len(trips)/2] / 2, nil
+}
+
+// catchLatencyProbe reports a probe arrival during a latency test (true = consumed)
+func (m *Manager) catchLatencyProbe(evt midi.NoteEvent) bool {
+	m.latencyMu.Lock()
+	defer m.latencyMu.Unlock()
+	if m.latencyProbe == nil || evt.Note != latencyProbeNote {
+		return false
+	}
+	if evt.Velocity == 0 {
+		return true // the probe's note-off - swallowed but not timed
+	}
+	select {
+	case m.latencyProbe <- time.Now():
+	default:
+	}
+	return true
+}
+
+// ApplyLatency sets latency compensation on every track that outputs to portName
+func (m *Manager) ApplyLatency(portName string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := 0; i < 8; i++ {
+		if m.TrackPort(i) == portName {
+			S.Tracks[i].LatencyMs = min(int(latency/time.Millisecond), maxLatencyMs)
+		}
+	}
+}
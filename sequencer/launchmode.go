@@ -0,0 +1,164 @@
+package sequencer
+
+import (
+	"go-sequence/midi"
+	"go-sequence/netsync"
+)
+
+// Launch modes - what pressing a clip in the session does, per track (Settings,
+// Launch column). Trigger launches at the next boundary as always; toggle stops the
+// track when its playing clip is pressed again; retrigger restarts the playing clip
+// from the top at the next bar; gate starts the clip straight away and plays it only
+// while its pad is held (its stop waits for the next bar, so a tap still plays).
+
+// Launch modes (TrackState.LaunchMode)
+const (
+	LaunchTrigger = iota
+	LaunchToggle
+	LaunchRetrigger
+	LaunchGate
+)
+
+var launchModeNames = []string{"trigger", "toggle", "retrig", "gate"}
+
+// CycleLaunchMode steps a track to its next launch mode
+func (s *State) CycleLaunchMode(track int) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	s.Tracks[track].LaunchMode = (s.Tracks[track].LaunchMode + 1) % len(launchModeNames)
+}
+
+// launchModeName names a track's launch mode
+func launchModeName(track int) string {
+	mode := S.Tracks[track].LaunchMode
+	if mode < 0 || mode >= len(launchModeNames) {
+		return launchModeNames[LaunchTrigger]
+	}
+	return launchModeNames[mode]
+}
+
+// launchClip launches a clip from the session, following the track's launch mode
+func (s *SessionDevice) launchClip(track, pattern int) {
+	current, _ := s.getTrackPatternState(track)
+	playing := current == pattern && S.Playing && !s.manager.ClipStopped(track)
+	switch {
+	case playing && S.Tracks[track].LaunchMode == LaunchToggle:
+		s.manager.StopClip(track)
+	case playing && S.Tracks[track].LaunchMode == LaunchRetrigger:
+		s.manager.RetriggerClip(track)
+	case S.Tracks[track].LaunchMode == LaunchGate:
+		s.manager.GateClip(track, pattern)
+	default:
+		s.queuePattern(track, pattern)
+	}
+}
+
+// releaseClip ends a gate-mode launch when the clip pad is let go
+func (s *SessionDevice) releaseClip(track int) {
+	if S.Tracks[track].LaunchMode == LaunchGate {
+		s.manager.StopClip(track)
+	}
+}
+
+// GateClip switches a track to a clip now rather than at the next boundary - a gate
+// launch only lasts while its pad is held, so a quantized start could miss a short tap
+func (m *Manager) GateClip(trackIdx, patternIdx int) {
+	if !S.Playing {
+		m.QueuePattern(trackIdx, patternIdx)
+		return
+	}
+	at := S.Tick
+	m.switchPatternAt(trackIdx, patternIdx, at)
+	m.sendSync(netsync.Message{Type: netsync.MsgQueue, Track: trackIdx, Pattern: patternIdx, Tick: at})
+}
+
+// RetriggerClip restarts a track's playing clip from the top at the next bar
+func (m *Manager) RetriggerClip(trackIdx int) {
+	dev := m.GetDevice(trackIdx)
+	if dev == nil {
+		return
+	}
+	if _, ok := dev.(boundaryDevice); ok && S.Playing {
+		m.switchPatternAt(trackIdx, dev.CurrentPattern(), nextBarTick(dev))
+		m.announce("track %d restarts at the next bar", trackIdx+1)
+		return
+	}
+	m.queuePattern(trackIdx, dev.CurrentPattern())
+}
+
+// cutEvents drops events from tick on, keeping note-offs (moved to tick) so notes
+// already sounding still end
+func cutEvents(events []midi.Event, tick int64) []midi.Event {
+	var kept, offs []midi.Event
+	for _, e := range events {
+		switch {
+		case e.Tick < tick:
+			kept = append(kept, e)
+		case e.Type == midi.NoteOff:
+			e.Tick = tick
+			offs = append(offs, e)
+		}
+	}
+	return append(kept, offs...)
+}
+
+// --- Drums ---
+
+// switchAt cuts the schedule slot playing at tick short there and plays p after it
+func (d *DrumDevice) switchAt(p int, tick int64) {
+	if p < 0 || p >= NumPatterns {
+		return
+	}
+	d.state.Next = p
+	d.extendSchedule(tick + 1)
+	start := d.schedule.StartTick
+	for i := range d.schedule.Patterns {
+		slotLen := d.slotTicks(i)
+		if start+slotLen <= tick {
+			start += slotLen
+			continue
+		}
+		keep := i
+		if tick > start {
+			for len(d.schedule.Cuts) <= i {
+				d.schedule.Cuts = append(d.schedule.Cuts, 0)
+			}
+			d.schedule.Cuts[i] = tick - start
+			keep = i + 1
+		}
+		d.schedule.Patterns = append(d.schedule.Patterns[:keep], p)
+		d.schedule.Cuts = d.schedule.Cuts[:min(keep, len(d.schedule.Cuts))]
+		break
+	}
+	d.patternDirty[p] = true
+	d.syncQueueToSchedule()
+}
+
+// --- Piano roll ---
+
+func (p *PianoRollDevice) switchAt(pattern int, tick int64) {
+	p.state.Next = pattern
+	p.queueMu.Lock()
+	p.queue = cutEvents(p.queue, tick)
+	p.queuedUntilTick = min(p.queuedUntilTick, tick)
+	p.nextPatternTick = tick
+	p.queueMu.Unlock()
+	if p.onQueueChange != nil {
+		p.onQueueChange()
+	}
+}
+
+// --- Metropolix ---
+
+func (d *MetropolixDevice) switchAt(p int, tick int64) {
+	d.state.Next = p
+	d.queueMu.Lock()
+	d.queue = cutEvents(d.queue, tick)
+	d.queuedUntilTick = min(d.queuedUntilTick, tick)
+	d.nextPatternTick = tick
+	d.queueMu.Unlock()
+	if d.onQueueChange != nil {
+		d.onQueueChange()
+	}
+}
@@ -0,0 +1,147 @@
+package sequencer
+
+import (
+	"sync"
+
+	"go-sequence/debug"
+	"go-sequence/midi"
+)
+
+// LED pipeline - rendering and sending are split across two goroutines around a
+// double buffer. The LED loop renders the focused device's LEDs into a snapshot at a
+// fixed rate when they're dirty and hands it over; the writer takes the newest
+// snapshot, diffs it against what the controller already shows and sends the changes.
+// A controller that is slow to take a batch only delays the writer - frames rendered
+// meanwhile replace each other in the buffer, and nothing rendering, handling pads or
+// keys ever waits on the port.
+
+// ledBuffer is the hand-over between the LED loop and the writer
+type ledBuffer struct {
+	mu      sync.Mutex
+	pending []LEDState          // newest rendered frame the writer hasn't taken (nil = none)
+	show    []ledFrame          // LED show frames waiting to go out as they are
+	redraw  bool                // the controller's LEDs are unknown - send every LED next
+	ctrl    midi.Controller     // where the writer sends (the manager's controller)
+	ready   chan struct{}       // wakes the writer
+	sent    map[[2]int]LEDState // what the controller shows (writer only)
+}
+
+// newLEDBuffer creates an empty buffer
+func newLEDBuffer() *ledBuffer {
+	return &ledBuffer{
+		ready: make(chan struct{}, 1),
+		sent:  make(map[[2]int]LEDState),
+	}
+}
+
+// wake signals the writer without blocking
+func (b *ledBuffer) wake() {
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+}
+
+// publish swaps in a newly rendered frame, dropping one the writer never got to
+func (b *ledBuffer) publish(frame []LEDState) {
+	b.mu.Lock()
+	b.pending = frame
+	b.mu.Unlock()
+	b.wake()
+}
+
+// publishShow queues an LED show frame to send as is
+func (b *ledBuffer) publishShow(frame ledFrame) {
+	b.mu.Lock()
+	b.show = append(b.show, frame)
+	b.mu.Unlock()
+	b.wake()
+}
+
+// invalidate makes the writer send every LED of the next frame
+func (b *ledBuffer) invalidate() {
+	b.mu.Lock()
+	b.redraw = true
+	b.mu.Unlock()
+}
+
+// setController hands the writer a new controller, whose LEDs it knows nothing about
+func (b *ledBuffer) setController(c midi.Controller) {
+	b.mu.Lock()
+	b.ctrl = c
+	b.redraw = true
+	b.mu.Unlock()
+}
+
+// take returns what the writer should send next, and where, and clears it
+func (b *ledBuffer) take() (frame []LEDState, show []ledFrame, redraw bool, ctrl midi.Controller) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	frame, show, redraw, ctrl = b.pending, b.show, b.redraw, b.ctrl
+	b.pending, b.show, b.redraw = nil, nil, false
+	return frame, show, redraw, ctrl
+}
+
+// ledWriteLoop sends rendered frames and LED show frames to the controller
+func (m *Manager) ledWriteLoop() {
+	b := m.leds
+	for {
+		select {
+		case <-m.ledStopChan:
+			return
+		case <-b.ready:
+		}
+		frame, show, redraw, ctrl := b.take()
+		if ctrl == nil {
+			continue
+		}
+		if redraw {
+			clear(b.sent)
+		}
+		for _, f := range show {
+			if len(f) > 0 {
+				ctrl.SetLEDBatch(f)
+			}
+		}
+		if frame != nil {
+			if updates := b.diff(frame); len(updates) > 0 {
+				debug.Log("led", "writeLEDs: batch=%d", len(updates))
+				ctrl.SetLEDBatch(updates)
+			}
+		}
+	}
+}
+
+// diff returns the updates that take the controller from what it shows to a frame,
+// and remembers the frame as shown (writer only)
+func (b *ledBuffer) diff(frame []LEDState) []midi.LEDUpdate {
+	shown := make(map[[2]int]LEDState, len(frame))
+	var updates []midi.LEDUpdate
+	for _, led := range frame {
+		key := [2]int{led.Row, led.Col}
+		shown[key] = led
+
+		// Only send if changed
+		if prev, ok := b.sent[key]; !ok || prev != led {
+			updates = append(updates, midi.LEDUpdate{
+				Row:     led.Row,
+				Col:     led.Col,
+				Color:   led.Color,
+				Channel: led.Channel,
+			})
+		}
+	}
+
+	// Clear LEDs that are no longer present
+	for key := range b.sent {
+		if _, ok := shown[key]; !ok {
+			updates = append(updates, midi.LEDUpdate{
+				Row:   key[0],
+				Col:   key[1],
+				Color: [3]uint8{0, 0, 0},
+			})
+		}
+	}
+	b.sent = shown
+	return updates
+}
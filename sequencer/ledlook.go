@@ -0,0 +1,148 @@
+package sequencer
+
+import (
+	"go-sequence/midi"
+	"go-sequence/theme"
+)
+
+// LED look - brightness and color theme for the controller's LEDs (Settings, saved in
+// the config). Brightness goes to the hardware when the controller can dim itself
+// (Launchpad X SysEx) and scales the colors otherwise. A theme redraws every LED from a
+// palette: the hue picks the palette position and the original brightness is kept, so
+// devices still light in distinct colors - just the palette's. Grays stay as they are.
+
+// Brightness range and step (percent)
+const (
+	minLEDBrightness  = 10
+	ledBrightnessStep = 10
+)
+
+// SetLEDThemes sets the palettes the LED theme can be picked from
+func (m *Manager) SetLEDThemes(palettes []*theme.Palette) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ledThemes = palettes
+}
+
+// LEDThemes returns the names of the LED themes ("" is the devices' own colors)
+func (m *Manager) LEDThemes() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	names := []string{""}
+	for _, p := range m.ledThemes {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// SetLEDTheme picks the palette LEDs are drawn from by name ("" or an unknown name =
+// the devices' own colors)
+func (m *Manager) SetLEDTheme(name string) {
+	m.mu.Lock()
+	m.ledPalette = nil
+	for _, p := range m.ledThemes {
+		if p.Name == name {
+			m.ledPalette = p
+		}
+	}
+	m.mu.Unlock()
+	m.redrawLEDs()
+}
+
+// LEDTheme returns the LED theme's name ("" = the devices' own colors)
+func (m *Manager) LEDTheme() string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.ledPalette == nil {
+		return ""
+	}
+	return m.ledPalette.Name
+}
+
+// SetLEDBrightness sets LED brightness in percent (0 = full)
+func (m *Manager) SetLEDBrightness(percent int) {
+	if percent == 0 {
+		percent = 100
+	}
+	m.mu.Lock()
+	m.ledBrightness = clamp(percent, minLEDBrightness, 100)
+	m.mu.Unlock()
+	m.applyBrightness()
+	m.redrawLEDs()
+}
+
+// LEDBrightness returns LED brightness in percent
+func (m *Manager) LEDBrightness() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ledBrightness
+}
+
+// applyBrightness sends the brightness to a controller that dims in hardware
+func (m *Manager) applyBrightness() {
+	if bc, ok := m.controller.(midi.BrightnessController); ok {
+		bc.SetBrightness(uint8(m.LEDBrightness() * 127 / 100))
+	}
+}
+
+// redrawLEDs makes the next frame send every LED again
+func (m *Manager) redrawLEDs() {
+	if m.controller != nil {
+		m.leds.invalidate()
+		m.markLEDsDirty()
+	}
+}
+
+// styleLEDs applies the theme (and software dimming) to rendered LEDs
+func (m *Manager) styleLEDs(leds []LEDState) []LEDState {
+	m.mu.RLock()
+	palette, brightness := m.ledPalette, m.ledBrightness
+	m.mu.RUnlock()
+	if _, hw := m.controller.(midi.BrightnessController); hw {
+		brightness = 100
+	}
+	if palette == nil && brightness >= 100 {
+		return leds
+	}
+	for i := range leds {
+		c := leds[i].Color
+		if palette != nil {
+			c = themeColor(palette, c)
+		}
+		for ch := range c {
+			c[ch] = uint8(int(c[ch]) * brightness / 100)
+		}
+		leds[i].Color = c
+	}
+	return leds
+}
+
+// themeColor redraws a color from a palette, keeping its brightness (grays unchanged)
+func themeColor(palette *theme.Palette, c [3]uint8) [3]uint8 {
+	r, g, b := int(c[0]), int(c[1]), int(c[2])
+	hi, lo := max(r, g, b), min(r, g, b)
+	if hi == 0 || hi-lo < hi/4 {
+		return c
+	}
+	var hue float64 // 0-6
+	d := float64(hi - lo)
+	switch hi {
+	case r:
+		hue = float64(g-b) / d
+		if hue < 0 {
+			hue += 6
+		}
+	case g:
+		hue = float64(b-r)/d + 2
+	default:
+		hue = float64(r-g)/d + 4
+	}
+	p := palette.Lookup(hue / 6)
+	// Scale the palette color to the original's brightness
+	peak := max(int(p[0]), int(p[1]), int(p[2]), 1)
+	return [3]uint8{
+		uint8(min(int(p[0])*hi/peak, 255)),
+		uint8(min(int(p[1])*hi/peak, 255)),
+		uint8(min(int(p[2])*hi/peak, 255)),
+	}
+}
@@ -0,0 +1,130 @@
+package sequencer
+
+import (
+	"go-sequence/midi"
+)
+
+// LED shows - a short sweep when a controller connects, and an LED test (Settings or
+// controller profiles, `T`) that fills the pads red, green, blue and white, then a
+// gradient with a different color on every pad. If a fill is missing or off-color the
+// controller isn't in programmer mode or the RGB path is broken. Frames go to the LED
+// writer, one per tick, in place of the normal render; the view is redrawn after.
+
+// ledTestHold is how long each LED test fill stays up (in LED loop ticks)
+const ledTestHold = ledFPS / 2
+
+// ledFrame is one tick of an LED show (nil = hold the previous frame)
+type ledFrame []midi.LEDUpdate
+
+// playLEDShow starts an LED show, replacing one that is running
+func (m *Manager) playLEDShow(frames []ledFrame) {
+	m.mu.Lock()
+	m.ledShow = frames
+	m.mu.Unlock()
+}
+
+// TestLEDs runs the LED test on the connected controller
+func (m *Manager) TestLEDs() {
+	if m.controller == nil {
+		m.notify("LED test: no controller connected")
+		return
+	}
+	m.playLEDShow(ledTestFrames())
+	m.announce("LED test: red, green, blue, white, then a gradient")
+}
+
+// stepLEDShow sends the running show's next frame (false when no show is running)
+func (m *Manager) stepLEDShow() bool {
+	m.mu.Lock()
+	if len(m.ledShow) == 0 {
+		m.mu.Unlock()
+		return false
+	}
+	frame := m.ledShow[0]
+	m.ledShow = m.ledShow[1:]
+	done := len(m.ledShow) == 0
+	m.mu.Unlock()
+
+	if m.controller != nil && len(frame) > 0 {
+		m.leds.publishShow(frame)
+	}
+	if done {
+		m.redrawLEDs()
+	}
+	return true
+}
+
+// fillFrame lights every pad (grid, top row and scene column) with a color
+func fillFrame(color func(row, col int) [3]uint8) ledFrame {
+	frame := make(ledFrame, 0, 81)
+	for row := 0; row < 9; row++ {
+		for col := 0; col < 9; col++ {
+			frame = append(frame, midi.LEDUpdate{Row: row, Col: col, Color: color(row, col), Channel: midi.ChannelStatic})
+		}
+	}
+	return frame
+}
+
+// hold returns a frame followed by ticks of holding it
+func hold(frame ledFrame, ticks int) []ledFrame {
+	return append([]ledFrame{frame}, make([]ledFrame, ticks-1)...)
+}
+
+// startupFrames sweeps a rainbow diagonal from the bottom-left pad to the top-right
+func startupFrames() []ledFrame {
+	var frames []ledFrame
+	for step := 0; step <= 18; step++ {
+		var frame ledFrame
+		for row := 0; row < 9; row++ {
+			for col := 0; col < 9; col++ {
+				switch row + col {
+				case step:
+					frame = append(frame, midi.LEDUpdate{Row: row, Col: col, Color: hueColor(float64(step) / 17), Channel: midi.ChannelStatic})
+				case step - 2:
+					frame = append(frame, midi.LEDUpdate{Row: row, Col: col, Channel: midi.ChannelStatic})
+				}
+			}
+		}
+		frames = append(frames, frame)
+	}
+	return frames
+}
+
+// ledTestFrames fills red, green, blue and white, then a hue (column) by brightness
+// (row) gradient
+func ledTestFrames() []ledFrame {
+	var frames []ledFrame
+	for _, c := range [][3]uint8{{255, 0, 0}, {0, 255, 0}, {0, 0, 255}, {255, 255, 255}} {
+		frames = append(frames, hold(fillFrame(func(row, col int) [3]uint8 { return c }), ledTestHold)...)
+	}
+	gradient := fillFrame(func(row, col int) [3]uint8 {
+		c := hueColor(float64(col) / 9)
+		for ch := range c {
+			c[ch] = uint8(int(c[ch]) * (row + 1) / 9)
+		}
+		return c
+	})
+	frames = append(frames, hold(gradient, 3*ledTestHold)...)
+	return append(frames, fillFrame(func(row, col int) [3]uint8 { return [3]uint8{} }))
+}
+
+// hueColor is a fully saturated color at a hue (0-1 around the wheel)
+func hueColor(hue float64) [3]uint8 {
+	h := hue * 6
+	sector := int(h) % 6
+	f := h - float64(int(h))
+	up, down := uint8(255*f), uint8(255*(1-f))
+	switch sector {
+	case 0:
+		return [3]uint8{255, up, 0}
+	case 1:
+		return [3]uint8{down, 255, 0}
+	case 2:
+		return [3]uint8{0, 255, up}
+	case 3:
+		return [3]uint8{0, down, 255}
+	case 4:
+		return [3]uint8{up, 0, 255}
+	}
+	return [3]uint8{255, 0, down}
+}
@@ -0,0 +1,123 @@
+package sequencer
+
+import "sort"
+
+// Note tools - cleanup for recorded material and chord shaping. Legato stretches notes
+// to the next note start; trimming cuts same-pitch overlaps, which otherwise retrigger
+// or hang notes on synths that count note-ons; arpeggiate/strum spread a chord out.
+
+// legato extends the selected note (or every note) to the start of the next note.
+// The last note runs to the end of the pattern.
+func (p *PianoRollDevice) legato(whole bool) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+
+	for i := range pat.Notes {
+		if !whole && i != s.SelectedNote {
+			continue
+		}
+		n := &pat.Notes[i]
+		next := pat.Length
+		for _, other := range pat.Notes {
+			if other.Start > n.Start && other.Start < next {
+				next = other.Start
+			}
+		}
+		n.Duration = next - n.Start
+	}
+}
+
+// trimOverlaps shortens notes that run into the next note of the same pitch and drops
+// duplicates that start together (keeping the loudest)
+func (p *PianoRollDevice) trimOverlaps() {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+
+	var selected *NoteEventState
+	if s.SelectedNote >= 0 && s.SelectedNote < len(pat.Notes) {
+		sel := pat.Notes[s.SelectedNote]
+		selected = &sel
+	}
+
+	notes := pat.Notes
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Pitch != notes[j].Pitch {
+			return notes[i].Pitch < notes[j].Pitch
+		}
+		return notes[i].Start < notes[j].Start
+	})
+
+	kept := notes[:0]
+	for _, n := range notes {
+		if k := len(kept) - 1; k >= 0 && kept[k].Pitch == n.Pitch {
+			prev := &kept[k]
+			if n.Start-prev.Start < 1e-9 {
+				// Same start - one note, the loudest and longest
+				prev.Velocity = max(prev.Velocity, n.Velocity)
+				prev.Duration = max(prev.Duration, n.Duration)
+				continue
+			}
+			if prev.Start+prev.Duration > n.Start {
+				prev.Duration = n.Start - prev.Start
+			}
+		}
+		kept = append(kept, n)
+	}
+	pat.Notes = kept
+
+	// Keep the same note selected (by pitch and start) after the reorder
+	s.SelectedNote = -1
+	if selected != nil {
+		for i, n := range pat.Notes {
+			if n.Pitch == selected.Pitch && n.Start == selected.Start {
+				s.SelectedNote = i
+				break
+			}
+		}
+	}
+}
+
+// chordAtSelection returns the indices of notes starting with the selected note, low to high
+func (p *PianoRollDevice) chordAtSelection() []int {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return nil
+	}
+	start := pat.Notes[s.SelectedNote].Start
+	var chord []int
+	for i, n := range pat.Notes {
+		if n.Start > start-1e-9 && n.Start < start+1e-9 {
+			chord = append(chord, i)
+		}
+	}
+	sort.Slice(chord, func(a, b int) bool { return pat.Notes[chord[a]].Pitch < pat.Notes[chord[b]].Pitch })
+	return chord
+}
+
+// arpeggiate spreads the selected chord's notes one edit step apart, lowest first.
+// An arpeggio gives each note one step; a strum keeps the chord's end so notes overlap.
+func (p *PianoRollDevice) arpeggiate(strum bool) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	chord := p.chordAtSelection()
+	if len(chord) < 2 {
+		return
+	}
+	step := EditHorizSteps[s.EditHoriz]
+	for k, i := range chord {
+		n := &pat.Notes[i]
+		end := n.Start + n.Duration
+		start := n.Start + float64(k)*step
+		if start >= pat.Length {
+			break
+		}
+		n.Start = start
+		if strum {
+			n.Duration = max(end-start, step)
+		} else {
+			n.Duration = step
+		}
+		n.Duration = min(n.Duration, pat.Length-n.Start)
+	}
+}
@@ -0,0 +1,68 @@
+package sequencer
+
+import (
+	"strings"
+	"time"
+)
+
+// Look-ahead - each device's queue is filled this far past the playhead. The default
+// is half a beat; Metropolix, whose pattern generation (probability, ratchets,
+// accumulators) costs the most, fills a whole beat ahead. `lookAhead` in the config
+// sets it per device type in milliseconds (e.g. {"metropolix": 400}), and the queue
+// loop refills often enough that the shortest horizon never runs dry. A track with
+// latency compensation sends its events early, so its horizon grows by its latency.
+
+// lookAheadTicks is the default fill horizon - 250ms at 120 BPM
+const lookAheadTicks = PPQ / 2
+
+// Queue loop refill interval bounds (see fillInterval)
+const (
+	minFillInterval = 5 * time.Millisecond
+	maxFillInterval = 50 * time.Millisecond
+)
+
+// lookAheader is a device that fills further ahead than the default
+type lookAheader interface {
+	lookAhead() int64
+}
+
+func (d *MetropolixDevice) lookAhead() int64 { return PPQ }
+
+// deviceLookAhead returns a track's fill horizon in ticks, including its latency
+// compensation (hold m.mu)
+func (m *Manager) deviceLookAhead(trackIdx int, dev Device) int64 {
+	tickDur := S.TickDuration()
+	latency := int64((S.Tracks[trackIdx].Latency() + tickDur - 1) / tickDur)
+	return m.baseLookAhead(trackIdx, dev) + latency
+}
+
+// baseLookAhead returns a device's fill horizon in ticks: the config's, its own or
+// the default
+func (m *Manager) baseLookAhead(trackIdx int, dev Device) int64 {
+	if m.cfg != nil {
+		for name, ms := range m.cfg.LookAhead {
+			if ms > 0 && strings.EqualFold(name, string(S.Tracks[trackIdx].Type)) {
+				return max(int64(time.Duration(ms)*time.Millisecond/S.TickDuration()), 1)
+			}
+		}
+	}
+	if la, ok := dev.(lookAheader); ok {
+		return la.lookAhead()
+	}
+	return lookAheadTicks
+}
+
+// fillInterval is how often the queue loop refills - a quarter of the shortest
+// look-ahead, so a queue is topped up several times before it could run dry
+func (m *Manager) fillInterval() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	shortest := int64(lookAheadTicks)
+	for i, dev := range m.devices {
+		if dev != nil && S.Tracks[i].Type != DeviceTypeNone {
+			shortest = min(shortest, m.deviceLookAhead(i, dev))
+		}
+	}
+	interval := time.Duration(shortest) * S.TickDuration() / 4
+	return min(max(interval, minFillInterval), maxFillInterval)
+}
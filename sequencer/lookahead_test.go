@@ -0,0 +1,40 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeviceLookAheadCoversLatency(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name      string
+		tempo     int
+		latencyMs int
+		dev       Device
+		base      int64
+	}{
+		{"no latency", 120, 0, NewDrumDevice(NewDrumState()), lookAheadTicks},
+		{"120 BPM, most latency", 120, maxLatencyMs, NewDrumDevice(NewDrumState()), lookAheadTicks},
+		{"180 BPM, most latency", 180, maxLatencyMs, NewDrumDevice(NewDrumState()), lookAheadTicks},
+		{"Metropolix", 90, 35, NewMetropolixDevice(NewMetropolixState()), PPQ},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			S.Tempo = tt.tempo
+			S.Tracks[0].LatencyMs = tt.latencyMs
+			m := NewManager()
+			got := m.deviceLookAhead(0, tt.dev)
+			if got < tt.base {
+				t.Errorf("horizon %d is shorter than the device's own %d", got, tt.base)
+			}
+			ahead := time.Duration(got-tt.base) * S.TickDuration()
+			if ahead < S.Tracks[0].Latency() || ahead >= S.Tracks[0].Latency()+S.TickDuration() {
+				t.Errorf("horizon adds %v for %dms of latency", ahead, tt.latencyMs)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package sequencer
+
+import "math"
+
+// Loop regions - a piano pattern can play just a slice of itself. GeneratePattern,
+// the playhead and the queue length all work on the region, so a long pattern can be
+// edited and heard a few bars at a time without changing its length.
+
+// Loop returns the region that plays, in beats (the whole pattern when no loop is set)
+func (pat *PianoPatternState) Loop() (start, end float64) {
+	if pat.LoopEnd <= 0 {
+		return 0, pat.Length
+	}
+	end = min(pat.LoopEnd, pat.Length)
+	start = max(0, pat.LoopStart)
+	if start >= end {
+		return 0, pat.Length
+	}
+	return start, end
+}
+
+// HasLoop reports whether a loop region narrower than the pattern is set
+func (pat *PianoPatternState) HasLoop() bool {
+	start, end := pat.Loop()
+	return start > 0 || end < pat.Length
+}
+
+// setLoopPoint sets the loop start (in) or end (out) at the view center, snapped to the
+// edit grid. Setting one side past the other moves the other along.
+func (p *PianoRollDevice) setLoopPoint(out bool) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	editH := EditHorizSteps[s.EditHoriz]
+	start, end := pat.Loop()
+	at := math.Round(s.CenterBeat/editH) * editH
+	at = max(0, min(at, pat.Length))
+
+	if out {
+		end = max(at, editH)
+		if start >= end {
+			start = end - editH
+		}
+	} else {
+		start = min(at, pat.Length-editH)
+		if end <= start {
+			end = start + editH
+		}
+	}
+	pat.LoopStart, pat.LoopEnd = start, end
+	if start <= 0 && end >= pat.Length {
+		pat.LoopStart, pat.LoopEnd = 0, 0
+	}
+}
+
+// clearLoop plays the whole pattern again
+func (p *PianoRollDevice) clearLoop() {
+	pat := &p.state.Patterns[p.state.Editing]
+	pat.LoopStart, pat.LoopEnd = 0, 0
+}
@@ -0,0 +1,32 @@
+package sequencer
+
+import "testing"
+
+func TestPianoLoop(t *testing.T) {
+	tests := []struct {
+		name               string
+		start, end         float64
+		wantStart, wantEnd float64
+		has                bool
+	}{
+		{"none", 0, 0, 0, 16, false},
+		{"region", 4, 8, 4, 8, true},
+		{"end past the pattern", 4, 20, 4, 16, true},
+		{"negative start", -2, 8, 0, 8, true},
+		{"inverted", 8, 4, 0, 16, false},
+		{"empty", 6, 6, 0, 16, false},
+		{"whole pattern", 0, 16, 0, 16, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pat := PianoPatternState{Length: 16, LoopStart: tt.start, LoopEnd: tt.end}
+			start, end := pat.Loop()
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("Loop() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+			if has := pat.HasLoop(); has != tt.has {
+				t.Errorf("HasLoop() = %v, want %v", has, tt.has)
+			}
+		})
+	}
+}
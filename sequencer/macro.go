@@ -0,0 +1,404 @@
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// MacroAction is something a macro pad does when pressed
+type MacroAction string
+
+const (
+	MacroNone     MacroAction = ""
+	MacroMute     MacroAction = "mute"   // toggle mute on track Arg
+	MacroScene    MacroAction = "scene"  // launch pattern Arg on every track
+	MacroPlayStop MacroAction = "play"   // toggle transport
+	MacroTapTempo MacroAction = "tap"    // tap tempo
+	MacroFocus    MacroAction = "focus"  // focus the device on track Arg
+	MacroRecord   MacroAction = "record" // toggle recording (while playing)
+	MacroSnapshot MacroAction = "snap"   // recall snapshot slot Arg
+)
+
+// MacroActions lists assignable actions in the order the assign UI cycles them
+var MacroActions = []MacroAction{MacroMute, MacroScene, MacroPlayStop, MacroTapTempo, MacroFocus, MacroRecord, MacroSnapshot}
+
+// MacroBinding binds a pad in the macro bank to an action (saved per project)
+type MacroBinding struct {
+	Pad    int         `json:"pad"` // 0-63, top-left first
+	Action MacroAction `json:"action"`
+	Arg    int         `json:"arg,omitempty"` // track or pattern index, depending on action
+}
+
+// NumMacroPads is the size of the macro bank (the full 8x8 grid)
+const NumMacroPads = 64
+
+// Tap tempo - taps further apart than this start a new measurement
+const (
+	tapTimeout = 2 * time.Second
+	tapHistory = 4 // intervals averaged
+)
+
+// argRange returns how many values an action's argument takes (0 = no argument)
+func (a MacroAction) argRange() int {
+	switch a {
+	case MacroMute, MacroFocus:
+		return 8
+	case MacroSnapshot:
+		return NumSnapshots
+	case MacroScene:
+		return NumPatterns
+	}
+	return 0
+}
+
+// Label is a short description of a binding ("mute 3", "scene 5", ...)
+func (b MacroBinding) Label() string {
+	switch b.Action {
+	case MacroMute:
+		return fmt.Sprintf("mute %d", b.Arg+1)
+	case MacroScene:
+		return fmt.Sprintf("scene %d", b.Arg+1)
+	case MacroPlayStop:
+		return "play/stop"
+	case MacroTapTempo:
+		return "tap"
+	case MacroFocus:
+		return fmt.Sprintf("focus %d", b.Arg+1)
+	case MacroRecord:
+		return "record"
+	case MacroSnapshot:
+		return snapshotLabel(b.Arg)
+	}
+	return ""
+}
+
+// macroColor is the pad color for an action
+func macroColor(a MacroAction) [3]uint8 {
+	switch a {
+	case MacroMute:
+		return [3]uint8{255, 60, 60}
+	case MacroScene:
+		return [3]uint8{148, 18, 126}
+	case MacroPlayStop:
+		return [3]uint8{0, 255, 0}
+	case MacroTapTempo:
+		return [3]uint8{255, 200, 0}
+	case MacroFocus:
+		return [3]uint8{80, 200, 255}
+	case MacroRecord:
+		return [3]uint8{255, 0, 0}
+	case MacroSnapshot:
+		return [3]uint8{0, 120, 255}
+	}
+	return [3]uint8{20, 20, 20}
+}
+
+// Macro returns the binding on a pad (Action is MacroNone if unbound)
+func (s *State) Macro(pad int) MacroBinding {
+	for _, b := range s.Macros {
+		if b.Pad == pad {
+			return b
+		}
+	}
+	return MacroBinding{Pad: pad}
+}
+
+// SetMacro binds a pad, replacing any existing binding (MacroNone unbinds)
+func (s *State) SetMacro(b MacroBinding) {
+	kept := s.Macros[:0]
+	for _, old := range s.Macros {
+		if old.Pad != b.Pad {
+			kept = append(kept, old)
+		}
+	}
+	if b.Action != MacroNone {
+		kept = append(kept, b)
+	}
+	s.Macros = kept
+}
+
+// RunMacro performs a macro binding
+func (m *Manager) RunMacro(b MacroBinding) {
+	switch b.Action {
+	case MacroMute:
+		m.ToggleMute(b.Arg)
+	case MacroScene:
+		m.LaunchScene(b.Arg)
+	case MacroPlayStop:
+		_, playing, _ := m.GetState()
+		if playing {
+			m.Stop()
+		} else {
+			m.Play()
+		}
+	case MacroTapTempo:
+		m.TapTempo()
+	case MacroFocus:
+		m.FocusDevice(b.Arg)
+	case MacroRecord:
+		if _, playing, _ := m.GetState(); playing {
+			m.ToggleRecording()
+		}
+	case MacroSnapshot:
+		m.RecallSnapshot(b.Arg)
+	}
+}
+
+// LaunchScene queues the same pattern on every track that has content there; each
+// device switches at its own boundary. Tracks with an empty slot keep playing.
+func (m *Manager) LaunchScene(patternIdx int) {
+	if patternIdx < 0 || patternIdx >= NumPatterns {
+		return
+	}
+	queued := 0
+	for i := 0; i < 8; i++ {
+		dev := m.GetDevice(i)
+		if dev == nil || !dev.ContentMask()[patternIdx] {
+			continue
+		}
+		m.QueuePattern(i, patternIdx)
+		queued++
+	}
+	if queued == 0 {
+		m.announce("scene %d is empty", patternIdx+1)
+		return
+	}
+	m.announce("scene %d queued on %d tracks", patternIdx+1, queued)
+}
+
+// TapTempo sets the tempo from the average interval of recent taps
+func (m *Manager) TapTempo() {
+	now := time.Now()
+	m.tapMu.Lock()
+	if n := len(m.taps); n > 0 && now.Sub(m.taps[n-1]) > tapTimeout {
+		m.taps = m.taps[:0]
+	}
+	m.taps = append(m.taps, now)
+	if len(m.taps) > tapHistory+1 {
+		m.taps = m.taps[len(m.taps)-tapHistory-1:]
+	}
+	taps := len(m.taps)
+	avg := time.Duration(0)
+	if taps > 1 {
+		avg = m.taps[taps-1].Sub(m.taps[0]) / time.Duration(taps-1)
+	}
+	m.tapMu.Unlock()
+
+	if avg > 0 {
+		m.SetTempo(int(time.Minute / avg))
+	}
+}
+
+// MacroDevice is a bank of user-assignable pads (the whole 8x8 grid)
+type MacroDevice struct {
+	manager *Manager
+
+	cursor int // selected pad 0-63, top-left first
+
+	// Assign mode - editing the binding on the cursor pad
+	assigning bool
+	pending   MacroBinding
+}
+
+// NewMacroDevice creates a macro pad device
+func NewMacroDevice(manager *Manager) *MacroDevice {
+	return &MacroDevice{manager: manager}
+}
+
+// IsInputMode returns true while a binding is being assigned
+func (md *MacroDevice) IsInputMode() bool {
+	return md.assigning
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (md *MacroDevice) FillUntil(tick int64)                           {}
+func (md *MacroDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (md *MacroDevice) PopNextEvent() *midi.Event                      { return nil }
+func (md *MacroDevice) ClearQueue()                                    {}
+func (md *MacroDevice) QueuePattern(p int, atTick int64)               {}
+func (md *MacroDevice) CurrentPattern() int                            { return 0 }
+func (md *MacroDevice) NextPattern() int                               { return -1 }
+func (md *MacroDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (md *MacroDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (md *MacroDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (md *MacroDevice) HandleMIDI(event midi.Event)                    {}
+func (md *MacroDevice) ToggleRecording()                               {}
+func (md *MacroDevice) TogglePreview()                                 {}
+func (md *MacroDevice) IsRecording() bool                              { return false }
+func (md *MacroDevice) IsPreviewing() bool                             { return false }
+func (md *MacroDevice) HandlePadRelease(row, col int)                  {}
+func (md *MacroDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (md *MacroDevice) View() string {
+	var out strings.Builder
+	out.WriteString("MACROS  Pad Bank\n\n")
+
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			pad := row*8 + col
+			b := S.Macro(pad)
+			if md.assigning && pad == md.cursor {
+				b = md.pending
+			}
+			label := truncateName(b.Label(), 9)
+			if label == "" {
+				label = "·"
+			}
+			if pad == md.cursor {
+				out.WriteString(fmt.Sprintf("[%-9s]", label))
+			} else {
+				out.WriteString(fmt.Sprintf(" %-9s ", label))
+			}
+		}
+		out.WriteString("\n")
+	}
+
+	if md.assigning {
+		out.WriteString(fmt.Sprintf("\nAssign pad %d: %s\n\n", md.cursor+1, md.pending.Label()))
+		out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+			{Keys: []widgets.KeyBinding{
+				{Key: "h / l", Desc: "action"},
+				{Key: "j / k", Desc: "track/pattern"},
+				{Key: "enter", Desc: "bind"},
+				{Key: "esc", Desc: "cancel"},
+			}},
+		}))
+		return out.String()
+	}
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "hjkl", Desc: "select pad"},
+			{Key: "space", Desc: "run macro"},
+			{Key: "a", Desc: "assign action"},
+			{Key: "x", Desc: "clear pad"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(md.HelpLayout()))
+	return out.String()
+}
+
+func (md *MacroDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	for pad := 0; pad < NumMacroPads; pad++ {
+		b := S.Macro(pad)
+		color := macroColor(b.Action)
+		if pad == md.cursor {
+			color = [3]uint8{255, 255, 255}
+		}
+		leds = append(leds, LEDState{Row: 7 - pad/8, Col: pad % 8, Color: color, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+func (md *MacroDevice) HandleKey(key string) {
+	if md.assigning {
+		md.handleAssignKey(key)
+		return
+	}
+
+	switch key {
+	case "h", "left":
+		if md.cursor%8 > 0 {
+			md.cursor--
+		}
+	case "l", "right":
+		if md.cursor%8 < 7 {
+			md.cursor++
+		}
+	case "k", "up":
+		if md.cursor >= 8 {
+			md.cursor -= 8
+		}
+	case "j", "down":
+		if md.cursor < NumMacroPads-8 {
+			md.cursor += 8
+		}
+	case " ", "enter":
+		md.manager.RunMacro(S.Macro(md.cursor))
+	case "a":
+		md.pending = S.Macro(md.cursor)
+		if md.pending.Action == MacroNone {
+			md.pending.Action = MacroActions[0]
+		}
+		md.assigning = true
+	case "x":
+		S.SetMacro(MacroBinding{Pad: md.cursor})
+	}
+}
+
+// handleAssignKey edits the pending binding
+func (md *MacroDevice) handleAssignKey(key string) {
+	p := &md.pending
+	switch key {
+	case "h", "left", "l", "right":
+		idx := 0
+		for i, a := range MacroActions {
+			if a == p.Action {
+				idx = i
+			}
+		}
+		if key == "h" || key == "left" {
+			idx = (idx + len(MacroActions) - 1) % len(MacroActions)
+		} else {
+			idx = (idx + 1) % len(MacroActions)
+		}
+		p.Action = MacroActions[idx]
+		if n := p.Action.argRange(); n == 0 {
+			p.Arg = 0
+		} else if p.Arg >= n {
+			p.Arg = n - 1
+		}
+	case "j", "down":
+		if p.Arg > 0 {
+			p.Arg--
+		}
+	case "k", "up":
+		if p.Arg < p.Action.argRange()-1 {
+			p.Arg++
+		}
+	case "enter":
+		S.SetMacro(*p)
+		md.assigning = false
+	case "esc", "q":
+		md.assigning = false
+	}
+}
+
+// HandlePad runs the macro on a pad (and moves the cursor to it)
+func (md *MacroDevice) HandlePad(row, col int, velocity uint8) {
+	if row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	md.cursor = (7-row)*8 + col
+	md.manager.RunMacro(S.Macro(md.cursor))
+}
+
+// HelpLayout shows each macro pad's color and bound action
+func (md *MacroDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	for i := range l.TopRow {
+		l.TopRow[i] = widgets.Pad{Color: [3]uint8{30, 30, 30}}
+	}
+	for pad := 0; pad < NumMacroPads; pad++ {
+		b := S.Macro(pad)
+		l.Grid[7-pad/8][pad%8] = widgets.Pad{Color: macroColor(b.Action), Tooltip: b.Label()}
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: macroColor(MacroMute), Name: "Mute", Desc: "toggle track mute"},
+		{Color: macroColor(MacroScene), Name: "Scene", Desc: "launch pattern on all tracks"},
+		{Color: macroColor(MacroPlayStop), Name: "Play", Desc: "play/stop"},
+		{Color: macroColor(MacroTapTempo), Name: "Tap", Desc: "tap tempo"},
+		{Color: macroColor(MacroFocus), Name: "Focus", Desc: "focus track device"},
+	}
+	return l
+}
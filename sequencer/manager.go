@@ -1,12 +1,17 @@
 package sequencer
 
 import (
+	"fmt"
 	"runtime"
+	"slices"
 	"sync"
 	"time"
 
+	"go-sequence/config"
 	"go-sequence/debug"
 	"go-sequence/midi"
+	"go-sequence/netsync"
+	"go-sequence/theme"
 
 	gomidi "gitlab.com/gomidi/midi/v2"
 )
@@ -17,17 +22,71 @@ type Manager struct {
 	session  *SessionDevice
 	settings *SettingsDevice
 	save     *SaveDevice
+	macro    *MacroDevice
+	setList  *SetListDevice
+	routing  *RoutingDevice
+	monitor  *MonitorDevice
+	learn    *LearnDevice
+	sysex    *SysExDevice
+	profiles *ControllersDevice
+	mixer    *MixerDevice
 
 	// Multi-port MIDI output
-	defaultPort string
-	senders     map[string]func(gomidi.Message) error
-	sendersMu   sync.RWMutex
+	defaultPort  string
+	senders      map[string]func(gomidi.Message) error
+	sendersMu    sync.RWMutex
+	midiOff      bool                   // safe mode - no output ports are opened (guarded by sendersMu)
+	portFailures map[string]time.Time   // last failed open/send per port (guarded by sendersMu, see senders.go)
+	voiceMu      sync.Mutex             // guards the voice state below (see devicelock.go)
+	monoNotes    [8]int                 // held note per track for mono output profiles (-1 = none)
+	noteShift    [8][128]int8           // transpose each sounding note was sent with (see transposeEvent)
+	sounding     [8][128]bool           // notes sent on and not yet off (see silenced)
+	mpe          [8]mpeVoices           // member channel allocation for MPE tracks (see mpe.go)
+	active       [8]map[activeNote]bool // notes sent on per port/channel, for flushing (see noteoff.go)
+	activeMu     sync.Mutex
+	keyRoutes    [16][128]uint16 // where each held keyboard note went (input loop only, see keyzones.go)
+	panicHeld    [2]bool         // top row corner pads held (see panicCombo)
+	panicSwallow [2]bool         // their releases are swallowed (they fired a panic)
+	midiLog      midiMonitor     // recent messages in and out (see monitor.go)
+	jitter       jitterStats     // how late events go out (see jitter.go)
+
+	// Launchpad Pro Mk3 track buttons (see trackbuttons.go)
+	trackBtnMu   sync.Mutex
+	trackBtnMode int // midi.ProModeMute, ProModeSolo or ProModeStopClip
+
+	// Start/Stop out for drum machines (see startstop.go)
+	transportMu   sync.Mutex
+	transport     []transportEvent
+	transportWake chan struct{} // a Start/Stop was scheduled - the dispatch loop looks again
+
+	// Soft MIDI thru (see thru.go)
+	thruMu sync.Mutex
+	thru   []ThruRoute
+
+	// MIDI learn (see midilearn.go)
+	ccMu       sync.Mutex
+	ccLearning bool // the next CC maps ccArmed
+	ccArmed    CCMapping
+	ccValues   map[ccSource]uint8 // last value of each control (for button presses)
+
+	// Keyboard hotkeys (see hotkeys.go)
+	hotkeyMu   sync.Mutex
+	hotkeyPort string
+	hotkeys    []Hotkey
+
+	// Config rig and the project's overrides of it (see overrides.go)
+	cfg            *config.Config
+	rigControllers []config.ControllerConfig // project controller assignments in use (guarded by mu)
+	rigChanged     bool                      // they changed - the TUI reconnects (guarded by mu)
 
 	controller midi.Controller
 
+	devMu         sync.Mutex // device state - patterns and playback (see devicelock.go)
 	stopChan      chan struct{}
-	interruptChan chan struct{} // signal dispatch loop to recalculate (queue changed)
-	mu            sync.RWMutex  // RWMutex for concurrent reads in midiOutputLoop
+	interruptChan chan struct{}  // signal dispatch loop to recalculate (queue changed)
+	resampleChan  chan resampled // sent events for tracks recording them (output loop → queue loop)
+	dispatch      dispatchQueue  // next event per track, for the output loop (see dispatch.go)
+	mu            sync.RWMutex   // RWMutex for concurrent reads in midiOutputLoop
 
 	focused Device // which device gets UI/input
 
@@ -36,9 +95,46 @@ type Manager struct {
 	midiInputStopChan chan struct{}
 
 	// LED rendering at fixed FPS
-	ledDirty    bool                // true if LEDs need refresh
-	prevLEDs    map[[2]int]LEDState // for diffing
-	ledStopChan chan struct{}       // stop the LED loop
+	ledDirty    bool          // true if LEDs need refresh
+	leds        *ledBuffer    // rendered frames on their way to the controller (see ledbuffer.go)
+	ledStopChan chan struct{} // stop the LED loops
+	ledShow     []ledFrame    // LED show frames still to send (see ledshow.go, guarded by mu)
+
+	// LED look (see ledlook.go, guarded by mu)
+	ledBrightness int              // percent
+	ledThemes     []*theme.Palette // palettes to pick the theme from
+	ledPalette    *theme.Palette   // nil = the devices' own colors
+
+	// Announcements - short descriptions of state changes (for plain/screen-reader output)
+	announceMu   sync.Mutex
+	announcement string
+	status       string // pending status line message (see notify)
+	lastPatterns [8]int // per-track playing pattern, to announce changes
+
+	// Latency test - probe arrivals from the note input (nil = no test running)
+	latencyMu    sync.Mutex
+	latencyProbe chan time.Time
+
+	// Generative mode - last boundary seen (-1 = not running)
+	genBoundary int64
+
+	// Clip stop - per-track silence windows (see clipstop.go)
+	clipStops [8]clipStop
+
+	// Session capture - the take being recorded (nil = none, see capture.go)
+	captureMu sync.Mutex
+	capture   *captureTake
+
+	// Tap tempo - recent tap times
+	tapMu sync.Mutex
+	taps  []time.Time
+
+	// Tooltip of the last pad pressed, for the status line
+	padTipMu sync.Mutex
+	padTip   string
+
+	// Network sync with another instance (nil = standalone)
+	syncLink *netsync.Link
 
 	// Notify TUI of updates
 	UpdateChan chan struct{}
@@ -50,11 +146,21 @@ const ledFPS = 30
 // NewManager creates a new sequencer manager
 func NewManager() *Manager {
 	m := &Manager{
-		senders:     make(map[string]func(gomidi.Message) error),
-		prevLEDs:    make(map[[2]int]LEDState),
-		ledStopChan: make(chan struct{}),
-		UpdateChan:  make(chan struct{}, 1),
+		senders:       make(map[string]func(gomidi.Message) error),
+		portFailures:  make(map[string]time.Time),
+		ccValues:      make(map[ccSource]uint8),
+		leds:          newLEDBuffer(),
+		ledStopChan:   make(chan struct{}),
+		UpdateChan:    make(chan struct{}, 1),
+		transportWake: make(chan struct{}, 1),
+		resampleChan:  make(chan resampled, resampleBuffer),
+		genBoundary:   -1,
+		ledBrightness: 100,
 	}
+	for i := range m.monoNotes {
+		m.monoNotes[i] = -1
+	}
+	m.trackBtnMode = midi.ProModeMute
 	return m
 }
 
@@ -66,8 +172,9 @@ func (m *Manager) StartRuntime() {
 	m.stopChan = make(chan struct{})
 	m.interruptChan = make(chan struct{}, 1)
 
-	// Start all 5 goroutines
-	go m.ledLoop()          // LED updates
+	// Start all 6 goroutines
+	go m.ledLoop()          // LED rendering
+	go m.ledWriteLoop()     // LED output
 	go m.midiInputLoop()    // MIDI keyboard input
 	go m.queueManagerLoop() // Queue filling
 	go m.midiOutputLoop()   // MIDI output
@@ -75,14 +182,23 @@ func (m *Manager) StartRuntime() {
 
 // SetDevice assigns a device to a slot and wires up callbacks
 func (m *Manager) SetDevice(idx int, d Device) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setDevice(idx, d)
+}
+
+// setDevice swaps a slot's device - the output loop reads the slots under m.mu, not
+// the device lock (hold m.mu)
+func (m *Manager) setDevice(idx int, d Device) {
 	if idx >= 0 && idx < 8 {
 		m.devices[idx] = d
-		m.wireDeviceCallbacks(d)
+		m.wireDeviceCallbacks(idx, d)
+		m.dispatch.invalidate()
 	}
 }
 
-// wireDeviceCallbacks sets up the onQueueChange callback for a device
-func (m *Manager) wireDeviceCallbacks(d Device) {
+// wireDeviceCallbacks sets up the onQueueChange callback (and track index) for a device
+func (m *Manager) wireDeviceCallbacks(idx int, d Device) {
 	if d == nil {
 		return
 	}
@@ -90,6 +206,7 @@ func (m *Manager) wireDeviceCallbacks(d Device) {
 	switch dev := d.(type) {
 	case *DrumDevice:
 		dev.SetOnQueueChange(m.interrupt)
+		dev.SetTrack(idx)
 	case *PianoRollDevice:
 		dev.SetOnQueueChange(m.interrupt)
 	case *MetropolixDevice:
@@ -105,11 +222,35 @@ func (m *Manager) GetDevice(idx int) Device {
 	return nil
 }
 
+// ClipInfo returns content, length and play state for every pattern on a track
+func (m *Manager) ClipInfo(trackIdx int) []ClipInfo {
+	clips := make([]ClipInfo, NumPatterns)
+	dev := m.GetDevice(trackIdx)
+	if dev == nil {
+		return clips
+	}
+	mask, bars := dev.ContentMask(), dev.PatternBars()
+	playing, next := dev.CurrentPattern(), dev.NextPattern()
+	stopped := m.ClipStopped(trackIdx)
+	for p := range clips {
+		clips[p] = ClipInfo{
+			HasContent: mask[p],
+			Bars:       bars[p],
+			Playing:    p == playing && !stopped,
+			Queued:     p == next && next != playing,
+		}
+	}
+	return clips
+}
+
 // Devices returns the devices array
 func (m *Manager) Devices() [8]Device {
 	return m.devices
 }
 
+// The Create*Device functions set the track's device type and state, so once the
+// runtime is running they're called with m.mu held (see ChangeDeviceType).
+
 // CreateDrumDevice creates a DrumDevice wired to the given track's state
 func (m *Manager) CreateDrumDevice(trackIdx int) Device {
 	if trackIdx < 0 || trackIdx >= 8 {
@@ -169,7 +310,27 @@ func (m *Manager) CreateMetropolixDevice(trackIdx int) Device {
 	return NewMetropolixDevice(ts.Metropolix)
 }
 
-// recreateDevicesFromState rebuilds all devices from the loaded state
+// ChangeDeviceType replaces a track's device with a new one of a type. The swap is
+// made under m.mu - the output loop reads the track's type and device without the
+// device lock.
+func (m *Manager) ChangeDeviceType(trackIdx int, deviceType DeviceType) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var dev Device
+	switch deviceType {
+	case DeviceTypeDrum:
+		dev = m.CreateDrumDevice(trackIdx)
+	case DeviceTypePiano:
+		dev = m.CreatePianoDevice(trackIdx)
+	case DeviceTypeMetropolix:
+		dev = m.CreateMetropolixDevice(trackIdx)
+	case DeviceTypeNone:
+		dev = m.CreateEmptyDevice(trackIdx)
+	}
+	m.setDevice(trackIdx, dev)
+}
+
+// recreateDevicesFromState rebuilds all devices from the loaded state (hold m.mu)
 func (m *Manager) recreateDevicesFromState() {
 	for i := 0; i < 8; i++ {
 		ts := S.Tracks[i]
@@ -184,10 +345,8 @@ func (m *Manager) recreateDevicesFromState() {
 		default:
 			dev = NewEmptyDevice(i + 1)
 		}
-		m.SetDevice(i, dev) // Use SetDevice to wire callbacks
+		m.setDevice(i, dev) // wires callbacks
 	}
-	// Focus session after loading
-	m.SetFocused(m.session)
 }
 
 // SetDefaultPort sets the default MIDI output port name
@@ -202,42 +361,79 @@ func (m *Manager) getSender(portName string) func(gomidi.Message) error {
 	}
 
 	m.sendersMu.RLock()
+	if m.midiOff {
+		m.sendersMu.RUnlock()
+		return nil
+	}
 	if sender, ok := m.senders[portName]; ok {
 		m.sendersMu.RUnlock()
 		return sender
 	}
+	// A port that just failed isn't looked for again until senderRetryDelay has passed
+	if failed, ok := m.portFailures[portName]; ok && time.Since(failed) < senderRetryDelay {
+		m.sendersMu.RUnlock()
+		return nil
+	}
 	m.sendersMu.RUnlock()
 
-	// Open port
+	return m.openSender(portName)
+}
+
+// ForgetSenders drops the senders of ports that are no longer present, so a device
+// that is unplugged and comes back gets its port opened again on the next send (ports
+// that are present are retried straight away, even if they failed recently)
+func (m *Manager) ForgetSenders(present []string) {
 	m.sendersMu.Lock()
 	defer m.sendersMu.Unlock()
-
-	// Double-check after acquiring write lock
-	if sender, ok := m.senders[portName]; ok {
-		return sender
+	for portName := range m.senders {
+		if !slices.Contains(present, portName) {
+			delete(m.senders, portName)
+		}
 	}
-
-	// Find and open port
-	for _, port := range gomidi.GetOutPorts() {
-		if port.String() == portName {
-			sender, err := gomidi.SendTo(port)
-			if err != nil {
-				return nil
-			}
-			m.senders[portName] = sender
-			return sender
+	for portName := range m.portFailures {
+		if slices.Contains(present, portName) {
+			delete(m.portFailures, portName)
 		}
 	}
-	return nil
+}
+
+// SetMIDIEnabled turns MIDI output on or off (off = safe mode, events are dropped)
+func (m *Manager) SetMIDIEnabled(on bool) {
+	m.sendersMu.Lock()
+	defer m.sendersMu.Unlock()
+	m.midiOff = !on
+}
+
+// MIDIEnabled reports whether MIDI ports may be opened (false in safe mode)
+func (m *Manager) MIDIEnabled() bool {
+	m.sendersMu.RLock()
+	defer m.sendersMu.RUnlock()
+	return !m.midiOff
 }
 
 // SetController sets the MIDI controller for LED feedback
 func (m *Manager) SetController(c midi.Controller) {
 	debug.Log("ctrl", "SetController called, resetting diff state")
 	m.controller = c
+	m.leds.setController(c)
+	if fc, ok := c.(midi.FaderController); ok {
+		go m.listenFaders(fc)
+	}
+	if c != nil && c.Type() == midi.ControllerAPC {
+		m.SetMIDIInput(c) // APC Key 25 keys play like the note input
+	}
+	m.applyBrightness()
+	if c != nil {
+		m.playLEDShow(startupFrames())
+	}
+	if m.mixer != nil {
+		m.mixer.mu.Lock()
+		m.mixer.fadersShown = false // a new controller starts on its own layout
+		m.mixer.mu.Unlock()
+		m.syncFaderPage()
+	}
 	if m.controller != nil && m.focused != nil {
-		m.prevLEDs = make(map[[2]int]LEDState) // reset state - diff will handle clearing
-		m.markLEDsDirty()
+		m.redrawLEDs() // reset state - diff will handle clearing
 	}
 }
 
@@ -248,7 +444,7 @@ func (m *Manager) markLEDsDirty() {
 	m.mu.Unlock()
 }
 
-// ledLoop runs at fixed FPS and flushes LED updates
+// ledLoop runs at fixed FPS and renders dirty LEDs for the writer
 func (m *Manager) ledLoop() {
 	ticker := time.NewTicker(time.Second / ledFPS)
 	defer ticker.Stop()
@@ -258,61 +454,29 @@ func (m *Manager) ledLoop() {
 		case <-m.ledStopChan:
 			return
 		case <-ticker.C:
+			if m.stepLEDShow() {
+				continue
+			}
 			m.mu.Lock()
 			dirty := m.ledDirty
 			m.ledDirty = false
 			m.mu.Unlock()
 
 			if dirty {
-				m.flushLEDs()
+				m.withDevices(m.renderLEDs)
 			}
 		}
 	}
 }
 
-// flushLEDs sends only changed LEDs to the controller (diffing + batching)
-func (m *Manager) flushLEDs() {
+// renderLEDs renders the focused device's LEDs (with the button rows, top row and
+// LED look) and hands the frame to the writer
+func (m *Manager) renderLEDs() {
 	if m.focused == nil || m.controller == nil {
 		return
 	}
-
-	newLEDs := m.focused.RenderLEDs()
-	newMap := make(map[[2]int]LEDState, len(newLEDs))
-
-	var updates []midi.LEDUpdate
-
-	for _, led := range newLEDs {
-		key := [2]int{led.Row, led.Col}
-		newMap[key] = led
-
-		// Only send if changed
-		if prev, ok := m.prevLEDs[key]; !ok || prev != led {
-			updates = append(updates, midi.LEDUpdate{
-				Row:     led.Row,
-				Col:     led.Col,
-				Color:   led.Color,
-				Channel: led.Channel,
-			})
-		}
-	}
-
-	// Clear LEDs that are no longer present
-	for key := range m.prevLEDs {
-		if _, ok := newMap[key]; !ok {
-			updates = append(updates, midi.LEDUpdate{
-				Row:   key[0],
-				Col:   key[1],
-				Color: [3]uint8{0, 0, 0},
-			})
-		}
-	}
-
-	if len(updates) > 0 {
-		debug.Log("led", "flushLEDs: batch=%d prev=%d", len(updates), len(m.prevLEDs))
-		m.controller.SetLEDBatch(updates)
-	}
-
-	m.prevLEDs = newMap
+	frame := append(m.focused.RenderLEDs(), m.buttonRowLEDs()...)
+	m.leds.publish(m.styleLEDs(append(frame, m.topRowLEDs()...)))
 }
 
 // SetSession sets the session device
@@ -335,6 +499,7 @@ func (m *Manager) GetSettings() *SettingsDevice {
 func (m *Manager) FocusSettings() {
 	if m.settings != nil {
 		m.SetFocused(m.settings)
+		m.announce("settings")
 	}
 }
 
@@ -353,57 +518,228 @@ func (m *Manager) FocusSave() {
 	if m.save != nil {
 		m.save.Refresh() // refresh project list when focusing
 		m.SetFocused(m.save)
+		m.announce("save browser")
+	}
+}
+
+// SetMacro sets the macro pad device
+func (m *Manager) SetMacro(d *MacroDevice) {
+	m.macro = d
+}
+
+// FocusMacro focuses the macro pad device
+func (m *Manager) FocusMacro() {
+	if m.macro != nil {
+		m.SetFocused(m.macro)
+		m.announce("macro pads")
+	}
+}
+
+// SetSetList sets the set list device
+func (m *Manager) SetSetList(d *SetListDevice) {
+	m.setList = d
+}
+
+// FocusSetList focuses the set list device
+func (m *Manager) FocusSetList() {
+	if m.setList != nil {
+		m.setList.Refresh() // pick up set lists edited on disk
+		m.SetFocused(m.setList)
+		m.announce("set list")
+	}
+}
+
+// SetRouting sets the routing matrix device
+func (m *Manager) SetRouting(d *RoutingDevice) {
+	m.routing = d
+}
+
+// FocusRouting focuses the routing matrix
+func (m *Manager) FocusRouting() {
+	if m.routing != nil {
+		m.SetFocused(m.routing)
+		m.announce("routing")
+	}
+}
+
+// FocusRoutingAt focuses the routing matrix with the cursor on a track's input or
+// output (track routingKeyboardRow = the keyboard port)
+func (m *Manager) FocusRoutingAt(track int, outputs bool) {
+	if m.routing != nil {
+		m.routing.FocusTrack(track, outputs)
+		m.FocusRouting()
+	}
+}
+
+// SetMonitor sets the MIDI monitor device
+func (m *Manager) SetMonitor(d *MonitorDevice) {
+	m.monitor = d
+}
+
+// FocusMonitor focuses the MIDI monitor
+func (m *Manager) FocusMonitor() {
+	if m.monitor != nil {
+		m.SetFocused(m.monitor)
+		m.announce("MIDI monitor")
+	}
+}
+
+// SetLearn sets the MIDI learn device
+func (m *Manager) SetLearn(d *LearnDevice) {
+	m.learn = d
+}
+
+// FocusLearn focuses the MIDI learn view
+func (m *Manager) FocusLearn() {
+	if m.learn != nil {
+		m.SetFocused(m.learn)
+		m.announce("MIDI learn")
+	}
+}
+
+// SetSysEx sets the SysEx librarian device
+func (m *Manager) SetSysEx(d *SysExDevice) {
+	m.sysex = d
+}
+
+// FocusSysEx focuses the SysEx librarian
+func (m *Manager) FocusSysEx() {
+	if m.sysex != nil {
+		m.sysex.Refresh() // the project may have been saved or loaded since
+		m.SetFocused(m.sysex)
+		m.announce("SysEx librarian")
 	}
 }
 
-// Look-ahead for queue filling (in ticks) - about 100ms worth at 120 BPM
-const lookAheadTicks = PPQ / 2
+// SetControllerProfiles sets the controller profile editor
+func (m *Manager) SetControllerProfiles(d *ControllersDevice) {
+	m.profiles = d
+}
+
+// FocusControllerProfiles focuses the controller profile editor
+func (m *Manager) FocusControllerProfiles() {
+	if m.profiles != nil {
+		m.SetFocused(m.profiles)
+		m.announce("controllers")
+	}
+}
+
+// SetMixer sets the mixer page
+func (m *Manager) SetMixer(d *MixerDevice) {
+	m.mixer = d
+}
 
-// Play starts playback
+// FocusMixer focuses the mixer page
+func (m *Manager) FocusMixer() {
+	if m.mixer != nil {
+		m.SetFocused(m.mixer)
+		m.announce("mixer")
+	}
+}
+
+// Play starts playback (ignored when following a sync leader)
 func (m *Manager) Play() {
+	if m.IsFollower() {
+		return
+	}
+	if m.playFrom(0) {
+		m.mu.RLock()
+		tempo := S.Tempo
+		m.mu.RUnlock()
+		m.sendSync(netsync.Message{Type: netsync.MsgPlay, Tempo: tempo})
+		m.announce("playing at %d bpm", tempo)
+	}
+}
+
+// playFrom starts playback as if it had been running since tick 0.
+// Returns false if already playing.
+func (m *Manager) playFrom(tick int64) bool {
 	m.mu.Lock()
 	if S.Playing {
 		m.mu.Unlock()
-		return
+		return false
 	}
 
 	// Initialize timing
-	S.Playing = true
-	S.T0 = time.Now()
-	S.Tick = 0
+	S.T0 = time.Now().Add(-time.Duration(tick) * S.TickDuration())
+	S.Tick = tick
 
 	// Clear and initialize all device queues
-	for _, dev := range m.devices {
-		if dev != nil {
-			dev.ClearQueue()
-		}
+	m.clearQueues()
+	m.mu.Unlock()
+
+	// Joining mid-stream: fill now and drop everything already in the past
+	if tick > 0 {
+		m.fillQueues()
+		m.dropEventsBefore(tick)
 	}
+
+	m.mu.Lock()
+	m.startTransport(tick)
+	S.Playing = true
 	m.mu.Unlock()
 
 	// Goroutines already running, just signal to start filling
 	m.interrupt()
+	return true
 }
 
-// Stop stops playback
+// dropEventsBefore discards queued events earlier than tick
+func (m *Manager) dropEventsBefore(tick int64) {
+	for _, dev := range m.devices {
+		if dev == nil {
+			continue
+		}
+		for evt := dev.PeekNextEvent(); evt != nil && evt.Tick < tick; evt = dev.PeekNextEvent() {
+			dev.PopNextEvent()
+		}
+	}
+	m.dispatch.invalidate()
+}
+
+// Stop stops playback (ignored when following a sync leader)
 func (m *Manager) Stop() {
+	if m.IsFollower() {
+		return
+	}
+	if m.stop() {
+		m.sendSync(netsync.Message{Type: netsync.MsgStop})
+		m.announce("stopped")
+	}
+}
+
+// stop halts playback and clears queues. Returns false if already stopped.
+func (m *Manager) stop() bool {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if !S.Playing {
-		return
+		return false
 	}
 	S.Playing = false
+	m.settleClipStops()
 
-	// Clear all device queues
-	for _, dev := range m.devices {
-		if dev != nil {
-			dev.ClearQueue()
-		}
-	}
+	// Clear all device queues (and end the notes their note-offs were for)
+	m.clearQueues()
+	m.resetMonoNotes()
+	m.stopTransport()
+	m.reanchorClock()
 	// Don't stop goroutines - they keep running, just no playback
+	return true
+}
+
+// resetMonoNotes forgets every track's held mono note, so the first note after a
+// restart neither ends a note that isn't sounding nor skips its legato retrigger
+func (m *Manager) resetMonoNotes() {
+	m.voiceMu.Lock()
+	defer m.voiceMu.Unlock()
+	for i := range m.monoNotes {
+		m.monoNotes[i] = -1
+	}
 }
 
 // interrupt signals the dispatch loop to recalculate (called when queues change)
 func (m *Manager) interrupt() {
+	m.dispatch.invalidate()
 	select {
 	case m.interruptChan <- struct{}{}:
 	default:
@@ -417,12 +753,32 @@ func (m *Manager) midiInputLoop() {
 		case <-m.midiInputStopChan:
 			return
 		case evt := <-m.midiInputChan:
-			// HandleNote does immediate echo + routes to device
-			m.HandleNote(evt.Note, evt.Velocity)
+			m.withDevices(func() {
+				m.monitorInput(evt) // reads the input port from S
+				m.routeInput(evt)
+			})
 		}
 	}
 }
 
+// routeInput plays a note input event (or runs its hotkey)
+func (m *Manager) routeInput(evt midi.NoteEvent) {
+	if m.noteHotkey(evt) {
+		return
+	}
+	evt = curveNoteEvent(evt)
+	if evt.Type != midi.NoteOn {
+		m.HandleExpression(evt)
+		return
+	}
+	// Latency test probes come back on the note input - don't play/record them
+	if m.catchLatencyProbe(evt) {
+		return
+	}
+	// HandleNote does immediate echo + routes to device
+	m.HandleNote(evt.Channel, evt.Note, evt.Velocity)
+}
+
 // SetMIDIInput sets the MIDI keyboard input source
 func (m *Manager) SetMIDIInput(ctrl midi.Controller) {
 	if ctrl == nil {
@@ -446,26 +802,41 @@ func (m *Manager) SetMIDIInput(ctrl midi.Controller) {
 	}()
 }
 
-// fillQueues fills all device queues up to horizon
+// fillQueues fills each device queue up to its look-ahead horizon
 func (m *Manager) fillQueues() {
+	var targets [8]int64
 	m.mu.Lock()
 	now := time.Now()
 	currentTick := S.TimeToTick(now)
-	targetTick := currentTick + lookAheadTicks
+	for i, dev := range m.devices {
+		if dev != nil {
+			targets[i] = currentTick + m.deviceLookAhead(i, dev)
+		}
+	}
 	m.mu.Unlock()
 
 	// Fill all device queues
-	for _, dev := range m.devices {
+	for i, dev := range m.devices {
 		if dev != nil {
-			dev.FillUntil(targetTick)
+			dev.FillUntil(targets[i])
 		}
 	}
+	m.dispatch.invalidate()
+
+	// Stopped, nothing pops the queues - let go of what's behind the playhead
+	m.mu.RLock()
+	playing := S.Playing
+	m.mu.RUnlock()
+	if !playing {
+		m.trimQueues(currentTick)
+	}
 }
 
 // queueManagerLoop ensures device queues are filled ahead of playhead
 func (m *Manager) queueManagerLoop() {
-	ticker := time.NewTicker(time.Millisecond * 50) // Every 50ms
-	uiTicker := time.NewTicker(time.Second / 30)    // 30 FPS
+	interval := m.fillInterval()
+	ticker := time.NewTicker(interval)           // Often enough for the shortest look-ahead
+	uiTicker := time.NewTicker(time.Second / 30) // 30 FPS
 	defer ticker.Stop()
 	defer uiTicker.Stop()
 
@@ -475,15 +846,27 @@ func (m *Manager) queueManagerLoop() {
 			return
 		case <-m.interruptChan:
 			// Queue changed, recalculate immediately
-			m.fillQueues()
+			m.withDevices(m.fillQueues)
+		case r := <-m.resampleChan:
+			m.withDevices(func() { m.resample(r) })
 		case <-ticker.C:
 			// Periodic fill
-			m.fillQueues()
+			m.withDevices(m.fillQueues)
+			if next := m.fillInterval(); next != interval {
+				// Tempo, devices or look-ahead changed
+				interval = next
+				ticker.Reset(interval)
+			}
 		case <-uiTicker.C:
-			// Update UI state
-			m.mu.Lock()
-			S.Tick = S.TimeToTick(time.Now())
-			m.mu.Unlock()
+			// Update UI state (the playhead is read by the devices too)
+			m.withDevices(func() {
+				m.mu.Lock()
+				S.Tick = S.TimeToTick(time.Now())
+				m.mu.Unlock()
+				m.checkGenerative()
+				m.checkCapture()
+				m.announcePatternChanges()
+			})
 			m.markLEDsDirty()
 			select {
 			case m.UpdateChan <- struct{}{}:
@@ -503,28 +886,21 @@ func (m *Manager) midiOutputLoop() {
 		case <-m.stopChan:
 			return
 		default:
-			// Find earliest event across all devices
-			var nextEvent *midi.Event
-			var nextDeviceIdx int = -1
-
+			// Earliest event across all devices (by send time - see dispatch.go)
 			m.mu.RLock()
-			for i, dev := range m.devices {
-				if dev == nil {
-					continue
-				}
-				ts := S.Tracks[i]
-				if ts.Muted {
-					continue
-				}
-				evt := dev.PeekNextEvent()
-				if evt != nil && (nextEvent == nil || evt.Tick < nextEvent.Tick) {
-					nextEvent = evt
-					nextDeviceIdx = i
+			head, ok := m.nextDispatch()
+			m.mu.RUnlock()
+
+			// Start/Stop due before the next event (see startstop.go)
+			if at, due := m.nextTransportTime(); due && (!ok || at.Before(head.at)) {
+				if !m.waitUntil(at) {
+					return
 				}
+				m.sendDueTransport()
+				continue
 			}
-			m.mu.RUnlock()
 
-			if nextEvent == nil {
+			if !ok {
 				// No events, sleep briefly
 				time.Sleep(time.Millisecond)
 				continue
@@ -537,67 +913,189 @@ func (m *Manager) midiOutputLoop() {
 				time.Sleep(time.Millisecond)
 				continue
 			}
-			eventTime := S.TickToTime(nextEvent.Tick)
+			eventTime := S.TickToTime(head.tick).Add(-S.Tracks[head.track].Latency())
 			m.mu.RUnlock()
-			waitDuration := eventTime.Sub(time.Now())
 
-			if waitDuration > 0 {
-				timer := time.NewTimer(waitDuration)
-				select {
-				case <-m.stopChan:
-					timer.Stop()
+			if time.Until(eventTime) > 0 {
+				switch m.waitPrecise(eventTime) {
+				case waitStopped:
 					return
-				case <-timer.C:
-					// Ready
+				case waitWoken:
+					// A Start/Stop may be due first - look again
+					continue
+				}
+				if m.dispatch.stale.Load() {
+					// A queue changed while waiting - look again
+					continue
 				}
 			}
 
-			// Pop and send
+			// Pop and send (a copy - the queue's slot stays as it was)
+			nextDeviceIdx := head.track
+			m.mu.RLock()
 			dev := m.devices[nextDeviceIdx]
-			evt := dev.PopNextEvent()
-			if evt == nil {
+			m.mu.RUnlock()
+			var popped *midi.Event
+			if dev != nil {
+				popped = dev.PopNextEvent()
+			}
+			m.mu.RLock()
+			m.advanceDispatch(nextDeviceIdx)
+			m.mu.RUnlock()
+			if popped == nil {
 				continue
 			}
+			event := *popped
+			evt := &event
+			played := event // as the device played it, for tracks resampling this one
 
 			m.mu.RLock()
 			ts := S.Tracks[nextDeviceIdx]
+			silent := m.trackSilent(nextDeviceIdx, evt.Tick)
+			drum, kitName, portName := ts.Type == DeviceTypeDrum, ts.Kit, ts.PortName
 			m.mu.RUnlock()
 
 			// Translate drum slot → MIDI note if needed
-			if ts.Type == DeviceTypeDrum {
-				kit := GetKit(ts.Kit)
+			if drum {
+				kit := GetKit(kitName)
 				if evt.Note < 16 {
 					evt.Note = kit.Notes[evt.Note]
 				}
 			}
 
 			// Send MIDI
-			portName := ts.PortName
 			if portName == "" {
 				portName = m.defaultPort
 			}
-			sender := m.getSender(portName)
-			if sender != nil {
-				midiCh := ts.Channel - 1
-				switch evt.Type {
-				case midi.NoteOn:
-					sender(gomidi.NoteOn(midiCh, evt.Note, evt.Velocity))
-				case midi.NoteOff:
-					sender(gomidi.NoteOff(midiCh, evt.Note))
-				case midi.Trigger:
-					sender(gomidi.NoteOn(midiCh, evt.Note, evt.Velocity))
-					sender(gomidi.NoteOff(midiCh, evt.Note))
-				case midi.PitchBend:
-					sender(gomidi.Pitchbend(midiCh, evt.BendValue))
-				}
-				debug.Log("dispatch", "track=%d port=%s ch=%d tick=%d type=%d note=%d", nextDeviceIdx, portName, midiCh+1, evt.Tick, evt.Type, evt.Note)
+			m.voiceMu.Lock()
+			// Track transpose (dropped if it pushes the note out of range), then stopped
+			// clips and muted tracks only let note-offs for sounding notes through
+			send := m.transposeEvent(nextDeviceIdx, ts, evt) && !m.silenced(nextDeviceIdx, evt, silent)
+			if sender := m.getSender(portName); send && sender != nil {
+				m.jitter.record(time.Since(eventTime))
+				ch := m.sendEvent(sender, nextDeviceIdx, ts, evt)
+				m.trackNote(nextDeviceIdx, portName, ch, evt)
+				debug.Log("dispatch", "track=%d port=%s ch=%d tick=%d type=%d note=%d", nextDeviceIdx, portName, ts.Channel, evt.Tick, evt.Type, evt.Note)
+			}
+			m.voiceMu.Unlock()
+			if !send {
+				continue
+			}
+
+			// Tracks resampling this one record the event as if it was played in - the
+			// queue loop hands it over, so sending never waits on the device lock
+			select {
+			case m.resampleChan <- resampled{src: nextDeviceIdx, evt: played}:
+			default:
+				debug.Log("dispatch", "resample buffer full, dropped track=%d tick=%d", nextDeviceIdx, played.Tick)
 			}
 		}
 	}
 }
 
-// SetTempo sets the BPM
+// resampleBuffer is how many sent events can wait for the queue loop to resample them
+const resampleBuffer = 256
+
+// resampled is an event a track sent, on its way to the tracks recording it
+type resampled struct {
+	src int
+	evt midi.Event
+}
+
+// resample feeds a sent event to every track recording from its track, as the source
+// device played it - before the kit map and track transpose (run as the device lock's
+// owner)
+func (m *Manager) resample(r resampled) {
+	m.mu.RLock()
+	var targets []Device
+	for i, dev := range m.devices {
+		if dev != nil && i != r.src && S.Tracks[i].RecordFrom == r.src+1 {
+			targets = append(targets, dev)
+		}
+	}
+	m.mu.RUnlock()
+	for _, dev := range targets {
+		dev.HandleMIDI(r.evt)
+	}
+}
+
+// sendEvent sends one event on a track, shaped by the track's output profile, and
+// returns the channel it went out on (hold m.voiceMu)
+func (m *Manager) sendEvent(sender func(gomidi.Message) error, trackIdx int, ts *TrackState, evt *midi.Event) uint8 {
+	profile := GetProfile(ts.Profile)
+	if profile.MPE {
+		return m.sendMPE(sender, trackIdx, ts, evt)
+	}
+	midiCh := ts.Channel - 1
+	if evt.OutChannel > 0 {
+		midiCh = evt.OutChannel - 1 // per-note channel override
+	}
+	gateCh := (midiCh + uint8(profile.GateChannel)) % 16
+
+	noteOn := func(note, vel uint8) {
+		if profile.Mono {
+			// Steal the voice - release the held note first
+			if held := m.monoNotes[trackIdx]; held >= 0 && held != int(note) {
+				sender(gomidi.NoteOff(midiCh, uint8(held)))
+			}
+			m.monoNotes[trackIdx] = int(note)
+		}
+		if profile.VelocityCC >= 0 {
+			sender(gomidi.ControlChange(midiCh, uint8(profile.VelocityCC), vel))
+		}
+		sender(gomidi.NoteOn(midiCh, note, vel))
+		if profile.GateNote >= 0 {
+			sender(gomidi.NoteOn(gateCh, uint8(profile.GateNote), vel))
+		}
+	}
+	noteOff := func(note uint8) {
+		if profile.Mono {
+			// Only the held note may close the gate (legato overlaps keep it open)
+			if m.monoNotes[trackIdx] != int(note) {
+				return
+			}
+			m.monoNotes[trackIdx] = -1
+		}
+		sender(gomidi.NoteOff(midiCh, note))
+		if profile.GateNote >= 0 {
+			sender(gomidi.NoteOff(gateCh, uint8(profile.GateNote)))
+		}
+	}
+
+	switch evt.Type {
+	case midi.NoteOn:
+		noteOn(evt.Note, evt.Velocity)
+	case midi.NoteOff:
+		noteOff(evt.Note)
+	case midi.Trigger:
+		noteOn(evt.Note, evt.Velocity)
+		noteOff(evt.Note)
+	case midi.PitchBend:
+		bend := evt.BendValue
+		if evt.PerNote {
+			bend = bendValue(bend, profile.BendRange)
+		}
+		sender(gomidi.Pitchbend(midiCh, bend))
+	case midi.Pressure:
+		sender(gomidi.AfterTouch(midiCh, evt.Velocity))
+	case midi.CC:
+		sender(gomidi.ControlChange(midiCh, evt.Note, evt.Velocity))
+	}
+	return midiCh
+}
+
+// SetTempo sets the BPM (ignored when following a sync leader)
 func (m *Manager) SetTempo(bpm int) {
+	if m.IsFollower() {
+		return
+	}
+	bpm = m.setTempo(bpm)
+	m.sendSync(netsync.Message{Type: netsync.MsgTempo, Tempo: bpm})
+	m.announce("tempo %d", bpm)
+}
+
+// setTempo clamps and applies a BPM, returning the one applied
+func (m *Manager) setTempo(bpm int) int {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if bpm < 20 {
@@ -607,6 +1105,7 @@ func (m *Manager) SetTempo(bpm int) {
 		bpm = 300
 	}
 	S.Tempo = bpm
+	return bpm
 }
 
 // GetState returns the current sequencer state
@@ -627,21 +1126,27 @@ func (m *Manager) GetFocused() Device {
 func (m *Manager) SetFocused(d Device) {
 	debug.Log("focus", "SetFocused called, resetting diff state")
 	m.focused = d
+	m.syncFaderPage()
 	if m.focused != nil && m.controller != nil {
-		m.prevLEDs = make(map[[2]int]LEDState) // reset - diff will handle clearing
-		m.markLEDsDirty()
+		m.redrawLEDs() // reset - diff will handle clearing
 	}
 }
 
 // FocusSession focuses the session device
 func (m *Manager) FocusSession() {
 	m.SetFocused(m.session)
+	m.announce("session")
 }
 
 // FocusDevice focuses a device by index
 func (m *Manager) FocusDevice(idx int) {
 	if idx >= 0 && idx < 8 && m.devices[idx] != nil {
 		m.SetFocused(m.devices[idx])
+		name := string(S.Tracks[idx].Type)
+		if name == "" {
+			name = "empty"
+		}
+		m.announce("track %d %s", idx+1, name)
 	}
 }
 
@@ -651,26 +1156,48 @@ func (m *Manager) FocusDevice(idx int) {
 func (m *Manager) HandleKey(key string) {
 	if m.focused != nil {
 		m.focused.HandleKey(key)
+		m.handlePreviewEvents()
 		m.notifyUpdate()
 	}
 }
 
 // HandlePad routes a pad press to the focused device
-func (m *Manager) HandlePad(row, col int) {
+func (m *Manager) HandlePad(row, col int, velocity uint8) {
+	if m.panicCombo(row, col, true) || m.handleButtonRows(row, col) || m.handleTopRow(row, col) {
+		return
+	}
 	if m.focused != nil {
-		m.focused.HandlePad(row, col)
+		// Describe the pad before the press changes what it does
+		layout := m.focused.HelpLayout()
+		m.padTipMu.Lock()
+		m.padTip = layout.Tooltip(row, col)
+		m.padTipMu.Unlock()
+
+		m.focused.HandlePad(row, col, velocity)
 
-		// Check for preview events from DrumDevice
+		// Check for preview events from drum pads and piano auditions
 		m.handlePreviewEvents()
 
 		m.notifyUpdate()
 	}
 }
 
-// handlePreviewEvents drains preview channels from drum devices and sends MIDI
+// HandlePadRelease routes a pad release to the focused device
+func (m *Manager) HandlePadRelease(row, col int) {
+	if m.panicCombo(row, col, false) || m.releaseTopRow(row, col) || row < 0 {
+		return // buttons under the grid and the global top row act on press
+	}
+	if m.focused != nil {
+		m.focused.HandlePadRelease(row, col)
+		m.notifyUpdate()
+	}
+}
+
+// handlePreviewEvents drains preview channels from drum and piano devices and sends MIDI
 func (m *Manager) handlePreviewEvents() {
 	for i, dev := range m.devices {
-		if dev == nil {
+		if pianoDev, ok := dev.(*PianoRollDevice); ok {
+			m.drainAuditions(i, pianoDev)
 			continue
 		}
 		drumDev, ok := dev.(*DrumDevice)
@@ -689,24 +1216,7 @@ func (m *Manager) handlePreviewEvents() {
 					continue
 				}
 				note := kit.Notes[slotIdx]
-
-				// Get sender for this track's port
-				portName := ts.PortName
-				if portName == "" {
-					portName = m.defaultPort
-				}
-				sender := m.getSender(portName)
-				if sender == nil {
-					continue
-				}
-
-				// Send note on/off
-				midiCh := ts.Channel - 1
-				sender(gomidi.NoteOn(midiCh, note, 100))
-				go func(s func(gomidi.Message) error, ch, n uint8) {
-					time.Sleep(100 * time.Millisecond)
-					s(gomidi.NoteOff(ch, n))
-				}(sender, midiCh, note)
+				m.sendPreview(i, midi.Event{Type: midi.NoteOn, Note: note, Velocity: 100}, drumPreviewLength)
 			default:
 				// Channel empty
 				return
@@ -715,49 +1225,19 @@ func (m *Manager) handlePreviewEvents() {
 	}
 }
 
-// HandleNote handles live MIDI input: echo immediately, then record
-func (m *Manager) HandleNote(note uint8, velocity uint8) {
-	eventType := midi.NoteOn
-	if velocity == 0 {
-		eventType = midi.NoteOff
-	}
+// drumPreviewLength is how long a drum pad preview sounds
+const drumPreviewLength = 100 * time.Millisecond
 
-	// Calculate tick from wall clock
-	tick := int64(0)
-	if S.Playing {
-		tick = S.TimeToTick(time.Now())
-	}
-
-	// Echo immediately to MIDI out (bypass queue for low latency)
-	// Find which track is focused and use its output settings
-	focusedIdx := m.getFocusedTrackIdx()
-	if focusedIdx >= 0 {
-		ts := S.Tracks[focusedIdx]
-		portName := ts.PortName
-		if portName == "" {
-			portName = m.defaultPort
-		}
-		sender := m.getSender(portName)
-		if sender != nil {
-			midiCh := ts.Channel - 1
-			if eventType == midi.NoteOn {
-				sender(gomidi.NoteOn(midiCh, note, velocity))
-			} else {
-				sender(gomidi.NoteOff(midiCh, note))
-			}
+// drainAuditions plays every pending piano roll audition
+func (m *Manager) drainAuditions(trackIdx int, p *PianoRollDevice) {
+	for {
+		select {
+		case n := <-p.PreviewChan():
+			m.playAudition(trackIdx, n)
+		default:
+			return
 		}
 	}
-
-	// Send to device for recording (with tick)
-	if m.focused != nil {
-		m.focused.HandleMIDI(midi.Event{
-			Tick:     tick,
-			Type:     eventType,
-			Note:     note,
-			Velocity: velocity,
-		})
-		m.notifyUpdate()
-	}
 }
 
 // getFocusedTrackIdx returns the track index of the focused device (-1 if none)
@@ -781,6 +1261,84 @@ func (m *Manager) notifyUpdate() {
 	}
 }
 
+// announce records a state change description for screen-reader output
+func (m *Manager) announce(format string, args ...any) {
+	m.announceMu.Lock()
+	m.announcement = fmt.Sprintf(format, args...)
+	m.announceMu.Unlock()
+	m.notifyUpdate()
+}
+
+// notify announces something the user must see in every view (the TUI shows it on
+// the status line - see TakeStatus)
+func (m *Manager) notify(format string, args ...any) {
+	m.announceMu.Lock()
+	m.status = fmt.Sprintf(format, args...)
+	m.announceMu.Unlock()
+	m.announce(format, args...)
+}
+
+// notifyPort is notify for the output senders, which also run with m.mu held (notes
+// flushed on stop, mute or solo, transport Stop) - it wakes the TUI but leaves the
+// LED refresh, which takes m.mu, to the next frame
+func (m *Manager) notifyPort(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	m.announceMu.Lock()
+	m.status = msg
+	m.announcement = msg
+	m.announceMu.Unlock()
+	select {
+	case m.UpdateChan <- struct{}{}:
+	default:
+	}
+}
+
+// TakeStatus returns a message for the status line once ("" if there is none)
+func (m *Manager) TakeStatus() string {
+	m.announceMu.Lock()
+	defer m.announceMu.Unlock()
+	status := m.status
+	m.status = ""
+	return status
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// Announcement returns the latest state change description
+func (m *Manager) Announcement() string {
+	m.announceMu.Lock()
+	defer m.announceMu.Unlock()
+	return m.announcement
+}
+
+// PadTooltip returns what the last pressed pad does ("" if nothing)
+func (m *Manager) PadTooltip() string {
+	m.padTipMu.Lock()
+	defer m.padTipMu.Unlock()
+	return m.padTip
+}
+
+// announcePatternChanges announces tracks whose playing pattern changed
+func (m *Manager) announcePatternChanges() {
+	for i, dev := range m.devices {
+		if dev == nil {
+			continue
+		}
+		p := dev.CurrentPattern()
+		if p != m.lastPatterns[i] {
+			m.lastPatterns[i] = p
+			if S.Playing {
+				m.announce("track %d playing pattern %d", i+1, p+1)
+			}
+		}
+	}
+}
+
 // View returns the view of the focused device
 func (m *Manager) View() string {
 	if m.focused != nil {
@@ -793,7 +1351,7 @@ func (m *Manager) View() string {
 func (m *Manager) ToggleRecording() {
 	if m.focused != nil {
 		m.focused.ToggleRecording()
-		m.notifyUpdate()
+		m.announce("recording %s", onOff(m.focused.IsRecording()))
 	}
 }
 
@@ -801,6 +1359,6 @@ func (m *Manager) ToggleRecording() {
 func (m *Manager) TogglePreview() {
 	if m.focused != nil {
 		m.focused.TogglePreview()
-		m.notifyUpdate()
+		m.announce("preview %s", onOff(m.focused.IsPreviewing()))
 	}
 }
@@ -0,0 +1,44 @@
+package sequencer
+
+import "math"
+
+// Meter - piano patterns can be any length in quarter-beat steps (3.5 beats, 6.75...)
+// and carry a time signature. The signature decides what a bar is for the session's
+// clip lengths and density, launch quantization lines and clip stops; unquantized
+// pattern switches land on the playing pattern's own boundary, so odd lengths launch
+// in time.
+
+// Piano pattern length limits, in beats
+const (
+	minPianoLength  = 0.25
+	maxPianoLength  = 64.0
+	pianoLengthStep = 0.25 // finest length change
+)
+
+// BarBeats returns the length of one bar in quarter-note beats (7/8 = 3.5)
+func (t TimeSig) BarBeats() float64 {
+	return float64(t.Beats) * 4 / float64(t.Unit)
+}
+
+// Sig returns the pattern's time signature
+func (p *PianoPatternState) Sig() TimeSig {
+	if p.TimeSig < 0 || p.TimeSig >= len(TimeSigs) {
+		return TimeSigs[0]
+	}
+	return TimeSigs[p.TimeSig]
+}
+
+// nudgeLength changes the editing pattern's length by delta beats, on the quarter-beat grid
+func (p *PianoRollDevice) nudgeLength(delta float64) {
+	pat := &p.state.Patterns[p.state.Editing]
+	length := math.Round((pat.Length+delta)/pianoLengthStep) * pianoLengthStep
+	pat.Length = max(minPianoLength, min(length, maxPianoLength))
+}
+
+// cycleTimeSig moves the editing pattern to the next time signature, keeping its bar count
+func (p *PianoRollDevice) cycleTimeSig() {
+	pat := &p.state.Patterns[p.state.Editing]
+	bars := max(math.Round(pat.Length/pat.Sig().BarBeats()), 1)
+	pat.TimeSig = (pat.TimeSig + 1) % len(TimeSigs)
+	pat.Length = min(bars*pat.Sig().BarBeats(), maxPianoLength)
+}
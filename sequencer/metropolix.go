@@ -2,7 +2,6 @@ package sequencer
 
 import (
 	"fmt"
-	"math/rand"
 	"sync"
 
 	"go-sequence/debug"
@@ -45,6 +44,45 @@ var scaleNames = []string{
 }
 var modeNames = []string{"Forward", "Reverse", "Pendulum", "Random"}
 
+// inScale reports whether a pitch belongs to scale rooted at pitch class root
+func inScale(pitch int, root uint8, scale ScaleType) bool {
+	class := ((pitch-int(root))%12 + 12) % 12
+	for _, interval := range scaleIntervals(scale) {
+		if interval%12 == class {
+			return true
+		}
+	}
+	return false
+}
+
+// stepInScale returns the next in-scale pitch above (dir > 0) or below, -1 if none in MIDI range
+func stepInScale(pitch, dir int, root uint8, scale ScaleType) int {
+	if dir > 0 {
+		dir = 1
+	} else {
+		dir = -1
+	}
+	for p := pitch + dir; p >= 0 && p <= 127; p += dir {
+		if inScale(p, root, scale) {
+			return p
+		}
+	}
+	return -1
+}
+
+// snapToScale returns the nearest in-scale pitch (ties go down)
+func snapToScale(pitch int, root uint8, scale ScaleType) int {
+	for d := 0; d < 12; d++ {
+		if p := pitch - d; p >= 0 && inScale(p, root, scale) {
+			return p
+		}
+		if p := pitch + d; p <= 127 && inScale(p, root, scale) {
+			return p
+		}
+	}
+	return pitch
+}
+
 // Launchpad pages
 const (
 	PageAccumulator  = 0 // Has sub-pages: value, reset, mode
@@ -83,6 +121,7 @@ type MetropolixDevice struct {
 	// Queue-based playback - protected by queueMu (held ONLY during swap, not generation)
 	queueMu          sync.RWMutex
 	queue            []midi.Event // events sorted by tick
+	popped           poppedMark   // how far dispatch has popped it (see queuetrim.go)
 	queuedUntilTick  int64        // how far we've filled the queue
 	patternStartTick int64        // tick when current pattern started
 	onQueueChange    func()       // callback to wake manager when queue needs recalc
@@ -94,6 +133,40 @@ type MetropolixDevice struct {
 	confirmMode   bool
 	confirmMsg    string
 	confirmAction func()
+
+	// Undo/redo - snapshots of patterns before edits
+	history undoHistory[MetropolixPatternState]
+
+	// Re-roll - seed switching in at the next bar (see seed.go)
+	pendingSeed     int64
+	pendingSeedTick int64
+	seedPending     bool
+
+	// MOD lanes - lane the keys edit, last value sent per lane (-1 = unknown)
+	modLane int
+	modLast [numModLanes]int
+
+	// Scale editing - pitch class under the cursor (0-11 above the root)
+	scaleCursor int
+
+	// Root follow - shift switching in at pendingRootTick (see rootfollow.go)
+	pendingRoot     int
+	pendingRootTick int64
+	rootPending     bool
+
+	// Stage loop - runtime only (see stageloop.go)
+	looping    bool
+	loopStart  int
+	loopEnd    int
+	loopAnchor int  // first stage of a loop being set (-1 = none)
+	loopHeld   bool // loop pad is down
+	loopSet    bool // a loop was set during this hold
+
+	// Randomize preview (see metropolixrandom.go)
+	randomMode    bool
+	random        metropolixRandomize
+	randomPattern int                    // pattern being randomized
+	randomBase    MetropolixPatternState // pattern data before randomize
 }
 
 // NewMetropolixDevice creates a device that operates on the given state
@@ -101,6 +174,8 @@ func NewMetropolixDevice(state *MetropolixState) *MetropolixDevice {
 	return &MetropolixDevice{
 		state:           state,
 		nextPatternTick: -1,
+		modLast:         [numModLanes]int{-1, -1},
+		loopAnchor:      -1,
 	}
 }
 
@@ -113,7 +188,7 @@ func (d *MetropolixDevice) SetOnQueueChange(fn func()) {
 func (d *MetropolixDevice) fauxPatternLength(patternNum int) int {
 	pat := &d.state.Patterns[patternNum]
 	total := 0
-	for i := 0; i < pat.Length; i++ {
+	for _, i := range d.cycleStages(patternNum) {
 		total += pat.Stages[i].PulseCount
 	}
 	return total
@@ -121,7 +196,7 @@ func (d *MetropolixDevice) fauxPatternLength(patternNum int) int {
 
 // fauxPatternTicks returns the faux pattern length in ticks
 func (d *MetropolixDevice) fauxPatternTicks(patternNum int) int64 {
-	return int64(d.fauxPatternLength(patternNum)) * (PPQ / 4)
+	return int64(d.fauxPatternLength(patternNum)) * d.state.Patterns[patternNum].stepTicks()
 }
 
 // GeneratePattern generates all MIDI events for one faux cycle starting at startTick.
@@ -129,39 +204,54 @@ func (d *MetropolixDevice) fauxPatternTicks(patternNum int) int64 {
 func (d *MetropolixDevice) GeneratePattern(patternNum int, startTick int64) []midi.Event {
 	s := d.state
 	pat := &s.Patterns[patternNum]
-	ticksPerStep := int64(PPQ / 4)
+	ticksPerStep := pat.stepTicks()
 
 	var events []midi.Event
 
-	// Reset stage position for fresh faux cycle
-	s.Stage = 0
+	// Reset stage position for fresh faux cycle (first stage that isn't skipped or looped out)
+	active := d.cycleStages(patternNum)
+	s.Stage = active[0]
 
 	// Track current tick position
 	currentTick := startTick
 
-	// Process each stage
-	for stageIdx := 0; stageIdx < pat.Length; stageIdx++ {
+	// Process each playing stage
+	for range active {
 		stage := &pat.Stages[s.Stage]
 		stageTicks := int64(stage.PulseCount) * ticksPerStep
+		d.commitRoot(currentTick)
+
+		// MOD lanes - each stage's CC values, gliding in by the lane's slew
+		for lane := range pat.Mod {
+			events = append(events, d.modEvents(pat, lane, s.Stage, currentTick, stageTicks)...)
+		}
 
 		// Generate ratchets within this stage's time span
-		if stage.Gate && stage.Ratchets > 0 {
-			ratchetInterval := stageTicks / int64(stage.Ratchets)
-			if ratchetInterval < 1 {
-				ratchetInterval = 1
+		if stage.Gate && stage.Ratchets > 0 && stage.playsOnCycle(s.Cycle) {
+			// Held stages tie one note across every pulse
+			ratchets := stage.Ratchets
+			if stage.Hold {
+				ratchets = 1
 			}
+			offsets := ratchetOffsets(ratchets, stage.RatchetShape, stageTicks)
 
-			for r := 0; r < stage.Ratchets; r++ {
-				// Probability check per ratchet
-				if rand.Intn(100) >= stage.Probability {
+			for r := 0; r < ratchets; r++ {
+				// Probability check per ratchet - repeats also roll the ratchet probability
+				ratchetTick := currentTick + offsets[r]
+				if d.randIntn(100, ratchetTick, ratchetTick-startTick, r) >= stage.Probability {
+					continue
+				}
+				if r > 0 && d.randIntn(100, ratchetTick, ratchetTick-startTick, 100+r) >= stage.ratchetProbability() {
 					continue
 				}
 
-				ratchetTick := currentTick + int64(r)*ratchetInterval
+				// Swing pushes off-beat hits late (the gate shrinks to keep its end)
+				swing := swingDelay(ratchetTick, pat.Swing, ticksPerStep)
+				hitTick := ratchetTick + swing
 
 				pitch := d.calculatePitch(s.Stage)
 				events = append(events, midi.Event{
-					Tick:     ratchetTick,
+					Tick:     hitTick,
 					Type:     midi.NoteOn,
 					Note:     uint8(pitch),
 					Velocity: 100,
@@ -169,25 +259,29 @@ func (d *MetropolixDevice) GeneratePattern(patternNum int, startTick int64) []mi
 
 				// Note-off based on gate length
 				gateLengths := []int64{0, 1, 2, 4, 8, 16}
-				gt := gateLengths[stage.GateLength] * ticksPerStep
+				gt := gateLengths[stage.GateLength] * ticksPerStep * int64(s.gateScale()) / 100
+				if stage.Hold {
+					gt = stageTicks
+				}
 				if gt == 0 {
 					// Trigger mode - immediate note-off
 					events = append(events, midi.Event{
-						Tick: ratchetTick,
+						Tick: hitTick,
 						Type: midi.NoteOff,
 						Note: uint8(pitch),
 					})
 				} else {
 					// Clamp gate to not exceed next ratchet or stage end
-					maxGate := ratchetInterval
-					if r == stage.Ratchets-1 {
-						maxGate = stageTicks - int64(r)*ratchetInterval
+					maxGate := stageTicks - offsets[r]
+					if r < ratchets-1 {
+						maxGate = offsets[r+1] - offsets[r]
 					}
+					maxGate = max(maxGate-swing, 1)
 					if gt > maxGate {
 						gt = maxGate
 					}
 					events = append(events, midi.Event{
-						Tick: ratchetTick + gt,
+						Tick: hitTick + gt,
 						Type: midi.NoteOff,
 						Note: uint8(pitch),
 					})
@@ -199,7 +293,8 @@ func (d *MetropolixDevice) GeneratePattern(patternNum int, startTick int64) []mi
 		d.applyAccumulator(s.Stage)
 
 		// Slide pitch bend at stage transitions
-		nextStage := d.nextStage()
+		stageEnd := currentTick + stageTicks
+		nextStage := d.nextStage(stageEnd, stageEnd-startTick)
 		if stage.Slide && nextStage != s.Stage && pat.SlideTime > 0 {
 			slideStartTick := currentTick + stageTicks
 			startPitch := d.calculatePitch(s.Stage)
@@ -225,6 +320,7 @@ func (d *MetropolixDevice) GeneratePattern(patternNum int, startTick int64) []mi
 		currentTick += stageTicks
 		s.Stage = nextStage
 	}
+	s.Cycle++
 
 	return events
 }
@@ -256,12 +352,20 @@ func (d *MetropolixDevice) FillUntil(tick int64) {
 			patternStart = nextPatTick
 			nextPatTick = -1
 			d.state.ResetAccumulators()
+			d.state.Cycle = 0
 		}
 
 		events := d.GeneratePattern(currentPattern, queuedUntil)
+		end := queuedUntil + d.fauxPatternTicks(currentPattern)
+		if nextPatTick > queuedUntil && nextPatTick < end {
+			// A restart cuts this pass short
+			events = cutEvents(events, nextPatTick)
+			end = nextPatTick
+		}
 		newEvents = append(newEvents, events...)
-		queuedUntil += d.fauxPatternTicks(currentPattern)
+		queuedUntil = end
 	}
+	d.commitSeed(queuedUntil)
 
 	// Swap in new events (brief lock)
 	d.queueMu.Lock()
@@ -291,9 +395,10 @@ func (d *MetropolixDevice) PopNextEvent() *midi.Event {
 	if len(d.queue) == 0 {
 		return nil
 	}
-	event := d.queue[0]
+	event := &d.queue[0] // no copy - queues are replaced, never written in place
 	d.queue = d.queue[1:]
-	return &event
+	d.popped.pop(event.Tick)
+	return event
 }
 
 // ClearQueue clears all queued events (for stop/restart)
@@ -302,10 +407,14 @@ func (d *MetropolixDevice) ClearQueue() {
 	defer d.queueMu.Unlock()
 
 	d.queue = nil
+	d.popped = poppedMark{}
 	d.queuedUntilTick = 0
 	d.patternStartTick = 0
 	d.nextPatternTick = -1
 	d.state.ResetPlayback()
+	for i := range d.modLast {
+		d.modLast[i] = -1 // the next stage sends its value outright
+	}
 }
 
 func (d *MetropolixDevice) calculatePitch(stageIdx int) int {
@@ -313,7 +422,7 @@ func (d *MetropolixDevice) calculatePitch(stageIdx int) int {
 	pat := &s.Patterns[s.Pattern]
 	stage := &pat.Stages[stageIdx]
 
-	scale := scales[pat.Scale]
+	scale := scaleIntervals(pat.Scale)
 	scaleLen := len(scale)
 
 	// Base pitch from scale degree
@@ -324,8 +433,8 @@ func (d *MetropolixDevice) calculatePitch(stageIdx int) int {
 	// Add octave offset (4 = middle, so offset from 4)
 	basePitch += (stage.Octave - 4) * 12
 
-	// Add accumulator
-	basePitch += s.Accum[stageIdx]
+	// Add accumulator and live transpose
+	basePitch += s.Accum[stageIdx] + s.RootShift
 
 	// Clamp to valid MIDI range
 	if basePitch < 0 {
@@ -338,7 +447,33 @@ func (d *MetropolixDevice) calculatePitch(stageIdx int) int {
 	return basePitch
 }
 
-func (d *MetropolixDevice) nextStage() int {
+// nextStage returns the stage after the current one, stepping over skipped stages
+// and stages outside the stage loop
+func (d *MetropolixDevice) nextStage(tick, offset int64) int {
+	s := d.state
+	pat := &s.Patterns[s.Pattern]
+
+	if pat.Mode == ModeRandom {
+		active := d.cycleStages(s.Pattern)
+		return active[d.randIntn(len(active), tick, offset, -1)]
+	}
+
+	// Walk on from the current stage until one plays (pendulum keeps turning at the ends)
+	current := s.Stage
+	next := current
+	for range pat.Length {
+		next = d.stepStage(tick, offset)
+		if d.stagePlays(next) {
+			break
+		}
+		s.Stage = next
+	}
+	s.Stage = current
+	return next
+}
+
+// stepStage returns the neighbouring stage for the playback mode
+func (d *MetropolixDevice) stepStage(tick, offset int64) int {
 	s := d.state
 	pat := &s.Patterns[s.Pattern]
 
@@ -368,7 +503,7 @@ func (d *MetropolixDevice) nextStage() int {
 		}
 		return next
 	case ModeRandom:
-		return rand.Intn(pat.Length)
+		return d.randIntn(pat.Length, tick, offset, -1)
 	default:
 		return (s.Stage + 1) % pat.Length
 	}
@@ -430,23 +565,15 @@ func (d *MetropolixDevice) QueuePattern(p int, atTick int64) {
 	d.queueMu.RUnlock()
 
 	// Calculate when the next pattern boundary occurs
-	ticksSinceStart := atTick - patternStart
-	ticksIntoPattern := ticksSinceStart % patternTicks
-	ticksToNextBoundary := patternTicks - ticksIntoPattern
-	boundaryTick := atTick + ticksToNextBoundary
+	boundaryTick := boundaryAfter(atTick, patternStart, patternTicks)
 
 	needsNotify := false
 
-	// If we've already queued past the boundary, wipe those events
+	// If we've already queued past the boundary, wipe those events (note-offs move
+	// onto the boundary so nothing is left hanging)
 	if queuedUntil > boundaryTick {
 		d.queueMu.Lock()
-		newQueue := d.queue[:0]
-		for _, e := range d.queue {
-			if e.Tick < boundaryTick {
-				newQueue = append(newQueue, e)
-			}
-		}
-		d.queue = newQueue
+		d.queue = cutEvents(d.queue, boundaryTick)
 		d.queuedUntilTick = boundaryTick
 		d.nextPatternTick = boundaryTick
 		d.queueMu.Unlock()
@@ -477,6 +604,7 @@ func (d *MetropolixDevice) regeneratePatternInQueue(patternNum int) {
 	oldQueue := d.queue
 	oldQueuedUntil := d.queuedUntilTick
 	patternStart := d.patternStartTick
+	nextPatTick := d.nextPatternTick
 	d.queueMu.RUnlock()
 
 	// --- Generate new queue OUTSIDE the lock (this is the slow part) ---
@@ -490,16 +618,22 @@ func (d *MetropolixDevice) regeneratePatternInQueue(patternNum int) {
 	}
 
 	// Regenerate from pattern start to where we had queued
+	d.state.Cycle = 0
 	newQueuedUntil := patternStart
 	for newQueuedUntil < oldQueuedUntil {
 		events := d.GeneratePattern(d.state.Pattern, newQueuedUntil)
 		newQueue = append(newQueue, events...)
 		newQueuedUntil += patternTicks
 	}
+	if nextPatTick >= 0 && nextPatTick < newQueuedUntil {
+		// A pending restart cuts the last pass short
+		newQueue = cutEvents(newQueue, nextPatTick)
+		newQueuedUntil = nextPatTick
+	}
 
 	// --- Swap in new queue (brief lock) ---
 	d.queueMu.Lock()
-	d.queue = newQueue
+	d.queue = d.popped.trim(newQueue) // already sent events stay sent
 	d.queuedUntilTick = newQueuedUntil
 	d.queueMu.Unlock()
 
@@ -530,7 +664,8 @@ func (d *MetropolixDevice) ContentMask() []bool {
 		for s := 0; s < pat.Length; s++ {
 			stage := &pat.Stages[s]
 			if !stage.Gate || stage.Ratchets != 1 || stage.PulseCount != 1 ||
-				stage.Slide || stage.Accumulator != 0 || stage.Probability != 100 {
+				stage.Slide || stage.Accumulator != 0 || stage.Probability != 100 ||
+				stage.Skip || stage.Hold {
 				mask[i] = true
 				break
 			}
@@ -539,7 +674,37 @@ func (d *MetropolixDevice) ContentMask() []bool {
 	return mask
 }
 
+// PatternBars returns each pattern's faux length in bars (16 steps)
+func (d *MetropolixDevice) PatternBars() []float64 {
+	bars := make([]float64, NumPatterns)
+	for i := range d.state.Patterns {
+		bars[i] = float64(d.fauxPatternTicks(i)) / (4 * PPQ)
+	}
+	return bars
+}
+
+// Density returns expected notes per bar for each pattern,
+// counting ratchets and weighting by probability
+func (d *MetropolixDevice) Density() []float64 {
+	density := make([]float64, NumPatterns)
+	for i := range d.state.Patterns {
+		pat := &d.state.Patterns[i]
+		notes := 0.0
+		for s := 0; s < pat.Length; s++ {
+			stage := &pat.Stages[s]
+			if stage.Gate {
+				notes += float64(stage.Ratchets) * float64(stage.Probability) / 100
+			}
+		}
+		if ticks := d.fauxPatternTicks(i); ticks > 0 {
+			density[i] = notes * 4 * PPQ / float64(ticks)
+		}
+	}
+	return density
+}
+
 func (d *MetropolixDevice) HandleMIDI(event midi.Event) {
+	d.followRoot(event)
 	// Could record incoming notes to stages
 }
 
@@ -557,8 +722,12 @@ func (d *MetropolixDevice) View() string {
 	if s.Editing != s.Pattern {
 		playInfo = fmt.Sprintf(" (playing:%d)", s.Pattern+1)
 	}
-	out := fmt.Sprintf("METROPOLIX  Pattern %d%s  Stage %d/%d  Mode: %s\n\n",
-		s.Editing+1, playInfo, s.Stage+1, pat.Length, modeNames[pat.Mode])
+	seedInfo := seedName(s.Seed)
+	if d.seedPending {
+		seedInfo += " → " + seedName(d.pendingSeed) + " next bar"
+	}
+	out := fmt.Sprintf("METROPOLIX  Pattern %d%s  Stage %d/%d  Mode: %s  Seed: %s\n\n",
+		s.Editing+1, playInfo, s.Stage+1, pat.Length, modeNames[pat.Mode], seedInfo)
 
 	// Confirmation dialog
 	if d.confirmMode {
@@ -632,10 +801,21 @@ func (d *MetropolixDevice) View() string {
 	for i := 0; i < 8; i++ {
 		if i < pat.Length {
 			stage := &pat.Stages[i]
-			out += fmt.Sprintf("  %d  │", stage.Ratchets)
+			shape := " "
+			switch stage.RatchetShape {
+			case RatchetAccel:
+				shape = ">"
+			case RatchetDecel:
+				shape = "<"
+			}
+			prob := " "
+			if stage.ratchetProbability() < 100 {
+				prob = "?"
+			}
+			out += fmt.Sprintf(" %d%s%s │", stage.Ratchets, shape, prob)
 		}
 	}
-	out += " Ratchets\n"
+	out += " Ratchets (> accel, < decel, ? chance)\n"
 
 	// Slide row
 	out += "   │"
@@ -651,6 +831,39 @@ func (d *MetropolixDevice) View() string {
 	}
 	out += " Slide\n"
 
+	// Skip/hold row
+	out += "   │"
+	for i := 0; i < 8; i++ {
+		if i < pat.Length {
+			stage := &pat.Stages[i]
+			flag := "     "
+			switch {
+			case stage.Skip && stage.Hold:
+				flag = " s+h "
+			case stage.Skip:
+				flag = "skip "
+			case stage.Hold:
+				flag = "hold "
+			}
+			out += flag + "│"
+		}
+	}
+	out += " Skip/Hold\n"
+
+	// Cycle condition row
+	out += "   │"
+	for i := 0; i < 8; i++ {
+		if i < pat.Length {
+			stage := &pat.Stages[i]
+			if stage.every() > 1 {
+				out += fmt.Sprintf(" 1:%d │", stage.every())
+			} else {
+				out += "     │"
+			}
+		}
+	}
+	out += " Every\n"
+
 	// Accumulator row
 	out += "   │"
 	for i := 0; i < 8; i++ {
@@ -666,6 +879,20 @@ func (d *MetropolixDevice) View() string {
 		}
 	}
 	out += " Accum\n"
+	out += d.accumLiveRow(pat.Length)
+
+	// MOD lane rows - the lane the keys edit is marked
+	for lane := range pat.Mod {
+		out += "   │"
+		for i := 0; i < pat.Length; i++ {
+			out += fmt.Sprintf(" %3d │", pat.Mod[lane].Values[i])
+		}
+		marker := " "
+		if lane == d.modLane {
+			marker = "*"
+		}
+		out += fmt.Sprintf("%s%s\n", marker, modLaneNames[lane])
+	}
 
 	out += "   └"
 	for i := 0; i < 8; i++ {
@@ -681,8 +908,29 @@ func (d *MetropolixDevice) View() string {
 	out += "┘\n"
 
 	// Global settings
-	out += fmt.Sprintf("\nLength: %d  Scale: %s  Root: %s  SlideTime: %d\n",
-		pat.Length, scaleNames[pat.Scale], d.pitchToName(int(pat.RootNote)), pat.SlideTime)
+	out += fmt.Sprintf("\nLength: %d  Clock: %s  Scale: %s  Root: %s  SlideTime: %d\n",
+		pat.Length, clockDivNames[pat.ClockDiv], scaleName(pat.Scale), d.pitchToName(int(pat.RootNote)), pat.SlideTime)
+	out += fmt.Sprintf("Scale notes: %s  (b/B move, i toggle)\n", scaleRow(pat.Scale, int(pat.RootNote), d.scaleCursor))
+	out += fmt.Sprintf("Swing: %d%%  Gate time: %d%%  Root follow: %s  Loop: %s\n", pat.Swing, s.gateScale(), d.rootFollowInfo(), d.loopInfo())
+	out += fmt.Sprintf("Mod A: %s  Mod B: %s\n", modLaneInfo(&pat.Mod[0]), modLaneInfo(&pat.Mod[1]))
+
+	// Randomize preview replaces key help
+	if d.randomMode {
+		out += "\n─────────────────────────────────────────────────\n"
+		out += fmt.Sprintf("RANDOMIZE  re-rolling: %s  (previewing)\n\n", d.random)
+		out += widgets.RenderKeyHelp([]widgets.KeySection{
+			{Keys: []widgets.KeyBinding{
+				{Key: "p / g / r", Desc: "toggle pitches / gates / ratchets"},
+				{Key: "R", Desc: "ratchets only"},
+				{Key: "h / l", Desc: "pitch range -/+ (octaves)"},
+				{Key: "space", Desc: "re-roll"},
+				{Key: "y / enter", Desc: "apply"},
+				{Key: "n / esc", Desc: "cancel"},
+			}},
+		})
+		out += "\n─────────────────────────────────────────────────\n"
+		return out
+	}
 
 	// Key help
 	out += "\n"
@@ -692,20 +940,37 @@ func (d *MetropolixDevice) View() string {
 			{Key: "j / k", Desc: "adjust pitch"},
 			{Key: "space", Desc: "toggle gate"},
 			{Key: "r / R", Desc: "ratchets -/+"},
+			{Key: "y / Y", Desc: "ratchet probability -/+"},
+			{Key: "d", Desc: "cycle ratchet shape"},
 			{Key: "s", Desc: "toggle slide"},
+			{Key: "o / t", Desc: "toggle skip / hold"},
+			{Key: "O", Desc: "mark stage loop start / end (again clears)"},
 			{Key: "a / A", Desc: "accumulator -/+"},
 			{Key: "p / P", Desc: "probability -/+"},
+			{Key: "C", Desc: "cycle condition (play every 1-8 passes)"},
 			{Key: "m", Desc: "cycle mode"},
 			{Key: "q", Desc: "cycle scale"},
+			{Key: "{ / }", Desc: "swing -/+"},
+			{Key: "T", Desc: "cycle clock division (1/16, 1/8, 1/4)"},
+			{Key: "( / )", Desc: "gate time -/+ (all stages)"},
+			{Key: "f / F", Desc: "root follow off/stage/cycle, reset"},
+			{Key: "b / B, i", Desc: "scale note cursor, toggle note (makes a user scale)"},
 			{Key: "z / x", Desc: "root note -/+"},
 			{Key: "[ / ]", Desc: "length -/+"},
 			{Key: "< / >", Desc: "prev/next pattern"},
+			{Key: "e / E", Desc: "re-roll seed / free (next bar)"},
+			{Key: "w", Desc: "switch MOD lane"},
+			{Key: "v / V", Desc: "MOD value -/+"},
+			{Key: "n / N", Desc: "MOD CC -/+ (0 = off)"},
+			{Key: "g / G", Desc: "MOD slew -/+"},
+			{Key: "X", Desc: "randomize (pitches / gates / ratchets, previewed)"},
+			{Key: "u / ctrl+r", Desc: "undo / redo"},
 		}},
 	})
 
 	// Launchpad help
 	out += "\n\n"
-	out += d.renderLaunchpadHelp()
+	out += widgets.RenderLayout(d.HelpLayout())
 
 	return out
 }
@@ -739,7 +1004,12 @@ func (d *MetropolixDevice) RenderLEDs() []LEDState {
 	// Main grid depends on current page
 	switch s.Page {
 	case PageSettings:
-		leds = append(leds, d.renderSettingsPage()...)
+		if s.SettingsSubPage == SettingsSubScales {
+			leds = append(leds, d.renderScalePickerPage()...)
+		} else {
+			leds = append(leds, d.renderSettingsPage()...)
+		}
+		leds = append(leds, subPageArrows(s.SettingsSubPage, SettingsSubScales)...)
 	case PageOctave:
 		leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.Octave }, 8)...)
 	case PageNotes:
@@ -747,15 +1017,66 @@ func (d *MetropolixDevice) RenderLEDs() []LEDState {
 	case PagePulseCount:
 		leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.PulseCount - 1 }, 8)...)
 	case PageRatchets:
-		leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.Ratchets - 1 }, 8)...)
+		switch s.RatchetSubPage {
+		case RatchetSubProb:
+			leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return ratchetProbRow(stage.ratchetProbability()) }, 8)...)
+		case RatchetSubShape:
+			leds = append(leds, d.renderRatchetShapePage()...)
+		default:
+			leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.Ratchets - 1 }, 8)...)
+		}
+		leds = append(leds, subPageArrows(s.RatchetSubPage, RatchetSubShape)...)
 	case PageProbability:
-		leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.Probability / 13 }, 8)...)
+		if s.ProbSubPage == ProbSubEvery {
+			leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.every() - 1 }, 8)...)
+		} else {
+			leds = append(leds, d.renderValuePage(func(stage *MetropolixStageState) int { return stage.Probability / 13 }, 8)...)
+		}
+		leds = append(leds, subPageArrows(s.ProbSubPage, ProbSubEvery)...)
 	case PageGate:
-		leds = append(leds, d.renderGatePage()...)
+		if s.GateSubPage == GateSubFlags {
+			leds = append(leds, d.renderStageFlagsPage()...)
+		} else {
+			leds = append(leds, d.renderGatePage()...)
+		}
+		leds = append(leds, subPageArrows(s.GateSubPage, GateSubFlags)...)
 	case PageAccumulator:
 		leds = append(leds, d.renderAccumulatorPage()...)
+	case PageModA, PageModB:
+		leds = append(leds, d.renderModPage(modPageLane(s.Page))...)
+	}
+
+	// MOD page pads (top row, cols 5-6) - bright while open
+	for lane := range numModLanes {
+		color := [3]uint8{0, 40, 35}
+		if modPageLane(s.Page) == lane {
+			color = [3]uint8{0, 200, 180}
+		}
+		leds = append(leds, LEDState{Row: 8, Col: 5 + lane, Color: color, Channel: midi.ChannelStatic})
 	}
 
+	// Re-roll pad (top row, right) - pulses until the new seed's bar, bright while locked
+	rerollColor := [3]uint8{60, 50, 0}
+	rerollChannel := midi.ChannelStatic
+	if s.Seed != 0 {
+		rerollColor = [3]uint8{255, 200, 0}
+	}
+	if d.seedPending {
+		rerollChannel = midi.ChannelPulse
+	}
+	leds = append(leds, LEDState{Row: 8, Col: 7, Color: rerollColor, Channel: rerollChannel})
+
+	// Stage loop pad (top row, col 4) - bright while looping, pulsing while held
+	loopColor := [3]uint8{0, 30, 60}
+	loopChannel := midi.ChannelStatic
+	if d.looping {
+		loopColor = [3]uint8{0, 120, 255}
+	}
+	if d.loopHeld {
+		loopChannel = midi.ChannelPulse
+	}
+	leds = append(leds, LEDState{Row: 8, Col: loopPadCol, Color: loopColor, Channel: loopChannel})
+
 	// Playhead indicator - pulse the current stage column
 	for row := 0; row < 8; row++ {
 		if s.Stage < pat.Length {
@@ -880,20 +1201,7 @@ func (d *MetropolixDevice) renderAccumulatorPage() []LEDState {
 	centerColor := [3]uint8{100, 100, 100}
 
 	// Top row: show up/down arrows on cols 1 and 2
-	upColor := dimColor
-	downColor := dimColor
-	if s.AccumSubPage > 0 {
-		upColor = activeColor // Can go up (to previous sub-page)
-	}
-	if s.AccumSubPage < 2 {
-		downColor = activeColor // Can go down (to next sub-page)
-	}
-	leds = append(leds, LEDState{Row: 8, Col: 0, Color: offColor, Channel: midi.ChannelStatic})
-	leds = append(leds, LEDState{Row: 8, Col: 1, Color: upColor, Channel: midi.ChannelStatic})
-	leds = append(leds, LEDState{Row: 8, Col: 2, Color: downColor, Channel: midi.ChannelStatic})
-	for col := 3; col < 8; col++ {
-		leds = append(leds, LEDState{Row: 8, Col: col, Color: offColor, Channel: midi.ChannelStatic})
-	}
+	leds = append(leds, subPageArrows(s.AccumSubPage, AccumSubLive)...)
 
 	// Render based on sub-page
 	switch s.AccumSubPage {
@@ -989,6 +1297,9 @@ func (d *MetropolixDevice) renderAccumulatorPage() []LEDState {
 				leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
 			}
 		}
+
+	case AccumSubLive:
+		leds = append(leds, d.renderAccumLivePage()...)
 	}
 
 	return leds
@@ -1011,8 +1322,13 @@ func (d *MetropolixDevice) renderSettingsPage() []LEDState {
 		}
 		leds = append(leds, LEDState{Row: 7, Col: col, Color: color, Channel: midi.ChannelStatic})
 	}
-	for col := 4; col < 8; col++ {
-		leds = append(leds, LEDState{Row: 7, Col: col, Color: offColor, Channel: midi.ChannelStatic})
+	// Row 7 (columns 4-7): Swing
+	for i, v := range swingPadValues {
+		color := dimColor
+		if pat.Swing >= v {
+			color = activeColor
+		}
+		leds = append(leds, LEDState{Row: 7, Col: 4 + i, Color: color, Channel: midi.ChannelStatic})
 	}
 
 	// Row 6: Scale (columns 0-3)
@@ -1023,8 +1339,13 @@ func (d *MetropolixDevice) renderSettingsPage() []LEDState {
 		}
 		leds = append(leds, LEDState{Row: 6, Col: col, Color: color, Channel: midi.ChannelStatic})
 	}
-	for col := 4; col < 8; col++ {
-		leds = append(leds, LEDState{Row: 6, Col: col, Color: offColor, Channel: midi.ChannelStatic})
+	// Row 6 (columns 4-7): Gate time
+	for i, v := range gateScalePadValues {
+		color := dimColor
+		if s.gateScale() == v {
+			color = activeColor
+		}
+		leds = append(leds, LEDState{Row: 6, Col: 4 + i, Color: color, Channel: midi.ChannelStatic})
 	}
 
 	// Row 5: Length (1-8)
@@ -1055,17 +1376,79 @@ func (d *MetropolixDevice) renderSettingsPage() []LEDState {
 		leds = append(leds, LEDState{Row: 3, Col: col, Color: color, Channel: midi.ChannelStatic})
 	}
 
-	// Rows 0-2: Reserved
-	for row := 0; row < 3; row++ {
-		for col := 0; col < 8; col++ {
-			leds = append(leds, LEDState{Row: row, Col: col, Color: offColor, Channel: midi.ChannelStatic})
+	// Rows 2-1: Scale keyboard (black keys above white)
+	leds = append(leds, renderScaleKeys(pat.Scale)...)
+
+	// Row 0: Root follow mode (columns 0-2), clock division (columns 4-6), reset live transpose (column 7)
+	for col := 0; col < 8; col++ {
+		color := offColor
+		div := col - clockDivPadCol
+		switch {
+		case div >= 0 && div < len(clockDivNames) && div == pat.ClockDiv:
+			color = activeColor
+		case div >= 0 && div < len(clockDivNames):
+			color = dimColor
+		case col < len(rootFollowNames) && col == s.RootFollow:
+			color = activeColor
+		case col < len(rootFollowNames):
+			color = dimColor
+		case col == 7 && s.RootShift != 0:
+			color = activeColor
+		case col == 7:
+			color = dimColor
 		}
+		leds = append(leds, LEDState{Row: 0, Col: col, Color: color, Channel: midi.ChannelStatic})
 	}
 
 	return leds
 }
 
+// trackEdit snapshots the editing pattern; the returned func records an undo step
+// if the pattern changed since. The randomize preview records its own step when applied.
+func (d *MetropolixDevice) trackEdit() func() {
+	idx := d.state.Editing
+	before := d.state.Patterns[idx]
+	previewing := d.randomMode
+	return func() {
+		if previewing || d.randomMode {
+			return
+		}
+		if d.state.Patterns[idx] != before {
+			d.history.push(idx, before)
+		}
+	}
+}
+
+func (d *MetropolixDevice) patternData(pattern int) MetropolixPatternState {
+	return d.state.Patterns[pattern]
+}
+
+// Undo restores the most recent snapshot
+func (d *MetropolixDevice) Undo() {
+	if e, ok := d.history.stepBack(d.patternData); ok {
+		d.restorePattern(e.pattern, e.data)
+	}
+}
+
+// Redo re-applies the most recently undone edit
+func (d *MetropolixDevice) Redo() {
+	if e, ok := d.history.stepForward(d.patternData); ok {
+		d.restorePattern(e.pattern, e.data)
+	}
+}
+
+// restorePattern swaps in a snapshot and shows it
+func (d *MetropolixDevice) restorePattern(pattern int, data MetropolixPatternState) {
+	d.state.Patterns[pattern] = data
+	d.state.Editing = pattern
+	d.regeneratePatternInQueue(pattern)
+}
+
 func (d *MetropolixDevice) HandleKey(key string) {
+	if key != "u" && key != "ctrl+r" {
+		defer d.trackEdit()()
+	}
+
 	// Confirmation mode
 	if d.confirmMode {
 		switch key {
@@ -1082,6 +1465,12 @@ func (d *MetropolixDevice) HandleKey(key string) {
 		return
 	}
 
+	// Randomize preview
+	if d.randomMode {
+		d.handleRandomizeKey(key)
+		return
+	}
+
 	s := d.state
 	pat := &s.Patterns[s.Editing]
 	stage := &pat.Stages[s.Selected]
@@ -1121,8 +1510,43 @@ func (d *MetropolixDevice) HandleKey(key string) {
 		if stage.Ratchets < 8 {
 			stage.Ratchets++
 		}
+	case "y":
+		stage.RatchetProb = max(stage.ratchetProbability()-10, 10)
+	case "Y":
+		stage.RatchetProb = min(stage.ratchetProbability()+10, 100)
+	case "d":
+		stage.RatchetShape = (stage.RatchetShape + 1) % len(ratchetShapeNames)
 	case "s":
 		stage.Slide = !stage.Slide
+	case "b":
+		d.scaleCursor = (d.scaleCursor + 11) % 12
+	case "B":
+		d.scaleCursor = (d.scaleCursor + 1) % 12
+	case "i":
+		toggleScaleClass(&pat.Scale, d.scaleCursor)
+		d.regeneratePatternInQueue(s.Editing)
+	case "f":
+		d.cycleRootFollow()
+	case "F":
+		d.resetRoot()
+	case "{":
+		d.nudgeSwing(-swingStep)
+	case "}":
+		d.nudgeSwing(swingStep)
+	case "(":
+		d.nudgeGateScale(-gateScaleStep)
+	case ")":
+		d.nudgeGateScale(gateScaleStep)
+	case "O":
+		d.markLoop(s.Selected)
+	case "o":
+		stage.Skip = !stage.Skip
+	case "t":
+		stage.Hold = !stage.Hold
+	case "T":
+		d.cycleClockDiv()
+	case "C":
+		stage.cycleEvery()
 	case "a":
 		if stage.Accumulator > -4 {
 			stage.Accumulator--
@@ -1168,9 +1592,29 @@ func (d *MetropolixDevice) HandleKey(key string) {
 		}
 	case "c":
 		d.confirmClearPattern()
+	case "X":
+		d.StartRandomize()
+	case "w":
+		d.modLane = (d.modLane + 1) % numModLanes
+	case "v":
+		d.nudgeModValue(-modStep)
+	case "V":
+		d.nudgeModValue(modStep)
+	case "n":
+		d.nudgeModCC(-1)
+	case "N":
+		d.nudgeModCC(1)
+	case "g":
+		d.nudgeModSlew(-1)
+	case "G":
+		d.nudgeModSlew(1)
+	case "e":
+		d.Reroll()
+	case "E":
+		d.FreeSeed()
 	case "q":
 		// Cycle scale forward (wraps)
-		pat.Scale = (pat.Scale + 1) % ScaleCount
+		pat.Scale = ScaleType((int(pat.Scale) + 1) % numScales())
 		d.regeneratePatternInQueue(s.Editing)
 	case "z":
 		// Root note down
@@ -1184,20 +1628,33 @@ func (d *MetropolixDevice) HandleKey(key string) {
 			pat.RootNote++
 		}
 		d.regeneratePatternInQueue(s.Editing)
+	case "u":
+		d.Undo()
+	case "ctrl+r":
+		d.Redo()
 	}
 }
 
+// IsInputMode returns true if in confirm or randomize mode
+func (d *MetropolixDevice) IsInputMode() bool {
+	return d.confirmMode || d.randomMode
+}
+
 func (d *MetropolixDevice) confirmClearPattern() {
 	s := d.state
 
 	d.confirmMsg = fmt.Sprintf("Clear pattern %d?", s.Editing+1)
 	d.confirmAction = func() {
+		autoBackup("clear")
 		pat := &s.Patterns[s.Editing]
 		pat.Length = 8
 		pat.Mode = ModeForward
 		pat.Scale = ScaleMajor
 		pat.RootNote = 60
 		pat.SlideTime = 3
+		pat.Swing = 0
+		pat.ClockDiv = ClockDiv16
+		pat.Mod = [2]MetropolixModLane{}
 		for i := 0; i < 8; i++ {
 			pat.Stages[i] = MetropolixStageState{
 				Octave:      4,
@@ -1214,20 +1671,72 @@ func (d *MetropolixDevice) confirmClearPattern() {
 	d.confirmMode = true
 }
 
-func (d *MetropolixDevice) HandlePad(row, col int) {
+// HandlePadRelease ends a stage loop gesture when the loop pad is let go
+func (d *MetropolixDevice) HandlePadRelease(row, col int) {
+	if row == 8 && col == loopPadCol && d.loopHeld {
+		d.releaseLoopPad()
+	}
+}
+
+func (d *MetropolixDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (d *MetropolixDevice) HandlePad(row, col int, velocity uint8) {
+	defer d.trackEdit()()
+
 	s := d.state
 	pat := &s.Patterns[s.Editing]
 
 	debug.Log("metro", "HandlePad row=%d col=%d page=%d", row, col, s.Page)
 
-	// Top row (row 8) - handle up/down arrows for accumulator sub-pages
+	// Top row (row 8) - re-roll, MOD pages, plus up/down arrows for accumulator sub-pages
 	if row == 8 {
+		if col == 7 {
+			d.Reroll()
+			return
+		}
+		if col == loopPadCol {
+			d.pressLoopPad()
+			return
+		}
+		if col == 5 || col == 6 {
+			s.Page = PageModA + col - 5
+			d.modLane = col - 5
+			return
+		}
+		if s.Page == PageRatchets {
+			if col == 1 && s.RatchetSubPage > 0 {
+				s.RatchetSubPage--
+			} else if col == 2 && s.RatchetSubPage < RatchetSubShape {
+				s.RatchetSubPage++
+			}
+		}
+		if s.Page == PageSettings {
+			if col == 1 && s.SettingsSubPage > 0 {
+				s.SettingsSubPage--
+			} else if col == 2 && s.SettingsSubPage < SettingsSubScales {
+				s.SettingsSubPage++
+			}
+		}
+		if s.Page == PageProbability {
+			if col == 1 && s.ProbSubPage > 0 {
+				s.ProbSubPage--
+			} else if col == 2 && s.ProbSubPage < ProbSubEvery {
+				s.ProbSubPage++
+			}
+		}
+		if s.Page == PageGate {
+			if col == 1 && s.GateSubPage > 0 {
+				s.GateSubPage--
+			} else if col == 2 && s.GateSubPage < GateSubFlags {
+				s.GateSubPage++
+			}
+		}
 		if s.Page == PageAccumulator {
 			if col == 1 && s.AccumSubPage > 0 {
 				// Up arrow - go to previous sub-page
 				s.AccumSubPage--
 				debug.Log("metro", "Accum sub-page up to %d", s.AccumSubPage)
-			} else if col == 2 && s.AccumSubPage < 2 {
+			} else if col == 2 && s.AccumSubPage < AccumSubLive {
 				// Down arrow - go to next sub-page
 				s.AccumSubPage++
 				debug.Log("metro", "Accum sub-page down to %d", s.AccumSubPage)
@@ -1236,6 +1745,14 @@ func (d *MetropolixDevice) HandlePad(row, col int) {
 		return
 	}
 
+	// Stage pads while the loop pad is held set the stage loop
+	if d.loopHeld && col < 8 {
+		if col < pat.Length {
+			d.loopPadStage(col)
+		}
+		return
+	}
+
 	// Scene buttons (col 8) - page selection
 	if col == 8 {
 		debug.Log("metro", "Scene button pressed, setting page to %d", row)
@@ -1246,7 +1763,11 @@ func (d *MetropolixDevice) HandlePad(row, col int) {
 	// Handle based on current page
 	switch s.Page {
 	case PageSettings:
-		d.handleSettingsPad(row, col)
+		if s.SettingsSubPage == SettingsSubScales {
+			d.handleScalePickerPad(row, col)
+		} else {
+			d.handleSettingsPad(row, col)
+		}
 	case PageOctave:
 		if col < pat.Length {
 			pat.Stages[col].Octave = row
@@ -1261,15 +1782,32 @@ func (d *MetropolixDevice) HandlePad(row, col int) {
 		}
 	case PageRatchets:
 		if col < pat.Length {
-			pat.Stages[col].Ratchets = row + 1
+			switch s.RatchetSubPage {
+			case RatchetSubProb:
+				pat.Stages[col].RatchetProb = ratchetRowProb(row)
+			case RatchetSubShape:
+				if row <= RatchetDecel {
+					pat.Stages[col].RatchetShape = row
+				}
+			default:
+				pat.Stages[col].Ratchets = row + 1
+			}
 		}
 	case PageProbability:
-		if col < pat.Length {
+		if col < pat.Length && s.ProbSubPage == ProbSubEvery {
+			pat.Stages[col].Every = row + 1
+		} else if col < pat.Length {
 			// 8 levels: 0, 14, 28, 42, 57, 71, 85, 100
 			pat.Stages[col].Probability = row * 100 / 7
 		}
 	case PageGate:
-		if col < pat.Length {
+		if col < pat.Length && s.GateSubPage == GateSubFlags {
+			if row == 1 {
+				pat.Stages[col].Skip = !pat.Stages[col].Skip
+			} else if row == 0 {
+				pat.Stages[col].Hold = !pat.Stages[col].Hold
+			}
+		} else if col < pat.Length {
 			if row >= 2 && row <= 7 {
 				// Gate length: row 7 = index 5 (full), row 2 = index 0 (trigger)
 				pat.Stages[col].GateLength = row - 2
@@ -1281,6 +1819,10 @@ func (d *MetropolixDevice) HandlePad(row, col int) {
 		}
 	case PageAccumulator:
 		d.handleAccumulatorPad(row, col)
+	case PageModA, PageModB:
+		if col < pat.Length {
+			pat.Mod[modPageLane(s.Page)].Values[col] = modRowValue(row)
+		}
 	}
 }
 
@@ -1289,13 +1831,19 @@ func (d *MetropolixDevice) handleSettingsPad(row, col int) {
 	pat := &s.Patterns[s.Editing]
 
 	switch row {
-	case 7: // Mode
+	case 7: // Mode, swing
 		if col < 4 {
 			pat.Mode = PlaybackMode(col)
+		} else {
+			pat.Swing = swingPadValues[col-4]
+			d.regeneratePatternInQueue(s.Editing)
 		}
-	case 6: // Scale
+	case 6: // Scale, gate time
 		if col < 4 {
 			pat.Scale = ScaleType(col)
+		} else {
+			s.GateScale = gateScalePadValues[col-4]
+			d.regeneratePatternInQueue(s.Pattern)
 		}
 	case 5: // Length
 		pat.Length = col + 1
@@ -1308,6 +1856,21 @@ func (d *MetropolixDevice) handleSettingsPad(row, col int) {
 		pat.RootNote = uint8(currentOctave*12 + col)
 	case 3: // Slide time
 		pat.SlideTime = col + 1
+	case 0: // Root follow, clock division
+		if col < len(rootFollowNames) {
+			s.RootFollow = col
+		} else if col == 7 {
+			d.resetRoot()
+		} else if div := col - clockDivPadCol; div >= 0 && div < len(clockDivNames) {
+			pat.ClockDiv = div
+			d.regeneratePatternInQueue(s.Editing)
+		}
+	case 2, 1: // Scale keyboard - toggle a pitch class
+		if class := scaleKeyClass(row, col); class >= 0 {
+			toggleScaleClass(&pat.Scale, class)
+			d.scaleCursor = class
+			d.regeneratePatternInQueue(s.Editing)
+		}
 	}
 }
 
@@ -1333,47 +1896,176 @@ func (d *MetropolixDevice) handleAccumulatorPad(row, col int) {
 		if row < 3 {
 			stage.AccumMode = row
 		}
+	case AccumSubLive:
+		s.resetStageAccum(col)
 	}
 }
 
-func (d *MetropolixDevice) renderLaunchpadHelp() string {
+// HelpLayout describes the Metropolix Launchpad page; grid tooltips follow the current page
+func (d *MetropolixDevice) HelpLayout() widgets.LaunchpadLayout {
 	pageColor := [3]uint8{200, 100, 255}
 	gridColor := [3]uint8{255, 100, 50}
 	offColor := [3]uint8{30, 30, 30}
 
-	var grid [8][8][3]uint8
-	var rightCol [8][3]uint8
+	s := d.state
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
 
-	// Fill grid with active color
 	for row := 0; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			grid[row][col] = gridColor
+			l.Grid[row][col] = widgets.Pad{Color: gridColor, Tooltip: d.padTooltip(row, col)}
 		}
+		rightCol[row] = widgets.Pad{Color: pageColor, Tooltip: pageNames[row] + " page"}
 	}
 
-	// Right column - pages
-	for row := 0; row < 8; row++ {
-		rightCol[row] = pageColor
+	for i := 0; i < 8; i++ {
+		l.TopRow[i] = widgets.Pad{Color: offColor}
 	}
-
-	topRow := make([][3]uint8, 8)
-	for i := range topRow {
-		topRow[i] = offColor
+	l.TopRow[7] = widgets.Pad{Color: [3]uint8{255, 200, 0}, Tooltip: "re-roll seed (next bar)"}
+	l.TopRow[loopPadCol] = widgets.Pad{Color: [3]uint8{0, 120, 255}, Tooltip: "stage loop - hold + press two stages, tap to clear"}
+	for lane := range numModLanes {
+		l.TopRow[5+lane] = widgets.Pad{Color: [3]uint8{0, 200, 180}, Tooltip: modLaneNames[lane] + " page"}
+	}
+	if s.Page == PageAccumulator {
+		l.TopRow[1].Tooltip = "previous accumulator sub-page"
+		l.TopRow[2].Tooltip = "next accumulator sub-page"
+	}
+	if s.Page == PageRatchets {
+		l.TopRow[1].Tooltip = "previous ratchet sub-page (count, probability, shape)"
+		l.TopRow[2].Tooltip = "next ratchet sub-page (count, probability, shape)"
+	}
+	if s.Page == PageSettings {
+		l.TopRow[1].Tooltip = "settings"
+		l.TopRow[2].Tooltip = "scale picker (every scale)"
+	}
+	if s.Page == PageProbability {
+		l.TopRow[1].Tooltip = "probability"
+		l.TopRow[2].Tooltip = "every N cycles"
+	}
+	if s.Page == PageGate {
+		l.TopRow[1].Tooltip = "gate length / gate / slide"
+		l.TopRow[2].Tooltip = "skip / hold"
 	}
 
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, &rightCol) + "\n\n"
-
-	out += widgets.RenderLegendItem(pageColor, "Pages", "select parameter page") + "\n"
-	out += `    Scene 7 → Settings (mode, scale, length, root, slide time)
+	l.Legend = []widgets.LegendItem{
+		{Color: pageColor, Name: "Pages", Desc: "select parameter page", Detail: `    Scene 7 → Settings (mode + swing, scale + gate time, length, root, slide time, scale keyboard on rows 2-1, root follow + clock division on row 0; sub-page 2: every scale, one per pad)
     Scene 6 → Octave (0-7 per stage)
     Scene 5 → Notes (scale degree 0-7 per stage)
     Scene 4 → Pulse Count (1-8 per stage)
-    Scene 3 → Ratchets (1-8 per stage)
-    Scene 2 → Gate (rows 7-2: length, row 1: on/off, row 0: slide)
-    Scene 1 → Probability (0-100% per stage)
-    Scene 0 → Accumulator (sub-pages: value/reset/mode via top row)` + "\n"
-	out += widgets.RenderLegendItem(gridColor, "Grid", "8 columns = 8 stages, 8 rows = values")
+    Scene 3 → Ratchets (sub-pages: count 1-8, repeat probability, shape even/accel/decel)
+    Scene 2 → Gate (rows 7-2: length, row 1: on/off, row 0: slide; sub-page 2: row 1 skip, row 0 hold)
+    Scene 1 → Probability (0-100% per stage; sub-page 2: play every 1-8 cycles)
+    Scene 0 → Accumulator (sub-pages: value/reset/mode, live offsets - press to reset)`},
+		{Color: gridColor, Name: "Grid", Desc: "8 columns = 8 stages, 8 rows = values"},
+		{Color: [3]uint8{255, 200, 0}, Name: "Re-roll", Desc: "new seed for probability / random mode at the next bar"},
+		{Color: [3]uint8{0, 200, 180}, Name: "MOD", Desc: "top row 6-7: MOD lane pages (rows = CC value per stage)"},
+		{Color: [3]uint8{0, 120, 255}, Name: "Loop", Desc: "top row 5: hold + press two stage columns to loop them (next cycle), tap to clear"},
+	}
+	return l
+}
 
-	return out
+// padTooltip describes what a grid pad sets on the current page (mirrors HandlePad)
+func (d *MetropolixDevice) padTooltip(row, col int) string {
+	s := d.state
+	pat := &s.Patterns[s.Editing]
+
+	if s.Page == PageSettings && s.SettingsSubPage == SettingsSubScales {
+		return scalePickerTooltip(row, col)
+	}
+	if s.Page == PageSettings {
+		switch {
+		case row == 7 && col < 4:
+			return "mode " + modeNames[col]
+		case row == 7:
+			return fmt.Sprintf("swing %d%%", swingPadValues[col-4])
+		case row == 6 && col < 4:
+			return "scale " + scaleNames[col]
+		case row == 6:
+			return fmt.Sprintf("gate time %d%%", gateScalePadValues[col-4])
+		case row == 5:
+			return fmt.Sprintf("length %d", col+1)
+		case row == 4:
+			return "root " + d.pitchToName(int(pat.RootNote)/12*12+col)
+		case row == 3:
+			return fmt.Sprintf("slide time %d", col+1)
+		case row == 0 && col < len(rootFollowNames):
+			return "root follow " + rootFollowNames[col]
+		case row == 0 && col == 7:
+			return "reset live transpose"
+		case row == 0 && col >= clockDivPadCol && col-clockDivPadCol < len(clockDivNames):
+			return "clock division " + clockDivNames[col-clockDivPadCol]
+		case row == 2 || row == 1:
+			if class := scaleKeyClass(row, col); class >= 0 {
+				return "toggle " + pitchClassName(int(pat.RootNote)+class) + " in scale"
+			}
+		}
+		return ""
+	}
+
+	if col >= pat.Length {
+		return ""
+	}
+	stage := fmt.Sprintf("stage %d ", col+1)
+	switch s.Page {
+	case PageOctave:
+		return stage + fmt.Sprintf("octave %d", row)
+	case PageNotes:
+		return stage + fmt.Sprintf("degree %d", row)
+	case PagePulseCount:
+		return stage + fmt.Sprintf("pulses %d", row+1)
+	case PageRatchets:
+		switch s.RatchetSubPage {
+		case RatchetSubProb:
+			return stage + fmt.Sprintf("ratchet probability %d%%", ratchetRowProb(row))
+		case RatchetSubShape:
+			if row <= RatchetDecel {
+				return stage + "ratchets " + ratchetShapeNames[row]
+			}
+			return ""
+		}
+		return stage + fmt.Sprintf("ratchets %d", row+1)
+	case PageProbability:
+		if s.ProbSubPage == ProbSubEvery {
+			return stage + fmt.Sprintf("play every %d cycles", row+1)
+		}
+		return stage + fmt.Sprintf("probability %d%%", row*100/7)
+	case PageGate:
+		if s.GateSubPage == GateSubFlags {
+			switch row {
+			case 1:
+				return stage + "toggle skip"
+			case 0:
+				return stage + "toggle hold"
+			}
+			return ""
+		}
+		switch {
+		case row >= 2:
+			return stage + fmt.Sprintf("gate length %d", row-2)
+		case row == 1:
+			return stage + "toggle gate"
+		default:
+			return stage + "toggle slide"
+		}
+	case PageModA, PageModB:
+		return stage + fmt.Sprintf("%s value %d", modLaneNames[modPageLane(s.Page)], modRowValue(row))
+	case PageAccumulator:
+		switch s.AccumSubPage {
+		case AccumSubValue:
+			return stage + fmt.Sprintf("accumulate %+d", row-4)
+		case AccumSubReset:
+			if row == 0 {
+				return stage + "never reset"
+			}
+			return stage + fmt.Sprintf("reset after %d", row)
+		case AccumSubMode:
+			if row < 3 {
+				return stage + []string{"accum reset", "accum ping-pong", "accum hold"}[row]
+			}
+		case AccumSubLive:
+			return stage + fmt.Sprintf("offset %+d (press to reset)", s.Accum[col])
+		}
+	}
+	return ""
 }
@@ -0,0 +1,135 @@
+package sequencer
+
+import (
+	"fmt"
+
+	"go-sequence/midi"
+)
+
+// MOD lanes - like the hardware Metropolix's MOD outputs, each pattern has two lanes
+// that send a CC value per stage alongside the notes. Slew glides from the previous
+// stage's value over part of the stage (0 = jump, 7 = the whole stage). Each lane has
+// its own Launchpad page, opened from the top row.
+
+// MOD lane pages (beyond the 8 scene-button pages)
+const (
+	PageModA = 8
+	PageModB = 9
+)
+
+// Mod lane limits
+const (
+	numModLanes = 2
+	maxModSlew  = 7
+	modStep     = 8 // value change per key press
+)
+
+var modLaneNames = []string{"Mod A", "Mod B"}
+
+// modPageLane returns the lane shown on a page (-1 if it isn't a MOD page)
+func modPageLane(page int) int {
+	switch page {
+	case PageModA:
+		return 0
+	case PageModB:
+		return 1
+	}
+	return -1
+}
+
+// modEvents renders one lane for a stage starting at tick, gliding from the last sent value
+func (d *MetropolixDevice) modEvents(pat *MetropolixPatternState, lane, stageIdx int, tick, stageTicks int64) []midi.Event {
+	l := &pat.Mod[lane]
+	if l.CC <= 0 {
+		return nil
+	}
+	cc := func(at int64, v int) midi.Event {
+		// CC events carry the controller in Note, the value in Velocity
+		return midi.Event{Tick: at, Type: midi.CC, Note: uint8(l.CC), Velocity: uint8(v)}
+	}
+
+	target := l.Values[stageIdx]
+	from := d.modLast[lane]
+	d.modLast[lane] = target
+	if from == target {
+		return nil
+	}
+	rampTicks := stageTicks * int64(l.Slew) / maxModSlew
+	if from < 0 || rampTicks < int64(automationTicks) {
+		return []midi.Event{cc(tick, target)}
+	}
+
+	var events []midi.Event
+	last := from
+	for t := int64(automationTicks); t < rampTicks; t += int64(automationTicks) {
+		v := from + int(float64(target-from)*float64(t)/float64(rampTicks)+0.5)
+		if v != last {
+			events = append(events, cc(tick+t, v))
+			last = v
+		}
+	}
+	return append(events, cc(tick+rampTicks, target))
+}
+
+// nudgeModValue changes the selected stage's value on the active lane
+func (d *MetropolixDevice) nudgeModValue(delta int) {
+	s := d.state
+	l := &s.Patterns[s.Editing].Mod[d.modLane]
+	l.Values[s.Selected] = clamp(l.Values[s.Selected]+delta, 0, 127)
+}
+
+// nudgeModCC changes the active lane's controller (0 = off)
+func (d *MetropolixDevice) nudgeModCC(delta int) {
+	s := d.state
+	l := &s.Patterns[s.Editing].Mod[d.modLane]
+	l.CC = clamp(l.CC+delta, 0, 127)
+	d.modLast[d.modLane] = -1
+}
+
+// nudgeModSlew changes the active lane's slew
+func (d *MetropolixDevice) nudgeModSlew(delta int) {
+	s := d.state
+	l := &s.Patterns[s.Editing].Mod[d.modLane]
+	l.Slew = clamp(l.Slew+delta, 0, maxModSlew)
+}
+
+// modLaneInfo describes a lane for the view
+func modLaneInfo(l *MetropolixModLane) string {
+	if l.CC <= 0 {
+		return "off"
+	}
+	return fmt.Sprintf("CC%d slew %d", l.CC, l.Slew)
+}
+
+// renderModPage shows a lane's per-stage values as bars, one column per stage
+func (d *MetropolixDevice) renderModPage(lane int) []LEDState {
+	pat := &d.state.Patterns[d.state.Editing]
+	l := &pat.Mod[lane]
+
+	activeColor := [3]uint8{0, 200, 180}
+	barColor := [3]uint8{0, 60, 55}
+	dimColor := [3]uint8{0, 15, 15}
+	offColor := [3]uint8{0, 0, 0}
+
+	var leds []LEDState
+	for col := 0; col < 8; col++ {
+		level := modValueRow(l.Values[col])
+		for row := 0; row < 8; row++ {
+			color := dimColor
+			switch {
+			case col >= pat.Length || l.CC <= 0 && row != level:
+				color = offColor
+			case row == level:
+				color = activeColor
+			case row < level:
+				color = barColor
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+// modValueRow maps a 0-127 value to a pad row, and modRowValue back (row 7 = 127)
+func modValueRow(v int) int   { return clamp((v*7+63)/127, 0, 7) }
+func modRowValue(row int) int { return row * 127 / 7 }
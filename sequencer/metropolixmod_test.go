@@ -0,0 +1,23 @@
+package sequencer
+
+import "testing"
+
+func TestModGlideStartsFreshAfterClearQueue(t *testing.T) {
+	d := NewMetropolixDevice(NewMetropolixState())
+	pat := &d.state.Patterns[0]
+	pat.Mod[0].CC = 74
+	pat.Mod[0].Slew = maxModSlew
+	pat.Mod[0].Values[0], pat.Mod[0].Values[1] = 20, 100
+	stage := int64(PPQ)
+
+	d.modEvents(pat, 0, 0, 0, stage)
+	if glide := d.modEvents(pat, 0, 1, stage, stage); len(glide) < 2 {
+		t.Fatalf("%d events from one stage to the next, want a glide", len(glide))
+	}
+
+	d.ClearQueue()
+	got := d.modEvents(pat, 0, 0, 0, stage)
+	if len(got) != 1 || got[0].Velocity != 20 || got[0].Tick != 0 {
+		t.Errorf("first value after ClearQueue = %+v, want one CC of 20 at the stage start", got)
+	}
+}
@@ -0,0 +1,135 @@
+package sequencer
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// Metropolix randomize - re-rolls chosen parts of the editing pattern from a snapshot,
+// previewed live until applied or cancelled (like the drum randomize). Constraints keep
+// it a writing tool: each part (pitches, gates, ratchets) can be left alone, and
+// pitches only move within a range of octaves around each stage's current octave.
+
+// Randomize limits
+const (
+	maxRandomOctaves = 3
+	randomSlideP     = 15 // percent of stages given a slide
+	randomGateP      = 85 // percent of stages with the gate on
+	randomRatchetP   = 25 // percent of stages that ratchet
+	maxRandomRatchet = 4
+)
+
+// metropolixRandomize picks what a randomize touches
+type metropolixRandomize struct {
+	pitches  bool
+	gates    bool
+	ratchets bool
+	octaves  int // pitch range, 1-maxRandomOctaves
+}
+
+// StartRandomize snapshots the editing pattern and enters the randomize preview
+func (d *MetropolixDevice) StartRandomize() {
+	if d.random.octaves == 0 {
+		d.random = metropolixRandomize{pitches: true, octaves: 1}
+	}
+	d.randomPattern = d.state.Editing
+	d.randomBase = d.state.Patterns[d.randomPattern]
+	d.randomMode = true
+	d.rollRandomize()
+}
+
+// rollRandomize re-rolls the chosen parts from the snapshot (so toggles don't compound)
+func (d *MetropolixDevice) rollRandomize() {
+	pat := &d.state.Patterns[d.randomPattern]
+	*pat = d.randomBase
+	r := d.random
+
+	for i := 0; i < pat.Length; i++ {
+		stage := &pat.Stages[i]
+		if r.pitches {
+			stage.Note = rand.Intn(8)
+			spread := rand.Intn(r.octaves) - (r.octaves-1)/2
+			stage.Octave = clamp(stage.Octave+spread, 0, 7)
+		}
+		if r.gates {
+			stage.Gate = rand.Intn(100) < randomGateP
+			stage.GateLength = 1 + rand.Intn(len(gateLengthValues)-1)
+			stage.Slide = rand.Intn(100) < randomSlideP
+		}
+		if r.ratchets {
+			stage.Ratchets = 1
+			if rand.Intn(100) < randomRatchetP {
+				stage.Ratchets = 2 + rand.Intn(maxRandomRatchet-1)
+			}
+		}
+	}
+
+	d.regeneratePatternInQueue(d.randomPattern)
+}
+
+// applyRandomize keeps the previewed pattern (undoable)
+func (d *MetropolixDevice) applyRandomize() {
+	d.history.push(d.randomPattern, d.randomBase)
+	d.randomMode = false
+}
+
+// cancelRandomize restores the pattern as it was before the preview
+func (d *MetropolixDevice) cancelRandomize() {
+	d.state.Patterns[d.randomPattern] = d.randomBase
+	d.regeneratePatternInQueue(d.randomPattern)
+	d.randomMode = false
+}
+
+func (d *MetropolixDevice) handleRandomizeKey(key string) {
+	r := &d.random
+	switch key {
+	case "p":
+		r.pitches = !r.pitches
+		d.rollRandomize()
+	case "g":
+		r.gates = !r.gates
+		d.rollRandomize()
+	case "r":
+		r.ratchets = !r.ratchets
+		d.rollRandomize()
+	case "R":
+		// Ratchets only
+		*r = metropolixRandomize{ratchets: true, octaves: r.octaves}
+		d.rollRandomize()
+	case "h", "left":
+		if r.octaves > 1 {
+			r.octaves--
+			d.rollRandomize()
+		}
+	case "l", "right":
+		if r.octaves < maxRandomOctaves {
+			r.octaves++
+			d.rollRandomize()
+		}
+	case " ":
+		d.rollRandomize()
+	case "y", "Y", "enter":
+		d.applyRandomize()
+	case "n", "N", "esc", "q":
+		d.cancelRandomize()
+	}
+}
+
+// String describes what the preview re-rolls
+func (r metropolixRandomize) String() string {
+	var parts []string
+	if r.pitches {
+		parts = append(parts, fmt.Sprintf("pitches (%d oct)", r.octaves))
+	}
+	if r.gates {
+		parts = append(parts, "gates")
+	}
+	if r.ratchets {
+		parts = append(parts, "ratchets")
+	}
+	if len(parts) == 0 {
+		return "nothing"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,484 @@
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// MIDI learn - a control change from any input port (a keyboard's knobs, a fader box)
+// can drive tempo, transport or a track's mute, solo, transpose, pattern or relaunch
+// chance. Pick a target in the learn view (ctrl+l), press enter and move the control;
+// mappings are saved with the project.
+
+// CCTarget is what a mapped CC controls
+type CCTarget string
+
+const (
+	CCTempo     CCTarget = "tempo"     // 60-187 bpm
+	CCPlayStop  CCTarget = "play"      // press toggles the transport
+	CCMute      CCTarget = "mute"      // press toggles mute on Track
+	CCSolo      CCTarget = "solo"      // press toggles solo on Track
+	CCTranspose CCTarget = "transpose" // -24..+24 semitones (center = 0)
+	CCPattern   CCTarget = "pattern"   // queues pattern value+1 on Track
+	CCRelaunch  CCTarget = "relaunch"  // generative relaunch chance 0-100%
+)
+
+// Targets in the order the learn view lists them
+var (
+	ccGlobalTargets = []CCTarget{CCTempo, CCPlayStop}
+	ccTrackTargets  = []CCTarget{CCMute, CCSolo, CCTranspose, CCPattern, CCRelaunch}
+)
+
+// ccTempoBase is the tempo a CC value of 0 sets
+const ccTempoBase = 60
+
+// ccTransposeRange is the transpose at either end of a CC's travel
+const ccTransposeRange = 24
+
+// ccPressed is the value from which a CC counts as a button press
+const ccPressed = 64
+
+// CCMapping binds an incoming CC to a target (saved per project)
+type CCMapping struct {
+	Target  CCTarget `json:"target"`
+	Track   int      `json:"track,omitempty"` // for track targets
+	Port    string   `json:"port"`            // input port the CC was learned from
+	Channel uint8    `json:"channel"`         // 0-15
+	CC      uint8    `json:"cc"`
+}
+
+// ccSource identifies an incoming control
+type ccSource struct {
+	port    string
+	channel uint8
+	cc      uint8
+}
+
+// global reports whether a target isn't per track
+func (t CCTarget) global() bool {
+	return t == CCTempo || t == CCPlayStop
+}
+
+// Label describes the target ("tempo", "T3 mute")
+func (b CCMapping) Label() string {
+	if b.Target.global() {
+		return string(b.Target)
+	}
+	return fmt.Sprintf("T%d %s", b.Track+1, b.Target)
+}
+
+// Source describes the CC ("cc 74 ch 1")
+func (b CCMapping) Source() string {
+	return fmt.Sprintf("cc %d ch %d", b.CC, b.Channel+1)
+}
+
+// sameTarget reports whether two mappings drive the same thing
+func (b CCMapping) sameTarget(o CCMapping) bool {
+	return b.Target == o.Target && (b.Target.global() || b.Track == o.Track)
+}
+
+// matches reports whether a mapping listens to an event
+func (b CCMapping) matches(evt midi.CCEvent) bool {
+	return b.Port == evt.Port && b.Channel == evt.Channel && b.CC == evt.Controller
+}
+
+// CCMap returns the mapping for a target (ok false if unmapped)
+func (s *State) CCMap(target CCTarget, track int) (CCMapping, bool) {
+	want := CCMapping{Target: target, Track: track}
+	for _, b := range s.CCMaps {
+		if b.sameTarget(want) {
+			return b, true
+		}
+	}
+	return want, false
+}
+
+// SetCCMap maps a target, replacing its old mapping and anything else on the same CC
+func (s *State) SetCCMap(b CCMapping) {
+	kept := s.CCMaps[:0]
+	for _, old := range s.CCMaps {
+		if !old.sameTarget(b) && !(old.Port == b.Port && old.Channel == b.Channel && old.CC == b.CC) {
+			kept = append(kept, old)
+		}
+	}
+	s.CCMaps = append(kept, b)
+}
+
+// ClearCCMap removes a target's mapping
+func (s *State) ClearCCMap(target CCTarget, track int) {
+	want := CCMapping{Target: target, Track: track}
+	kept := s.CCMaps[:0]
+	for _, old := range s.CCMaps {
+		if !old.sameTarget(want) {
+			kept = append(kept, old)
+		}
+	}
+	s.CCMaps = kept
+}
+
+// SetCCInput starts consuming control changes from the input ports
+func (m *Manager) SetCCInput(events <-chan midi.CCEvent) {
+	go func() {
+		for evt := range events {
+			m.withDevices(func() { m.HandleCC(evt) })
+		}
+	}()
+}
+
+// HandleCC learns an armed mapping from a CC, or applies the mappings it drives
+func (m *Manager) HandleCC(evt midi.CCEvent) {
+	m.monitorCC(evt)
+
+	m.ccMu.Lock()
+	key := ccSource{evt.Port, evt.Channel, evt.Controller}
+	wasPressed := m.ccValues[key] >= ccPressed
+	m.ccValues[key] = evt.Value
+	learning, armed := m.ccLearning, m.ccArmed
+	m.ccLearning = false
+	m.ccMu.Unlock()
+
+	if learning {
+		armed.Port, armed.Channel, armed.CC = evt.Port, evt.Channel, evt.Controller
+		m.mu.Lock()
+		S.SetCCMap(armed)
+		m.mu.Unlock()
+		m.notify("%s mapped to %s (%s)", armed.Label(), armed.Source(), evt.Port)
+		return
+	}
+
+	pressed := !wasPressed && evt.Value >= ccPressed
+	if m.ccHotkey(evt, pressed) {
+		return
+	}
+
+	m.mu.RLock()
+	var hits []CCMapping
+	for _, b := range S.CCMaps {
+		if b.matches(evt) {
+			hits = append(hits, b)
+		}
+	}
+	m.mu.RUnlock()
+
+	for _, b := range hits {
+		m.applyCC(b, evt.Value, pressed)
+	}
+}
+
+// applyCC moves a target to a CC value (buttons act on the press)
+func (m *Manager) applyCC(b CCMapping, value uint8, pressed bool) {
+	switch b.Target {
+	case CCTempo:
+		m.SetTempo(ccTempoBase + int(value))
+	case CCPlayStop:
+		if pressed {
+			_, playing, _ := m.GetState()
+			if playing {
+				m.Stop()
+			} else {
+				m.Play()
+			}
+		}
+	case CCMute:
+		if pressed {
+			m.ToggleMute(b.Track)
+		}
+	case CCSolo:
+		if pressed {
+			m.ToggleSolo(b.Track)
+		}
+	case CCTranspose:
+		semis := (int(value)*2*ccTransposeRange+63)/127 - ccTransposeRange
+		m.mu.Lock()
+		S.Tracks[b.Track].Transpose = semis
+		m.mu.Unlock()
+		m.announce("track %d transpose %+d", b.Track+1, semis)
+	case CCPattern:
+		if int(value) < NumPatterns {
+			m.QueuePattern(b.Track, int(value))
+		}
+	case CCRelaunch:
+		m.mu.Lock()
+		S.Tracks[b.Track].Relaunch = int(value) * 100 / 127
+		m.mu.Unlock()
+		m.notifyUpdate()
+	}
+}
+
+// ArmCCLearn makes the next incoming CC map to a target
+func (m *Manager) ArmCCLearn(target CCTarget, track int) {
+	m.ccMu.Lock()
+	m.ccArmed = CCMapping{Target: target, Track: track}
+	m.ccLearning = true
+	m.ccMu.Unlock()
+	m.announce("move a control to map %s", m.ccArmed.Label())
+}
+
+// CancelCCLearn stops waiting for a CC
+func (m *Manager) CancelCCLearn() {
+	m.ccMu.Lock()
+	m.ccLearning = false
+	m.ccMu.Unlock()
+}
+
+// CCLearning returns the target waiting for a CC (ok false if none)
+func (m *Manager) CCLearning() (CCMapping, bool) {
+	m.ccMu.Lock()
+	defer m.ccMu.Unlock()
+	return m.ccArmed, m.ccLearning
+}
+
+// LearnDevice lists the CC mapping targets - global ones on the first row, then one
+// column per track
+type LearnDevice struct {
+	manager *Manager
+
+	cursorRow int // 0 = global targets, then ccTrackTargets
+	cursorCol int // global target, or track
+}
+
+// NewLearnDevice creates the MIDI learn view
+func NewLearnDevice(manager *Manager) *LearnDevice {
+	return &LearnDevice{manager: manager}
+}
+
+// IsInputMode returns true while waiting for a CC (esc cancels)
+func (ld *LearnDevice) IsInputMode() bool {
+	_, learning := ld.manager.CCLearning()
+	return learning
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (ld *LearnDevice) FillUntil(tick int64)                           {}
+func (ld *LearnDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (ld *LearnDevice) PopNextEvent() *midi.Event                      { return nil }
+func (ld *LearnDevice) ClearQueue()                                    {}
+func (ld *LearnDevice) QueuePattern(p int, atTick int64)               {}
+func (ld *LearnDevice) CurrentPattern() int                            { return 0 }
+func (ld *LearnDevice) NextPattern() int                               { return -1 }
+func (ld *LearnDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (ld *LearnDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (ld *LearnDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (ld *LearnDevice) HandleMIDI(event midi.Event)                    {}
+func (ld *LearnDevice) ToggleRecording()                               {}
+func (ld *LearnDevice) TogglePreview()                                 {}
+func (ld *LearnDevice) IsRecording() bool                              { return false }
+func (ld *LearnDevice) IsPreviewing() bool                             { return false }
+func (ld *LearnDevice) HandlePadRelease(row, col int)                  {}
+func (ld *LearnDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+// selected returns the target under the cursor
+func (ld *LearnDevice) selected() (CCTarget, int) {
+	if ld.cursorRow == 0 {
+		return ccGlobalTargets[ld.cursorCol], 0
+	}
+	return ccTrackTargets[ld.cursorRow-1], ld.cursorCol
+}
+
+func (ld *LearnDevice) View() string {
+	var out strings.Builder
+	out.WriteString("MIDI LEARN  CC Mappings\n\n")
+
+	armed, learning := ld.manager.CCLearning()
+	cell := func(target CCTarget, track int, selected bool) string {
+		label := "·"
+		if b, ok := S.CCMap(target, track); ok {
+			label = fmt.Sprintf("cc%d/%d", b.CC, b.Channel+1)
+		}
+		if learning && armed.sameTarget(CCMapping{Target: target, Track: track}) {
+			label = "learn.."
+		}
+		if selected {
+			return fmt.Sprintf("[%-8s]", label)
+		}
+		return fmt.Sprintf(" %-8s ", label)
+	}
+
+	for i, t := range ccGlobalTargets {
+		out.WriteString(fmt.Sprintf("%-10s%s", t, cell(t, 0, ld.cursorRow == 0 && ld.cursorCol == i)))
+	}
+	out.WriteString("\n\n          ")
+	for i := 0; i < 8; i++ {
+		out.WriteString(fmt.Sprintf(" %-8s ", fmt.Sprintf("T%d", i+1)))
+	}
+	out.WriteString("\n")
+	for r, t := range ccTrackTargets {
+		out.WriteString(fmt.Sprintf("%-10s", t))
+		for i := 0; i < 8; i++ {
+			out.WriteString(cell(t, i, ld.cursorRow == r+1 && ld.cursorCol == i))
+		}
+		out.WriteString("\n")
+	}
+
+	target, track := ld.selected()
+	if b, ok := S.CCMap(target, track); ok {
+		out.WriteString(fmt.Sprintf("\n%s: %s from %s\n", b.Label(), b.Source(), b.Port))
+	} else {
+		out.WriteString(fmt.Sprintf("\n%s: not mapped\n", CCMapping{Target: target, Track: track}.Label()))
+	}
+
+	out.WriteString("\n")
+	if learning {
+		out.WriteString(fmt.Sprintf("Move a knob, fader or button to map %s (esc cancels)\n", armed.Label()))
+		return out.String()
+	}
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "hjkl", Desc: "select target"},
+			{Key: "enter", Desc: "learn (then move a control)"},
+			{Key: "x", Desc: "clear mapping"},
+		}},
+	}))
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(ld.HelpLayout()))
+	return out.String()
+}
+
+func (ld *LearnDevice) HandleKey(key string) {
+	if ld.IsInputMode() {
+		if key == "esc" {
+			ld.manager.CancelCCLearn()
+		}
+		return
+	}
+
+	switch key {
+	case "h", "left":
+		if ld.cursorCol > 0 {
+			ld.cursorCol--
+		}
+	case "l", "right":
+		ld.cursorCol++
+	case "k", "up":
+		if ld.cursorRow > 0 {
+			ld.cursorRow--
+		}
+	case "j", "down":
+		if ld.cursorRow < len(ccTrackTargets) {
+			ld.cursorRow++
+		}
+	case "enter", " ":
+		ld.manager.ArmCCLearn(ld.selected())
+	case "x", "backspace":
+		target, track := ld.selected()
+		ld.manager.mu.Lock()
+		S.ClearCCMap(target, track)
+		ld.manager.mu.Unlock()
+	}
+	ld.clampCursor()
+}
+
+// clampCursor keeps the column inside the row (two globals, eight tracks)
+func (ld *LearnDevice) clampCursor() {
+	cols := 8
+	if ld.cursorRow == 0 {
+		cols = len(ccGlobalTargets)
+	}
+	ld.cursorCol = min(ld.cursorCol, cols-1)
+}
+
+// Launchpad layout - rows 7..3 are the track targets (a column per track), row 0
+// holds the global ones
+func learnPad(row, col int) (CCTarget, int, bool) {
+	switch {
+	case row >= 3 && row <= 7 && col < 8:
+		return ccTrackTargets[7-row], col, true
+	case row == 0 && col < len(ccGlobalTargets):
+		return ccGlobalTargets[col], 0, true
+	}
+	return "", 0, false
+}
+
+// ccTargetColor is the pad color for a target
+func ccTargetColor(t CCTarget) [3]uint8 {
+	switch t {
+	case CCTempo:
+		return [3]uint8{255, 200, 0}
+	case CCPlayStop:
+		return [3]uint8{0, 255, 0}
+	case CCMute:
+		return [3]uint8{255, 60, 60}
+	case CCSolo:
+		return [3]uint8{80, 200, 255}
+	case CCTranspose:
+		return [3]uint8{148, 18, 126}
+	case CCPattern:
+		return [3]uint8{255, 120, 0}
+	case CCRelaunch:
+		return [3]uint8{0, 200, 120}
+	}
+	return [3]uint8{20, 20, 20}
+}
+
+func (ld *LearnDevice) RenderLEDs() []LEDState {
+	armed, learning := ld.manager.CCLearning()
+	var leds []LEDState
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			target, track, ok := learnPad(row, col)
+			if !ok {
+				continue
+			}
+			color := [3]uint8{20, 20, 20}
+			if _, mapped := S.CCMap(target, track); mapped {
+				color = ccTargetColor(target)
+			}
+			channel := midi.ChannelStatic
+			if learning && armed.sameTarget(CCMapping{Target: target, Track: track}) {
+				color, channel = [3]uint8{255, 255, 255}, midi.ChannelPulse
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: channel})
+		}
+	}
+	return leds
+}
+
+// HandlePad arms learning for the pad's target (pressing it again cancels)
+func (ld *LearnDevice) HandlePad(row, col int, velocity uint8) {
+	target, track, ok := learnPad(row, col)
+	if !ok {
+		return
+	}
+	if armed, learning := ld.manager.CCLearning(); learning && armed.sameTarget(CCMapping{Target: target, Track: track}) {
+		ld.manager.CancelCCLearn()
+		return
+	}
+	ld.cursorRow, ld.cursorCol = 0, col
+	if !target.global() {
+		ld.cursorRow = 8 - row
+	}
+	ld.manager.ArmCCLearn(target, track)
+}
+
+// HelpLayout shows the mapping targets and which are mapped
+func (ld *LearnDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	for i := range l.TopRow {
+		l.TopRow[i] = widgets.Pad{Color: [3]uint8{30, 30, 30}}
+	}
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			target, track, ok := learnPad(row, col)
+			if !ok {
+				continue
+			}
+			b, mapped := S.CCMap(target, track)
+			tooltip := b.Label() + ": not mapped"
+			if mapped {
+				tooltip = b.Label() + ": " + b.Source()
+			}
+			l.Grid[row][col] = widgets.Pad{Color: ccTargetColor(target), Tooltip: tooltip}
+		}
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: ccTargetColor(CCMute), Name: "Mute", Desc: "rows from the top: mute, solo, transpose, pattern, relaunch - a column per track"},
+		{Color: ccTargetColor(CCTempo), Name: "Tempo", Desc: "bottom row: tempo, play/stop"},
+		{Color: [3]uint8{255, 255, 255}, Name: "Learning", Desc: "tap a pad, then move a control (tap again to cancel)"},
+	}
+	return l
+}
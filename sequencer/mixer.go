@@ -0,0 +1,254 @@
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// Mixer - a page of the eight tracks' levels (`ctrl+g`). A level goes out as the
+// track's Fader CC (Settings, volume by default - see faders.go). While the page is
+// focused a Launchpad X turns its grid into eight DAW faders; other grids show each
+// track as a column of pads to tap a level on. Controller faders (APC) move the same
+// levels from any view.
+
+// Mixer level steps (j/k and [/])
+const (
+	mixerCoarseStep   = 8
+	mixerDefaultLevel = 100 // where a track starts when its level hasn't been sent yet
+)
+
+// MixerDevice is the track level page
+type MixerDevice struct {
+	manager *Manager
+	cursor  int // selected track
+
+	mu          sync.Mutex
+	levels      [8]int // last level sent per track (-1 = none yet)
+	fadersShown bool   // the controller's grid is showing DAW faders
+}
+
+// NewMixerDevice creates the mixer page
+func NewMixerDevice(manager *Manager) *MixerDevice {
+	d := &MixerDevice{manager: manager}
+	for i := range d.levels {
+		d.levels[i] = -1
+	}
+	return d
+}
+
+// Level returns a track's last sent level (-1 = none yet)
+func (d *MixerDevice) Level(track int) int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.levels[track]
+}
+
+// setLevel records a level sent on a track, moving the hardware fader to match
+func (d *MixerDevice) setLevel(track int, value uint8, ctrl midi.Controller) {
+	d.mu.Lock()
+	d.levels[track] = int(value)
+	shown := d.fadersShown
+	d.mu.Unlock()
+	if fpc, ok := ctrl.(midi.FaderPageController); ok && shown {
+		fpc.SetFader(track, value)
+	}
+}
+
+// nudge moves the selected track's level
+func (d *MixerDevice) nudge(delta int) {
+	level := d.Level(d.cursor)
+	if level < 0 {
+		level = mixerDefaultLevel
+	}
+	d.manager.SetTrackLevel(d.cursor, uint8(clamp(level+delta, 0, 127)))
+}
+
+// faderColor is a track's fader color (dim for tracks without a device)
+func faderColor(track int) [3]uint8 {
+	if S.Tracks[track].Type == DeviceTypeNone {
+		return mixerEmptyColor
+	}
+	return mixerLevelColor
+}
+
+// syncFaderPage shows the controller's DAW faders while the mixer is focused, and
+// hides them once it isn't (or the controller changed)
+func (m *Manager) syncFaderPage() {
+	d := m.mixer
+	if d == nil {
+		return
+	}
+	fpc, ok := m.controller.(midi.FaderPageController)
+	show := ok && m.focused == Device(d)
+
+	d.mu.Lock()
+	changed := show != d.fadersShown
+	d.fadersShown = show
+	levels := d.levels
+	d.mu.Unlock()
+	if !ok || !changed {
+		return
+	}
+	if !show {
+		fpc.HideFaders()
+		return
+	}
+	var colors [8][3]uint8
+	var values [8]uint8
+	for i := range colors {
+		colors[i] = faderColor(i)
+		values[i] = uint8(max(levels[i], 0))
+	}
+	fpc.ShowFaders(colors, values)
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (d *MixerDevice) FillUntil(tick int64)                           {}
+func (d *MixerDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (d *MixerDevice) PopNextEvent() *midi.Event                      { return nil }
+func (d *MixerDevice) ClearQueue()                                    {}
+func (d *MixerDevice) QueuePattern(p int, atTick int64)               {}
+func (d *MixerDevice) CurrentPattern() int                            { return 0 }
+func (d *MixerDevice) NextPattern() int                               { return -1 }
+func (d *MixerDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (d *MixerDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (d *MixerDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (d *MixerDevice) HandleMIDI(event midi.Event)                    {}
+func (d *MixerDevice) ToggleRecording()                               {}
+func (d *MixerDevice) TogglePreview()                                 {}
+func (d *MixerDevice) IsRecording() bool                              { return false }
+func (d *MixerDevice) IsPreviewing() bool                             { return false }
+func (d *MixerDevice) HandlePadRelease(row, col int)                  {}
+func (d *MixerDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (d *MixerDevice) View() string {
+	var out strings.Builder
+	out.WriteString("MIXER\n\n")
+
+	for track := 0; track < 8; track++ {
+		prefix := "  "
+		if track == d.cursor {
+			prefix = "> "
+		}
+		ts := S.Tracks[track]
+		name := string(ts.Type)
+		if name == "" {
+			name = "empty"
+		}
+		level := d.Level(track)
+		bar, value := strings.Repeat("·", 16), "--"
+		if level >= 0 {
+			filled := (level*16 + 126) / 127
+			bar = strings.Repeat("█", filled) + strings.Repeat("·", 16-filled)
+			value = fmt.Sprintf("%3d", level)
+		}
+		out.WriteString(fmt.Sprintf("%sT%d %-10s  cc %-3d  %s %s\n", prefix, track+1, truncateName(name, 10), ts.FaderCC(), bar, value))
+	}
+
+	d.mu.Lock()
+	shown := d.fadersShown
+	d.mu.Unlock()
+	if shown {
+		out.WriteString("\nLaunchpad faders on - leave the mixer to get the grid back\n")
+	}
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "h / l", Desc: "select track"},
+			{Key: "j / k", Desc: fmt.Sprintf("level -/+ %d", mixerCoarseStep)},
+			{Key: "[ / ]", Desc: "level -/+ 1"},
+			{Key: "enter", Desc: fmt.Sprintf("level %d", mixerDefaultLevel)},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(d.HelpLayout()))
+	return out.String()
+}
+
+// Mixer pad colors
+var (
+	mixerLevelColor = [3]uint8{0, 200, 80}
+	mixerEmptyColor = [3]uint8{60, 60, 60}
+	mixerDimColor   = [3]uint8{10, 10, 10}
+)
+
+// RenderLEDs draws level columns - nothing while the grid shows the hardware faders
+func (d *MixerDevice) RenderLEDs() []LEDState {
+	d.mu.Lock()
+	shown := d.fadersShown
+	d.mu.Unlock()
+	if shown {
+		return nil
+	}
+	var leds []LEDState
+	layout := d.HelpLayout()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: row, Col: col, Color: layout.Grid[row][col].Color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+func (d *MixerDevice) HandleKey(key string) {
+	switch key {
+	case "h", "left":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "l", "right":
+		if d.cursor < 7 {
+			d.cursor++
+		}
+	case "j", "down":
+		d.nudge(-mixerCoarseStep)
+	case "k", "up":
+		d.nudge(mixerCoarseStep)
+	case "[":
+		d.nudge(-1)
+	case "]":
+		d.nudge(1)
+	case "enter", " ":
+		d.manager.SetTrackLevel(d.cursor, mixerDefaultLevel)
+	}
+}
+
+// padLevel is the level a pad row stands for (bottom row = 0, top row = 127)
+func padLevel(row int) uint8 {
+	return uint8(row * 127 / 7)
+}
+
+// HandlePad sets a track's level from its column (grids without hardware faders)
+func (d *MixerDevice) HandlePad(row, col int, velocity uint8) {
+	if row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	d.cursor = col
+	d.manager.SetTrackLevel(col, padLevel(row))
+}
+
+// HelpLayout shows each track as a column lit up to its level
+func (d *MixerDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	for col := 0; col < 8; col++ {
+		level := d.Level(col)
+		for row := 0; row < 8; row++ {
+			pad := widgets.Pad{Color: mixerDimColor, Tooltip: fmt.Sprintf("track %d level %d", col+1, padLevel(row))}
+			if level >= 0 && int(padLevel(row)) <= level {
+				pad.Color = faderColor(col)
+			}
+			l.Grid[row][col] = pad
+		}
+	}
+	l.Legend = []widgets.LegendItem{
+		{Color: mixerLevelColor, Name: "Level", Desc: "tap a track's column to set its level (Launchpad X: DAW faders)"},
+	}
+	return l
+}
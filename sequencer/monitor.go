@@ -0,0 +1,413 @@
+package sequencer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// MIDI monitor - every message sent to an output port, every note from the keyboard
+// input and every CC from the inputs (see midilearn.go) goes into a ring buffer; the monitor view (`` ` ``) lists the latest with
+// timestamps, port and channel, and sums them up per port/channel. The Launchpad shows
+// which of the 16 channels are busy.
+
+// monitorSize is how many messages the ring buffer keeps
+const monitorSize = 512
+
+// monitorLines is how many messages the view lists
+const monitorLines = 20
+
+// monitorRecent is how long a channel pad stays lit after a message
+const monitorRecent = 300 * time.Millisecond
+
+// monitorEntry is one logged message
+type monitorEntry struct {
+	at   time.Time
+	in   bool // from an input (false = sent to an output)
+	port string
+	msg  gomidi.Message
+}
+
+// midiMonitor is the ring buffer of recent messages (written from the dispatch and
+// input goroutines, read by the view)
+type midiMonitor struct {
+	mu      sync.Mutex
+	entries [monitorSize]monitorEntry
+	next    int // slot the next entry goes in
+	count   int // entries held (up to monitorSize)
+}
+
+// record logs a message
+func (mm *midiMonitor) record(e monitorEntry) {
+	mm.mu.Lock()
+	mm.entries[mm.next] = e
+	mm.next = (mm.next + 1) % monitorSize
+	mm.count = min(mm.count+1, monitorSize)
+	mm.mu.Unlock()
+}
+
+// snapshot returns the logged messages, oldest first
+func (mm *midiMonitor) snapshot() []monitorEntry {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+	out := make([]monitorEntry, 0, mm.count)
+	for i := 0; i < mm.count; i++ {
+		out = append(out, mm.entries[(mm.next-mm.count+i+monitorSize)%monitorSize])
+	}
+	return out
+}
+
+// clear empties the buffer
+func (mm *midiMonitor) clear() {
+	mm.mu.Lock()
+	mm.count = 0
+	mm.mu.Unlock()
+}
+
+// monitorSender wraps a port's sender so everything sent on it is logged
+func (m *Manager) monitorSender(port string, send func(gomidi.Message) error) func(gomidi.Message) error {
+	return func(msg gomidi.Message) error {
+		m.midiLog.record(monitorEntry{at: time.Now(), port: port, msg: msg})
+		return send(msg)
+	}
+}
+
+// monitorInput logs a note from the keyboard input
+func (m *Manager) monitorInput(evt midi.NoteEvent) {
+	var msg gomidi.Message
+	switch {
+	case evt.Type == midi.PolyPressure:
+		msg = gomidi.PolyAfterTouch(evt.Channel, evt.Note, evt.Velocity)
+	case evt.Type == midi.Pressure:
+		msg = gomidi.AfterTouch(evt.Channel, evt.Velocity)
+	case evt.Type == midi.PitchBend:
+		msg = gomidi.Pitchbend(evt.Channel, evt.Bend)
+	case evt.Velocity == 0:
+		msg = gomidi.NoteOff(evt.Channel, evt.Note)
+	default:
+		msg = gomidi.NoteOn(evt.Channel, evt.Note, evt.Velocity)
+	}
+	m.midiLog.record(monitorEntry{at: time.Now(), in: true, port: S.NoteInputPort, msg: msg})
+}
+
+// monitorCC logs a control change from an input
+func (m *Manager) monitorCC(evt midi.CCEvent) {
+	msg := gomidi.ControlChange(evt.Channel, evt.Controller, evt.Value)
+	m.midiLog.record(monitorEntry{at: time.Now(), in: true, port: evt.Port, msg: msg})
+}
+
+// describeMessage returns a message's channel (0-15, -1 = none) and a short description
+func describeMessage(msg gomidi.Message) (int, string) {
+	var ch, key, vel, cc, val uint8
+	var bend int16
+	var abs uint16
+	switch {
+	case msg.GetNoteStart(&ch, &key, &vel):
+		return int(ch), fmt.Sprintf("note on   %-4s (%3d) vel %d", pitchName(int(key)), key, vel)
+	case msg.GetNoteEnd(&ch, &key):
+		return int(ch), fmt.Sprintf("note off  %-4s (%3d)", pitchName(int(key)), key)
+	case msg.GetControlChange(&ch, &cc, &val):
+		return int(ch), fmt.Sprintf("cc %-3d    = %d", cc, val)
+	case msg.GetPitchBend(&ch, &bend, &abs):
+		return int(ch), fmt.Sprintf("bend      %+d", bend)
+	case msg.GetAfterTouch(&ch, &val):
+		return int(ch), fmt.Sprintf("pressure  %d", val)
+	case msg.GetChannel(&ch):
+		return int(ch), msg.String()
+	}
+	return -1, msg.String()
+}
+
+// pitchName names a MIDI note ("C4", "F#2")
+func pitchName(pitch int) string {
+	noteNames := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	return fmt.Sprintf("%s%d", noteNames[pitch%12], pitch/12)
+}
+
+// Monitor direction filters
+const (
+	monitorAll = iota
+	monitorIn
+	monitorOut
+)
+
+var monitorFilterNames = []string{"all", "in", "out"}
+
+// MonitorDevice shows the MIDI activity log
+type MonitorDevice struct {
+	manager *Manager
+
+	filter  int            // monitorAll / monitorIn / monitorOut
+	channel int            // 1-16 shows one channel, 0 = all
+	frozen  []monitorEntry // paused view (nil = live)
+}
+
+// NewMonitorDevice creates a MIDI monitor device
+func NewMonitorDevice(manager *Manager) *MonitorDevice {
+	return &MonitorDevice{manager: manager}
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (md *MonitorDevice) FillUntil(tick int64)                           {}
+func (md *MonitorDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (md *MonitorDevice) PopNextEvent() *midi.Event                      { return nil }
+func (md *MonitorDevice) ClearQueue()                                    {}
+func (md *MonitorDevice) QueuePattern(p int, atTick int64)               {}
+func (md *MonitorDevice) CurrentPattern() int                            { return 0 }
+func (md *MonitorDevice) NextPattern() int                               { return -1 }
+func (md *MonitorDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (md *MonitorDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (md *MonitorDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (md *MonitorDevice) HandleMIDI(event midi.Event)                    {}
+func (md *MonitorDevice) ToggleRecording()                               {}
+func (md *MonitorDevice) TogglePreview()                                 {}
+func (md *MonitorDevice) IsRecording() bool                              { return false }
+func (md *MonitorDevice) IsPreviewing() bool                             { return false }
+func (md *MonitorDevice) HandlePadRelease(row, col int)                  {}
+func (md *MonitorDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+// entries returns the log (or the paused copy)
+func (md *MonitorDevice) entries() []monitorEntry {
+	if md.frozen != nil {
+		return md.frozen
+	}
+	return md.manager.midiLog.snapshot()
+}
+
+// shows reports whether an entry passes the direction and channel filters
+func (md *MonitorDevice) shows(e monitorEntry) bool {
+	if (md.filter == monitorIn && !e.in) || (md.filter == monitorOut && e.in) {
+		return false
+	}
+	ch, _ := describeMessage(e.msg)
+	return md.channel == 0 || ch == md.channel-1
+}
+
+// portChannel is a summary row key
+type portChannel struct {
+	port    string
+	in      bool
+	channel int
+}
+
+func (md *MonitorDevice) View() string {
+	var out strings.Builder
+	entries := md.entries()
+
+	state := "live"
+	if md.frozen != nil {
+		state = "paused"
+	}
+	channel := "all"
+	if md.channel > 0 {
+		channel = fmt.Sprintf("%d", md.channel)
+	}
+	out.WriteString(fmt.Sprintf("MONITOR  MIDI Activity  (%s, showing %s, channel %s)\n\n", state, monitorFilterNames[md.filter], channel))
+
+	// Latest messages, newest at the bottom
+	var shown []monitorEntry
+	for _, e := range entries {
+		if md.shows(e) {
+			shown = append(shown, e)
+		}
+	}
+	if len(shown) > monitorLines {
+		shown = shown[len(shown)-monitorLines:]
+	}
+	out.WriteString("Time          Dir  Port                  Ch  Message\n")
+	out.WriteString("──────────────────────────────────────────────────────────────────────\n")
+	if len(shown) == 0 {
+		out.WriteString("  (no MIDI yet)\n")
+	}
+	for _, e := range shown {
+		ch, desc := describeMessage(e.msg)
+		chStr := "-"
+		if ch >= 0 {
+			chStr = fmt.Sprintf("%d", ch+1)
+		}
+		dir := "out"
+		if e.in {
+			dir = "in"
+		}
+		out.WriteString(fmt.Sprintf("%s  %-3s  %-20s  %2s  %s\n", e.at.Format("15:04:05.000"), dir, truncateName(e.port, 20), chStr, desc))
+	}
+
+	// Per port/channel totals over the whole buffer
+	counts := make(map[portChannel]int)
+	last := make(map[portChannel]time.Time)
+	for _, e := range entries {
+		ch, _ := describeMessage(e.msg)
+		key := portChannel{e.port, e.in, ch}
+		counts[key]++
+		last[key] = e.at
+	}
+	keys := make([]portChannel, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].port != keys[j].port {
+			return keys[i].port < keys[j].port
+		}
+		if keys[i].in != keys[j].in {
+			return keys[i].in
+		}
+		return keys[i].channel < keys[j].channel
+	})
+	out.WriteString(fmt.Sprintf("\nPer port/channel (last %d messages)\n", len(entries)))
+	for _, key := range keys {
+		dir := "out"
+		if key.in {
+			dir = "in"
+		}
+		chStr := "-"
+		if key.channel >= 0 {
+			chStr = fmt.Sprintf("%d", key.channel+1)
+		}
+		out.WriteString(fmt.Sprintf("  %-3s  %-20s  ch %-2s  %4d msgs, last %s\n", dir, truncateName(key.port, 20), chStr, counts[key], last[key].Format("15:04:05.000")))
+	}
+
+	// How late the sequencer's own events went out (see jitter.go)
+	out.WriteString(fmt.Sprintf("\nDispatch timing: %s\n", md.manager.jitter.summary()))
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "space", Desc: "pause / resume"},
+			{Key: "f", Desc: "show all / in / out"},
+			{Key: "[ / ]", Desc: "channel filter -/+ (0 = all)"},
+			{Key: "c", Desc: "clear (and the timing stats)"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(md.HelpLayout()))
+	return out.String()
+}
+
+func (md *MonitorDevice) HandleKey(key string) {
+	switch key {
+	case " ", "enter":
+		if md.frozen != nil {
+			md.frozen = nil
+		} else {
+			md.frozen = md.manager.midiLog.snapshot()
+		}
+	case "f":
+		md.filter = (md.filter + 1) % len(monitorFilterNames)
+	case "[":
+		md.channel = (md.channel + 16) % 17
+	case "]":
+		md.channel = (md.channel + 1) % 17
+	case "c":
+		md.manager.midiLog.clear()
+		md.manager.jitter.clear()
+		if md.frozen != nil {
+			md.frozen = []monitorEntry{}
+		}
+	}
+}
+
+// HandlePad picks a channel filter from the two channel rows (again clears it)
+func (md *MonitorDevice) HandlePad(row, col int, velocity uint8) {
+	if (row != 7 && row != 6) || col < 0 || col > 7 {
+		return
+	}
+	channel := (7-row)*8 + col + 1
+	if md.channel == channel {
+		channel = 0
+	}
+	md.channel = channel
+}
+
+// Monitor pad colors
+var (
+	monitorOutColor  = [3]uint8{0, 255, 0}
+	monitorInColor   = [3]uint8{0, 80, 255}
+	monitorBothColor = [3]uint8{0, 255, 255}
+	monitorSeenColor = [3]uint8{20, 40, 20}
+	monitorIdleColor = [3]uint8{0, 0, 0}
+)
+
+// channelColors colors the 16 channel pads by recent traffic
+func (md *MonitorDevice) channelColors() [16][3]uint8 {
+	var colors [16][3]uint8
+	var recentIn, recentOut, seen [16]bool
+	now := time.Now()
+	for _, e := range md.manager.midiLog.snapshot() {
+		ch, _ := describeMessage(e.msg)
+		if ch < 0 {
+			continue
+		}
+		seen[ch] = true
+		if now.Sub(e.at) < monitorRecent {
+			if e.in {
+				recentIn[ch] = true
+			} else {
+				recentOut[ch] = true
+			}
+		}
+	}
+	for ch := range colors {
+		switch {
+		case recentIn[ch] && recentOut[ch]:
+			colors[ch] = monitorBothColor
+		case recentIn[ch]:
+			colors[ch] = monitorInColor
+		case recentOut[ch]:
+			colors[ch] = monitorOutColor
+		case seen[ch]:
+			colors[ch] = monitorSeenColor
+		default:
+			colors[ch] = monitorIdleColor
+		}
+		if md.channel == ch+1 {
+			colors[ch] = [3]uint8{255, 255, 255}
+		}
+	}
+	return colors
+}
+
+func (md *MonitorDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	colors := md.channelColors()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			color := monitorIdleColor
+			if row >= 6 {
+				color = colors[(7-row)*8+col]
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+// HelpLayout shows the channel pads (channels 1-8 on the top row, 9-16 below)
+func (md *MonitorDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	colors := md.channelColors()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			l.Grid[row][col] = widgets.Pad{Color: monitorIdleColor}
+			if row >= 6 {
+				ch := (7-row)*8 + col
+				l.Grid[row][col] = widgets.Pad{Color: colors[ch], Tooltip: fmt.Sprintf("show channel %d only", ch+1)}
+			}
+		}
+	}
+	l.Legend = []widgets.LegendItem{
+		{Color: monitorOutColor, Name: "Out", Desc: "channel sent to just now"},
+		{Color: monitorInColor, Name: "In", Desc: "keyboard played on it just now"},
+		{Color: monitorSeenColor, Name: "Seen", Desc: "has traffic in the log (press to filter)"},
+	}
+	return l
+}
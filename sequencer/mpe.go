@@ -0,0 +1,196 @@
+package sequencer
+
+import (
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+)
+
+// MPE - with the "mpe" output profile a track is an MPE lower zone: the track channel
+// is the master channel and every note gets a member channel of its own (the channels
+// after it), so per-note pitch bend and pressure move that note alone. Piano roll
+// notes carry the expression - a bend the note glides to by its end and a pressure held
+// through it. On other profiles the same expression goes out on the note's channel,
+// which suits mono lines.
+
+// mpeBendRange is the MPE default per-note pitch bend range (semitones)
+const mpeBendRange = 48
+
+// defaultBendRange is the pitch bend range assumed for non-MPE synths
+const defaultBendRange = 2
+
+// Expression edit limits
+const (
+	noteBendMax      = 24 // semitones either way
+	pressureEditStep = 16
+)
+
+// MPE Configuration Message - RPN 6 on the master channel, data = member channel count
+const (
+	mpeRPNZone  = 6
+	ccRPNLSB    = 100
+	ccRPNMSB    = 101
+	ccDataEntry = 6
+)
+
+// mpeVoices allocates a track's member channels (guarded by m.voiceMu)
+type mpeVoices struct {
+	ready bool      // zone announced with an MPE Configuration Message
+	note  [16]uint8 // note+1 sounding on each channel (0 = free)
+	used  [16]uint64
+	clock uint64
+}
+
+// noteExpression generates a note's per-note pressure and pitch bend events. Bends are
+// in cents (see bendValue) and return to center as the note ends.
+func noteExpression(note NoteEventState, onTick, offTick int64) []midi.Event {
+	var events []midi.Event
+	if note.Pressure > 0 {
+		events = append(events, midi.Event{Tick: onTick, Type: midi.Pressure, Note: note.Pitch, Velocity: note.Pressure, OutChannel: note.Channel, PerNote: true})
+	}
+	if note.Bend == 0 || offTick <= onTick {
+		return events
+	}
+	length := offTick - onTick
+	for t := int64(0); t < length; t += automationTicks {
+		cents := int64(note.Bend*100) * t / length
+		events = append(events, midi.Event{Tick: onTick + t, Type: midi.PitchBend, Note: note.Pitch, BendValue: int16(cents), OutChannel: note.Channel, PerNote: true})
+	}
+	events = append(events, midi.Event{Tick: offTick, Type: midi.PitchBend, Note: note.Pitch, OutChannel: note.Channel, PerNote: true})
+	return events
+}
+
+// bendValue converts a bend in cents to a pitch bend value for a bend range
+func bendValue(cents int16, semitones int) int16 {
+	if semitones <= 0 {
+		semitones = defaultBendRange
+	}
+	return int16(clamp(int(cents)*8192/(semitones*100), -8192, 8191))
+}
+
+// sendMPE sends one event on an MPE track, returning the channel a note went out on
+// (hold m.voiceMu)
+func (m *Manager) sendMPE(sender func(gomidi.Message) error, trackIdx int, ts *TrackState, evt *midi.Event) uint8 {
+	master := ts.Channel - 1
+	v := &m.mpe[trackIdx]
+	members := 15 - int(master)
+	if !v.ready {
+		v.ready = true
+		sender(gomidi.ControlChange(master, ccRPNMSB, 0))
+		sender(gomidi.ControlChange(master, ccRPNLSB, mpeRPNZone))
+		sender(gomidi.ControlChange(master, ccDataEntry, uint8(members)))
+		sender(gomidi.ControlChange(master, ccRPNMSB, 127))
+		sender(gomidi.ControlChange(master, ccRPNLSB, 127))
+	}
+
+	switch evt.Type {
+	case midi.NoteOn, midi.Trigger:
+		ch := v.allocate(sender, master, evt.Note)
+		sender(gomidi.Pitchbend(ch, 0))
+		sender(gomidi.AfterTouch(ch, 0))
+		sender(gomidi.NoteOn(ch, evt.Note, evt.Velocity))
+		if evt.Type == midi.Trigger {
+			v.release(sender, ch)
+		}
+		return ch
+	case midi.NoteOff:
+		ch, ok := v.channelOf(master, evt.Note)
+		if ok {
+			v.release(sender, ch)
+		}
+		return ch
+	}
+
+	// Per-note expression follows its note (dropped once it has ended); the rest is
+	// zone-wide on the master channel
+	ch := master
+	if evt.PerNote {
+		var ok bool
+		if ch, ok = v.channelOf(master, evt.Note); !ok {
+			return master
+		}
+	}
+	switch evt.Type {
+	case midi.PitchBend:
+		bend := evt.BendValue
+		if evt.PerNote {
+			bend = bendValue(bend, mpeBendRange)
+		}
+		sender(gomidi.Pitchbend(ch, bend))
+	case midi.Pressure:
+		sender(gomidi.AfterTouch(ch, evt.Velocity))
+	case midi.CC:
+		sender(gomidi.ControlChange(ch, evt.Note, evt.Velocity))
+	}
+	return ch
+}
+
+// allocate picks a member channel for a note - the free one used longest ago, or the
+// oldest voice (stolen) when all are busy. A zone without members plays on its master.
+func (v *mpeVoices) allocate(sender func(gomidi.Message) error, master, note uint8) uint8 {
+	if master >= 15 {
+		return master
+	}
+	best, stolen := -1, -1
+	for ch := int(master) + 1; ch < 16; ch++ {
+		if v.note[ch] == 0 && (best < 0 || v.used[ch] < v.used[best]) {
+			best = ch
+		}
+		if stolen < 0 || v.used[ch] < v.used[stolen] {
+			stolen = ch
+		}
+	}
+	if best < 0 {
+		best = stolen
+		v.release(sender, uint8(best))
+	}
+	v.clock++
+	v.note[best] = note + 1
+	v.used[best] = v.clock
+	return uint8(best)
+}
+
+// release ends the note on a member channel
+func (v *mpeVoices) release(sender func(gomidi.Message) error, ch uint8) {
+	if v.note[ch] == 0 {
+		return
+	}
+	sender(gomidi.NoteOff(ch, v.note[ch]-1))
+	v.note[ch] = 0
+	v.clock++
+	v.used[ch] = v.clock
+}
+
+// channelOf finds the member channel a note is sounding on
+func (v *mpeVoices) channelOf(master, note uint8) (uint8, bool) {
+	for ch := int(master); ch < 16; ch++ {
+		if v.note[ch] == note+1 {
+			return uint8(ch), true
+		}
+	}
+	return master, false
+}
+
+// --- Piano roll editing ---
+
+// nudgeBend changes the selected note's bend (semitones)
+func (p *PianoRollDevice) nudgeBend(delta int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := &pat.Notes[s.SelectedNote]
+	n.Bend = clamp(n.Bend+delta, -noteBendMax, noteBendMax)
+}
+
+// nudgePressure changes the selected note's pressure (0 = none)
+func (p *PianoRollDevice) nudgePressure(delta int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := &pat.Notes[s.SelectedNote]
+	n.Pressure = uint8(clamp(int(n.Pressure)+delta, 0, 127))
+}
@@ -0,0 +1,67 @@
+package sequencer
+
+// Mute and solo - a muted track stays running but silent (its queue drains so it comes
+// back in time, and notes already sounding are ended on the spot). While any track is
+// soloed, only soloed tracks are heard; mute wins over solo.
+
+// trackAudible reports whether a track is heard under the mute/solo settings (hold m.mu)
+func trackAudible(trackIdx int) bool {
+	ts := S.Tracks[trackIdx]
+	if ts.Muted {
+		return false
+	}
+	if ts.Solo {
+		return true
+	}
+	for _, t := range S.Tracks {
+		if t.Solo {
+			return false
+		}
+	}
+	return true
+}
+
+// ToggleMute mutes or unmutes a track
+func (m *Manager) ToggleMute(trackIdx int) {
+	if trackIdx < 0 || trackIdx >= 8 {
+		return
+	}
+	m.mu.Lock()
+	ts := S.Tracks[trackIdx]
+	ts.Muted = !ts.Muted
+	muted := ts.Muted
+	if muted {
+		m.flushNotes(trackIdx)
+	}
+	m.mu.Unlock()
+	m.announce("track %d mute %s", trackIdx+1, onOff(muted))
+}
+
+// ToggleSolo solos or unsolos a track
+func (m *Manager) ToggleSolo(trackIdx int) {
+	if trackIdx < 0 || trackIdx >= 8 {
+		return
+	}
+	m.mu.Lock()
+	var audible [8]bool
+	for i := range audible {
+		audible[i] = trackAudible(i)
+	}
+	ts := S.Tracks[trackIdx]
+	ts.Solo = !ts.Solo
+	solo := ts.Solo
+	for i, was := range audible {
+		if was && !trackAudible(i) {
+			m.flushNotes(i)
+		}
+	}
+	m.mu.Unlock()
+	m.announce("track %d solo %s", trackIdx+1, onOff(solo))
+}
+
+// TrackAudible reports whether a track is currently heard (not muted or left out of a solo)
+func (m *Manager) TrackAudible(trackIdx int) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return trackAudible(trackIdx)
+}
@@ -0,0 +1,202 @@
+package sequencer
+
+import (
+	"fmt"
+	"time"
+
+	"go-sequence/debug"
+	"go-sequence/netsync"
+)
+
+// Leader broadcasts its position this often while playing
+const syncInterval = 250 * time.Millisecond
+
+// Follower re-aligns when it drifts more than this from the leader (~1/32 note)
+const syncDriftTicks = PPQ / 8
+
+// SetSyncLink attaches a network sync link (leader or follower)
+func (m *Manager) SetSyncLink(l *netsync.Link) {
+	m.syncLink = l
+	if l == nil {
+		return
+	}
+	go m.syncLoop(l)
+	if l.Role() == netsync.RoleLeader {
+		go m.syncClockLoop(l)
+	}
+}
+
+// IsFollower returns true if transport is driven by a remote leader
+func (m *Manager) IsFollower() bool {
+	return m.syncLink != nil && m.syncLink.Role() == netsync.RoleFollower
+}
+
+// SyncStatus describes the sync link for the header ("" when standalone)
+func (m *Manager) SyncStatus() string {
+	l := m.syncLink
+	if l == nil {
+		return ""
+	}
+	switch l.Role() {
+	case netsync.RoleLeader:
+		return fmt.Sprintf("leader %s (peers: %d)", l.Addr(), l.Peers())
+	case netsync.RoleFollower:
+		if l.Peers() == 0 {
+			return "follower (disconnected)"
+		}
+		return fmt.Sprintf("follower of %s", l.Addr())
+	}
+	return ""
+}
+
+// QueuePattern launches a pattern on a track and shares the launch with the sync peer
+func (m *Manager) QueuePattern(trackIdx, patternIdx int) {
+	at := m.queuePattern(trackIdx, patternIdx)
+	m.sendSync(netsync.Message{Type: netsync.MsgQueue, Track: trackIdx, Pattern: patternIdx, Tick: at})
+}
+
+// queuePattern switches a track to a pattern on the next launch quantization line (see
+// overrides.go), or queues it at the next boundary when launches aren't quantized.
+// Returns the line (0 when it queued)
+func (m *Manager) queuePattern(trackIdx, patternIdx int) int64 {
+	if line, ok := m.launchLine(trackIdx); ok {
+		m.switchPatternAt(trackIdx, patternIdx, line)
+		return line
+	}
+	m.queuePatternAt(trackIdx, patternIdx, S.Tick)
+	return 0
+}
+
+// queuePatternAt queues a pattern on a device at its first boundary after atTick
+func (m *Manager) queuePatternAt(trackIdx, patternIdx int, atTick int64) {
+	dev := m.GetDevice(trackIdx)
+	if dev != nil {
+		at := nextBarTick(dev)
+		if bd, ok := dev.(boundaryDevice); ok && S.Playing {
+			at = bd.nextBoundary(atTick)
+			m.scheduleTransport(trackIdx, at, true)
+		}
+		dev.QueuePattern(patternIdx, atTick)
+		m.resumeClip(trackIdx, at)
+		m.announce("track %d pattern %d queued", trackIdx+1, patternIdx+1)
+	}
+}
+
+// sendSync sends a message to the sync peer, if any
+func (m *Manager) sendSync(msg netsync.Message) {
+	if m.syncLink == nil {
+		return
+	}
+	if err := m.syncLink.Send(msg); err != nil {
+		debug.Log("sync", "send %s failed: %v", msg.Type, err)
+	}
+}
+
+// relaySync passes a message from one sync peer on to the others
+func (m *Manager) relaySync(msg netsync.Message) {
+	if m.syncLink == nil {
+		return
+	}
+	if err := m.syncLink.Relay(msg); err != nil {
+		debug.Log("sync", "relay %s failed: %v", msg.Type, err)
+	}
+}
+
+// syncLoop applies messages from the sync peer
+func (m *Manager) syncLoop(l *netsync.Link) {
+	for {
+		select {
+		case <-l.Done():
+			return
+		case msg := <-l.Messages():
+			m.withDevices(func() { m.handleSyncMessage(msg) })
+			m.notifyUpdate()
+		}
+	}
+}
+
+// syncClockLoop periodically sends the leader's tick so followers can correct drift
+func (m *Manager) syncClockLoop(l *netsync.Link) {
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.Done():
+			return
+		case <-ticker.C:
+			m.mu.RLock()
+			playing := S.Playing
+			tick := S.TimeToTick(time.Now())
+			m.mu.RUnlock()
+			if playing {
+				m.sendSync(netsync.Message{Type: netsync.MsgSync, Tick: tick})
+			}
+		}
+	}
+}
+
+func (m *Manager) handleSyncMessage(msg netsync.Message) {
+	debug.Log("sync", "recv %s tick=%d tempo=%d track=%d pattern=%d", msg.Type, msg.Tick, msg.Tempo, msg.Track, msg.Pattern)
+
+	switch msg.Type {
+	case netsync.MsgHello:
+		// A follower joined - send it our tempo and position
+		if m.IsFollower() {
+			return
+		}
+		m.mu.RLock()
+		tempo, playing := S.Tempo, S.Playing
+		tick := S.TimeToTick(time.Now())
+		m.mu.RUnlock()
+		m.sendSync(netsync.Message{Type: netsync.MsgTempo, Tempo: tempo})
+		if playing {
+			m.sendSync(netsync.Message{Type: netsync.MsgPlay, Tempo: tempo, Tick: tick})
+		}
+
+	case netsync.MsgPlay:
+		if !m.IsFollower() {
+			return
+		}
+		if msg.Tempo > 0 {
+			m.setTempo(msg.Tempo)
+		}
+		m.playFrom(msg.Tick)
+
+	case netsync.MsgStop:
+		if m.IsFollower() {
+			m.stop()
+		}
+
+	case netsync.MsgTempo:
+		if m.IsFollower() {
+			m.setTempo(msg.Tempo)
+		}
+
+	case netsync.MsgSync:
+		if !m.IsFollower() {
+			return
+		}
+		m.mu.Lock()
+		if S.Playing {
+			drift := S.TimeToTick(time.Now()) - msg.Tick
+			if drift > syncDriftTicks || drift < -syncDriftTicks {
+				// Shift T0 so our tick lines up with the leader's
+				S.T0 = S.T0.Add(time.Duration(drift) * S.TickDuration())
+			}
+		}
+		m.mu.Unlock()
+
+	case netsync.MsgQueue:
+		// Launches are shared both ways - either performer can launch clips
+		// (group launches carry the tick every track switches on)
+		if msg.Tick > 0 {
+			m.switchPatternAt(msg.Track, msg.Pattern, msg.Tick)
+		} else {
+			m.queuePattern(msg.Track, msg.Pattern)
+		}
+		if !m.IsFollower() {
+			// Pass a follower's launch on to the other followers
+			m.relaySync(msg)
+		}
+	}
+}
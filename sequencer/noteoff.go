@@ -0,0 +1,92 @@
+package sequencer
+
+import (
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+)
+
+// Note-off safety - every note the dispatch loop sends on is remembered by port,
+// channel and note until its note-off goes out. Stopping the transport, clearing the
+// queues, or muting a track (or leaving it out of a solo) throws queued note-offs away,
+// so those notes are ended on the spot instead of being left hanging.
+
+// activeNote is one note sent on and not yet off
+type activeNote struct {
+	port string
+	ch   uint8
+	note uint8
+}
+
+// resetVoices forgets a track's sounding notes, mono voice and MPE channels (hold
+// m.voiceMu)
+func (m *Manager) resetVoices(trackIdx int) {
+	m.monoNotes[trackIdx] = -1
+	m.sounding[trackIdx] = [128]bool{}
+	m.mpe[trackIdx] = mpeVoices{}
+}
+
+// trackNote records a dispatched note on or off for a track (ch is the channel it
+// went out on)
+func (m *Manager) trackNote(trackIdx int, portName string, ch uint8, evt *midi.Event) {
+	if evt.Type != midi.NoteOn && evt.Type != midi.NoteOff {
+		return
+	}
+	key := activeNote{port: portName, ch: ch, note: evt.Note}
+
+	m.activeMu.Lock()
+	defer m.activeMu.Unlock()
+	if evt.Type == midi.NoteOff {
+		delete(m.active[trackIdx], key)
+		return
+	}
+	if m.active[trackIdx] == nil {
+		m.active[trackIdx] = make(map[activeNote]bool)
+	}
+	m.active[trackIdx][key] = true
+}
+
+// flushNotes sends note-offs for everything a track has sounding and forgets it, so
+// note-offs still on their way are dropped as well (hold m.mu)
+func (m *Manager) flushNotes(trackIdx int) {
+	m.voiceMu.Lock()
+	defer m.voiceMu.Unlock()
+	m.activeMu.Lock()
+	notes := m.active[trackIdx]
+	m.active[trackIdx] = nil
+	m.activeMu.Unlock()
+
+	m.resetVoices(trackIdx)
+	if len(notes) == 0 {
+		return
+	}
+
+	profile := GetProfile(S.Tracks[trackIdx].Profile)
+	gated := make(map[activeNote]bool)
+	for n := range notes {
+		sender := m.getSender(n.port)
+		if sender == nil {
+			continue
+		}
+		sender(gomidi.NoteOff(n.ch, n.note))
+		if profile.GateNote >= 0 {
+			gate := activeNote{port: n.port, ch: (n.ch + uint8(profile.GateChannel)) % 16, note: uint8(profile.GateNote)}
+			if !gated[gate] {
+				gated[gate] = true
+				sender(gomidi.NoteOff(gate.ch, gate.note))
+			}
+		}
+	}
+}
+
+// clearQueues empties every device queue and ends the notes they were going to end
+// (hold m.mu)
+func (m *Manager) clearQueues() {
+	for i, dev := range m.devices {
+		if dev != nil {
+			dev.ClearQueue()
+			m.flushNotes(i)
+		}
+	}
+	m.dispatch.invalidate()
+}
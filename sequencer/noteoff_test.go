@@ -0,0 +1,27 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopResetsMonoNotes(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+	S.Playing = true
+	S.T0 = time.Now()
+
+	m := NewManager()
+	m.monoNotes[0] = 60 // a track with a device and nothing left sounding
+	m.monoNotes[7] = 48
+	m.SetDevice(0, NewEmptyDevice(1))
+	if !m.stop() {
+		t.Fatal("stop did nothing while playing")
+	}
+	for i, n := range m.monoNotes {
+		if n != -1 {
+			t.Errorf("track %d mono note %d after stop, want -1", i, n)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package sequencer
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"go-sequence/config"
+)
+
+// Project overrides - a project can carry its own rig: the default output port, launch
+// quantization and controller assignments, used in place of the config's while it's
+// loaded (for gigs with a different setup per song). Pin the rig in use to the project
+// from Settings (Project Rig row); launch quantization set there is always the
+// project's. Tempo needs no override - it's saved with the project already.
+
+// ProjectOverrides are the rig settings a project carries in place of the config's
+type ProjectOverrides struct {
+	DefaultPort string                    `json:"defaultPort,omitempty"` // output for tracks set to (default)
+	LaunchBars  *int                      `json:"launchBars,omitempty"`  // launch quantization in bars
+	Controllers []config.ControllerConfig `json:"controllers,omitempty"` // controller assignments
+}
+
+// launchBarsOptions are the launch quantizations Settings cycles through (bars, 0 =
+// each track's next pattern boundary)
+var launchBarsOptions = []int{0, 1, 2, 4, 8}
+
+// SetConfig gives the manager the config's rig (default port, launch quantization and
+// controllers) for projects that don't override it
+func (m *Manager) SetConfig(cfg *config.Config) {
+	m.cfg = cfg
+	m.applyOverrides()
+}
+
+// applyOverrides resolves the rig from the loaded project and the config, flagging a
+// change of controller assignments for the TUI to reconnect
+func (m *Manager) applyOverrides() {
+	if m.cfg == nil {
+		return
+	}
+	o := S.Overrides
+	if o == nil {
+		o = &ProjectOverrides{}
+	}
+	m.defaultPort = m.cfg.SynthOutput.PortName
+	if o.DefaultPort != "" {
+		m.defaultPort = o.DefaultPort
+	}
+
+	m.mu.Lock()
+	if !reflect.DeepEqual(o.Controllers, m.rigControllers) {
+		m.rigControllers = o.Controllers
+		m.rigChanged = true
+	}
+	m.mu.Unlock()
+}
+
+// TakeRigChange reports (once) that the loaded project changed the controller
+// assignments, so the controllers should be reconnected with RigConfig
+func (m *Manager) TakeRigChange() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := m.rigChanged
+	m.rigChanged = false
+	return changed
+}
+
+// RigConfig is the config with the project's controller assignments in place of its own
+func (m *Manager) RigConfig() *config.Config {
+	o := S.Overrides
+	if m.cfg == nil || o == nil || len(o.Controllers) == 0 {
+		return m.cfg
+	}
+	c := *m.cfg
+	c.Controllers = o.Controllers
+	return &c
+}
+
+// LaunchBars is the launch quantization in bars (0 = each track's next boundary)
+func (m *Manager) LaunchBars() int {
+	if o := S.Overrides; o != nil && o.LaunchBars != nil {
+		return *o.LaunchBars
+	}
+	if m.cfg != nil {
+		return m.cfg.LaunchBars
+	}
+	return 0
+}
+
+// cycleLaunchBars steps the project's launch quantization
+func (m *Manager) cycleLaunchBars(dir int) {
+	idx := max(slices.Index(launchBarsOptions, m.LaunchBars()), 0)
+	bars := launchBarsOptions[(idx+dir+len(launchBarsOptions))%len(launchBarsOptions)]
+	if S.Overrides == nil {
+		S.Overrides = &ProjectOverrides{}
+	}
+	S.Overrides.LaunchBars = &bars
+	m.announce("launch quantization %s", launchBarsName(bars))
+}
+
+// launchBarsName describes a launch quantization
+func launchBarsName(bars int) string {
+	switch bars {
+	case 0:
+		return "next boundary"
+	case 1:
+		return "1 bar"
+	}
+	return fmt.Sprintf("%d bars", bars)
+}
+
+// launchLine returns the next launch quantization line for a track, counted in bars of
+// its playing pattern's time signature from where that pattern started (false when
+// launches aren't quantized, so they land on the track's next boundary instead)
+func (m *Manager) launchLine(trackIdx int) (int64, bool) {
+	bars := m.LaunchBars()
+	bd, ok := m.GetDevice(trackIdx).(boundaryDevice)
+	if bars <= 0 || !ok || !S.Playing {
+		return 0, false
+	}
+	start, barTicks := bd.barGrid()
+	return lineAfter(S.Tick, start, int64(bars)*barTicks), true
+}
+
+// toggleProjectRig pins the rig in use (default port and controllers) to the project,
+// or drops the pinned one so the config's is used again
+func (m *Manager) toggleProjectRig() {
+	o := S.Overrides
+	if o != nil && (o.DefaultPort != "" || len(o.Controllers) > 0) {
+		o.DefaultPort, o.Controllers = "", nil
+		m.applyOverrides()
+		m.announce("project rig cleared - using the config's")
+		return
+	}
+	if m.cfg == nil {
+		return
+	}
+	if o == nil {
+		o = &ProjectOverrides{}
+		S.Overrides = o
+	}
+	o.DefaultPort = m.defaultPort
+	o.Controllers = slices.Clone(m.cfg.Controllers)
+	m.applyOverrides()
+	m.announce("rig pinned to the project: %s", projectRigSummary())
+}
+
+// projectRigSummary describes the project's pinned rig ("" = none)
+func projectRigSummary() string {
+	o := S.Overrides
+	if o == nil || (o.DefaultPort == "" && len(o.Controllers) == 0) {
+		return ""
+	}
+	port := o.DefaultPort
+	if port == "" {
+		port = "(none)"
+	}
+	return fmt.Sprintf("port %s, %d controllers", port, len(o.Controllers))
+}
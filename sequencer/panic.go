@@ -0,0 +1,90 @@
+package sequencer
+
+import (
+	gomidi "gitlab.com/gomidi/midi/v2"
+)
+
+// Panic - for stuck notes: All Sound Off and All Notes Off on every channel of every
+// open output, and the note tracking (mono voices, sounding notes, device held notes)
+// forgotten. `!` in the TUI, or hold both ends of the Launchpad top row.
+
+// Channel mode messages sent by Panic
+const (
+	ccAllSoundOff = 120
+	ccAllNotesOff = 123
+)
+
+// noteReleaser is a device that tracks notes it has sent or is recording
+type noteReleaser interface {
+	releaseNotes()
+}
+
+// Panic silences every open output and clears held-note state
+func (m *Manager) Panic() {
+	m.sendersMu.RLock()
+	senders := make([]func(gomidi.Message) error, 0, len(m.senders))
+	for _, sender := range m.senders {
+		senders = append(senders, sender)
+	}
+	m.sendersMu.RUnlock()
+
+	for _, sender := range senders {
+		for ch := uint8(0); ch < 16; ch++ {
+			sender(gomidi.ControlChange(ch, ccAllSoundOff, 0))
+			sender(gomidi.ControlChange(ch, ccAllNotesOff, 0))
+			sender(gomidi.Pitchbend(ch, 0))
+		}
+	}
+
+	m.voiceMu.Lock()
+	m.activeMu.Lock()
+	m.active = [8]map[activeNote]bool{}
+	m.activeMu.Unlock()
+	for i := range m.devices {
+		m.resetVoices(i)
+	}
+	m.voiceMu.Unlock()
+	for _, dev := range m.devices {
+		if nr, ok := dev.(noteReleaser); ok {
+			nr.releaseNotes()
+		}
+	}
+	m.announce("panic - all notes off on %d outputs", len(senders))
+}
+
+// panicCombo tracks the two top row corner pads; a press that completes the pair fires
+// a panic and is swallowed (returns true), as is its release. So is the other pad's
+// release where the global top row holds corner actions for release (see toprow.go).
+func (m *Manager) panicCombo(row, col int, down bool) bool {
+	if row != 8 || (col != 0 && col != 7) {
+		return false
+	}
+	side := col / 7
+	m.panicHeld[side] = down
+	if !down {
+		swallow := m.panicSwallow[side]
+		m.panicSwallow[side] = false
+		return swallow
+	}
+	if m.panicHeld[1-side] {
+		m.Panic()
+		m.panicSwallow[side] = true
+		m.panicSwallow[1-side] = m.globalTopRow()
+		return true
+	}
+	return false
+}
+
+// --- Devices ---
+
+func (p *PianoRollDevice) releaseNotes() {
+	p.queueMu.Lock()
+	p.heldNotes = make(map[uint8]bool)
+	p.queueMu.Unlock()
+	p.pendingNotes = make(map[uint8]*NoteEventState)
+}
+
+func (d *MetropolixDevice) releaseNotes() {
+	d.state.ActiveNote = 0
+	d.state.NoteEndStep = -1
+}
@@ -0,0 +1,133 @@
+package sequencer
+
+import (
+	"fmt"
+	"math/rand"
+
+	"go-sequence/widgets"
+)
+
+// Piano humanize - like the drum humanize preview, but nudges timing as well as
+// velocity. The pattern is edited live and the snapshot restored on cancel.
+
+// Piano humanize limits
+const (
+	DefaultPianoHumanizeVelocity = 12
+	DefaultPianoHumanizeTiming   = 20 // percent of a 1/16 step
+	maxPianoHumanizeTiming       = 100
+	pianoHumanizeTimingStep      = 5
+)
+
+// startHumanize snapshots the editing pattern and enters humanize preview
+func (p *PianoRollDevice) startHumanize() {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if len(pat.Notes) == 0 {
+		return
+	}
+	if s.HumanizeVelocity == 0 && s.HumanizeTiming == 0 {
+		s.HumanizeVelocity = DefaultPianoHumanizeVelocity
+		s.HumanizeTiming = DefaultPianoHumanizeTiming
+	}
+	p.humanizePattern = s.Editing
+	p.humanizeBase = clonePianoPattern(*pat)
+	p.humanizeAll = s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes)
+	p.humanizeMode = true
+	p.rollHumanize()
+}
+
+// rollHumanize re-randomizes from the snapshot (so amount changes don't compound)
+func (p *PianoRollDevice) rollHumanize() {
+	s := p.state
+	pat := &s.Patterns[p.humanizePattern]
+	*pat = clonePianoPattern(p.humanizeBase)
+
+	maxShift := 0.25 * float64(s.HumanizeTiming) / 100 // a 1/16 step is a quarter beat
+	for i := range pat.Notes {
+		if !p.humanizeAll && i != s.SelectedNote {
+			continue
+		}
+		n := &pat.Notes[i]
+		if s.HumanizeVelocity > 0 {
+			vel := int(n.Velocity) + rand.Intn(2*s.HumanizeVelocity+1) - s.HumanizeVelocity
+			n.Velocity = uint8(clamp(vel, 1, 127))
+		}
+		if maxShift > 0 {
+			start := n.Start + (rand.Float64()*2-1)*maxShift
+			n.Start = max(0, min(start, pat.Length-n.Duration))
+		}
+	}
+}
+
+// applyHumanize keeps the previewed notes (undoable)
+func (p *PianoRollDevice) applyHumanize() {
+	p.history.push(p.humanizePattern, p.humanizeBase)
+	p.humanizeMode = false
+}
+
+// cancelHumanize restores the pattern as it was before the preview
+func (p *PianoRollDevice) cancelHumanize() {
+	p.state.Patterns[p.humanizePattern] = p.humanizeBase
+	p.humanizeMode = false
+}
+
+func (p *PianoRollDevice) handleHumanizeKey(key string) {
+	s := p.state
+	switch key {
+	case "h", "left":
+		if s.HumanizeVelocity > 0 {
+			s.HumanizeVelocity--
+			p.rollHumanize()
+		}
+	case "l", "right":
+		if s.HumanizeVelocity < maxHumanizeRange {
+			s.HumanizeVelocity++
+			p.rollHumanize()
+		}
+	case "j", "down":
+		if s.HumanizeTiming > 0 {
+			s.HumanizeTiming = max(0, s.HumanizeTiming-pianoHumanizeTimingStep)
+			p.rollHumanize()
+		}
+	case "k", "up":
+		if s.HumanizeTiming < maxPianoHumanizeTiming {
+			s.HumanizeTiming = min(maxPianoHumanizeTiming, s.HumanizeTiming+pianoHumanizeTimingStep)
+			p.rollHumanize()
+		}
+	case "tab":
+		if s.SelectedNote >= 0 {
+			p.humanizeAll = !p.humanizeAll
+			p.rollHumanize()
+		}
+	case " ":
+		p.rollHumanize()
+	case "y", "Y", "enter":
+		p.applyHumanize()
+	case "n", "N", "esc", "q":
+		p.cancelHumanize()
+	}
+}
+
+// humanizeView renders the preview panel that replaces the key help
+func (p *PianoRollDevice) humanizeView() string {
+	s := p.state
+	scope := "selected note"
+	if p.humanizeAll {
+		scope = "whole pattern"
+	}
+	out := "\n─────────────────────────────────────────────────\n"
+	out += fmt.Sprintf("HUMANIZE  velocity ±%d  timing ±%d%% of 1/16  scope: %s  (previewing)\n\n",
+		s.HumanizeVelocity, s.HumanizeTiming, scope)
+	out += widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "h / l", Desc: "velocity -/+"},
+			{Key: "j / k", Desc: fmt.Sprintf("timing -/+ %d%%", pianoHumanizeTimingStep)},
+			{Key: "tab", Desc: "toggle note/pattern scope"},
+			{Key: "space", Desc: "re-roll"},
+			{Key: "y / enter", Desc: "apply"},
+			{Key: "n / esc", Desc: "cancel"},
+		}},
+	})
+	out += "\n─────────────────────────────────────────────────\n"
+	return out
+}
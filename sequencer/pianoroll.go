@@ -2,6 +2,9 @@ package sequencer
 
 import (
 	"fmt"
+	"math"
+	"reflect"
+	"slices"
 	"sort"
 	"sync"
 
@@ -21,6 +24,9 @@ var ViewScales = []float64{
 	4.0,     // 4 beats per col - zoomed out
 }
 
+// viewCols is the width of the TUI grid in columns
+const viewCols = 48
+
 // Edit sensitivity: movement amounts
 var EditHorizSteps = []float64{
 	0.015625, // 1/64
@@ -34,21 +40,72 @@ var EditHorizSteps = []float64{
 
 var EditVertSteps = []int{1, 12} // semitone, octave
 
+// pianoClip holds copied notes - shared by all piano roll tracks so notes can move between them
+type pianoClip struct {
+	notes      []NoteEventState // starts relative to the first note (or the pattern start if whole)
+	length     float64          // source pattern length (whole-pattern copies)
+	timeSig    int              // source pattern time signature (whole-pattern copies)
+	automation []AutomationLane // source pattern automation (whole-pattern copies)
+	pattern    bool             // whole pattern - paste replaces the editing one
+}
+
+var pianoClipboard *pianoClip
+
+// maxNoteChannel is the highest per-note channel override (0 = track channel)
+const maxNoteChannel = 16
+
+// Velocity edit amounts (coarse with v/b, fine with V/B)
+const (
+	velocityCoarseStep = 8
+	velocityFineStep   = 1
+)
+
 // PianoRollDevice reads/writes from central PianoState
 type PianoRollDevice struct {
 	state        *PianoState
 	heldNotes    map[uint8]bool            // runtime only - for note-off tracking during playback
 	pendingNotes map[uint8]*NoteEventState // runtime only - for recording note-on/note-off pairs
+	autoRecLast  map[int]float64           // runtime only - last beat each lane was recorded at this take
 
 	// Queue-based playback - protected by queueMu (held ONLY during swap, not generation)
 	queueMu          sync.RWMutex
 	queue            []midi.Event // events sorted by tick
+	popped           poppedMark   // how far dispatch has popped it (see queuetrim.go)
 	queuedUntilTick  int64        // how far we've filled the queue
 	patternStartTick int64        // tick when current pattern started
 	onQueueChange    func()       // callback to wake manager when queue needs recalc
 
 	// Pattern switching
 	nextPatternTick int64 // tick when next pattern should start (-1 if none)
+
+	// Undo/redo - snapshots of patterns before edits
+	history undoHistory[PianoPatternState]
+
+	// Replace recording - set once the current take has cleared the pattern
+	takeCleared bool
+
+	// Automation edit mode - cursor over the selected lane
+	autoMode bool
+	autoLane int
+	autoBeat float64
+
+	// Humanize preview - pattern is modified live, base is restored on cancel
+	humanizeMode    bool
+	humanizeAll     bool              // false = selected note, true = whole pattern
+	humanizePattern int               // pattern being humanized
+	humanizeBase    PianoPatternState // pattern data before humanize
+
+	// Step record - keyboard notes land at stepBeat (see steprecord.go)
+	stepMode bool
+	stepBeat float64
+	stepHeld int // keys still down on the current step
+
+	previewChan chan NoteEventState // auditioned notes (see audition.go)
+
+	// Pad expression - the note the held pad placed takes the pad's pressure
+	padNote     int    // index of the note placed by the held pad (-1 = none)
+	padCell     [2]int // row, col of that pad
+	padPressure bool   // the held pad's pressure has recorded its undo step
 }
 
 // NewPianoRollDevice creates a device that operates on the given state
@@ -58,6 +115,8 @@ func NewPianoRollDevice(state *PianoState) *PianoRollDevice {
 		heldNotes:       make(map[uint8]bool),
 		pendingNotes:    make(map[uint8]*NoteEventState),
 		nextPatternTick: -1,
+		previewChan:     make(chan NoteEventState, 16),
+		padNote:         -1,
 	}
 }
 
@@ -68,14 +127,18 @@ func (p *PianoRollDevice) SetOnQueueChange(fn func()) {
 
 // currentBeat returns the current playback beat derived from global tick
 func (p *PianoRollDevice) currentBeat() float64 {
-	ticksSinceStart := S.Tick - p.patternStartTick
+	return p.beatAt(S.Tick)
+}
+
+// beatAt returns the beat within the playing pattern at a tick
+func (p *PianoRollDevice) beatAt(tick int64) float64 {
+	ticksSinceStart := tick - p.patternStartTick
 	if ticksSinceStart < 0 {
 		ticksSinceStart = 0
 	}
-	pat := &p.state.Patterns[p.state.Pattern]
-	patternTicks := int64(pat.Length * float64(PPQ))
-	tickInPattern := ticksSinceStart % patternTicks
-	return float64(tickInPattern) / float64(PPQ)
+	loopStart, _ := p.state.Patterns[p.state.Pattern].Loop()
+	tickInPattern := ticksSinceStart % p.patternLengthTicks(p.state.Pattern)
+	return loopStart + float64(tickInPattern)/float64(PPQ)
 }
 
 // GeneratePattern generates all MIDI events for a pattern starting at startTick.
@@ -86,23 +149,41 @@ func (p *PianoRollDevice) GeneratePattern(patternNum int, startTick int64) []mid
 
 	var events []midi.Event
 
+	// Only the loop region plays (the whole pattern when none is set)
+	loopStart, loopEnd := pat.Loop()
+
 	for _, note := range pat.Notes {
+		if note.Start < loopStart || note.Start >= loopEnd {
+			continue
+		}
+
 		// Note on
-		noteTick := startTick + int64(note.Start*float64(ticksPerBeat))
+		noteTick := startTick + int64((note.Start-loopStart)*float64(ticksPerBeat))
 		events = append(events, midi.Event{
-			Tick:     noteTick,
-			Type:     midi.NoteOn,
-			Note:     note.Pitch,
-			Velocity: note.Velocity,
+			Tick:       noteTick,
+			Type:       midi.NoteOn,
+			Note:       note.Pitch,
+			Velocity:   note.Velocity,
+			OutChannel: note.Channel,
 		})
 
 		// Note off
-		noteEndTick := startTick + int64((note.Start+note.Duration)*float64(ticksPerBeat))
+		noteEnd := min(note.Start+note.Duration, loopEnd)
+		noteEndTick := startTick + int64((noteEnd-loopStart)*float64(ticksPerBeat))
 		events = append(events, midi.Event{
-			Tick: noteEndTick,
-			Type: midi.NoteOff,
-			Note: note.Pitch,
+			Tick:       noteEndTick,
+			Type:       midi.NoteOff,
+			Note:       note.Pitch,
+			OutChannel: note.Channel,
 		})
+
+		// Per-note bend and pressure
+		events = append(events, noteExpression(note, noteTick, noteEndTick)...)
+	}
+
+	// Automation lanes
+	for i := range pat.Automation {
+		events = append(events, automationEvents(&pat.Automation[i], loopStart, loopEnd, startTick)...)
 	}
 
 	// Sort by tick (notes may not be in time order)
@@ -113,10 +194,10 @@ func (p *PianoRollDevice) GeneratePattern(patternNum int, startTick int64) []mid
 	return events
 }
 
-// patternLengthTicks returns the length of a pattern in ticks
+// patternLengthTicks returns the length of a pattern (its loop region, if set) in ticks
 func (p *PianoRollDevice) patternLengthTicks(patternNum int) int64 {
-	pat := &p.state.Patterns[patternNum]
-	return int64(pat.Length * float64(PPQ))
+	start, end := p.state.Patterns[patternNum].Loop()
+	return int64((end - start) * float64(PPQ))
 }
 
 // Device interface implementation - queue-based
@@ -147,8 +228,14 @@ func (p *PianoRollDevice) FillUntil(tick int64) {
 		}
 
 		events := p.GeneratePattern(currentPattern, queuedUntil)
+		end := queuedUntil + p.patternLengthTicks(currentPattern)
+		if nextPatTick > queuedUntil && nextPatTick < end {
+			// A restart cuts this pass short
+			events = cutEvents(events, nextPatTick)
+			end = nextPatTick
+		}
 		newEvents = append(newEvents, events...)
-		queuedUntil += p.patternLengthTicks(currentPattern)
+		queuedUntil = end
 	}
 
 	// Swap in new events (brief lock)
@@ -174,6 +261,7 @@ func (p *PianoRollDevice) regeneratePatternInQueue(patternNum int) {
 	oldQueue := p.queue
 	oldQueuedUntil := p.queuedUntilTick
 	patternStart := p.patternStartTick
+	nextPatTick := p.nextPatternTick
 	p.queueMu.RUnlock()
 
 	// --- Generate new queue OUTSIDE the lock (this is the slow part) ---
@@ -193,10 +281,15 @@ func (p *PianoRollDevice) regeneratePatternInQueue(patternNum int) {
 		newQueue = append(newQueue, events...)
 		newQueuedUntil += patternTicks
 	}
+	if nextPatTick >= 0 && nextPatTick < newQueuedUntil {
+		// A pending restart cuts the last pass short
+		newQueue = cutEvents(newQueue, nextPatTick)
+		newQueuedUntil = nextPatTick
+	}
 
 	// --- Swap in new queue (brief lock) ---
 	p.queueMu.Lock()
-	p.queue = newQueue
+	p.queue = p.popped.trim(newQueue) // already sent events stay sent
 	p.queuedUntilTick = newQueuedUntil
 	p.queueMu.Unlock()
 
@@ -225,9 +318,10 @@ func (p *PianoRollDevice) PopNextEvent() *midi.Event {
 	if len(p.queue) == 0 {
 		return nil
 	}
-	event := p.queue[0]
+	event := &p.queue[0] // no copy - queues are replaced, never written in place
 	p.queue = p.queue[1:]
-	return &event
+	p.popped.pop(event.Tick)
+	return event
 }
 
 // ClearQueue clears all queued events (for stop/restart)
@@ -236,6 +330,7 @@ func (p *PianoRollDevice) ClearQueue() {
 	defer p.queueMu.Unlock()
 
 	p.queue = nil
+	p.popped = poppedMark{}
 	p.queuedUntilTick = 0
 	p.patternStartTick = 0
 	p.nextPatternTick = -1
@@ -250,9 +345,7 @@ func (p *PianoRollDevice) QueuePattern(patIdx int, atTick int64) {
 	p.state.Next = patIdx
 
 	// Find next pattern boundary
-	pat := &p.state.Patterns[p.state.Pattern]
-	ticksPerBeat := int64(PPQ)
-	patternTicks := int64(pat.Length * float64(ticksPerBeat))
+	patternTicks := p.patternLengthTicks(p.state.Pattern)
 
 	// Read state under lock
 	p.queueMu.RLock()
@@ -261,23 +354,15 @@ func (p *PianoRollDevice) QueuePattern(patIdx int, atTick int64) {
 	p.queueMu.RUnlock()
 
 	// Calculate when the next pattern boundary occurs
-	ticksSinceStart := atTick - patternStart
-	ticksIntoPattern := ticksSinceStart % patternTicks
-	ticksToNextBoundary := patternTicks - ticksIntoPattern
-	boundaryTick := atTick + ticksToNextBoundary
+	boundaryTick := boundaryAfter(atTick, patternStart, patternTicks)
 
 	needsNotify := false
 
-	// If we've already queued past the boundary, wipe those events
+	// If we've already queued past the boundary, wipe those events (note-offs move
+	// onto the boundary so nothing is left hanging)
 	if queuedUntil > boundaryTick {
 		p.queueMu.Lock()
-		newQueue := p.queue[:0]
-		for _, e := range p.queue {
-			if e.Tick < boundaryTick {
-				newQueue = append(newQueue, e)
-			}
-		}
-		p.queue = newQueue
+		p.queue = cutEvents(p.queue, boundaryTick)
 		p.queuedUntilTick = boundaryTick
 		p.nextPatternTick = boundaryTick
 		p.queueMu.Unlock()
@@ -317,33 +402,90 @@ func (p *PianoRollDevice) ContentMask() []bool {
 	return mask
 }
 
+// PatternBars returns each pattern's playing length (loop region, if set) in bars of its time signature
+func (p *PianoRollDevice) PatternBars() []float64 {
+	bars := make([]float64, NumPatterns)
+	for i := range p.state.Patterns {
+		pat := &p.state.Patterns[i]
+		start, end := pat.Loop()
+		bars[i] = (end - start) / pat.Sig().BarBeats()
+	}
+	return bars
+}
+
+// Density returns notes per bar (of the pattern's time signature) for each pattern
+func (p *PianoRollDevice) Density() []float64 {
+	density := make([]float64, NumPatterns)
+	for i := range p.state.Patterns {
+		pat := &p.state.Patterns[i]
+		if pat.Length > 0 {
+			density[i] = float64(len(pat.Notes)) * pat.Sig().BarBeats() / pat.Length
+		}
+	}
+	return density
+}
+
 func (p *PianoRollDevice) HandleMIDI(event midi.Event) {
+	// Step record takes keyboard notes whether or not we're playing
+	if p.stepMode {
+		p.stepRecord(event)
+		return
+	}
+
 	// Only record while playing and recording is enabled
 	if !S.Playing || !p.state.Recording {
 		return
 	}
 
 	pattern := &p.state.Patterns[p.state.Editing]
-	// Get current beat from global tick
+	// Beat of the event (input and resampled events carry their tick)
 	currentBeat := p.currentBeat()
+	if event.Tick > 0 {
+		currentBeat = p.beatAt(event.Tick)
+	}
 
-	// Quantize to nearest 1/16th note
-	quantized := float64(int(currentBeat*4+0.5)) / 4.0
+	// Aftertouch and bend go to automation (unquantized)
+	if event.Type != midi.NoteOn && event.Type != midi.NoteOff {
+		p.recordExpression(pattern, event, currentBeat)
+		return
+	}
+
+	// Quantize to the record grid (off = free timing, shortest note 1/64)
+	grid := p.state.recordGrid()
+	minDuration := grid
+	quantized := currentBeat
+	if grid > 0 {
+		quantized = quantizeStart(currentBeat, grid, 100, pattern.Length)
+	} else {
+		minDuration = EditHorizSteps[0]
+	}
 
 	if event.Type == midi.NoteOn && event.Velocity > 0 {
+		// Replace mode - the first note of a take clears the pattern (undoable)
+		if p.state.RecordReplace && !p.takeCleared {
+			p.history.push(p.state.Editing, clonePianoPattern(*pattern))
+			pattern.Notes = []NoteEventState{}
+			p.state.SelectedNote = -1
+			p.takeCleared = true
+		}
+
 		// Note on - start a pending note
 		p.pendingNotes[event.Note] = &NoteEventState{
 			Start:    quantized,
 			Pitch:    event.Note,
 			Velocity: event.Velocity,
+			Channel:  event.OutChannel, // resampled notes keep their channel
 		}
 	} else if event.Type == midi.NoteOff || (event.Type == midi.NoteOn && event.Velocity == 0) {
 		// Note off - complete the pending note
 		if pending, ok := p.pendingNotes[event.Note]; ok {
-			endBeat := float64(int(currentBeat*4+0.5)) / 4.0
+			endBeat := currentBeat
+			if grid > 0 {
+				endBeat = math.Round(currentBeat/grid) * grid
+			}
 			duration := endBeat - pending.Start
-			if duration < 0.25 {
-				duration = 0.25 // minimum 1/16th note
+			if duration < minDuration {
+				duration = minDuration // at least one grid step
 			}
 			pending.Duration = duration
 			pattern.Notes = append(pattern.Notes, *pending)
@@ -354,6 +496,8 @@ func (p *PianoRollDevice) HandleMIDI(event midi.Event) {
 
 func (p *PianoRollDevice) ToggleRecording() {
 	p.state.Recording = !p.state.Recording
+	p.takeCleared = false
+	p.autoRecLast = nil
 }
 
 func (p *PianoRollDevice) TogglePreview() {
@@ -402,6 +546,9 @@ func (p *PianoRollDevice) View() string {
 	if s.Editing != s.Pattern {
 		playInfo = fmt.Sprintf(" (playing:%d)", s.Pattern)
 	}
+	if s.Preview {
+		playInfo += "  AUDITION"
+	}
 
 	viewScale := ViewScales[s.ViewScale]
 	editH := EditHorizSteps[s.EditHoriz]
@@ -410,25 +557,65 @@ func (p *PianoRollDevice) View() string {
 	if s.ViewRows == ViewSmushed {
 		vertMode = "smushed"
 	}
+	if s.Follow {
+		vertMode += " follow"
+	}
 
 	beat := p.currentBeat()
-	out := fmt.Sprintf("PIANO  Pattern %d%s  Beat %.1f/%g\n", s.Editing+1, playInfo, beat, pat.Length)
-	out += fmt.Sprintf("View: %s/col %s  Edit: %s horiz, %d semi vert\n\n", formatStep(viewScale), vertMode, formatStep(editH), editV)
-
+	out := fmt.Sprintf("PIANO  Pattern %d%s  %s  Beat %.2f/%g\n", s.Editing+1, playInfo, pat.Sig(), beat, pat.Length)
+	recordMode := "overdub"
+	if s.RecordReplace {
+		recordMode = "replace"
+	}
+	out += fmt.Sprintf("View: %s/col %s  Edit: %s horiz, %d semi vert  Quantize: %s %d%%\n",
+		formatStep(viewScale), vertMode, formatStep(editH), editV, s.quantizeGrid().Name, s.quantizeStrength())
 	noteNames := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	scaleInfo := "off"
+	if pat.Scale != ScaleChromatic {
+		scaleInfo = fmt.Sprintf("%s %s (lock, x = out of scale)", noteNames[pat.Root%12], scaleName(pat.Scale))
+	}
+	loopInfo := "off"
+	loopStart, loopEnd := pat.Loop()
+	if pat.HasLoop() {
+		loopInfo = fmt.Sprintf("%g-%g", loopStart, loopEnd)
+	}
+	out += fmt.Sprintf("Record: quantize %s, %s  Scale: %s  Loop: %s  Ghost: %s\n", s.recordQuantizeName(), recordMode, scaleInfo, loopInfo, p.ghostName())
+	if p.stepMode {
+		out += fmt.Sprintf("STEP RECORD  beat %.2f  (play keys to enter, space rest, backspace back, E/esc done)\n", p.stepBeat)
+	}
+	out += "\n"
 
-	cols := 48
+	cols := viewCols
 	rows := s.ViewRows
 
 	beatsPerCol := viewScale
 	totalBeats := float64(cols) * beatsPerCol
-	startBeat := s.CenterBeat - totalBeats/2
+	startBeat := p.viewCenter() - totalBeats/2
 	startPitch := int(s.CenterPitch) + rows/2
 
 	playheadCol := -1
 	if s.Editing == s.Pattern && beat >= startBeat {
 		playheadCol = int((beat - startBeat) / beatsPerCol)
 	}
+	ghost := p.ghostPattern()
+	stepCol := -1
+	if p.stepMode && p.stepBeat >= startBeat {
+		stepCol = int((p.stepBeat - startBeat) / beatsPerCol)
+	}
+
+	// Loop ruler - marks the region that plays
+	if pat.HasLoop() {
+		out += "loop"
+		for col := 0; col < cols; col++ {
+			colBeat := startBeat + float64(col)*beatsPerCol
+			if colBeat >= loopStart && colBeat < loopEnd {
+				out += "═"
+			} else {
+				out += " "
+			}
+		}
+		out += "\n"
+	}
 
 	for row := 0; row < rows; row++ {
 		pitch := uint8(startPitch - row)
@@ -474,6 +661,8 @@ func (p *PianoRollDevice) View() string {
 						char = "◉"
 					} else if isPlayhead {
 						char = "▶"
+					} else if pat.Scale != ScaleChromatic && !inScale(int(pitch), pat.Root, pat.Scale) {
+						char = "x"
 					} else {
 						char = "●"
 					}
@@ -487,6 +676,10 @@ func (p *PianoRollDevice) View() string {
 			} else {
 				if isPlayhead {
 					char = "▶"
+				} else if col == stepCol {
+					char = "│"
+				} else if p.ghostAt(ghost, pitch, colBeat, colBeatEnd) {
+					char = "░"
 				} else {
 					char = "·"
 				}
@@ -496,11 +689,102 @@ func (p *PianoRollDevice) View() string {
 		out += "\n"
 	}
 
+	// Velocity lane - bar per column for notes starting there (selected note wins)
+	out += "vel "
+	for col := 0; col < cols; col++ {
+		colBeat := startBeat + float64(col)*beatsPerCol
+		colBeatEnd := colBeat + beatsPerCol
+		if colBeat < 0 || colBeat >= pat.Length {
+			out += " "
+			continue
+		}
+		var vel uint8
+		for i := range pat.Notes {
+			n := &pat.Notes[i]
+			if n.Start >= colBeat && n.Start < colBeatEnd {
+				if i == s.SelectedNote {
+					vel = n.Velocity
+					break
+				}
+				vel = max(vel, n.Velocity)
+			}
+		}
+		if vel > 0 {
+			out += velocityGlyph(vel)
+		} else {
+			out += " "
+		}
+	}
+	out += "\n"
+
+	// Automation lanes - ◆ on breakpoints, bars for the interpolated value
+	autoCol := -1
+	if p.autoMode && p.autoBeat >= startBeat {
+		autoCol = int((p.autoBeat - startBeat) / beatsPerCol)
+	}
+	for li := range pat.Automation {
+		lane := &pat.Automation[li]
+		label := lane.Name()
+		if len(label) > 4 {
+			label = label[:4]
+		}
+		out += fmt.Sprintf("%-4s", label)
+		for col := 0; col < cols; col++ {
+			colBeat := startBeat + float64(col)*beatsPerCol
+			colBeatEnd := colBeat + beatsPerCol
+			if colBeat < 0 || colBeat >= pat.Length {
+				out += " "
+				continue
+			}
+			char := " "
+			if p.autoMode && li == p.autoLane && col == autoCol {
+				char = "▼"
+			} else if hasAutomationPoint(lane, colBeat, colBeatEnd) {
+				char = "◆"
+			} else if v := lane.ValueAt(colBeat); v > 0 {
+				char = velocityGlyph(uint8(v))
+			}
+			out += char
+		}
+		if p.autoMode && li == p.autoLane {
+			out += " <"
+		}
+		out += "\n"
+	}
+	if p.autoMode {
+		out += "\nAutomation: "
+		if p.autoLane < len(pat.Automation) {
+			lane := &pat.Automation[p.autoLane]
+			value := "-"
+			if v := lane.ValueAt(p.autoBeat); v >= 0 {
+				value = fmt.Sprint(v)
+			}
+			out += fmt.Sprintf("%s  beat:%.2f  value:%s  points:%d", lane.Name(), p.autoBeat, value, len(lane.Points))
+		} else {
+			out += "no lanes (n to add)"
+		}
+		out += "  [hl beat, jk lane, [] value -/+8, {} -/+1, space point, x delete, n new, c/C cc, d remove, esc done]\n"
+	}
+
 	if s.SelectedNote >= 0 && s.SelectedNote < len(pat.Notes) {
 		n := &pat.Notes[s.SelectedNote]
 		noteName := noteNames[n.Pitch%12]
 		octNum := n.Pitch / 12
-		out += fmt.Sprintf("\nSelected: %s%d  start:%.2f  dur:%.2f  vel:%d", noteName, octNum, n.Start, n.Duration, n.Velocity)
+		channel := "track"
+		if n.Channel > 0 {
+			channel = fmt.Sprint(n.Channel)
+		}
+		out += fmt.Sprintf("\nSelected: %s%d  start:%.2f  dur:%.2f  vel:%d  ch:%s", noteName, octNum, n.Start, n.Duration, n.Velocity, channel)
+		if n.Bend != 0 || n.Pressure > 0 {
+			out += fmt.Sprintf("  bend:%+d  pressure:%d", n.Bend, n.Pressure)
+		}
+	}
+
+	// Humanize preview replaces key help
+	if p.humanizeMode {
+		out += "\n"
+		out += p.humanizeView()
+		return out
 	}
 
 	out += "\n\n"
@@ -512,6 +796,13 @@ func (p *PianoRollDevice) View() string {
 			{Key: "yuio", Desc: "move note"},
 			{Key: "n / m", Desc: "shorter / longer"},
 		}},
+		{Title: "Velocity", Keys: []widgets.KeyBinding{
+			{Key: "v / b", Desc: fmt.Sprintf("velocity -/+ %d", velocityCoarseStep)},
+			{Key: "V / B", Desc: "velocity -/+ 1"},
+			{Key: "ctrl+o / ctrl+p", Desc: "note channel -/+ (track = default)"},
+			{Key: "% / ^", Desc: "bend -/+ 1 semitone (glides over the note)"},
+			{Key: "ctrl+v / ctrl+b", Desc: fmt.Sprintf("pressure -/+ %d", pressureEditStep)},
+		}},
 		{Title: "Notes", Keys: []widgets.KeyBinding{
 			{Key: "space", Desc: "add note"},
 			{Key: "x", Desc: "delete note"},
@@ -519,6 +810,9 @@ func (p *PianoRollDevice) View() string {
 		{Title: "View", Keys: []widgets.KeyBinding{
 			{Key: "q / w", Desc: "zoom out/in"},
 			{Key: "a / s", Desc: "smushed/spread"},
+			{Key: "Y", Desc: "zoom to fit"},
+			{Key: "#", Desc: "ghost notes: cycle source track"},
+			{Key: "ctrl+f", Desc: "follow playhead"},
 		}},
 		{Title: "Grid", Keys: []widgets.KeyBinding{
 			{Key: "d / f", Desc: "horiz coarse/fine"},
@@ -526,13 +820,55 @@ func (p *PianoRollDevice) View() string {
 		}},
 		{Title: "Pattern", Keys: []widgets.KeyBinding{
 			{Key: "< / >", Desc: "prev/next pattern"},
-			{Key: "[ / ]", Desc: "length -/+"},
+			{Key: "[ / ]", Desc: "length -/+ 1 beat"},
+			{Key: ": / \"", Desc: "length -/+ 1/4 beat"},
+			{Key: "|", Desc: "cycle time signature"},
 			{Key: "c", Desc: "clear"},
+			{Key: "U / ctrl+r", Desc: "undo / redo"},
+		}},
+		{Title: "Quantize", Keys: []widgets.KeyBinding{
+			{Key: "z / Z", Desc: "note / whole pattern"},
+			{Key: ";", Desc: "cycle grid"},
+			{Key: "'", Desc: fmt.Sprintf("strength +%d%%", quantizeStrengthStep)},
+		}},
+		{Title: "Cleanup", Keys: []widgets.KeyBinding{
+			{Key: "~", Desc: "humanize timing / velocity (preview)"},
+			{Key: "F / W", Desc: "legato note / whole pattern"},
+			{Key: "X", Desc: "trim same-pitch overlaps"},
+		}},
+		{Title: "Chord", Keys: []widgets.KeyBinding{
+			{Key: "*", Desc: "arpeggiate (edit step apart)"},
+			{Key: "&", Desc: "strum (keeps chord end)"},
+		}},
+		{Title: "Loop", Keys: []widgets.KeyBinding{
+			{Key: "I / O", Desc: "loop in / out at center"},
+			{Key: "C", Desc: "clear loop"},
+		}},
+		{Title: "Transpose", Keys: []widgets.KeyBinding{
+			{Key: "J / K", Desc: "pattern -/+ 1 semitone"},
+			{Key: "N / M", Desc: "pattern -/+ octave"},
+		}},
+		{Title: "Scale", Keys: []widgets.KeyBinding{
+			{Key: "{ / }", Desc: "scale (chromatic = off)"},
+			{Key: "( / )", Desc: "root -/+"},
+		}},
+		{Title: "Record", Keys: []widgets.KeyBinding{
+			{Key: "E", Desc: "step record from view center"},
+			{Key: "/", Desc: "cycle record quantize (off, grids)"},
+			{Key: "\\", Desc: "overdub / replace"},
+		}},
+		{Title: "Automation", Keys: []widgets.KeyBinding{
+			{Key: "A", Desc: "edit CC / bend lanes"},
+		}},
+		{Title: "Clipboard", Keys: []widgets.KeyBinding{
+			{Key: "g / G", Desc: "copy note / pattern"},
+			{Key: "t", Desc: "paste (notes at center)"},
+			{Key: "T", Desc: "duplicate to next slot"},
 		}},
 	})
 
 	out += "\n\n"
-	out += p.renderLaunchpadHelp()
+	out += widgets.RenderLayout(p.HelpLayout())
 
 	return out
 }
@@ -547,10 +883,16 @@ func (p *PianoRollDevice) RenderLEDs() []LEDState {
 	dimColor := [3]uint8{20, 50, 70}
 	playheadColor := [3]uint8{255, 255, 255}
 	offColor := [3]uint8{0, 0, 0}
+	outOfScaleColor := [3]uint8{255, 80, 0} // notes off the pattern's scale
+	offScaleDimColor := [3]uint8{5, 12, 18} // empty pads off the scale
+	outOfLoopColor := [3]uint8{30, 70, 90}  // notes outside the loop region
+	ghostColor := [3]uint8{60, 40, 10}      // ghost notes from another track
+	loopStart, loopEnd := pat.Loop()
+	ghost := p.ghostPattern()
 
 	basePitch := int(s.CenterPitch) - 4
 	viewScale := ViewScales[s.ViewScale]
-	startBeat := s.CenterBeat - 4*viewScale
+	startBeat := p.viewCenter() - 4*viewScale
 	beat := p.currentBeat()
 
 	playheadCol := -1
@@ -571,16 +913,27 @@ func (p *PianoRollDevice) RenderLEDs() []LEDState {
 
 			var color [3]uint8 = dimColor
 			channel := midi.ChannelStatic
+			offScale := pat.Scale != ScaleChromatic && !inScale(int(pitch), pat.Root, pat.Scale)
+			if offScale {
+				color = offScaleDimColor
+			}
 
 			if colBeat < 0 || colBeat >= pat.Length {
 				color = offColor
 			} else {
+				if p.ghostAt(ghost, pitch, colBeat, colBeatEnd) {
+					color = ghostColor
+				}
 				for i, n := range pat.Notes {
 					if n.Pitch == pitch {
 						noteEnd := n.Start + n.Duration
 						if n.Start < colBeatEnd && noteEnd > colBeat {
 							if i == s.SelectedNote {
 								color = selectedColor
+							} else if offScale {
+								color = outOfScaleColor
+							} else if colBeat < loopStart || colBeat >= loopEnd {
+								color = outOfLoopColor
 							} else {
 								color = noteColor
 							}
@@ -599,7 +952,35 @@ func (p *PianoRollDevice) RenderLEDs() []LEDState {
 		}
 	}
 
-	return leds
+	return append(leds, p.guideLEDs()...)
+}
+
+// following reports whether the view is tracking the playhead right now
+func (p *PianoRollDevice) following() bool {
+	s := p.state
+	return s.Follow && S.Playing && s.Editing == s.Pattern
+}
+
+// viewCenter returns the beat at the middle of the view (the playhead while following)
+func (p *PianoRollDevice) viewCenter() float64 {
+	if p.following() {
+		return p.currentBeat()
+	}
+	return p.state.CenterBeat
+}
+
+// zoomToFit picks the closest zoom that shows the whole pattern (or its loop) and centers it
+func (p *PianoRollDevice) zoomToFit() {
+	s := p.state
+	start, end := s.Patterns[s.Editing].Loop()
+	s.ViewScale = len(ViewScales) - 1
+	for i, scale := range ViewScales {
+		if float64(viewCols)*scale >= end-start {
+			s.ViewScale = i
+			break
+		}
+	}
+	s.CenterBeat = start + (end-start)/2
 }
 
 func (p *PianoRollDevice) centerOnSelection() {
@@ -673,13 +1054,211 @@ func abs(x float64) float64 {
 	return x
 }
 
+// clonePianoPattern copies a pattern (notes and automation are slices, so snapshots need their own)
+func clonePianoPattern(pat PianoPatternState) PianoPatternState {
+	pat.Notes = slices.Clone(pat.Notes)
+	pat.Automation = cloneAutomation(pat.Automation)
+	return pat
+}
+
+// pianoPatternEqual reports whether two patterns are the same in every field (notes,
+// automation, length, scale lock, loop region, ...)
+func pianoPatternEqual(a, b PianoPatternState) bool {
+	if !slices.Equal(a.Notes, b.Notes) || !automationEqual(a.Automation, b.Automation) {
+		return false
+	}
+	a.Notes, a.Automation = nil, nil
+	b.Notes, b.Automation = nil, nil
+	return reflect.DeepEqual(a, b)
+}
+
+// trackEdit snapshots the editing pattern; the returned func records an undo step
+// if the pattern changed since. The humanize preview records its own step when applied.
+func (p *PianoRollDevice) trackEdit() func() {
+	idx := p.state.Editing
+	before := clonePianoPattern(p.state.Patterns[idx])
+	previewing := p.humanizeMode
+	return func() {
+		if previewing || p.humanizeMode {
+			return
+		}
+		if !pianoPatternEqual(p.state.Patterns[idx], before) {
+			p.history.push(idx, before)
+		}
+	}
+}
+
+func (p *PianoRollDevice) patternData(pattern int) PianoPatternState {
+	return clonePianoPattern(p.state.Patterns[pattern])
+}
+
+// Undo restores the most recent snapshot
+func (p *PianoRollDevice) Undo() {
+	if e, ok := p.history.stepBack(p.patternData); ok {
+		p.restorePattern(e.pattern, e.data)
+	}
+}
+
+// Redo re-applies the most recently undone edit
+func (p *PianoRollDevice) Redo() {
+	if e, ok := p.history.stepForward(p.patternData); ok {
+		p.restorePattern(e.pattern, e.data)
+	}
+}
+
+// restorePattern swaps in a snapshot and shows it
+func (p *PianoRollDevice) restorePattern(pattern int, data PianoPatternState) {
+	s := p.state
+	s.Patterns[pattern] = data
+	s.Editing = pattern
+	if s.SelectedNote >= len(data.Notes) {
+		s.SelectedNote = len(data.Notes) - 1
+	}
+	p.regeneratePatternInQueue(pattern)
+}
+
+// copyNote puts the selected note on the clipboard
+func (p *PianoRollDevice) copyNote() {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := pat.Notes[s.SelectedNote]
+	n.Start = 0
+	pianoClipboard = &pianoClip{notes: []NoteEventState{n}}
+}
+
+// copyPattern puts the whole editing pattern on the clipboard
+func (p *PianoRollDevice) copyPattern() {
+	pat := &p.state.Patterns[p.state.Editing]
+	pianoClipboard = &pianoClip{notes: slices.Clone(pat.Notes), length: pat.Length, timeSig: pat.TimeSig, automation: cloneAutomation(pat.Automation), pattern: true}
+}
+
+// paste inserts the clipboard: notes go at the view center, a whole pattern replaces the editing one
+func (p *PianoRollDevice) paste() {
+	clip := pianoClipboard
+	if clip == nil {
+		return
+	}
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+
+	if clip.pattern {
+		pat.Notes = slices.Clone(clip.notes)
+		pat.Length = clip.length
+		pat.TimeSig = clip.timeSig
+		pat.Automation = cloneAutomation(clip.automation)
+		s.SelectedNote = -1
+		return
+	}
+
+	for _, n := range clip.notes {
+		n.Start += s.CenterBeat
+		if n.Start < 0 || n.Start >= pat.Length {
+			continue
+		}
+		n.Duration = min(n.Duration, pat.Length-n.Start)
+		pat.Notes = append(pat.Notes, n)
+		s.SelectedNote = len(pat.Notes) - 1
+	}
+}
+
+// duplicatePattern copies the editing pattern into the next slot and starts editing it
+func (p *PianoRollDevice) duplicatePattern() {
+	s := p.state
+	next := s.Editing + 1
+	if next >= NumPatterns {
+		return
+	}
+	p.history.push(next, clonePianoPattern(s.Patterns[next]))
+	s.Patterns[next] = clonePianoPattern(s.Patterns[s.Editing])
+	s.Editing = next
+	p.regeneratePatternInQueue(next)
+}
+
+// IsInputMode returns true while editing automation lanes or previewing humanize
+func (p *PianoRollDevice) IsInputMode() bool {
+	return p.autoMode || p.humanizeMode
+}
+
 func (p *PianoRollDevice) HandleKey(key string) {
+	if key != "U" && key != "ctrl+r" {
+		defer p.trackEdit()()
+	}
+	defer p.trackAudition()()
+
+	if p.autoMode {
+		p.handleAutomationKey(key)
+		return
+	}
+	if p.humanizeMode {
+		p.handleHumanizeKey(key)
+		return
+	}
+
+	// Step record - space is a rest, backspace steps back
+	if p.stepMode {
+		switch key {
+		case " ":
+			p.moveStep(1)
+			return
+		case "backspace":
+			p.moveStep(-1)
+			return
+		case "esc":
+			p.stepMode = false
+			return
+		}
+	}
+
 	s := p.state
 	pat := &s.Patterns[s.Editing]
 	editH := EditHorizSteps[s.EditHoriz]
 	editV := EditVertSteps[s.EditVert]
 
+	// While following, edits land where the view is
+	if p.following() {
+		s.CenterBeat = p.currentBeat()
+	}
+
 	switch key {
+	case "~":
+		p.startHumanize()
+	case "*":
+		p.arpeggiate(false)
+	case "&":
+		p.arpeggiate(true)
+	case "Y":
+		p.zoomToFit()
+	case "ctrl+f":
+		s.Follow = !s.Follow
+	case "E":
+		p.toggleStepRecord()
+	case "F":
+		p.legato(false)
+	case "W":
+		p.legato(true)
+	case "X":
+		p.trimOverlaps()
+	case "I":
+		p.setLoopPoint(false)
+	case "O":
+		p.setLoopPoint(true)
+	case "C":
+		p.clearLoop()
+	case "J":
+		p.transposePattern(-1)
+	case "K":
+		p.transposePattern(1)
+	case "ctrl+d":
+		p.transposePattern(-transposeOctave)
+	case "ctrl+u":
+		p.transposePattern(transposeOctave)
+	case "A":
+		p.autoMode = true
+		p.autoBeat = max(0, min(s.CenterBeat, pat.Length-editH))
+		p.autoLane = clamp(p.autoLane, 0, max(0, len(pat.Automation)-1))
 	case "h", "left":
 		p.selectNoteByTime(-1)
 	case "l", "right":
@@ -708,21 +1287,9 @@ func (p *PianoRollDevice) HandleKey(key string) {
 			p.centerOnSelection()
 		}
 	case "u":
-		if s.SelectedNote >= 0 && s.SelectedNote < len(pat.Notes) {
-			n := &pat.Notes[s.SelectedNote]
-			if int(n.Pitch) >= editV {
-				n.Pitch -= uint8(editV)
-			}
-			p.centerOnSelection()
-		}
+		p.movePitch(-editV)
 	case "i":
-		if s.SelectedNote >= 0 && s.SelectedNote < len(pat.Notes) {
-			n := &pat.Notes[s.SelectedNote]
-			if int(n.Pitch)+editV <= 127 {
-				n.Pitch += uint8(editV)
-			}
-			p.centerOnSelection()
-		}
+		p.movePitch(editV)
 
 	case "n":
 		if s.SelectedNote >= 0 && s.SelectedNote < len(pat.Notes) {
@@ -739,6 +1306,27 @@ func (p *PianoRollDevice) HandleKey(key string) {
 			}
 		}
 
+	case "v":
+		p.nudgeVelocity(-velocityCoarseStep)
+	case "b":
+		p.nudgeVelocity(velocityCoarseStep)
+	case "V":
+		p.nudgeVelocity(-velocityFineStep)
+	case "B":
+		p.nudgeVelocity(velocityFineStep)
+	case "ctrl+o":
+		p.nudgeChannel(-1)
+	case "ctrl+p":
+		p.nudgeChannel(1)
+	case "%":
+		p.nudgeBend(-1)
+	case "^":
+		p.nudgeBend(1)
+	case "ctrl+v":
+		p.nudgePressure(-pressureEditStep)
+	case "ctrl+b":
+		p.nudgePressure(pressureEditStep)
+
 	case "q":
 		if s.ViewScale < len(ViewScales)-1 {
 			s.ViewScale++
@@ -773,8 +1361,8 @@ func (p *PianoRollDevice) HandleKey(key string) {
 		newNote := NoteEventState{
 			Start:    s.CenterBeat,
 			Duration: EditHorizSteps[s.EditHoriz] * 4,
-			Pitch:    uint8(s.CenterPitch),
-			Velocity: 100,
+			Pitch:    p.lockPitch(uint8(s.CenterPitch)),
+			Velocity: DefaultVelocity,
 		}
 		if newNote.Duration < 0.25 {
 			newNote.Duration = 0.25
@@ -795,18 +1383,63 @@ func (p *PianoRollDevice) HandleKey(key string) {
 		}
 
 	case "[":
-		if pat.Length > 1.0 {
-			pat.Length -= 1.0
-		}
+		p.nudgeLength(-1)
 	case "]":
-		if pat.Length < 64.0 {
-			pat.Length += 1.0
-		}
+		p.nudgeLength(1)
+	case ":":
+		p.nudgeLength(-pianoLengthStep)
+	case "\"":
+		p.nudgeLength(pianoLengthStep)
+	case "|":
+		p.cycleTimeSig()
+	case "#":
+		p.cycleGhost()
 
 	case "c":
+		autoBackup("clear")
 		pat.Notes = []NoteEventState{}
 		s.SelectedNote = -1
 
+	case "z":
+		p.quantize(false)
+	case "Z":
+		p.quantize(true)
+	case ";":
+		s.cycleQuantizeGrid()
+	case "'":
+		s.cycleQuantizeStrength()
+	case "{", "}":
+		dir := 1
+		if key == "{" {
+			dir = numScales() - 1
+		}
+		pat.Scale = ScaleType((int(pat.Scale) + dir) % numScales())
+	case "(":
+		pat.Root = (pat.Root + 11) % 12
+	case ")":
+		pat.Root = (pat.Root + 1) % 12
+	case "/":
+		s.cycleRecordQuantize()
+	case "\\":
+		s.RecordReplace = !s.RecordReplace
+
+	case "g":
+		p.copyNote()
+	case "G":
+		p.copyPattern()
+	case "t":
+		p.paste()
+	case "T":
+		p.duplicatePattern()
+		return
+
+	case "U":
+		p.Undo()
+		return
+	case "ctrl+r":
+		p.Redo()
+		return
+
 	case "<":
 		if s.Editing > 0 {
 			s.Editing--
@@ -826,7 +1459,7 @@ func (p *PianoRollDevice) HandleKey(key string) {
 		selectedNote = &n
 	}
 
-	sort.Slice(pat.Notes, func(i, j int) bool {
+	sort.SliceStable(pat.Notes, func(i, j int) bool {
 		return pat.Notes[i].Start < pat.Notes[j].Start
 	})
 
@@ -840,13 +1473,112 @@ func (p *PianoRollDevice) HandleKey(key string) {
 	}
 }
 
-func (p *PianoRollDevice) HandlePad(row, col int) {
+// quantize pulls the selected note (or every note when whole) toward the quantize grid
+func (p *PianoRollDevice) quantize(whole bool) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	grid := s.quantizeGrid().Beats
+	strength := s.quantizeStrength()
+
+	for i := range pat.Notes {
+		if !whole && i != s.SelectedNote {
+			continue
+		}
+		pat.Notes[i].Start = quantizeStart(pat.Notes[i].Start, grid, strength, pat.Length)
+	}
+}
+
+// movePitch moves the selected note by semitones; with scale lock, single steps
+// go to the next in-scale note
+func (p *PianoRollDevice) movePitch(semitones int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := &pat.Notes[s.SelectedNote]
+	target := int(n.Pitch) + semitones
+	if pat.Scale != ScaleChromatic && (semitones == 1 || semitones == -1) {
+		target = stepInScale(int(n.Pitch), semitones, pat.Root, pat.Scale)
+	}
+	if target >= 0 && target <= 127 {
+		n.Pitch = uint8(target)
+	}
+	p.centerOnSelection()
+}
+
+// lockPitch snaps a new note's pitch to the editing pattern's scale (unchanged when unlocked)
+func (p *PianoRollDevice) lockPitch(pitch uint8) uint8 {
+	pat := &p.state.Patterns[p.state.Editing]
+	if pat.Scale == ScaleChromatic {
+		return pitch
+	}
+	return uint8(snapToScale(int(pitch), pat.Root, pat.Scale))
+}
+
+// nudgeVelocity changes the selected note's velocity, clamped to 1-127
+func (p *PianoRollDevice) nudgeVelocity(delta int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := &pat.Notes[s.SelectedNote]
+	n.Velocity = uint8(clamp(int(n.Velocity)+delta, 1, 127))
+}
+
+// nudgeChannel steps the selected note's channel override (0 = track channel, wraps)
+func (p *PianoRollDevice) nudgeChannel(delta int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	if s.SelectedNote < 0 || s.SelectedNote >= len(pat.Notes) {
+		return
+	}
+	n := &pat.Notes[s.SelectedNote]
+	n.Channel = uint8((int(n.Channel) + delta + maxNoteChannel + 1) % (maxNoteChannel + 1))
+}
+
+// HandlePadRelease ends pad pressure on the note the pad placed
+func (p *PianoRollDevice) HandlePadRelease(row, col int) {
+	if p.padCell == [2]int{row, col} {
+		p.padNote = -1
+	}
+}
+
+// HandlePadPressure gives the note the held pad placed the pad's peak pressure
+// (played as aftertouch through the note - see noteExpression)
+func (p *PianoRollDevice) HandlePadPressure(row, col int, pressure uint8) {
+	pat := &p.state.Patterns[p.state.Editing]
+	if p.padNote < 0 || p.padNote >= len(pat.Notes) || p.padCell != [2]int{row, col} {
+		return
+	}
+	n := &pat.Notes[p.padNote]
+	if pressure <= n.Pressure {
+		return
+	}
+	if !p.padPressure {
+		// One undo step for the pad's pressure - later changes join it
+		p.padPressure = true
+		defer p.trackEdit()()
+	}
+	n.Pressure = pressure
+}
+
+func (p *PianoRollDevice) HandlePad(row, col int, velocity uint8) {
+	defer p.trackEdit()()
+
+	// Scene column - keyboard guide, scrolls the window an octave
+	if col == 8 {
+		p.scrollOctave(row >= 4)
+		return
+	}
+
 	s := p.state
 	pat := &s.Patterns[s.Editing]
 
 	basePitch := int(s.CenterPitch) - 4
 	viewScale := ViewScales[s.ViewScale]
-	startBeat := s.CenterBeat - 4*viewScale
+	startBeat := p.viewCenter() - 4*viewScale
 
 	pitch := uint8(basePitch + row)
 	beat := startBeat + float64(col)*viewScale
@@ -862,48 +1594,68 @@ func (p *PianoRollDevice) HandlePad(row, col int) {
 			if n.Start < beatEnd && noteEnd > beat {
 				s.SelectedNote = i
 				p.centerOnSelection()
+				p.audition()
 				return
 			}
 		}
 	}
 
+	// Pads with velocity (Programmer mode) set the note's velocity
+	if velocity == 0 {
+		velocity = DefaultVelocity
+	}
 	newNote := NoteEventState{
 		Start:    beat,
 		Duration: viewScale,
-		Pitch:    pitch,
-		Velocity: 100,
+		Pitch:    p.lockPitch(pitch),
+		Velocity: velocity,
 	}
 	if newNote.Duration < 0.25 {
 		newNote.Duration = 0.25
 	}
 	pat.Notes = append(pat.Notes, newNote)
 	s.SelectedNote = len(pat.Notes) - 1
+	p.padNote, p.padCell, p.padPressure = s.SelectedNote, [2]int{row, col}, false
 	p.centerOnSelection()
+	p.audition()
 }
 
-func (p *PianoRollDevice) renderLaunchpadHelp() string {
+// HelpLayout describes the piano roll Launchpad page; grid tooltips follow the current view
+func (p *PianoRollDevice) HelpLayout() widgets.LaunchpadLayout {
 	topRowColor := [3]uint8{111, 10, 126}
 	gridColor := [3]uint8{80, 200, 255}
-	sceneColor := [3]uint8{148, 18, 126}
 
-	var grid [8][8][3]uint8
-	var rightCol [8][3]uint8
-	topRow := make([][3]uint8, 8)
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	noteNames := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	basePitch := int(s.CenterPitch) - 4
+	viewScale := ViewScales[s.ViewScale]
+	startBeat := p.viewCenter() - 4*viewScale
+
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
 
 	for i := 0; i < 8; i++ {
-		topRow[i] = topRowColor
-		rightCol[i] = sceneColor
+		l.TopRow[i] = widgets.Pad{Color: topRowColor}
+		rightCol[i] = widgets.Pad{Color: pat.guideColor(basePitch + i), Tooltip: p.guideTooltip(i)}
 	}
 	for row := 0; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			grid[row][col] = gridColor
+			l.Grid[row][col] = widgets.Pad{Color: gridColor}
+			pitch := basePitch + row
+			beat := startBeat + float64(col)*viewScale
+			if beat < 0 || beat >= pat.Length || pitch < 0 || pitch > 127 {
+				continue
+			}
+			l.Grid[row][col].Tooltip = fmt.Sprintf("add/select %s%d at beat %.2f", noteNames[pitch%12], pitch/12, beat)
 		}
 	}
 
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, &rightCol) + "\n\n"
-	out += widgets.RenderLegendItem(gridColor, "Notes", "tap to add/select notes") + "\n"
-	out += widgets.RenderLegendItem(sceneColor, "Scene", "launch scenes")
-
-	return out
+	l.Legend = []widgets.LegendItem{
+		{Color: gridColor, Name: "Notes", Desc: "tap to add/select notes"},
+		{Color: guideRootColor, Name: "Root", Desc: "keyboard guide: pattern root (C when chromatic)"},
+		{Color: guideOctaveColor, Name: "Octave", Desc: "keyboard guide: C - tap upper/lower half to scroll an octave"},
+	}
+	return l
 }
@@ -0,0 +1,106 @@
+package sequencer
+
+import "testing"
+
+func TestPianoPatternEqual(t *testing.T) {
+	base := func() PianoPatternState {
+		return PianoPatternState{
+			Notes:      []NoteEventState{{Start: 0, Duration: 1, Pitch: 60, Velocity: 100}},
+			Length:     16,
+			Automation: []AutomationLane{{CC: 74}},
+		}
+	}
+	tests := []struct {
+		name  string
+		edit  func(*PianoPatternState)
+		equal bool
+	}{
+		{"unchanged", func(*PianoPatternState) {}, true},
+		{"note", func(p *PianoPatternState) { p.Notes[0].Pitch = 62 }, false},
+		{"length", func(p *PianoPatternState) { p.Length = 8 }, false},
+		{"time signature", func(p *PianoPatternState) { p.TimeSig = 1 }, false},
+		{"scale", func(p *PianoPatternState) { p.Scale = 1 }, false},
+		{"root", func(p *PianoPatternState) { p.Root = 2 }, false},
+		{"loop start", func(p *PianoPatternState) { p.LoopStart = 4 }, false},
+		{"loop end", func(p *PianoPatternState) { p.LoopEnd = 12 }, false},
+		{"automation", func(p *PianoPatternState) { p.Automation[0].CC = 1 }, false},
+	}
+	for _, tt := range tests {
+		a, b := base(), base()
+		tt.edit(&b)
+		if got := pianoPatternEqual(a, b); got != tt.equal {
+			t.Errorf("%s: equal = %v, want %v", tt.name, got, tt.equal)
+		}
+	}
+	if !pianoPatternEqual(PianoPatternState{}, PianoPatternState{Notes: []NoteEventState{}}) {
+		t.Error("no notes and an empty note list should be equal")
+	}
+}
+
+func TestPianoTrackEditRecordsEveryField(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name string
+		edit func(*PianoPatternState)
+	}{
+		{"scale", func(p *PianoPatternState) { p.Scale = 2 }},
+		{"root", func(p *PianoPatternState) { p.Root = 5 }},
+		{"loop", func(p *PianoPatternState) { p.LoopStart, p.LoopEnd = 4, 8 }},
+	}
+	for _, tt := range tests {
+		S = NewState()
+		p := NewPianoRollDevice(NewPianoState())
+		done := p.trackEdit()
+		tt.edit(&p.state.Patterns[p.state.Editing])
+		done()
+		if len(p.history.undo) != 1 || !S.Dirty {
+			t.Errorf("%s edit: %d undo steps, dirty %v, want 1 and true", tt.name, len(p.history.undo), S.Dirty)
+		}
+	}
+}
+
+func TestPadPressureUndo(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name      string
+		pressures []uint8
+		steps     int
+	}{
+		{"none", nil, 0},
+		{"one", []uint8{40}, 1},
+		{"a stream joins one step", []uint8{20, 40, 80, 60, 100}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			p := NewPianoRollDevice(NewPianoState())
+			pat := &p.state.Patterns[p.state.Editing]
+			pat.Notes = []NoteEventState{{Duration: 1, Pitch: 60, Velocity: 100}}
+			p.padNote, p.padCell = 0, [2]int{2, 3}
+
+			d := NewDrumDevice(NewDrumState())
+			d.holdStep, d.holdActive = 0, true
+			d.state.Patterns[0].Notes[0].Steps[0] = DrumStepState{Active: true, Velocity: 10}
+
+			for _, pr := range tt.pressures {
+				p.HandlePadPressure(2, 3, pr)
+				d.HandlePadPressure(7, 0, pr)
+			}
+			if len(p.history.undo) != tt.steps || len(d.history.undo) != tt.steps || S.Dirty != (tt.steps > 0) {
+				t.Fatalf("undo steps piano %d drum %d, dirty %v, want %d", len(p.history.undo), len(d.history.undo), S.Dirty, tt.steps)
+			}
+			p.Undo()
+			d.Undo()
+			if got := p.state.Patterns[p.state.Editing].Notes[0].Pressure; got != 0 {
+				t.Errorf("piano pressure after undo = %d, want 0", got)
+			}
+			if got := d.state.Patterns[0].Notes[0].Steps[0].Velocity; got != 10 {
+				t.Errorf("drum velocity after undo = %d, want 10", got)
+			}
+		})
+	}
+}
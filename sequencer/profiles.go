@@ -0,0 +1,76 @@
+package sequencer
+
+// OutputProfile shapes a track's MIDI for a MIDI-to-CV converter.
+// Converters turn notes into pitch CV + gate, and CCs into extra CV outs.
+type OutputProfile struct {
+	Name        string
+	Description string
+	Mono        bool // one voice: new notes steal the gate, note-offs for stolen notes are dropped
+	GateNote    int  // -1 = none; otherwise also send this fixed note as a separate gate/trigger
+	GateChannel int  // channel offset for GateNote (0 = track channel, 1 = next channel, ...)
+	VelocityCC  int  // -1 = none; otherwise send velocity as this CC just before each note
+	MPE         bool // one member channel per note (see mpe.go)
+	BendRange   int  // pitch bend range in semitones, for per-note bends (0 = 2)
+}
+
+// OutputProfiles contains all available output profiles
+var OutputProfiles = map[string]OutputProfile{
+	"midi": {
+		Name:        "MIDI",
+		Description: "plain MIDI notes",
+		GateNote:    -1,
+		VelocityCC:  -1,
+	},
+	"cvocd": {
+		Name:        "CV.OCD",
+		Description: "mono pitch/gate, velocity on CC 1",
+		Mono:        true,
+		GateNote:    -1,
+		VelocityCC:  1,
+	},
+	"fh2": {
+		Name:        "ES FH-2",
+		Description: "mono pitch/gate, trigger note 60 on next ch, velocity on CC 7",
+		Mono:        true,
+		GateNote:    60,
+		GateChannel: 1,
+		VelocityCC:  7,
+	},
+	"es9": {
+		Name:        "ES-9 / ES-8",
+		Description: "mono pitch/gate, velocity on CC 2",
+		Mono:        true,
+		GateNote:    -1,
+		VelocityCC:  2,
+	},
+	"trig": {
+		Name:        "Triggers",
+		Description: "poly gates (drums), velocity on CC 1",
+		GateNote:    -1,
+		VelocityCC:  1,
+	},
+	"mpe": {
+		Name:        "MPE",
+		Description: "MPE lower zone: track ch is master, a channel per note after it, per-note bend/pressure",
+		GateNote:    -1,
+		VelocityCC:  -1,
+		MPE:         true,
+		BendRange:   mpeBendRange,
+	},
+}
+
+// ProfileNames returns the list of available profile names
+func ProfileNames() []string {
+	return []string{"midi", "cvocd", "fh2", "es9", "trig", "mpe"}
+}
+
+// GetProfile returns a profile by name, defaulting to plain MIDI if not found
+func GetProfile(name string) OutputProfile {
+	if p, ok := OutputProfiles[name]; ok {
+		return p
+	}
+	return OutputProfiles[DefaultProfile]
+}
+
+// DefaultProfile is the default output profile name
+const DefaultProfile = "midi"
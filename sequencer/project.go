@@ -15,8 +15,18 @@ type SaveInfo struct {
 	Filename  string
 	Name      string // parsed from filename (empty if unnamed)
 	Timestamp time.Time
+	Thumbnail []string // session snapshot (nil for saves made before thumbnails)
 }
 
+// Thumbnail size - the first page of the session grid (patterns 1-8 x tracks 1-8)
+const ThumbnailRows = 8
+
+// Thumbnail cell characters
+const (
+	thumbFull  = 'x'
+	thumbEmpty = '.'
+)
+
 // ProjectsDir returns the projects directory path
 func ProjectsDir() (string, error) {
 	home, err := os.UserHomeDir()
@@ -79,6 +89,9 @@ func ListSaves(projectName string) ([]SaveInfo, error) {
 	var saves []SaveInfo
 	for _, entry := range entries {
 		if entry.IsDir() {
+			if entry.Name() == explodedDir {
+				saves = append(saves, explodedSave(dir))
+			}
 			continue
 		}
 		name := entry.Name()
@@ -111,6 +124,7 @@ func ListSaves(projectName string) ([]SaveInfo, error) {
 			Filename:  name,
 			Name:      saveName,
 			Timestamp: ts,
+			Thumbnail: readThumbnail(filepath.Join(dir, name)),
 		})
 	}
 
@@ -122,77 +136,153 @@ func ListSaves(projectName string) ([]SaveInfo, error) {
 	return saves, nil
 }
 
-// SaveProject saves current state to project with timestamp
-func SaveProject(projectName string) error {
-	if projectName == "" {
-		projectName = "untitled"
+// explodedSave lists a project's exploded tree, dated by its last write
+func explodedSave(dir string) SaveInfo {
+	path := filepath.Join(dir, explodedDir, "project.json")
+	save := SaveInfo{Filename: explodedDir, Name: explodedDir, Thumbnail: readThumbnail(path)}
+	if info, err := os.Stat(path); err == nil {
+		save.Timestamp = info.ModTime()
 	}
+	return save
+}
 
-	dir, err := ProjectDir(projectName)
+// readSaveFile loads a save (a file, or the exploded tree) into a fresh State
+func readSaveFile(dir, filename string) (*State, error) {
+	if filename == explodedDir {
+		return readExploded(filepath.Join(dir, explodedDir))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, filename))
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	// Create project directory if it doesn't exist
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return err
+	st := NewState()
+	if err := json.Unmarshal(data, st); err != nil {
+		return nil, err
 	}
+	return st, nil
+}
 
-	// Serialize state
-	data, err := json.MarshalIndent(S, "", "  ")
+// readThumbnail reads just the session thumbnail from a save file
+func readThumbnail(path string) []string {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		return err
+		return nil
+	}
+	var save struct {
+		Thumbnail []string `json:"thumbnail"`
+	}
+	if err := json.Unmarshal(data, &save); err != nil {
+		return nil
+	}
+	return save.Thumbnail
+}
+
+// SessionThumbnail snapshots which clips have content on the first page of the
+// session grid: one string per pattern row, one char per track
+func (m *Manager) SessionThumbnail() []string {
+	rows := make([]string, ThumbnailRows)
+	var masks [8][]bool
+	for i := 0; i < 8; i++ {
+		if dev := m.GetDevice(i); dev != nil {
+			masks[i] = dev.ContentMask()
+		}
+	}
+	for row := range rows {
+		line := make([]byte, 8)
+		for col := 0; col < 8; col++ {
+			line[col] = thumbEmpty
+			if row < len(masks[col]) && masks[col][row] {
+				line[col] = thumbFull
+			}
+		}
+		rows[row] = string(line)
+	}
+	return rows
+}
+
+// Save snapshots the session thumbnail and saves current state to the project
+func (m *Manager) Save(projectName string) error {
+	S.Thumbnail = m.SessionThumbnail()
+	return SaveProject(projectName)
+}
+
+// SaveProject saves current state to project with timestamp
+func SaveProject(projectName string) error {
+	if projectName == "" {
+		projectName = "untitled"
 	}
 
-	// Save with timestamp
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	timestampPath := filepath.Join(dir, timestamp+".json")
-	if err := os.WriteFile(timestampPath, data, 0644); err != nil {
+	// Save with timestamp (creates the project directory if needed), or rewrite the
+	// exploded tree
+	save := func() error { return writeSave(projectName, "") }
+	if S.Exploded {
+		save = func() error { return writeExploded(projectName) }
+	}
+	if err := save(); err != nil {
 		return err
 	}
 
 	// Update project name in runtime state
 	S.ProjectName = projectName
+	S.MarkSaved()
 
 	return nil
 }
 
-// LoadProject loads a specific save (or most recent if filename empty)
-func LoadProject(projectName, filename string) error {
-	dir, err := ProjectDir(projectName)
+// OpenProject loads a project save (latest if filename is empty) and rebuilds the
+// devices from it. The state and devices are swapped under m.mu - the output loop
+// reads them without the device lock.
+func (m *Manager) OpenProject(projectName, filename string) error {
+	newState, err := readProject(projectName, filename)
 	if err != nil {
 		return err
 	}
+	m.mu.Lock()
+	*S = *newState
+	m.recreateDevicesFromState()
+	m.mu.Unlock()
+	m.applyOverrides()
+	// Focus session after loading
+	m.SetFocused(m.session)
+	return nil
+}
+
+// readProject reads a specific save (or most recent if filename empty) into a new
+// state, ready to replace S
+func readProject(projectName, filename string) (*State, error) {
+	dir, err := ProjectDir(projectName)
+	if err != nil {
+		return nil, err
+	}
 
-	// If no filename specified, load most recent
+	// If no filename specified, load most recent (not counting backups)
 	if filename == "" {
 		saves, err := ListSaves(projectName)
-		if err != nil || len(saves) == 0 {
-			return fmt.Errorf("no saves found in project %s", projectName)
+		if err != nil {
+			return nil, fmt.Errorf("no saves found in project %s", projectName)
+		}
+		for _, save := range saves { // saves are sorted newest first
+			if !save.IsBackup() {
+				filename = save.Filename
+				break
+			}
+		}
+		if filename == "" {
+			return nil, fmt.Errorf("no saves found in project %s", projectName)
 		}
-		filename = saves[0].Filename // saves are sorted newest first
 	}
 
-	path := filepath.Join(dir, filename)
-	data, err := os.ReadFile(path)
+	// Read into a new state
+	newState, err := readSaveFile(dir, filename)
 	if err != nil {
-		return err
-	}
-
-	// Create a new state to load into
-	newState := NewState()
-	if err := json.Unmarshal(data, newState); err != nil {
-		return err
+		return nil, err
 	}
-
-	// Copy loaded state to global singleton
-	*S = *newState
-	S.ProjectName = projectName
+	newState.ProjectName = projectName
 
 	// Reset runtime-only fields
-	S.Playing = false
-	S.Tick = 0
-	for _, track := range S.Tracks {
+	newState.Playing = false
+	newState.Tick = 0
+	for _, track := range newState.Tracks {
 		if track.Drum != nil {
 			track.Drum.Step = 0
 			track.Drum.Recording = false
@@ -210,8 +300,9 @@ func LoadProject(projectName, filename string) error {
 			track.Metropolix.Validate()
 		}
 	}
+	newState.MarkSaved()
 
-	return nil
+	return newState, nil
 }
 
 // CreateProject creates a new empty project folder
@@ -230,6 +321,9 @@ func DeleteSave(projectName, filename string) error {
 		return err
 	}
 	path := filepath.Join(dir, filename)
+	if filename == explodedDir {
+		return os.RemoveAll(path)
+	}
 	return os.Remove(path)
 }
 
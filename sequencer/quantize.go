@@ -0,0 +1,122 @@
+package sequencer
+
+import "math"
+
+// Quantize - pulls piano note starts toward the nearest grid line. Strength is how
+// far of the way each note moves (100% = hard snap, lower keeps some feel)
+
+// QuantizeGrid is a grid notes can snap to
+type QuantizeGrid struct {
+	Name  string
+	Beats float64 // grid spacing in beats
+}
+
+// QuantizeGrids are the grids the piano roll cycles through
+var QuantizeGrids = []QuantizeGrid{
+	{Name: "1/8", Beats: 0.5},
+	{Name: "1/16", Beats: 0.25},
+	{Name: "1/16T", Beats: 1.0 / 6},
+	{Name: "1/32", Beats: 0.125},
+}
+
+// Quantize defaults
+const (
+	DefaultQuantizeGrid     = "1/16"
+	RecordQuantizeOff       = "off" // record with free timing
+	DefaultQuantizeStrength = 100
+	quantizeStrengthStep    = 10 // percent per key press
+)
+
+// quantizeGrid returns the selected quantize grid
+func (s *PianoState) quantizeGrid() QuantizeGrid {
+	for _, g := range QuantizeGrids {
+		if g.Name == s.QuantizeGrid {
+			return g
+		}
+	}
+	for _, g := range QuantizeGrids {
+		if g.Name == DefaultQuantizeGrid {
+			return g
+		}
+	}
+	return QuantizeGrids[0]
+}
+
+// quantizeStrength returns the quantize strength in percent
+func (s *PianoState) quantizeStrength() int {
+	if s.QuantizeStrength <= 0 {
+		return DefaultQuantizeStrength
+	}
+	return s.QuantizeStrength
+}
+
+// cycleQuantizeGrid steps to the next quantize grid
+func (s *PianoState) cycleQuantizeGrid() {
+	cur := s.quantizeGrid()
+	for i, g := range QuantizeGrids {
+		if g.Name == cur.Name {
+			s.QuantizeGrid = QuantizeGrids[(i+1)%len(QuantizeGrids)].Name
+			return
+		}
+	}
+}
+
+// cycleQuantizeStrength steps strength up by quantizeStrengthStep, wrapping back to the first step after 100%
+func (s *PianoState) cycleQuantizeStrength() {
+	next := s.quantizeStrength() + quantizeStrengthStep
+	if next > 100 {
+		next = quantizeStrengthStep
+	}
+	s.QuantizeStrength = next
+}
+
+// quantizeStart moves a start time toward the nearest grid line by strength percent,
+// keeping it inside a pattern of the given length
+func quantizeStart(start, grid float64, strength int, length float64) float64 {
+	target := math.Round(start/grid) * grid
+	for target >= length && target > 0 {
+		target -= grid // last grid line instead of wrapping past the end
+	}
+	return start + (target-start)*float64(strength)/100
+}
+
+// recordGrid returns the record quantize grid in beats (0 = off)
+func (s *PianoState) recordGrid() float64 {
+	if s.RecordQuantize == RecordQuantizeOff {
+		return 0
+	}
+	name := s.RecordQuantize
+	if name == "" {
+		name = DefaultQuantizeGrid
+	}
+	for _, g := range QuantizeGrids {
+		if g.Name == name {
+			return g.Beats
+		}
+	}
+	return 0.25
+}
+
+// recordQuantizeName returns the record quantize setting for display
+func (s *PianoState) recordQuantizeName() string {
+	if s.RecordQuantize == "" {
+		return DefaultQuantizeGrid
+	}
+	return s.RecordQuantize
+}
+
+// cycleRecordQuantize steps through off and each quantize grid
+func (s *PianoState) cycleRecordQuantize() {
+	options := []string{RecordQuantizeOff}
+	for _, g := range QuantizeGrids {
+		options = append(options, g.Name)
+	}
+	cur := s.recordQuantizeName()
+	for i, name := range options {
+		if name == cur {
+			s.RecordQuantize = options[(i+1)%len(options)]
+			return
+		}
+	}
+	s.RecordQuantize = ""
+}
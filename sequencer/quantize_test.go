@@ -0,0 +1,62 @@
+package sequencer
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantizeStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, grid float64
+		strength    int
+		length      float64
+		want        float64
+	}{
+		{"on the grid", 1, 0.25, 100, 4, 1},
+		{"rounds down", 1.1, 0.25, 100, 4, 1},
+		{"rounds up", 1.2, 0.25, 100, 4, 1.25},
+		{"half strength", 1.1, 0.25, 50, 4, 1.05},
+		{"no strength", 1.1, 0.25, 0, 4, 1.1},
+		{"eighths", 0.7, 0.5, 100, 4, 0.5},
+		{"triplets", 0.3, 1.0 / 6, 100, 4, 1.0 / 3},
+		{"last line instead of wrapping", 3.9, 0.25, 100, 4, 3.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := quantizeStart(tt.start, tt.grid, tt.strength, tt.length)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("quantizeStart(%v, %v, %d, %v) = %v, want %v", tt.start, tt.grid, tt.strength, tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuantizeSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    PianoState
+		grid     string
+		strength int
+		record   float64
+	}{
+		{"defaults", PianoState{}, "1/16", 100, 0.25},
+		{"unknown grid", PianoState{QuantizeGrid: "1/7"}, "1/16", 100, 0.25},
+		{"eighths at 60%", PianoState{QuantizeGrid: "1/8", QuantizeStrength: 60, RecordQuantize: "1/8"}, "1/8", 60, 0.5},
+		{"record off", PianoState{RecordQuantize: RecordQuantizeOff}, "1/16", 100, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.state
+			if g := s.quantizeGrid().Name; g != tt.grid {
+				t.Errorf("grid %s, want %s", g, tt.grid)
+			}
+			if st := s.quantizeStrength(); st != tt.strength {
+				t.Errorf("strength %d, want %d", st, tt.strength)
+			}
+			if r := s.recordGrid(); r != tt.record {
+				t.Errorf("record grid %v, want %v", r, tt.record)
+			}
+		})
+	}
+}
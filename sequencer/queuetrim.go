@@ -0,0 +1,105 @@
+package sequencer
+
+import (
+	"time"
+
+	"go-sequence/midi"
+)
+
+// Queue memory - device queues only lose events when the dispatch loop pops them, and
+// a queue rebuilt from its pattern data (every drum fill, piano roll and Metropolix
+// edits) started again from the pattern's start, bringing back events already sent.
+// Each queue now remembers how far dispatch has popped it and a rebuilt queue drops
+// what's behind that. The queue loop also keeps filling while the transport is
+// stopped (queued pattern switches still happen on time) with nothing popping, so
+// stopping re-anchors the clock at tick 0 alongside the cleared queues, and every fill
+// while stopped drops the events behind the playhead.
+
+// poppedMark is how far dispatch has popped a queue (guarded by the queue's lock,
+// the zero value is nothing popped)
+type poppedMark struct {
+	tick  int64 // tick of the last popped event
+	count int   // events popped at that tick
+}
+
+// pop records a popped event
+func (pm *poppedMark) pop(tick int64) {
+	if tick == pm.tick {
+		pm.count++
+		return
+	}
+	pm.tick, pm.count = tick, 1
+}
+
+// trim drops the events a rebuilt queue has in common with what was already popped -
+// everything before the last popped tick, and as many at that tick as were popped
+func (pm *poppedMark) trim(queue []midi.Event) []midi.Event {
+	n, atTick := 0, 0
+	for n < len(queue) {
+		e := queue[n]
+		if e.Tick > pm.tick || (e.Tick == pm.tick && atTick == pm.count) {
+			break
+		}
+		if e.Tick == pm.tick {
+			atTick++
+		}
+		n++
+	}
+	return queue[n:]
+}
+
+// dropBefore returns a right-sized copy of a queue without its events before tick
+// (the queue itself when there's nothing to drop)
+func dropBefore(queue []midi.Event, tick int64) []midi.Event {
+	n := 0
+	for n < len(queue) && queue[n].Tick < tick {
+		n++
+	}
+	if n == 0 {
+		return queue
+	}
+	return append([]midi.Event(nil), queue[n:]...)
+}
+
+// queueTrimmer is a device whose queue can let go of events behind the playhead
+type queueTrimmer interface {
+	trimQueue(before int64)
+}
+
+// trimQueues drops queued events behind tick on every device (while stopped -
+// playing, dispatch pops them)
+func (m *Manager) trimQueues(tick int64) {
+	for _, dev := range m.devices {
+		if qt, ok := dev.(queueTrimmer); ok {
+			qt.trimQueue(tick)
+		}
+	}
+	m.dispatch.invalidate()
+}
+
+// reanchorClock restarts a stopped transport's clock at tick 0, in step with the
+// cleared queues (hold m.mu)
+func (m *Manager) reanchorClock() {
+	S.T0 = time.Now()
+	S.Tick = 0
+}
+
+// --- Devices ---
+
+func (d *DrumDevice) trimQueue(before int64) {
+	d.queueMu.Lock()
+	d.queue = dropBefore(d.queue, before)
+	d.queueMu.Unlock()
+}
+
+func (p *PianoRollDevice) trimQueue(before int64) {
+	p.queueMu.Lock()
+	p.queue = dropBefore(p.queue, before)
+	p.queueMu.Unlock()
+}
+
+func (d *MetropolixDevice) trimQueue(before int64) {
+	d.queueMu.Lock()
+	d.queue = dropBefore(d.queue, before)
+	d.queueMu.Unlock()
+}
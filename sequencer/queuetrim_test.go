@@ -0,0 +1,58 @@
+package sequencer
+
+import (
+	"slices"
+	"testing"
+
+	"go-sequence/midi"
+)
+
+func TestPoppedMarkTrim(t *testing.T) {
+	queue := []midi.Event{{Tick: 0, Note: 1}, {Tick: 0, Note: 2}, {Tick: 10, Note: 3}, {Tick: 10, Note: 4}, {Tick: 20, Note: 5}}
+	tests := []struct {
+		name   string
+		popped []int64
+		want   []uint8
+	}{
+		{"nothing popped", nil, []uint8{1, 2, 3, 4, 5}},
+		{"one of a pair", []int64{0}, []uint8{2, 3, 4, 5}},
+		{"the whole first tick", []int64{0, 0}, []uint8{3, 4, 5}},
+		{"part of a later tick", []int64{0, 0, 10}, []uint8{4, 5}},
+		{"everything", []int64{0, 0, 10, 10, 20}, nil},
+		{"past the queue", []int64{30}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pm poppedMark
+			for _, tick := range tt.popped {
+				pm.pop(tick)
+			}
+			var got []uint8
+			for _, e := range pm.trim(slices.Clone(queue)) {
+				got = append(got, e.Note)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("trim kept %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDropBefore(t *testing.T) {
+	queue := []midi.Event{{Tick: 0}, {Tick: 10}, {Tick: 10}, {Tick: 20}}
+	tests := []struct {
+		tick int64
+		want int
+	}{
+		{0, 4},
+		{5, 3},
+		{10, 3},
+		{11, 1},
+		{30, 0},
+	}
+	for _, tt := range tests {
+		if got := dropBefore(queue, tt.tick); len(got) != tt.want {
+			t.Errorf("dropBefore(%d) kept %d events, want %d", tt.tick, len(got), tt.want)
+		}
+	}
+}
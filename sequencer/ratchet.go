@@ -0,0 +1,83 @@
+package sequencer
+
+import (
+	"math"
+
+	"go-sequence/midi"
+)
+
+// Ratchet probability and shape - each repeat after a stage's first hit plays with the
+// stage's ratchet probability (separate from the stage probability), and the repeats
+// can be spaced evenly, speed up (accel) or slow down (decel) across the stage.
+
+// Ratchet shapes
+const (
+	RatchetEven  = 0
+	RatchetAccel = 1 // repeats get closer together
+	RatchetDecel = 2 // repeats spread out
+)
+
+// Ratchets page sub-pages (navigated with the top row up/down arrows)
+const (
+	RatchetSubCount = 0
+	RatchetSubProb  = 1
+	RatchetSubShape = 2
+)
+
+var ratchetShapeNames = []string{"even", "accel", "decel"}
+
+// ratchetProbability returns the chance (percent) each repeat plays
+func (st *MetropolixStageState) ratchetProbability() int {
+	if st.RatchetProb == 0 {
+		return 100
+	}
+	return st.RatchetProb
+}
+
+// ratchetOffsets returns each ratchet's start within a stage of stageTicks, following the shape
+func ratchetOffsets(ratchets int, shape int, stageTicks int64) []int64 {
+	offsets := make([]int64, ratchets)
+	for r := range offsets {
+		x := float64(r) / float64(ratchets)
+		switch shape {
+		case RatchetAccel:
+			x = 1 - (1-x)*(1-x)
+		case RatchetDecel:
+			x = x * x
+		}
+		offsets[r] = int64(math.Round(x * float64(stageTicks)))
+	}
+	return offsets
+}
+
+// ratchetProbRow maps a ratchet probability to a pad row (row 0 = 10%, row 7 = 100%)
+func ratchetProbRow(prob int) int { return clamp((prob-10)*7/90, 0, 7) }
+func ratchetRowProb(row int) int  { return 10 + row*90/7 }
+
+// renderRatchetShapePage shows the shape per stage on the bottom 3 rows
+func (d *MetropolixDevice) renderRatchetShapePage() []LEDState {
+	pat := &d.state.Patterns[d.state.Editing]
+	shapeColors := [][3]uint8{
+		{0, 255, 0},   // Even = green
+		{255, 200, 0}, // Accel = yellow
+		{0, 150, 255}, // Decel = blue
+	}
+	offColor := [3]uint8{0, 0, 0}
+
+	var leds []LEDState
+	for col := 0; col < 8; col++ {
+		shape := pat.Stages[col].RatchetShape
+		for row := 0; row < 8; row++ {
+			color := offColor
+			if col < pat.Length && row < len(shapeColors) {
+				c := shapeColors[row]
+				if row != shape {
+					c = [3]uint8{c[0] / 5, c[1] / 5, c[2] / 5}
+				}
+				color = c
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
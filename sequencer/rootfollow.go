@@ -0,0 +1,83 @@
+package sequencer
+
+import (
+	"fmt"
+
+	"go-sequence/midi"
+)
+
+// Root follow - notes from the note-input keyboard become the Metropolix's root (a live
+// transpose of the playing pattern), switching in at the next stage or the next cycle
+// so key changes land in time. The shift is kept until reset, across pattern changes.
+
+// Root follow modes
+const (
+	RootFollowOff     = 0
+	RootFollowStage   = 1 // at the next stage start
+	RootFollowPattern = 2 // at the next cycle start
+)
+
+var rootFollowNames = []string{"off", "stage", "cycle"}
+
+// maxRootShift bounds the live transpose (semitones either way)
+const maxRootShift = 48
+
+// followRoot schedules a new root from a played note
+func (d *MetropolixDevice) followRoot(event midi.Event) {
+	s := d.state
+	if s.RootFollow == RootFollowOff || event.Type != midi.NoteOn || event.Velocity == 0 {
+		return
+	}
+	pat := &s.Patterns[s.Pattern]
+	shift := clamp(int(event.Note)-int(pat.RootNote), -maxRootShift, maxRootShift)
+
+	if !S.Playing {
+		s.RootShift = shift
+		d.rootPending = false
+		return
+	}
+
+	at := S.Tick
+	if s.RootFollow == RootFollowPattern {
+		d.queueMu.RLock()
+		start := d.patternStartTick
+		d.queueMu.RUnlock()
+		if cycle := d.fauxPatternTicks(s.Pattern); cycle > 0 && at > start {
+			at = start + ((at-start)/cycle+1)*cycle
+		}
+	}
+	d.pendingRoot = shift
+	d.pendingRootTick = at
+	d.rootPending = true
+	d.regeneratePatternInQueue(s.Pattern)
+}
+
+// commitRoot switches to the pending root once generation reaches its tick (call at stage starts)
+func (d *MetropolixDevice) commitRoot(tick int64) {
+	if d.rootPending && tick >= d.pendingRootTick {
+		d.state.RootShift = d.pendingRoot
+		d.rootPending = false
+	}
+}
+
+// cycleRootFollow steps through off / stage / cycle
+func (d *MetropolixDevice) cycleRootFollow() {
+	d.state.RootFollow = (d.state.RootFollow + 1) % len(rootFollowNames)
+}
+
+// resetRoot drops the live transpose
+func (d *MetropolixDevice) resetRoot() {
+	d.state.RootShift = 0
+	d.rootPending = false
+	d.regeneratePatternInQueue(d.state.Pattern)
+}
+
+// rootFollowInfo describes the follow mode and current shift for the view
+func (d *MetropolixDevice) rootFollowInfo() string {
+	s := d.state
+	info := fmt.Sprintf("%s (%+d)", rootFollowNames[s.RootFollow], s.RootShift)
+	if d.rootPending {
+		info += fmt.Sprintf(" → %+d", d.pendingRoot)
+	}
+	return info
+}
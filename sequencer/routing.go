@@ -0,0 +1,496 @@
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// Routing matrix - one view (`ctrl+t`) of where notes come from and where they go: each
+// track's input (the keyboard while the track is focused, or another track's output
+// for resampling), its output port and channel, its keyboard zone (see keyzones.go),
+// plus the port the keyboard is read from. Settings' Output / Rec from cells and Note
+// Input row open it at that spot.
+
+// routingKeyboardRow is the cursor row of the keyboard port line (above the tracks)
+const routingKeyboardRow = -1
+
+// Matrix columns: input sources (keys, T1-T8), then outputs (default, ports...)
+const (
+	routingKeysCol    = 0
+	routingDefaultCol = 9
+)
+
+// Launchpad pages (top row pads 0/1 switch)
+const (
+	routingPageInputs = iota
+	routingPageOutputs
+)
+
+// RoutingDevice edits the inputs → tracks → outputs matrix
+type RoutingDevice struct {
+	manager *Manager
+
+	cursorRow int // routingKeyboardRow or track 0-7
+	cursorCol int // matrix column (keyboard row: input port, 0 = none)
+	padPage   int // routingPageInputs / routingPageOutputs
+}
+
+// NewRoutingDevice creates a routing matrix device
+func NewRoutingDevice(manager *Manager) *RoutingDevice {
+	return &RoutingDevice{manager: manager}
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (r *RoutingDevice) FillUntil(tick int64)                           {}
+func (r *RoutingDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (r *RoutingDevice) PopNextEvent() *midi.Event                      { return nil }
+func (r *RoutingDevice) ClearQueue()                                    {}
+func (r *RoutingDevice) QueuePattern(p int, atTick int64)               {}
+func (r *RoutingDevice) CurrentPattern() int                            { return 0 }
+func (r *RoutingDevice) NextPattern() int                               { return -1 }
+func (r *RoutingDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (r *RoutingDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (r *RoutingDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (r *RoutingDevice) HandleMIDI(event midi.Event)                    {}
+func (r *RoutingDevice) ToggleRecording()                               {}
+func (r *RoutingDevice) TogglePreview()                                 {}
+func (r *RoutingDevice) IsRecording() bool                              { return false }
+func (r *RoutingDevice) IsPreviewing() bool                             { return false }
+func (r *RoutingDevice) HandlePadRelease(row, col int)                  {}
+func (r *RoutingDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+// ports returns the scanned MIDI ports (cached by settings on rescan)
+func (r *RoutingDevice) ports() (inputs, outputs []string) {
+	if s := r.manager.GetSettings(); s != nil {
+		return s.midiInputs, s.midiOutputs
+	}
+	return nil, nil
+}
+
+// lastCol returns the rightmost column of a cursor row
+func (r *RoutingDevice) lastCol(row int) int {
+	inputs, outputs := r.ports()
+	if row == routingKeyboardRow {
+		return len(inputs)
+	}
+	return routingDefaultCol + len(outputs)
+}
+
+// FocusTrack puts the cursor on a track's current input (outputs = false) or output
+// (outputs = true); track routingKeyboardRow selects the keyboard port
+func (r *RoutingDevice) FocusTrack(track int, outputs bool) {
+	r.cursorRow = track
+	switch {
+	case track == routingKeyboardRow:
+		r.cursorCol = r.keyboardCol()
+	case outputs:
+		r.cursorCol = r.outputCol(track)
+		r.padPage = routingPageOutputs
+	default:
+		r.cursorCol = S.Tracks[track].RecordFrom
+		r.padPage = routingPageInputs
+	}
+}
+
+// keyboardCol returns the keyboard row column of the note input port (0 = none)
+func (r *RoutingDevice) keyboardCol() int {
+	inputs, _ := r.ports()
+	for i, port := range inputs {
+		if port == S.NoteInputPort {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// outputCol returns the matrix column of a track's output port (the default column
+// when it's unset or not among the scanned ports)
+func (r *RoutingDevice) outputCol(track int) int {
+	_, outputs := r.ports()
+	for i, port := range outputs {
+		if port == S.Tracks[track].PortName {
+			return routingDefaultCol + 1 + i
+		}
+	}
+	return routingDefaultCol
+}
+
+// connected reports whether a matrix cell is the track's current route
+func (r *RoutingDevice) connected(track, col int) bool {
+	if col < routingDefaultCol {
+		return S.Tracks[track].RecordFrom == col
+	}
+	return r.outputCol(track) == col
+}
+
+// connect routes a track through a matrix cell: an input column sets where it records
+// from, an output column where it plays to
+func (r *RoutingDevice) connect(track, col int) {
+	ts := S.Tracks[track]
+	_, outputs := r.ports()
+	switch {
+	case col < routingDefaultCol:
+		if col == track+1 {
+			r.manager.announce("track %d can't record its own output", track+1)
+			return
+		}
+		ts.RecordFrom = col
+		r.manager.announce("track %d input: %s", track+1, routingInputName(col))
+	case col == routingDefaultCol:
+		ts.PortName = ""
+		r.manager.announce("track %d output: default", track+1)
+	case col-routingDefaultCol-1 < len(outputs):
+		ts.PortName = outputs[col-routingDefaultCol-1]
+		r.manager.announce("track %d output: %s", track+1, ts.PortName)
+	}
+}
+
+// setKeyboardPort picks the port notes are read from (0 = none) and asks the TUI to
+// reconnect (via the settings flag it already checks)
+func (r *RoutingDevice) setKeyboardPort(col int) {
+	inputs, _ := r.ports()
+	port := ""
+	if col > 0 && col <= len(inputs) {
+		port = inputs[col-1]
+	}
+	S.NoteInputPort = port
+	if s := r.manager.GetSettings(); s != nil {
+		s.NoteInputChanged = true
+	}
+	if port == "" {
+		port = "(none)"
+	}
+	r.manager.announce("keyboard input: %s", port)
+}
+
+// routingInputName names an input column ("keys", "T3 out")
+func routingInputName(col int) string {
+	if col == routingKeysCol {
+		return "keys"
+	}
+	return fmt.Sprintf("T%d out", col)
+}
+
+func (r *RoutingDevice) View() string {
+	var out strings.Builder
+	inputs, outputs := r.ports()
+
+	out.WriteString("ROUTING  Inputs → Tracks → Outputs\n\n")
+
+	// Keyboard port line
+	out.WriteString("Keyboard in: ")
+	for col := 0; col <= len(inputs); col++ {
+		name := "(none)"
+		if col > 0 {
+			name = truncateName(inputs[col-1], 20)
+			if curve := S.InputCurve(inputs[col-1]); curve != CurveLinear {
+				name += " (" + curve.Name() + ")"
+			}
+		}
+		if col == r.keyboardCol() {
+			name = "●" + name
+		}
+		if r.cursorRow == routingKeyboardRow && r.cursorCol == col {
+			out.WriteString(fmt.Sprintf("[%s] ", name))
+		} else {
+			out.WriteString(fmt.Sprintf(" %s  ", name))
+		}
+	}
+	if len(inputs) == 0 {
+		out.WriteString(" (no inputs found)")
+	}
+	out.WriteString("\n\n")
+
+	// Matrix header
+	out.WriteString("                ─── in (records from) ──────────────────     ─── out\n")
+	out.WriteString("Track           keys")
+	for i := 1; i <= 8; i++ {
+		out.WriteString(fmt.Sprintf("  T%d", i))
+	}
+	out.WriteString("  │  def")
+	for i := range outputs {
+		out.WriteString(fmt.Sprintf(" %3s", fmt.Sprintf("O%d", i+1)))
+	}
+	out.WriteString("   Ch  S/S  Zone\n")
+	out.WriteString(strings.Repeat("─", 60+4*len(outputs)+5+23) + "\n")
+
+	// Track rows
+	for track := 0; track < 8; track++ {
+		ts := S.Tracks[track]
+		name := ts.Name
+		if name == "" {
+			name = string(ts.Type)
+		}
+		if name == "" {
+			name = "-"
+		}
+		out.WriteString(fmt.Sprintf("  %d %-12s", track+1, truncateName(name, 12)))
+		for col := 0; col <= routingDefaultCol+len(outputs); col++ {
+			if col == routingDefaultCol {
+				out.WriteString("  │")
+			}
+			glyph := "·"
+			switch {
+			case col == track+1:
+				glyph = " "
+			case r.connected(track, col):
+				glyph = "●"
+			}
+			if r.cursorRow == track && r.cursorCol == col {
+				out.WriteString(fmt.Sprintf(" [%s]", glyph))
+			} else {
+				out.WriteString(fmt.Sprintf("  %s ", glyph))
+			}
+		}
+		zone := "-"
+		if ts.Zone != nil {
+			zone = ts.Zone.String()
+		}
+		startStop := "·"
+		if ts.StartStop {
+			startStop = "▶"
+		}
+		out.WriteString(fmt.Sprintf("  %2d   %s   %s\n", ts.Channel, startStop, zone))
+	}
+
+	// Output port legend (default resolves to the first port found at startup)
+	out.WriteString("\nOutputs\n")
+	def := r.manager.defaultPort
+	if def == "" {
+		def = "(none)"
+	}
+	out.WriteString(fmt.Sprintf("  def  %s\n", def))
+	for i, port := range outputs {
+		out.WriteString(fmt.Sprintf("  O%-3d %s\n", i+1, port))
+	}
+	if len(outputs) == 0 {
+		out.WriteString("  No MIDI outputs found\n")
+	}
+
+	// Thru routes (from the config - see thru.go)
+	if routes := r.manager.ThruRoutes(); len(routes) > 0 {
+		out.WriteString("\nThru\n")
+		for _, route := range routes {
+			out.WriteString("  " + route.label() + "\n")
+		}
+	}
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "hjkl", Desc: "move (k from track 1: keyboard port)"},
+			{Key: "space", Desc: "connect (one input and one output per track)"},
+			{Key: "x", Desc: "reset track to keys / default output"},
+			{Key: "[ / ]", Desc: "output channel -/+"},
+			{Key: "t", Desc: "send MIDI Start/Stop to the track's port on launches (S/S)"},
+			{Key: "z", Desc: "keyboard zone on/off (split the keys across tracks)"},
+			{Key: "{ / }", Desc: "zone low note -/+"},
+			{Key: "( / )", Desc: "zone high note -/+"},
+			{Key: "c / C", Desc: "zone input channel (any, 1-16)"},
+			{Key: "v / V", Desc: "zone min velocity -/+ (keyboard line: the port's velocity curve)"},
+			{Key: "r", Desc: "rescan MIDI devices"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(r.HelpLayout()))
+	return out.String()
+}
+
+func (r *RoutingDevice) HandleKey(key string) {
+	switch key {
+	case "h", "left":
+		if r.cursorCol > 0 {
+			r.cursorCol--
+		}
+	case "l", "right":
+		if r.cursorCol < r.lastCol(r.cursorRow) {
+			r.cursorCol++
+		}
+	case "k", "up":
+		if r.cursorRow > routingKeyboardRow {
+			r.cursorRow--
+			r.cursorCol = min(r.cursorCol, r.lastCol(r.cursorRow))
+		}
+	case "j", "down":
+		if r.cursorRow < 7 {
+			r.cursorRow++
+			r.cursorCol = min(r.cursorCol, r.lastCol(r.cursorRow))
+		}
+	case " ", "enter":
+		if r.cursorRow == routingKeyboardRow {
+			r.setKeyboardPort(r.cursorCol)
+			return
+		}
+		r.connect(r.cursorRow, r.cursorCol)
+	case "x":
+		if r.cursorRow == routingKeyboardRow {
+			r.setKeyboardPort(0)
+			return
+		}
+		r.connect(r.cursorRow, routingKeysCol)
+		r.connect(r.cursorRow, routingDefaultCol)
+	case "[":
+		r.nudgeChannel(-1)
+	case "]":
+		r.nudgeChannel(1)
+	case "t":
+		if r.cursorRow != routingKeyboardRow {
+			r.manager.ToggleStartStop(r.cursorRow)
+		}
+	case "z":
+		r.toggleZone()
+	case "{":
+		r.nudgeZone(-1, 0, 0, 0)
+	case "}":
+		r.nudgeZone(1, 0, 0, 0)
+	case "(":
+		r.nudgeZone(0, -1, 0, 0)
+	case ")":
+		r.nudgeZone(0, 1, 0, 0)
+	case "c":
+		r.nudgeZone(0, 0, 1, 0)
+	case "C":
+		r.nudgeZone(0, 0, -1, 0)
+	case "v", "V":
+		delta := 1
+		if key == "v" {
+			delta = -1
+		}
+		if r.cursorRow == routingKeyboardRow {
+			r.cycleCurve(delta)
+			return
+		}
+		r.nudgeZone(0, 0, 0, delta*zoneVelocityStep)
+	}
+
+	// Launchpad follows the half of the matrix the cursor is in
+	if r.cursorRow != routingKeyboardRow {
+		r.padPage = routingPageInputs
+		if r.cursorCol >= routingDefaultCol {
+			r.padPage = routingPageOutputs
+		}
+	}
+}
+
+// nudgeChannel steps the cursor track's output channel (1-16)
+func (r *RoutingDevice) nudgeChannel(delta int) {
+	if r.cursorRow == routingKeyboardRow {
+		return
+	}
+	ts := S.Tracks[r.cursorRow]
+	ts.Channel = uint8(clamp(int(ts.Channel)+delta, 1, 16))
+}
+
+// padCol maps a Launchpad column to a matrix column on the current page: inputs are
+// T1-T8 (pressing the lit source goes back to keys), outputs are default then the
+// first seven ports
+func (r *RoutingDevice) padCol(col int) int {
+	if r.padPage == routingPageOutputs {
+		return routingDefaultCol + col
+	}
+	return col + 1
+}
+
+func (r *RoutingDevice) HandlePad(row, col int, velocity uint8) {
+	if row == 8 {
+		switch col {
+		case 0:
+			r.padPage = routingPageInputs
+		case 1:
+			r.padPage = routingPageOutputs
+		}
+		return
+	}
+	if row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	track := 7 - row
+	target := r.padCol(col)
+	if r.padPage == routingPageInputs && r.connected(track, target) {
+		target = routingKeysCol
+	}
+	r.cursorRow, r.cursorCol = track, target
+	r.connect(track, target)
+}
+
+// Routing pad colors
+var (
+	routingOnColor     = [3]uint8{0, 255, 120}
+	routingOffColor    = [3]uint8{20, 40, 30}
+	routingPageColor   = [3]uint8{255, 200, 0}
+	routingUnusedColor = [3]uint8{0, 0, 0}
+)
+
+// padColor returns the color of a grid pad on the current page
+func (r *RoutingDevice) padColor(track, col int) [3]uint8 {
+	target := r.padCol(col)
+	_, outputs := r.ports()
+	switch {
+	case r.padPage == routingPageInputs && target == track+1:
+		return routingUnusedColor
+	case r.padPage == routingPageOutputs && target > routingDefaultCol+len(outputs):
+		return routingUnusedColor
+	case r.connected(track, target):
+		return routingOnColor
+	}
+	return routingOffColor
+}
+
+func (r *RoutingDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: row, Col: col, Color: r.padColor(7-row, col), Channel: midi.ChannelStatic})
+		}
+	}
+	for col := 0; col < 8; col++ {
+		color := routingUnusedColor
+		if col < 2 {
+			color = routingOffColor
+			if col == r.padPage {
+				color = routingPageColor
+			}
+		}
+		leds = append(leds, LEDState{Row: 8, Col: col, Color: color, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+// HelpLayout shows the current page: rows are tracks (T1 at top), columns sources or
+// outputs
+func (r *RoutingDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	l.TopRow[0] = widgets.Pad{Color: routingOffColor, Tooltip: "inputs page (record from T1-T8)"}
+	l.TopRow[1] = widgets.Pad{Color: routingOffColor, Tooltip: "outputs page (default, O1-O7)"}
+	l.TopRow[r.padPage].Color = routingPageColor
+	for i := 2; i < 8; i++ {
+		l.TopRow[i] = widgets.Pad{Color: routingUnusedColor}
+	}
+
+	for row := 0; row < 8; row++ {
+		track := 7 - row
+		for col := 0; col < 8; col++ {
+			target := r.padCol(col)
+			tip := fmt.Sprintf("T%d records from T%d out", track+1, target)
+			if r.padPage == routingPageOutputs {
+				tip = fmt.Sprintf("T%d plays to O%d", track+1, target-routingDefaultCol)
+				if target == routingDefaultCol {
+					tip = fmt.Sprintf("T%d plays to the default output", track+1)
+				}
+			}
+			l.Grid[row][col] = widgets.Pad{Color: r.padColor(track, col), Tooltip: tip}
+		}
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: routingOnColor, Name: "Routed", Desc: "track's current input/output (press a lit input for keys)"},
+		{Color: routingOffColor, Name: "Free", Desc: "press to route"},
+		{Color: routingPageColor, Name: "Page", Desc: "top row: inputs / outputs"},
+	}
+	return l
+}
@@ -39,6 +39,11 @@ type SaveDevice struct {
 	confirmMode   bool
 	confirmMsg    string
 	confirmAction func()
+
+	// Save comparison (see savediff.go)
+	compareFile string   // save marked to compare against ("" = none)
+	compare     SaveDiff // marked save vs the selected one
+	compareErr  error    // reading either save failed
 }
 
 // NewSaveDevice creates a save device
@@ -80,6 +85,52 @@ func (s *SaveDevice) Refresh() {
 	if s.saveIdx >= len(s.saves) {
 		s.saveIdx = max(0, len(s.saves)-1)
 	}
+
+	// Drop a comparison mark that isn't in this project's saves
+	if s.markedSave() < 0 {
+		s.compareFile = ""
+	}
+	s.updateCompare()
+}
+
+// markedSave is the index of the save marked for comparison (-1 = none)
+func (s *SaveDevice) markedSave() int {
+	for i, save := range s.saves {
+		if save.Filename == s.compareFile {
+			return i
+		}
+	}
+	return -1
+}
+
+// toggleCompare marks the selected save to compare against (again clears the mark)
+func (s *SaveDevice) toggleCompare() {
+	if s.column != 1 || len(s.saves) == 0 {
+		return
+	}
+	if s.saves[s.saveIdx].Filename == s.compareFile {
+		s.compareFile = ""
+	} else {
+		s.compareFile = s.saves[s.saveIdx].Filename
+	}
+	s.updateCompare()
+}
+
+// updateCompare diffs the marked save against the selected one
+func (s *SaveDevice) updateCompare() {
+	if s.compareFile == "" || s.saveIdx >= len(s.saves) {
+		return
+	}
+	s.compare, s.compareErr = DiffSaves(s.projects[s.projectIdx], s.compareFile, s.saves[s.saveIdx].Filename)
+}
+
+// saveLabel is a save's timestamp and name as listed
+func saveLabel(save SaveInfo) string {
+	display := save.Timestamp.Format("01-02 15:04")
+	if save.Name != "" {
+		display += " " + save.Name
+	}
+	return display
 }
 
 // Device interface implementation - queue-based (stubs for non-music device)
@@ -92,6 +143,8 @@ func (s *SaveDevice) QueuePattern(p int, atTick int64) {}
 func (s *SaveDevice) CurrentPattern() int            { return 0 }
 func (s *SaveDevice) NextPattern() int               { return -1 }
 func (s *SaveDevice) ContentMask() []bool            { return make([]bool, NumPatterns) }
+func (s *SaveDevice) Density() []float64             { return make([]float64, NumPatterns) }
+func (s *SaveDevice) PatternBars() []float64            { return make([]float64, NumPatterns) }
 
 func (s *SaveDevice) HandleMIDI(event midi.Event) {}
 
@@ -108,7 +161,11 @@ func (s *SaveDevice) View() string {
 	if S.ProjectName != "" {
 		projectName = S.ProjectName
 	}
-	out.WriteString(fmt.Sprintf("SAVE  Project: %s\n\n", projectName))
+	format := "timestamped"
+	if S.Exploded {
+		format = "exploded (exploded/ folder, for version control)"
+	}
+	out.WriteString(fmt.Sprintf("SAVE  Project: %s  Format: %s\n\n", projectName, format))
 
 	// Confirmation dialog takes over
 	if s.confirmMode {
@@ -172,6 +229,9 @@ func (s *SaveDevice) View() string {
 		// Saves column
 		if row < len(s.saves) {
 			prefix := "  "
+			if s.saves[row].Filename == s.compareFile {
+				prefix = "= "
+			}
 			if row == s.saveIdx {
 				if s.column == 1 {
 					prefix = "> "
@@ -180,11 +240,7 @@ func (s *SaveDevice) View() string {
 				}
 			}
 			// Format: timestamp + name if present
-			save := s.saves[row]
-			display := save.Timestamp.Format("01-02 15:04")
-			if save.Name != "" {
-				display += " " + save.Name
-			}
+			display := saveLabel(s.saves[row])
 			if len(display) > 24 {
 				display = display[:21] + "..."
 			}
@@ -198,6 +254,22 @@ func (s *SaveDevice) View() string {
 		out.WriteString("  (no projects yet)\n")
 	}
 
+	// Thumbnail of the selected save's session grid
+	if s.saveIdx < len(s.saves) {
+		out.WriteString("\n")
+		out.WriteString(renderThumbnail(s.saves[s.saveIdx].Thumbnail))
+	}
+
+	// Comparison of the marked save against the selected one
+	if marked := s.markedSave(); marked >= 0 && s.saveIdx < len(s.saves) {
+		out.WriteString(fmt.Sprintf("\nCompare %s → %s\n", saveLabel(s.saves[marked]), saveLabel(s.saves[s.saveIdx])))
+		if s.compareErr != nil {
+			out.WriteString(fmt.Sprintf("  can't compare: %v\n", s.compareErr))
+		} else {
+			out.WriteString(renderSaveDiff(s.compare))
+		}
+	}
+
 	// Key help
 	out.WriteString("\n")
 	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
@@ -208,12 +280,14 @@ func (s *SaveDevice) View() string {
 			{Key: "n", Desc: "new project"},
 			{Key: "r", Desc: "rename project"},
 			{Key: "d", Desc: "delete"},
+			{Key: "c", Desc: "mark save to compare (again: clear)"},
+			{Key: "e", Desc: "save format: timestamped / exploded (one file per track and pattern)"},
 		}},
 	}))
 
 	// Launchpad
 	out.WriteString("\n\n")
-	out.WriteString(s.renderLaunchpadHelp())
+	out.WriteString(widgets.RenderLayout(s.HelpLayout()))
 
 	return out.String()
 }
@@ -324,6 +398,7 @@ func (s *SaveDevice) HandleKey(key string) {
 		} else {
 			if s.saveIdx < len(s.saves)-1 {
 				s.saveIdx++
+				s.updateCompare()
 			}
 		}
 	case "k", "up":
@@ -335,6 +410,7 @@ func (s *SaveDevice) HandleKey(key string) {
 		} else {
 			if s.saveIdx > 0 {
 				s.saveIdx--
+				s.updateCompare()
 			}
 		}
 	case "enter", " ":
@@ -354,6 +430,17 @@ func (s *SaveDevice) HandleKey(key string) {
 		}
 	case "d":
 		s.deleteSelected()
+	case "c":
+		s.toggleCompare()
+	case "e":
+		S.Exploded = !S.Exploded
+		if S.Exploded {
+			s.manager.announce("saves rewrite the exploded folder")
+		} else {
+			s.manager.announce("saves are timestamped files")
+		}
+	case "esc":
+		s.compareFile = ""
 	}
 }
 
@@ -396,12 +483,10 @@ func (s *SaveDevice) loadSelected() {
 		filename = s.saves[s.saveIdx].Filename
 	}
 
-	if err := LoadProject(projectName, filename); err != nil {
+	autoBackup("load")
+	if err := s.manager.OpenProject(projectName, filename); err != nil {
 		return // TODO: show error
 	}
-
-	// Recreate devices from loaded state
-	s.manager.recreateDevicesFromState()
 }
 
 func (s *SaveDevice) deleteSelected() {
@@ -433,7 +518,10 @@ func (s *SaveDevice) deleteSelected() {
 	}
 }
 
-func (s *SaveDevice) HandlePad(row, col int) {
+func (s *SaveDevice) HandlePadRelease(row, col int) {}
+func (s *SaveDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (s *SaveDevice) HandlePad(row, col int, velocity uint8) {
 	// Left half: select project
 	if col < 4 {
 		idx := (7-row)*4 + col
@@ -448,50 +536,71 @@ func (s *SaveDevice) HandlePad(row, col int) {
 		if idx < len(s.saves) {
 			s.saveIdx = idx
 			s.column = 1
+			s.updateCompare()
 		}
 	}
 }
 
-func (s *SaveDevice) renderLaunchpadHelp() string {
+// renderThumbnail draws a save's session snapshot as a mini clip grid
+func renderThumbnail(thumb []string) string {
+	if len(thumb) == 0 {
+		return "Session: (no thumbnail - saved before thumbnails existed)\n"
+	}
+	var out strings.Builder
+	out.WriteString("Session    T1T2T3T4T5T6T7T8\n")
+	for row, line := range thumb {
+		out.WriteString(fmt.Sprintf("  Pat %2d:  ", row+1))
+		for _, c := range line {
+			if c == thumbFull {
+				out.WriteString("■ ")
+			} else {
+				out.WriteString("· ")
+			}
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// HelpLayout describes the save browser Launchpad page (first entries at top-left)
+func (s *SaveDevice) HelpLayout() widgets.LaunchpadLayout {
 	projectColor := [3]uint8{100, 200, 100}
 	saveColor := [3]uint8{100, 100, 200}
 	dimColor := [3]uint8{30, 30, 30}
 
-	var grid [8][8][3]uint8
-	topRow := make([][3]uint8, 8)
+	var l widgets.LaunchpadLayout
 
 	for i := 0; i < 8; i++ {
-		topRow[i] = dimColor
+		l.TopRow[i] = widgets.Pad{Color: dimColor}
 	}
 
-	// Left half: projects
+	// Left half: projects, right half: saves (same order as HandlePad)
 	for row := 0; row < 8; row++ {
-		for col := 0; col < 4; col++ {
-			idx := row*4 + col
-			if idx < len(s.projects) {
-				grid[row][col] = projectColor
+		for col := 0; col < 8; col++ {
+			pad := widgets.Pad{Color: dimColor}
+			if col < 4 {
+				idx := (7-row)*4 + col
+				if idx < len(s.projects) {
+					pad = widgets.Pad{Color: projectColor, Tooltip: "project " + s.projects[idx]}
+				}
 			} else {
-				grid[row][col] = dimColor
+				idx := (7-row)*4 + (col - 4)
+				if idx < len(s.saves) {
+					save := s.saves[idx]
+					tooltip := "save " + save.Timestamp.Format("01-02 15:04")
+					if save.Name != "" {
+						tooltip += " " + save.Name
+					}
+					pad = widgets.Pad{Color: saveColor, Tooltip: tooltip}
+				}
 			}
+			l.Grid[row][col] = pad
 		}
 	}
 
-	// Right half: saves
-	for row := 0; row < 8; row++ {
-		for col := 4; col < 8; col++ {
-			idx := row*4 + (col - 4)
-			if idx < len(s.saves) {
-				grid[row][col] = saveColor
-			} else {
-				grid[row][col] = dimColor
-			}
-		}
+	l.Legend = []widgets.LegendItem{
+		{Color: projectColor, Name: "Projects", Desc: "select project"},
+		{Color: saveColor, Name: "Saves", Desc: "select save"},
 	}
-
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, nil) + "\n\n"
-	out += widgets.RenderLegendItem(projectColor, "Projects", "select project") + "\n"
-	out += widgets.RenderLegendItem(saveColor, "Saves", "select save")
-
-	return out
+	return l
 }
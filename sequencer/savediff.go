@@ -0,0 +1,143 @@
+package sequencer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Save comparison - the save device can mark one save (`c`) and compare it against
+// the selected one before loading: which tracks changed device or settings, and which
+// of their pattern slots have different content. Step through the saves with the mark
+// held to find the one where a part was still right.
+
+// TrackDiff is how one track differs between two saves
+type TrackDiff struct {
+	Track    int
+	FromType DeviceType
+	ToType   DeviceType
+	Settings bool  // channel, port, mute, transpose, ... changed
+	Patterns []int // pattern slots with different content or clip labels
+}
+
+// SaveDiff compares two saves of a project
+type SaveDiff struct {
+	FromTempo, ToTempo int
+	Tracks             []TrackDiff // only tracks that differ
+}
+
+// Empty reports whether the saves hold the same project
+func (d SaveDiff) Empty() bool {
+	return d.FromTempo == d.ToTempo && len(d.Tracks) == 0
+}
+
+// readSave loads a save into a fresh State (the running project is untouched)
+func readSave(projectName, filename string) (*State, error) {
+	dir, err := ProjectDir(projectName)
+	if err != nil {
+		return nil, err
+	}
+	return readSaveFile(dir, filename)
+}
+
+// DiffSaves compares two saves of a project track by track
+func DiffSaves(projectName, from, to string) (SaveDiff, error) {
+	a, err := readSave(projectName, from)
+	if err != nil {
+		return SaveDiff{}, err
+	}
+	b, err := readSave(projectName, to)
+	if err != nil {
+		return SaveDiff{}, err
+	}
+
+	diff := SaveDiff{FromTempo: a.Tempo, ToTempo: b.Tempo}
+	for i := range a.Tracks {
+		ta, tb := a.Tracks[i], b.Tracks[i]
+		td := TrackDiff{Track: i, FromType: ta.Type, ToType: tb.Type}
+		td.Settings = !bytes.Equal(trackSettingsJSON(ta), trackSettingsJSON(tb))
+		pa, pb := patternsJSON(ta), patternsJSON(tb)
+		for p := 0; p < NumPatterns; p++ {
+			if ta.Clips[p] != tb.Clips[p] || !bytes.Equal(pa[p], pb[p]) {
+				td.Patterns = append(td.Patterns, p)
+			}
+		}
+		if td.FromType != td.ToType || td.Settings || len(td.Patterns) > 0 {
+			diff.Tracks = append(diff.Tracks, td)
+		}
+	}
+	return diff, nil
+}
+
+// trackSettingsJSON serializes a track without its device state or clip labels
+func trackSettingsJSON(ts *TrackState) []byte {
+	t := *ts
+	t.Drum, t.Piano, t.Metropolix = nil, nil, nil
+	t.Clips = nil
+	data, _ := json.Marshal(&t)
+	return data
+}
+
+// patternsJSON serializes each pattern slot of a track's device (nil for no device)
+func patternsJSON(ts *TrackState) [NumPatterns][]byte {
+	var out [NumPatterns][]byte
+	for p := 0; p < NumPatterns; p++ {
+		switch {
+		case ts.Drum != nil:
+			out[p], _ = json.Marshal(&ts.Drum.Patterns[p])
+		case ts.Piano != nil:
+			out[p], _ = json.Marshal(&ts.Piano.Patterns[p])
+		case ts.Metropolix != nil:
+			out[p], _ = json.Marshal(&ts.Metropolix.Patterns[p])
+		}
+	}
+	return out
+}
+
+// renderSaveDiff lists what differs between two saves
+func renderSaveDiff(diff SaveDiff) string {
+	if diff.Empty() {
+		return "  no differences\n"
+	}
+	var out strings.Builder
+	if diff.FromTempo != diff.ToTempo {
+		out.WriteString(fmt.Sprintf("  tempo %d → %d\n", diff.FromTempo, diff.ToTempo))
+	}
+	for _, td := range diff.Tracks {
+		var parts []string
+		if td.FromType != td.ToType {
+			parts = append(parts, fmt.Sprintf("device %s → %s", typeName(td.FromType), typeName(td.ToType)))
+		}
+		if td.Settings {
+			parts = append(parts, "settings")
+		}
+		if len(td.Patterns) > 0 {
+			parts = append(parts, "patterns "+patternList(td.Patterns))
+		}
+		out.WriteString(fmt.Sprintf("  T%d %-10s %s\n", td.Track+1, typeName(td.ToType), strings.Join(parts, ", ")))
+	}
+	return out.String()
+}
+
+// typeName names a device type for the comparison ("empty" for none)
+func typeName(t DeviceType) string {
+	if t == DeviceTypeNone {
+		return "empty"
+	}
+	return string(t)
+}
+
+// patternList shows 1-based pattern numbers, cut short past a dozen
+func patternList(patterns []int) string {
+	const shown = 12
+	var nums []string
+	for i, p := range patterns {
+		if i == shown {
+			nums = append(nums, fmt.Sprintf("+%d more", len(patterns)-shown))
+			break
+		}
+		nums = append(nums, fmt.Sprint(p+1))
+	}
+	return strings.Join(nums, " ")
+}
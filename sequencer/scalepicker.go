@@ -0,0 +1,67 @@
+package sequencer
+
+import "go-sequence/midi"
+
+// Scale picker - the Settings page's second sub-page lays every scale out on the grid
+// (built-ins first, then the project's user scales), reading left to right from the
+// top row, so any scale is one press away (past 64, `q` still reaches the rest). The
+// main Settings page keeps its four quick scale pads on row 6.
+
+// Settings sub-pages (navigated with the top row up/down arrows)
+const (
+	SettingsSubMain   = 0
+	SettingsSubScales = 1
+)
+
+// scalePadIndex returns the scale on a picker pad (row 7 is the first row of eight)
+func scalePadIndex(row, col int) int {
+	return (7-row)*8 + col
+}
+
+// renderScalePickerPage lights every selectable scale, the pattern's brightest
+func (d *MetropolixDevice) renderScalePickerPage() []LEDState {
+	pat := &d.state.Patterns[d.state.Editing]
+
+	activeColor := [3]uint8{255, 100, 50}
+	builtinColor := [3]uint8{50, 30, 20}
+	userColor := [3]uint8{0, 60, 50}
+	offColor := [3]uint8{0, 0, 0}
+
+	var leds []LEDState
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			i := scalePadIndex(row, col)
+			color := offColor
+			switch {
+			case i >= numScales():
+			case i == int(pat.Scale):
+				color = activeColor
+			case i >= int(ScaleCount):
+				color = userColor
+			default:
+				color = builtinColor
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+// handleScalePickerPad selects the scale under a picker pad
+func (d *MetropolixDevice) handleScalePickerPad(row, col int) {
+	i := scalePadIndex(row, col)
+	if i >= numScales() {
+		return
+	}
+	d.state.Patterns[d.state.Editing].Scale = ScaleType(i)
+	d.regeneratePatternInQueue(d.state.Editing)
+}
+
+// scalePickerTooltip names the scale under a picker pad
+func scalePickerTooltip(row, col int) string {
+	i := scalePadIndex(row, col)
+	if i >= numScales() {
+		return ""
+	}
+	return "scale " + scaleName(ScaleType(i))
+}
@@ -0,0 +1,236 @@
+package sequencer
+
+// Scene rows - a scene is one pattern row across all eight tracks. Duplicating a
+// scene copies the row into a new row below it, inserting opens a blank row, and
+// deleting closes the row up; either way every later pattern on every track moves
+// with its name, color and undo history, and what's playing keeps playing. Inserts
+// need the last row empty so nothing falls off the end.
+
+// rowShift is a scene row insert or delete
+type rowShift struct {
+	row    int
+	insert bool // false = delete
+}
+
+// move returns where a pattern lands after the shift; ok is false when its data is
+// gone (the deleted row), in which case the returned slot holds what replaced it
+func (rs rowShift) move(pattern int) (int, bool) {
+	switch {
+	case pattern < rs.row:
+		return pattern, true
+	case rs.insert:
+		return min(pattern+1, NumPatterns-1), pattern+1 < NumPatterns
+	case pattern == rs.row:
+		return pattern, false
+	}
+	return pattern - 1, true
+}
+
+// shiftPatterns applies a row shift to a pattern array, filling the opened slot
+// (insert) or the last slot (delete)
+func shiftPatterns[T any](pats *[NumPatterns]T, rs rowShift, fill T) {
+	if rs.insert {
+		copy(pats[rs.row+1:], pats[rs.row:NumPatterns-1])
+		pats[rs.row] = fill
+		return
+	}
+	copy(pats[rs.row:], pats[rs.row+1:])
+	pats[NumPatterns-1] = fill
+}
+
+// remap moves snapshots along with their patterns, dropping those whose pattern is gone
+func (h *undoHistory[T]) remap(rs rowShift) {
+	h.undo = remapEntries(h.undo, rs)
+	h.redo = remapEntries(h.redo, rs)
+}
+
+func remapEntries[T any](stack []undoEntry[T], rs rowShift) []undoEntry[T] {
+	var out []undoEntry[T]
+	for _, e := range stack {
+		if p, ok := rs.move(e.pattern); ok {
+			e.pattern = p
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// sceneDevice is a device whose patterns follow scene row changes
+type sceneDevice interface {
+	clipDevice
+	shiftRows(rs rowShift, fill any) // fill nil = blank pattern
+}
+
+// DuplicateScene copies a scene row into a new row below it (false if there's no room)
+func (m *Manager) DuplicateScene(row int) bool {
+	if row >= NumPatterns-1 {
+		m.announce("scene %d is the last row - nowhere to duplicate it", NumPatterns)
+		return false
+	}
+	if !m.canInsertScene() {
+		return false
+	}
+	for i := 0; i < 8; i++ {
+		if dev, ok := m.GetDevice(i).(sceneDevice); ok {
+			dev.shiftRows(rowShift{row: row + 1, insert: true}, dev.clipData(row))
+		}
+		S.Tracks[i].Clips.shift(rowShift{row: row + 1, insert: true}, S.Tracks[i].Clips[row])
+	}
+	m.shiftSceneRefs(rowShift{row: row + 1, insert: true})
+	m.announce("scene %d duplicated to %d", row+1, row+2)
+	return true
+}
+
+// InsertScene opens a blank scene row, moving it and every later row down one (false
+// if there's no room)
+func (m *Manager) InsertScene(row int) bool {
+	if !m.canInsertScene() {
+		return false
+	}
+	m.shiftScenes(rowShift{row: row, insert: true})
+	m.announce("blank scene inserted at %d", row+1)
+	return true
+}
+
+// DeleteScene removes a scene row on every track, moving later rows up one
+func (m *Manager) DeleteScene(row int) {
+	m.shiftScenes(rowShift{row: row})
+	m.announce("scene %d deleted", row+1)
+}
+
+// canInsertScene reports whether the last scene row is free to drop off the end
+func (m *Manager) canInsertScene() bool {
+	for i := 0; i < 8; i++ {
+		if dev := m.GetDevice(i); dev != nil && dev.ContentMask()[NumPatterns-1] {
+			m.announce("scene %d isn't empty - clear it to make room", NumPatterns)
+			return false
+		}
+	}
+	return true
+}
+
+// shiftScenes applies a blank insert or a delete to every track
+func (m *Manager) shiftScenes(rs rowShift) {
+	for i := 0; i < 8; i++ {
+		if dev, ok := m.GetDevice(i).(sceneDevice); ok {
+			dev.shiftRows(rs, nil)
+		}
+		S.Tracks[i].Clips.shift(rs, ClipLabel{})
+	}
+	m.shiftSceneRefs(rs)
+}
+
+// shiftSceneRefs moves the capture take's slot with its row (dropping a take whose
+// slot was deleted)
+func (m *Manager) shiftSceneRefs(rs rowShift) {
+	m.captureMu.Lock()
+	defer m.captureMu.Unlock()
+	if m.capture == nil {
+		return
+	}
+	if p, ok := rs.move(m.capture.pattern); ok {
+		m.capture.pattern = p
+	} else {
+		m.capture = nil
+		m.announce("capture cancelled (its scene was deleted)")
+	}
+}
+
+// shiftSessionRefs moves the group launch selection and a copied clip's source slot
+// with their rows; a cut whose source was deleted becomes a plain copy
+func (s *SessionDevice) shiftSessionRefs(rs rowShift) {
+	for i, p := range s.selected {
+		if p >= 0 {
+			s.selected[i], _ = rs.move(p)
+		}
+	}
+	if s.clip == nil {
+		return
+	}
+	p, ok := rs.move(s.clip.pattern)
+	s.clip.pattern = p
+	if !ok {
+		s.clip.move = false
+	}
+}
+
+// confirmDeleteScene asks before deleting the cursor's scene row (not undoable)
+func (s *SessionDevice) confirmDeleteScene() {
+	s.confirmDelete = true
+}
+
+// handleDeleteKey deletes the scene on y, cancels on anything else
+func (s *SessionDevice) handleDeleteKey(key string) {
+	s.confirmDelete = false
+	if key != "y" {
+		s.manager.announce("delete cancelled")
+		return
+	}
+	autoBackup("scene-delete")
+	s.manager.DeleteScene(s.cursorRow)
+	s.shiftSessionRefs(rowShift{row: s.cursorRow})
+}
+
+// --- Drum ---
+
+func (d *DrumDevice) shiftRows(rs rowShift, fill any) {
+	pat, ok := fill.(DrumPatternState)
+	if !ok {
+		pat = NewDrumState().Patterns[0]
+	}
+	s := d.state
+	shiftPatterns(&s.Patterns, rs, pat)
+	shiftPatterns(&d.patternDirty, rs, true)
+	d.history.remap(rs)
+
+	s.Next, _ = rs.move(s.Next)
+	s.EditingPatternIdx, _ = rs.move(s.EditingPatternIdx)
+	for i, p := range d.schedule.Patterns {
+		var kept bool
+		if d.schedule.Patterns[i], kept = rs.move(p); !kept {
+			d.patternDirty[d.schedule.Patterns[i]] = true
+		}
+	}
+	s.PlayingPatternIdx, _ = rs.move(s.PlayingPatternIdx)
+	d.syncQueueToSchedule()
+}
+
+// --- Piano roll ---
+
+func (p *PianoRollDevice) shiftRows(rs rowShift, fill any) {
+	pat, ok := fill.(PianoPatternState)
+	if !ok {
+		pat = NewPianoState().Patterns[0]
+	}
+	s := p.state
+	shiftPatterns(&s.Patterns, rs, pat)
+	p.history.remap(rs)
+
+	var kept bool
+	s.Next, _ = rs.move(s.Next)
+	if s.Editing, kept = rs.move(s.Editing); !kept {
+		s.SelectedNote = -1
+	}
+	if s.Pattern, kept = rs.move(s.Pattern); !kept {
+		p.regeneratePatternInQueue(s.Pattern)
+	}
+}
+
+// --- Metropolix ---
+
+func (d *MetropolixDevice) shiftRows(rs rowShift, fill any) {
+	pat, ok := fill.(MetropolixPatternState)
+	if !ok {
+		pat = NewMetropolixState().Patterns[0]
+	}
+	s := d.state
+	shiftPatterns(&s.Patterns, rs, pat)
+	d.history.remap(rs)
+
+	var kept bool
+	s.Next, _ = rs.move(s.Next)
+	s.Editing, _ = rs.move(s.Editing)
+	if s.Pattern, kept = rs.move(s.Pattern); !kept {
+		d.regeneratePatternInQueue(s.Pattern)
+	}
+}
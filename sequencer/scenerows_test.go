@@ -0,0 +1,60 @@
+package sequencer
+
+import "testing"
+
+func TestRowShiftMove(t *testing.T) {
+	tests := []struct {
+		name    string
+		rs      rowShift
+		pattern int
+		want    int
+		kept    bool
+	}{
+		{"insert above", rowShift{row: 5, insert: true}, 4, 4, true},
+		{"insert at", rowShift{row: 5, insert: true}, 5, 6, true},
+		{"insert below", rowShift{row: 5, insert: true}, 9, 10, true},
+		{"insert pushes last off", rowShift{row: 5, insert: true}, NumPatterns - 1, NumPatterns - 1, false},
+		{"delete above", rowShift{row: 5}, 4, 4, true},
+		{"delete at", rowShift{row: 5}, 5, 5, false},
+		{"delete below", rowShift{row: 5}, 9, 8, true},
+	}
+	for _, tt := range tests {
+		got, kept := tt.rs.move(tt.pattern)
+		if got != tt.want || kept != tt.kept {
+			t.Errorf("%s: move(%d) = %d, %v, want %d, %v", tt.name, tt.pattern, got, kept, tt.want, tt.kept)
+		}
+	}
+}
+
+func TestShiftPatterns(t *testing.T) {
+	tests := []struct {
+		name string
+		rs   rowShift
+		want map[int]int // slot -> value afterwards (value = original slot, -1 = fill)
+	}{
+		{"insert first", rowShift{row: 0, insert: true}, map[int]int{0: -1, 1: 0, NumPatterns - 1: NumPatterns - 2}},
+		{"insert middle", rowShift{row: 3, insert: true}, map[int]int{2: 2, 3: -1, 4: 3}},
+		{"insert last", rowShift{row: NumPatterns - 1, insert: true}, map[int]int{NumPatterns - 2: NumPatterns - 2, NumPatterns - 1: -1}},
+		{"delete first", rowShift{row: 0}, map[int]int{0: 1, NumPatterns - 2: NumPatterns - 1, NumPatterns - 1: -1}},
+		{"delete last", rowShift{row: NumPatterns - 1}, map[int]int{NumPatterns - 2: NumPatterns - 2, NumPatterns - 1: -1}},
+	}
+	for _, tt := range tests {
+		var pats [NumPatterns]int
+		for i := range pats {
+			pats[i] = i
+		}
+		shiftPatterns(&pats, tt.rs, -1)
+		for slot, want := range tt.want {
+			if pats[slot] != want {
+				t.Errorf("%s: slot %d = %d, want %d", tt.name, slot, pats[slot], want)
+			}
+		}
+	}
+}
+
+func TestDuplicateLastScene(t *testing.T) {
+	m := NewManager()
+	if m.DuplicateScene(NumPatterns - 1) {
+		t.Fatal("duplicating the last scene row should fail")
+	}
+}
@@ -0,0 +1,88 @@
+package sequencer
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Seeds - the Metropolix's random outcomes (stage probability, random mode) come from
+// a per-track seed. Seed 0 is free-running: fresh outcomes every cycle. A non-zero
+// seed locks them, so each cycle repeats the same variation until it's re-rolled.
+// A re-roll takes effect at the next bar so performers can audition variations in time.
+
+// newSeed picks a random non-zero seed
+func newSeed() int64 {
+	for {
+		if seed := rand.Int63(); seed != 0 {
+			return seed
+		}
+	}
+}
+
+// seedHash mixes a seed with position parts into a well-distributed value (splitmix64)
+func seedHash(seed int64, parts ...int64) uint64 {
+	h := uint64(seed)
+	for _, p := range parts {
+		h ^= uint64(p) + 0x9e3779b97f4a7c15 + (h << 6) + (h >> 2)
+	}
+	h += 0x9e3779b97f4a7c15
+	h = (h ^ (h >> 30)) * 0xbf58476d1ce4e5b9
+	h = (h ^ (h >> 27)) * 0x94d049bb133111eb
+	return h ^ (h >> 31)
+}
+
+// seedName formats a seed for display
+func seedName(seed int64) string {
+	if seed == 0 {
+		return "free"
+	}
+	return fmt.Sprintf("%04X", uint16(seed))
+}
+
+// Reroll picks a new seed, switching over at the next bar (immediately when stopped)
+func (d *MetropolixDevice) Reroll() {
+	d.setSeedAtNextBar(newSeed())
+}
+
+// FreeSeed returns to free-running randomness at the next bar
+func (d *MetropolixDevice) FreeSeed() {
+	d.setSeedAtNextBar(0)
+}
+
+func (d *MetropolixDevice) setSeedAtNextBar(seed int64) {
+	barTicks := int64(4 * PPQ)
+	at := S.Tick
+	if S.Playing {
+		at = (S.Tick/barTicks + 1) * barTicks
+	}
+	d.pendingSeed = seed
+	d.pendingSeedTick = at
+	d.seedPending = true
+	d.regeneratePatternInQueue(d.state.Pattern)
+}
+
+// seedAt returns the seed in effect at a tick (the pending seed once its bar arrives)
+func (d *MetropolixDevice) seedAt(tick int64) int64 {
+	if d.seedPending && tick >= d.pendingSeedTick {
+		return d.pendingSeed
+	}
+	return d.state.Seed
+}
+
+// commitSeed makes the pending seed current once the queue has been filled past its bar
+func (d *MetropolixDevice) commitSeed(queuedUntil int64) {
+	if d.seedPending && queuedUntil > d.pendingSeedTick {
+		d.state.Seed = d.pendingSeed
+		d.seedPending = false
+	}
+}
+
+// randIntn returns a value in [0, n) for an event at tick, offset ticks into its cycle.
+// Locked seeds key the outcome on the cycle position so every cycle repeats it.
+func (d *MetropolixDevice) randIntn(n int, tick, offset int64, salt int) int {
+	seed := d.seedAt(tick)
+	if seed == 0 {
+		return rand.Intn(n)
+	}
+	return int(seedHash(seed, offset, int64(salt)) % uint64(n))
+}
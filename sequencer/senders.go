@@ -0,0 +1,116 @@
+package sequencer
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/rtpmidi"
+)
+
+// Output senders - ports are opened on first use and cached. A send that fails (the
+// device was unplugged, the driver dropped it) invalidates the cached sender and the
+// port is reopened once and the message retried. A port that keeps failing is left
+// alone for senderRetryDelay between attempts rather than searched for on every note,
+// and the status line says when an output is lost and when it comes back.
+
+// senderRetryDelay is how long a failed port is left before it's tried again
+const senderRetryDelay = time.Second
+
+var errPortNotFound = errors.New("port not found")
+
+// openSender finds and opens an output port, caching its sender (nil if it can't be
+// opened)
+func (m *Manager) openSender(portName string) func(gomidi.Message) error {
+	m.sendersMu.Lock()
+	if sender, ok := m.senders[portName]; ok {
+		m.sendersMu.Unlock()
+		return sender
+	}
+	send, err := dialPort(portName)
+	if err != nil {
+		_, failedBefore := m.portFailures[portName]
+		m.portFailures[portName] = time.Now()
+		m.sendersMu.Unlock()
+		if !failedBefore {
+			m.notifyPort("MIDI output %s unavailable: %v", portName, err)
+		}
+		return nil
+	}
+	_, failedBefore := m.portFailures[portName]
+	sender := m.guardSender(portName, m.monitorSender(portName, send), failedBefore)
+	m.senders[portName] = sender
+	m.sendersMu.Unlock()
+	return sender
+}
+
+// SetSender installs a sender for a port name in place of opening a port (a stand-in
+// output for tools like cmd/racecheck)
+func (m *Manager) SetSender(portName string, send func(gomidi.Message) error) {
+	m.sendersMu.Lock()
+	defer m.sendersMu.Unlock()
+	m.senders[portName] = m.monitorSender(portName, send)
+}
+
+// dialPort opens an output port by name (network sessions included)
+func dialPort(portName string) (func(gomidi.Message) error, error) {
+	if session := rtpmidi.Lookup(portName); session != nil {
+		return func(msg gomidi.Message) error { return session.Send(msg) }, nil
+	}
+	for _, port := range gomidi.GetOutPorts() {
+		if port.String() == portName {
+			return gomidi.SendTo(port)
+		}
+	}
+	return nil, errPortNotFound
+}
+
+// guardSender wraps a port's sender so a failed send reopens the port and retries.
+// A sender opened after a failure clears it on its first good send.
+func (m *Manager) guardSender(portName string, send func(gomidi.Message) error, recovering bool) func(gomidi.Message) error {
+	var pending atomic.Bool
+	pending.Store(recovering)
+	return func(msg gomidi.Message) error {
+		err := send(msg)
+		if err == nil {
+			if pending.CompareAndSwap(true, false) {
+				m.senderRecovered(portName)
+			}
+			return nil
+		}
+		retry := m.senderFailed(portName, err)
+		if retry == nil {
+			return err
+		}
+		return retry(msg)
+	}
+}
+
+// senderFailed drops a port's cached sender after a send error and reopens it, unless
+// the port already failed within senderRetryDelay (nil then - the message is dropped)
+func (m *Manager) senderFailed(portName string, err error) func(gomidi.Message) error {
+	m.sendersMu.Lock()
+	delete(m.senders, portName)
+	failed, failedBefore := m.portFailures[portName]
+	m.portFailures[portName] = time.Now()
+	off := m.midiOff
+	m.sendersMu.Unlock()
+
+	if !failedBefore {
+		m.notifyPort("MIDI output %s lost (%v) - reconnecting", portName, err)
+	}
+	if off || (failedBefore && time.Since(failed) < senderRetryDelay) {
+		return nil
+	}
+	return m.openSender(portName)
+}
+
+// senderRecovered clears a port's failure once it is sending again
+func (m *Manager) senderRecovered(portName string) {
+	m.sendersMu.Lock()
+	delete(m.portFailures, portName)
+	m.sendersMu.Unlock()
+	m.notifyPort("MIDI output %s reconnected", portName)
+}
@@ -0,0 +1,48 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+
+	"go-sequence/midi"
+)
+
+// TestFlushToLostPort ends notes on a port that can't be opened with m.mu held - the
+// sender's status message must not wait on m.mu
+func TestFlushToLostPort(t *testing.T) {
+	tests := []struct {
+		name  string
+		flush func(m *Manager)
+	}{
+		{"stop", func(m *Manager) { m.stop() }},
+		{"mute", func(m *Manager) { m.ToggleMute(0) }},
+		{"solo", func(m *Manager) { m.ToggleSolo(1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			saved := S
+			S = NewState()
+			defer func() { S = saved }()
+			S.Playing = true
+			S.T0 = time.Now()
+
+			m := NewManager()
+			m.SetDevice(0, NewEmptyDevice(1))
+			m.trackNote(0, "unplugged synth", 0, &midi.Event{Type: midi.NoteOn, Note: 60, Velocity: 100})
+
+			done := make(chan struct{})
+			go func() {
+				tt.flush(m)
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(2 * time.Second):
+				t.Fatal("flushing a note on a lost port hung")
+			}
+			if status := m.TakeStatus(); status == "" {
+				t.Error("no status message for the lost port")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package sequencer
 
 import (
 	"fmt"
+	"strings"
 
 	"go-sequence/midi"
 	"go-sequence/widgets"
@@ -15,16 +16,136 @@ type SessionDevice struct {
 	cursorCol  int // track
 	viewRows   int // how many rows to show (default 8)
 	viewOffset int // scroll offset
+	heatMap    bool // show pattern density instead of content dots
+	trackRow   int  // what the top row pads do per track (stop/mute/solo), or page arrows
+
+	// Clip copy
+	clip        *sessionClip // copied clip waiting to be pasted
+	holdTrack   int          // clip pad being held (-1 = none)
+	holdPattern int
+	holdCopied  bool         // the held clip was pasted somewhere, so letting go doesn't launch it
+
+	// Session capture take length in bars
+	captureBars int
+
+	// Clip naming text entry
+	nameMode   bool
+	nameBuffer string
+
+	// Scene delete confirmation
+	confirmDelete bool
+
+	// Snapshot slots (see snapshots.go)
+	snapMode   bool // the slots take the keys
+	snapCursor int
+	snapNaming bool // naming the selected slot (in nameBuffer)
+
+	// Group launch - one selected pattern per track (-1 = none), and the scene pad
+	// held as shift on the Launchpad
+	selected    [8]int
+	sceneHeld   int  // pattern row of the held scene pad (-1 = none)
+	scenePicked bool // clips were picked during the hold
+}
+
+// Top row modes - one pad per track
+const (
+	TrackRowStop = iota
+	TrackRowMute
+	TrackRowSolo
+	TrackRowPage // up/down arrows page the view (see sessionpages.go)
+)
+
+var trackRowNames = []string{"stop", "mute", "solo", "page"}
+
+// trackRowPad performs the top row action on a track
+func (s *SessionDevice) trackRowPad(track int) {
+	switch s.trackRow {
+	case TrackRowMute:
+		s.manager.ToggleMute(track)
+	case TrackRowSolo:
+		s.manager.ToggleSolo(track)
+	case TrackRowPage:
+		s.handlePageArrow(track)
+	default:
+		s.manager.StopClip(track)
+	}
+}
+
+// trackRowLED colors a top row pad: bright when the track plays (stop), is muted or is soloed
+func (s *SessionDevice) trackRowLED(track int) [3]uint8 {
+	switch s.trackRow {
+	case TrackRowMute:
+		if S.Tracks[track].Muted {
+			return [3]uint8{255, 180, 0}
+		}
+		return [3]uint8{50, 36, 0}
+	case TrackRowSolo:
+		if S.Tracks[track].Solo {
+			return [3]uint8{0, 120, 255}
+		}
+		return [3]uint8{0, 24, 50}
+	}
+	if s.manager.GetDevice(track) != nil && !s.manager.ClipStopped(track) {
+		return [3]uint8{200, 30, 30}
+	}
+	return [3]uint8{40, 6, 6}
+}
+
+// densityGlyphs shows how busy a clip is, from empty to dense
+var densityGlyphs = []string{" ", "░", "▒", "▓", "█"}
+
+// densityLevel buckets notes-per-bar into 0-4 (0 = empty)
+func densityLevel(notesPerBar float64) int {
+	switch {
+	case notesPerBar <= 0:
+		return 0
+	case notesPerBar <= 4:
+		return 1
+	case notesPerBar <= 8:
+		return 2
+	case notesPerBar <= 16:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// barsLabel formats a clip length for a 2-char cell ("4", "16", "2+" for a partial bar)
+func barsLabel(bars float64) string {
+	whole := int(bars)
+	switch {
+	case bars <= 0:
+		return ""
+	case bars < 1:
+		return "<1"
+	case float64(whole) == bars || whole >= 10:
+		return fmt.Sprintf("%d", whole)
+	}
+	return fmt.Sprintf("%d+", whole)
+}
+
+// densityColor dims a color in proportion to density level (1-4 → 40%-100%)
+func densityColor(c [3]uint8, level int) [3]uint8 {
+	var out [3]uint8
+	for i := range c {
+		out[i] = uint8(int(c[i]) * (level + 1) / 5)
+	}
+	return out
 }
 
 func NewSessionDevice(manager *Manager) *SessionDevice {
-	return &SessionDevice{
-		manager:    manager,
-		cursorRow:  0,
-		cursorCol:  0,
-		viewRows:   8,
-		viewOffset: 0,
+	s := &SessionDevice{
+		manager:     manager,
+		cursorRow:   0,
+		cursorCol:   0,
+		viewRows:    8,
+		viewOffset:  0,
+		holdTrack:   -1,
+		captureBars: DefaultCaptureBars,
+		sceneHeld:   -1,
 	}
+	s.clearSelection()
+	return s
 }
 
 // getTrackPatternState returns (pattern, next) for a track by reading global state
@@ -50,12 +171,36 @@ func (s *SessionDevice) getTrackPatternState(trackIdx int) (pattern, next int) {
 	return 0, 0
 }
 
-// queuePattern queues a pattern on a device
-func (s *SessionDevice) queuePattern(trackIdx, patternIdx int) {
-	dev := s.manager.GetDevice(trackIdx)
-	if dev != nil {
-		dev.QueuePattern(patternIdx, S.Tick)
+// trackDensities returns notes-per-bar for every pattern on every track
+func (s *SessionDevice) trackDensities() [][]float64 {
+	densities := make([][]float64, 8)
+	for i := 0; i < 8; i++ {
+		dev := s.manager.GetDevice(i)
+		if dev != nil {
+			densities[i] = dev.Density()
+		} else {
+			densities[i] = make([]float64, NumPatterns)
+		}
 	}
+	return densities
+}
+
+// sceneHasContent reports whether any track has content in a pattern row
+func (s *SessionDevice) sceneHasContent(masks [][]bool, patternRow int) bool {
+	if patternRow >= NumPatterns {
+		return false
+	}
+	for _, mask := range masks {
+		if mask[patternRow] {
+			return true
+		}
+	}
+	return false
+}
+
+// queuePattern queues a pattern on a device (via manager so launches reach sync peers)
+func (s *SessionDevice) queuePattern(trackIdx, patternIdx int) {
+	s.manager.QueuePattern(trackIdx, patternIdx)
 }
 
 // Device interface implementation - queue-based (stubs for non-music device)
@@ -68,6 +213,8 @@ func (s *SessionDevice) QueuePattern(p int, atTick int64) {}
 func (s *SessionDevice) CurrentPattern() int            { return 0 }
 func (s *SessionDevice) NextPattern() int               { return -1 }
 func (s *SessionDevice) ContentMask() []bool            { return make([]bool, NumPatterns) }
+func (s *SessionDevice) Density() []float64             { return make([]float64, NumPatterns) }
+func (s *SessionDevice) PatternBars() []float64            { return make([]float64, NumPatterns) }
 
 func (s *SessionDevice) HandleMIDI(event midi.Event) {
 	if event.Type == midi.NoteOn && int(event.Channel) < 8 {
@@ -82,55 +229,158 @@ func (s *SessionDevice) IsPreviewing() bool { return false }
 
 func (s *SessionDevice) View() string {
 	var out string
-	out += "SESSION  Clip Launcher\n\n"
+	out += "SESSION  Clip Launcher"
+	out += fmt.Sprintf("  page %d/%d", s.page()+1, s.lastPage()+1)
+	if s.heatMap {
+		out += "  (density)"
+	}
+	if S.Generative {
+		out += fmt.Sprintf("  GENERATIVE every %d bars", S.GenBars())
+	}
+	out += "\n\n"
 	out += "       "
 	for i := 0; i < 8; i++ {
 		ts := S.Tracks[i]
 		if ts.Name != "" {
-			out += fmt.Sprintf(" %s ", ts.Name[:min(2, len(ts.Name))])
+			out += fmt.Sprintf(" %-2s  ", ts.Name[:min(2, len(ts.Name))])
 		} else {
-			out += fmt.Sprintf(" T%d ", i+1)
+			out += fmt.Sprintf(" T%d  ", i+1)
 		}
 	}
 	out += "\n"
-
-	masks := make([][]bool, 8)
+	stops := ""
 	for i := 0; i < 8; i++ {
-		dev := s.manager.GetDevice(i)
-		if dev != nil {
-			masks[i] = dev.ContentMask()
-		} else {
-			masks[i] = make([]bool, NumPatterns)
+		ts := S.Tracks[i]
+		flags := ""
+		if s.manager.ClipStopped(i) {
+			flags += "■"
 		}
+		if ts.Muted {
+			flags += "M"
+		}
+		if ts.Solo {
+			flags += "S"
+		}
+		if flags == "" && !s.manager.TrackAudible(i) {
+			flags = "·" // left out of a solo
+		}
+		stops += fmt.Sprintf(" %-3s ", flags)
+	}
+	if strings.TrimSpace(stops) != "" {
+		out += "State: " + stops + "\n"
+	}
+	launch := ""
+	for i := 0; i < 8; i++ {
+		mode := ""
+		if S.Tracks[i].LaunchMode != LaunchTrigger {
+			mode = truncateName(launchModeName(i), 4)
+		}
+		launch += fmt.Sprintf(" %-4s", mode)
+	}
+	if strings.TrimSpace(launch) != "" {
+		out += "Launch:" + launch + "\n"
+	}
+	if S.Generative {
+		out += "Relnch:"
+		for i := 0; i < 8; i++ {
+			out += fmt.Sprintf("%4d%%", S.Tracks[i].Relaunch)
+		}
+		out += "\n"
+	}
+
+	clips := make([][]ClipInfo, 8)
+	for i := 0; i < 8; i++ {
+		clips[i] = s.manager.ClipInfo(i)
+	}
+	var densities [][]float64
+	if s.heatMap {
+		densities = s.trackDensities()
 	}
 
 	for row := s.viewOffset; row < s.viewOffset+s.viewRows && row < NumPatterns; row++ {
-		out += fmt.Sprintf("Pat %2d: ", row+1)
+		if name := rowName(row); name != "" {
+			out += fmt.Sprintf("%-6s: ", truncateName(name, 6))
+		} else {
+			out += fmt.Sprintf("Pat %2d: ", row+1)
+		}
 		for col := 0; col < 8; col++ {
-			pattern, next := s.getTrackPatternState(col)
-			hasContent := masks[col][row]
+			clip := clips[col][row]
+			hasContent := clip.HasContent
 
 			char := " "
 			if hasContent {
 				char = "·"
 			}
-			if pattern == row {
+			if clip.Playing {
 				char = "▶"
-			} else if next == row && next != pattern {
+			} else if clip.Queued {
 				char = "◆"
+			} else if s.selected[col] == row {
+				char = "+"
+			}
+
+			// Heat map: marker (if any) followed by density glyph
+			if s.heatMap {
+				level := 0
+				if hasContent {
+					level = densityLevel(densities[col][row])
+				}
+				if char == "·" {
+					char = " "
+				}
+				char += densityGlyphs[level]
+				if row == s.cursorRow && col == s.cursorCol {
+					out += fmt.Sprintf("[%s] ", char)
+				} else {
+					out += fmt.Sprintf(" %s  ", char)
+				}
+				continue
 			}
 
+			// Clip length in bars after the marker
+			length := ""
+			if hasContent {
+				length = barsLabel(clip.Bars)
+			}
 			if row == s.cursorRow && col == s.cursorCol {
-				out += fmt.Sprintf("[%s] ", char)
+				out += fmt.Sprintf("[%s%-2s]", char, length)
 			} else {
-				out += fmt.Sprintf(" %s  ", char)
+				out += fmt.Sprintf(" %s%-2s ", char, length)
 			}
 		}
-		out += "\n"
+		out += " " + s.scrollGlyph(row-s.viewOffset) + "\n"
+	}
+	out += "Pages:  " + s.pageStrip(clips) + "\n"
+
+	if track, pattern, recording, ok := s.manager.Capturing(); ok {
+		state := "armed, starts at the next bar"
+		if recording {
+			state = "recording"
+		}
+		out += fmt.Sprintf("\nCapture: T%d pattern %d %s - a cancels\n", track+1, pattern+1, state)
+	}
+	if s.confirmDelete {
+		out += fmt.Sprintf("\nDelete scene %d on every track? Later scenes move up (not undoable)  [y] yes  [any] no\n", s.cursorRow+1)
+	}
+	if s.snapMode {
+		out += s.snapshotView()
+	}
+	if s.nameMode {
+		out += fmt.Sprintf("\nName for T%d pattern %d: %s_\n", s.cursorCol+1, s.cursorRow+1, s.nameBuffer)
+		out += "[enter] confirm (empty = no name)  [esc] cancel\n"
+	} else {
+		out += s.clipLabelLine()
+	}
+	if s.clip != nil {
+		out += "\nClipboard: " + s.clip.String() + " (v to paste)\n"
 	}
 
 	// Legend
-	out += "\n▶ playing  ◆ queued  · has content  - empty track\n"
+	if s.heatMap {
+		out += "\n▶ playing  ◆ queued  + selected  ░▒▓█ notes per bar: ≤4 ≤8 ≤16 more\n"
+	} else {
+		out += "\n▶ playing  ◆ queued  + selected  · has content  4 length in bars\n"
+	}
 
 	// Key help
 	out += "\n"
@@ -138,14 +388,33 @@ func (s *SessionDevice) View() string {
 		{Keys: []widgets.KeyBinding{
 			{Key: "h / l", Desc: "move cursor left/right (tracks)"},
 			{Key: "j / k", Desc: "move cursor up/down (patterns)"},
+			{Key: "J / K", Desc: "page down/up (also pgdown / pgup)"},
+			{Key: "home / end", Desc: "first / last scene"},
 			{Key: "space", Desc: "launch clip"},
+			{Key: "f / F", Desc: "select clip / launch selected together (esc clears)"},
+			{Key: "s", Desc: "launch scene (cursor row on every track)"},
+			{Key: "u / i", Desc: "duplicate scene below / insert blank scene"},
+			{Key: "bksp", Desc: "delete scene (later scenes move up)"},
+			{Key: "x / X", Desc: "stop cursor track / stop all clips (next bar)"},
+			{Key: "m / o", Desc: "mute / solo cursor track"},
+			{Key: "t", Desc: "top row pads: stop / mute / solo / page arrows"},
+			{Key: "n / N", Desc: "name clip at cursor / cycle its color"},
+			{Key: "a / A", Desc: "capture keyboard into next empty slot / capture length"},
+			{Key: "c / C", Desc: "copy / cut (move) clip at cursor"},
+			{Key: "v", Desc: "paste clip at cursor (same device type)"},
+			{Key: "e / E", Desc: "export clip / scene at cursor to a MIDI file"},
+			{Key: "w", Desc: "snapshot slots (capture / recall playing clips and mutes)"},
+			{Key: "d", Desc: "toggle density heat-map"},
+			{Key: "G", Desc: "toggle generative mode"},
+			{Key: "b", Desc: "generative boundary (bars)"},
+			{Key: "[ / ]", Desc: "track relaunch chance -/+"},
 			{Key: "1-8", Desc: "focus device on that track"},
 		}},
 	})
 
 	// Launchpad
 	out += "\n\n"
-	out += s.renderLaunchpadHelp()
+	out += widgets.RenderLayout(s.HelpLayout())
 
 	return out
 }
@@ -160,6 +429,9 @@ func (s *SessionDevice) RenderLEDs() []LEDState {
 	clipsQueued := [3]uint8{255, 200, 0}       // yellow - queued
 	clipsDim := [3]uint8{20, 4, 30}            // very dim purple - empty slot
 	sceneColor := [3]uint8{148, 18, 126}       // scene buttons
+	sceneEmpty := [3]uint8{30, 4, 25}          // scene with no content
+	captureColor := [3]uint8{255, 0, 0}        // capture target slot
+	selectedColor := [3]uint8{0, 255, 120}     // selected for a group launch
 
 	masks := make([][]bool, 8)
 	for i := 0; i < 8; i++ {
@@ -170,6 +442,12 @@ func (s *SessionDevice) RenderLEDs() []LEDState {
 			masks[i] = make([]bool, NumPatterns)
 		}
 	}
+	var densities [][]float64
+	if s.heatMap {
+		densities = s.trackDensities()
+	}
+
+	captureTrack, capturePattern, _, capturing := s.manager.Capturing()
 
 	// Main grid - clips
 	for col := 0; col < 8; col++ {
@@ -187,7 +465,7 @@ func (s *SessionDevice) RenderLEDs() []LEDState {
 				if pattern == patternRow {
 					if hasContent {
 						// Playing with content - bright pulsing
-						color = clipsPlaying
+						color = clipColor(col, patternRow, clipsPlaying)
 						channel = midi.ChannelPulse
 					} else {
 						// Playing but empty - gray
@@ -204,24 +482,62 @@ func (s *SessionDevice) RenderLEDs() []LEDState {
 					}
 				} else if hasContent {
 					// Has content but not playing
-					color = clipsBright
+					color = clipColor(col, patternRow, clipsBright)
+					if s.heatMap {
+						// Brightness follows density
+						color = densityColor(color, max(densityLevel(densities[col][patternRow]), 1))
+					}
 				}
 				// Empty + not playing stays clipsDim
+
+				if s.selected[col] == patternRow && patternRow != pattern {
+					color = selectedColor
+					channel = midi.ChannelStatic
+				}
+				if capturing && col == captureTrack && patternRow == capturePattern {
+					color = captureColor
+					channel = midi.ChannelPulse
+				}
 			}
 
 			leds = append(leds, LEDState{Row: lpRow, Col: col, Color: color, Channel: channel})
 		}
 	}
 
-	// Right column - scene launch buttons
+	// Top row - clip stop, mute or solo per track, or page arrows
+	if s.trackRow == TrackRowPage {
+		leds = append(leds, s.pageArrowLEDs()...)
+	} else {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: 8, Col: col, Color: s.trackRowLED(col), Channel: midi.ChannelStatic})
+		}
+	}
+
+	// Right column - scene launch buttons, dim where the row is empty
 	for row := 0; row < 8; row++ {
-		leds = append(leds, LEDState{Row: row, Col: 8, Color: sceneColor, Channel: midi.ChannelStatic})
+		color := sceneEmpty
+		if s.sceneHasContent(masks, s.viewOffset+(7-row)) {
+			color = sceneColor
+		}
+		leds = append(leds, LEDState{Row: row, Col: 8, Color: color, Channel: midi.ChannelStatic})
 	}
 
 	return leds
 }
 
 func (s *SessionDevice) HandleKey(key string) {
+	if s.nameMode {
+		s.handleNameKey(key)
+		return
+	}
+	if s.confirmDelete {
+		s.handleDeleteKey(key)
+		return
+	}
+	if s.snapMode {
+		s.handleSnapshotKey(key)
+		return
+	}
 	switch key {
 	case "h", "left":
 		if s.cursorCol > 0 {
@@ -245,39 +561,164 @@ func (s *SessionDevice) HandleKey(key string) {
 				s.viewOffset = s.cursorRow
 			}
 		}
+	case "J", "pgdown":
+		s.pageBy(1)
+	case "K", "pgup":
+		s.pageBy(-1)
+	case "home":
+		s.jumpToScene(0)
+	case "end":
+		s.jumpToScene(NumPatterns - 1)
 	case " ", "enter":
-		s.queuePattern(s.cursorCol, s.cursorRow)
+		s.launchClip(s.cursorCol, s.cursorRow)
+	case "f":
+		s.toggleSelected(s.cursorCol, s.cursorRow)
+	case "F":
+		s.launchSelection()
+	case "esc":
+		s.clearSelection()
+	case "s":
+		s.manager.LaunchScene(s.cursorRow)
+	case "u":
+		if s.manager.DuplicateScene(s.cursorRow) {
+			s.shiftSessionRefs(rowShift{row: s.cursorRow + 1, insert: true})
+		}
+	case "i":
+		if s.manager.InsertScene(s.cursorRow) {
+			s.shiftSessionRefs(rowShift{row: s.cursorRow, insert: true})
+		}
+	case "backspace", "delete":
+		s.confirmDeleteScene()
+	case "t":
+		s.trackRow = (s.trackRow + 1) % len(trackRowNames)
+		s.manager.announce("top row: %s", trackRowNames[s.trackRow])
+	case "m":
+		s.manager.ToggleMute(s.cursorCol)
+	case "o":
+		s.manager.ToggleSolo(s.cursorCol)
+	case "a":
+		s.manager.StartCapture(s.cursorCol, s.cursorRow, s.captureBars)
+	case "A":
+		s.cycleCaptureBars()
+	case "n":
+		s.startNaming()
+	case "N":
+		s.cycleClipColor()
+	case "c":
+		s.CopyClip(s.cursorCol, s.cursorRow, false)
+	case "C":
+		s.CopyClip(s.cursorCol, s.cursorRow, true)
+	case "v":
+		s.PasteClip(s.cursorCol, s.cursorRow)
+	case "e":
+		s.manager.ExportClip(s.cursorCol, s.cursorRow)
+	case "E":
+		s.manager.ExportScene(s.cursorRow)
+	case "x":
+		s.manager.StopClip(s.cursorCol)
+	case "X":
+		s.manager.StopAllClips()
+	case "d":
+		s.heatMap = !s.heatMap
+	case "w":
+		s.snapMode = true
+	case "G":
+		S.Generative = !S.Generative
+		s.manager.announce("generative mode %s", onOff(S.Generative))
+	case "b":
+		S.CycleGenerativeBars()
+	case "[":
+		S.NudgeRelaunch(s.cursorCol, -relaunchStep)
+	case "]":
+		S.NudgeRelaunch(s.cursorCol, relaunchStep)
 	}
 }
 
-func (s *SessionDevice) HandlePad(row, col int) {
+// HandlePadRelease ends a clip-hold gesture when the held clip pad is let go
+func (s *SessionDevice) HandlePadRelease(row, col int) {
+	if row < 8 && col == s.holdTrack && s.viewOffset+(7-row) == s.holdPattern {
+		s.holdTrack = -1
+		switch {
+		case S.Tracks[col].LaunchMode == LaunchGate:
+			s.releaseClip(col)
+		case !s.holdCopied:
+			s.launchClip(col, s.holdPattern)
+		}
+	}
+	if row < 8 && col == 8 {
+		s.releaseScenePad(s.viewOffset + (7 - row))
+	}
+}
+
+func (s *SessionDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (s *SessionDevice) HandlePad(row, col int, velocity uint8) {
+	// Top row - stop, mute or solo that track
+	if row == 8 {
+		if col < 8 {
+			s.trackRowPad(col)
+		}
+		return
+	}
 	patternRow := s.viewOffset + (7 - row)
-	if col < 8 && patternRow < NumPatterns {
-		s.queuePattern(col, patternRow)
+	if patternRow >= NumPatterns {
+		return
+	}
+	if col == 8 {
+		s.pressScenePad(patternRow)
+	} else if col < 8 {
+		// Holding a scene pad and tapping clips selects them for a group launch
+		if s.sceneHeld >= 0 {
+			s.toggleSelected(col, patternRow)
+			s.scenePicked = true
+			return
+		}
+		// Holding a clip and pressing another copies the held clip there
+		if s.copyHeldClip(col, patternRow) {
+			return
+		}
+		// Clips launch when let go without being copied - gate clips play while held
+		s.holdTrack, s.holdPattern, s.holdCopied = col, patternRow, false
+		if S.Tracks[col].LaunchMode == LaunchGate {
+			s.launchClip(col, patternRow)
+		}
 	}
 }
 
-func (s *SessionDevice) renderLaunchpadHelp() string {
+// HelpLayout describes the session Launchpad page, showing live clip state
+func (s *SessionDevice) HelpLayout() widgets.LaunchpadLayout {
 	// Define colors
-	clipColor := [3]uint8{71, 13, 121}     // clips with content
+	contentColor := [3]uint8{71, 13, 121}  // clips with content
 	playingColor := [3]uint8{71, 13, 121}  // currently playing
 	queuedColor := [3]uint8{255, 200, 0}   // queued for playback
 	emptyColor := [3]uint8{20, 4, 30}      // empty slot
-	topRowColor := [3]uint8{111, 10, 126}  // top row mode buttons
+	topRowColor := [3]uint8{200, 30, 30}   // top row clip stop buttons
 	sceneColor := [3]uint8{148, 18, 126}   // scene launch buttons
 
-	var out string
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
 
-	// Top row
-	topColors := make([][3]uint8, 8)
+	// Top row - clip stop, mute or solo per track, or page arrows
 	for i := 0; i < 8; i++ {
-		topColors[i] = topRowColor
+		tooltip := fmt.Sprintf("stop T%d clip (next bar)", i+1)
+		switch s.trackRow {
+		case TrackRowMute:
+			tooltip = fmt.Sprintf("mute T%d", i+1)
+		case TrackRowSolo:
+			tooltip = fmt.Sprintf("solo T%d", i+1)
+		case TrackRowPage:
+			tooltip = ""
+		}
+		l.TopRow[i] = widgets.Pad{Color: s.trackRowLED(i), Tooltip: tooltip}
+	}
+	if s.trackRow == TrackRowPage {
+		for _, led := range s.pageArrowLEDs() {
+			l.TopRow[led.Col] = widgets.Pad{Color: led.Color}
+		}
+		l.TopRow[1].Tooltip = "page up"
+		l.TopRow[2].Tooltip = "page down"
 	}
-	out += widgets.RenderPadRow(topColors) + "\n"
-
-	// Main grid with right column
-	var grid [8][8][3]uint8
-	var rightCol [8][3]uint8
 
 	// Get content masks for all tracks
 	masks := make([][]bool, 8)
@@ -289,6 +730,10 @@ func (s *SessionDevice) renderLaunchpadHelp() string {
 			masks[i] = make([]bool, NumPatterns)
 		}
 	}
+	var densities [][]float64
+	if s.heatMap {
+		densities = s.trackDensities()
+	}
 
 	// Build the grid with actual clip state
 	for lpRow := 0; lpRow < 8; lpRow++ {
@@ -299,37 +744,52 @@ func (s *SessionDevice) renderLaunchpadHelp() string {
 
 			// Default to empty
 			color := emptyColor
+			tooltip := ""
 
 			if patternRow < NumPatterns {
 				hasContent := masks[col][patternRow]
+				tooltip = fmt.Sprintf("launch T%d pattern %d", col+1, patternRow+1)
+				if name := S.Tracks[col].Clips[patternRow].Name; name != "" {
+					tooltip += " (" + name + ")"
+				}
 
 				if pattern == patternRow {
 					// Currently playing
-					color = playingColor
+					color = clipColor(col, patternRow, playingColor)
 				} else if next == patternRow && next != pattern {
 					// Queued
 					color = queuedColor
 				} else if hasContent {
 					// Has content
-					color = clipColor
+					color = clipColor(col, patternRow, contentColor)
+					if s.heatMap {
+						color = densityColor(color, max(densityLevel(densities[col][patternRow]), 1))
+					}
 				}
 			}
 
-			grid[lpRow][col] = color
+			l.Grid[lpRow][col] = widgets.Pad{Color: color, Tooltip: tooltip}
 		}
 
 		// Right column - scene buttons
-		rightCol[lpRow] = sceneColor
+		rightCol[lpRow] = widgets.Pad{Color: sceneColor}
+		if patternRow < NumPatterns {
+			rightCol[lpRow].Tooltip = fmt.Sprintf("launch scene %d on release (hold and tap clips to launch those together instead)", patternRow+1)
+		}
 	}
 
-	out += widgets.RenderPadGrid(grid, &rightCol) + "\n"
-
 	// Legend
-	out += widgets.RenderLegendItem(clipColor, "Clips", "tap to launch clip") + "\n"
-	out += widgets.RenderLegendItem(playingColor, "Playing", "currently playing clip") + "\n"
-	out += widgets.RenderLegendItem(queuedColor, "Queued", "queued for next bar") + "\n"
-	out += widgets.RenderLegendItem(emptyColor, "Empty", "no content") + "\n"
-	out += widgets.RenderLegendItem(sceneColor, "Scene", "launch entire row")
-
-	return out
+	l.Legend = append(l.Legend, widgets.LegendItem{Color: contentColor, Name: "Clips", Desc: "tap to launch clip"})
+	if s.heatMap {
+		l.Legend = append(l.Legend, widgets.LegendItem{Color: densityColor(contentColor, 1), Name: "Sparse", Desc: "dimmer = fewer notes per bar"})
+	}
+	l.Legend = append(l.Legend,
+		widgets.LegendItem{Color: playingColor, Name: "Playing", Desc: "currently playing clip"},
+		widgets.LegendItem{Color: queuedColor, Name: "Queued", Desc: "queued for next bar"},
+		widgets.LegendItem{Color: emptyColor, Name: "Empty", Desc: "no content"},
+		widgets.LegendItem{Color: sceneColor, Name: "Scene", Desc: "launch entire row; hold + tap clips to launch them together"},
+		widgets.LegendItem{Color: contentColor, Name: "Copy", Desc: "hold a clip and tap another slot to copy it there"},
+		widgets.LegendItem{Color: topRowColor, Name: "Tracks", Desc: "top row: stop / mute / solo per track, or page arrows (t switches)"},
+	)
+	return l
 }
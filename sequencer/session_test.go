@@ -0,0 +1,70 @@
+package sequencer
+
+import "testing"
+
+func TestSessionClipPadLaunch(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	type pad struct {
+		row, col int
+		press    bool
+	}
+	tests := []struct {
+		name    string
+		mode    int
+		pads    []pad
+		want    int // track 1's queued pattern
+		wantMid int // queued pattern before the last release
+	}{
+		{"tap launches on release", LaunchTrigger, []pad{{6, 0, true}, {6, 0, false}}, 1, -1},
+		{"copy doesn't launch", LaunchTrigger, []pad{{6, 0, true}, {5, 0, true}, {5, 0, false}, {6, 0, false}}, -1, -1},
+		{"gate launches on press", LaunchGate, []pad{{6, 0, true}, {6, 0, false}}, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			S.Tracks[0].LaunchMode = tt.mode
+			m := NewManager()
+			d := NewDrumDevice(NewDrumState())
+			d.state.Next = -1
+			m.SetDevice(0, d)
+			s := NewSessionDevice(m)
+
+			for i, p := range tt.pads {
+				if i == len(tt.pads)-1 && d.state.Next != tt.wantMid {
+					t.Errorf("queued %d before the release, want %d", d.state.Next, tt.wantMid)
+				}
+				if p.press {
+					s.HandlePad(p.row, p.col, 100)
+				} else {
+					s.HandlePadRelease(p.row, p.col)
+				}
+			}
+			if d.state.Next != tt.want {
+				t.Errorf("queued pattern %d, want %d", d.state.Next, tt.want)
+			}
+		})
+	}
+}
+
+func TestGateClipStartsNow(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+	S = NewState()
+	S.Tracks[0].LaunchMode = LaunchGate
+	S.Playing = true
+	step := int64(PPQ / 4)
+	S.Tick = 5 * step
+
+	m := NewManager()
+	d := NewDrumDevice(NewDrumState())
+	d.FillUntil(8 * step)
+	m.SetDevice(0, d)
+	s := NewSessionDevice(m)
+
+	s.HandlePad(6, 0, 100)
+	if len(d.schedule.Patterns) != 2 || d.schedule.Patterns[1] != 1 || len(d.schedule.Cuts) != 1 || d.schedule.Cuts[0] != S.Tick {
+		t.Errorf("schedule %v cuts %v, want pattern 2 switching in at tick %d", d.schedule.Patterns, d.schedule.Cuts, S.Tick)
+	}
+}
@@ -0,0 +1,97 @@
+package sequencer
+
+import (
+	"strings"
+
+	"go-sequence/midi"
+)
+
+// Session pages - the grid shows viewRows scenes at a time, so the 128 scenes make
+// sixteen pages. `J`/`K` (or page up/down) jump a page, home/end go to the first and
+// last scene, and with the top row in page mode (`t`) its up/down arrows page the
+// Launchpad. The TUI shows the page, a scrollbar and a strip of pages with content.
+
+// page returns the page the view starts on
+func (s *SessionDevice) page() int {
+	return s.viewOffset / s.viewRows
+}
+
+// lastPage returns the last page with any scenes on it
+func (s *SessionDevice) lastPage() int {
+	return (NumPatterns - 1) / s.viewRows
+}
+
+// pageBy jumps the view delta pages, keeping the cursor at the same spot in the view
+func (s *SessionDevice) pageBy(delta int) {
+	offset := clamp((s.page()+delta)*s.viewRows, 0, NumPatterns-s.viewRows)
+	s.cursorRow = clamp(s.cursorRow+offset-s.viewOffset, offset, offset+s.viewRows-1)
+	s.viewOffset = offset
+	s.manager.announce("page %d/%d (scenes %d-%d)", s.page()+1, s.lastPage()+1, offset+1, offset+s.viewRows)
+}
+
+// jumpToScene moves the cursor to a scene, scrolling it into view
+func (s *SessionDevice) jumpToScene(row int) {
+	s.cursorRow = clamp(row, 0, NumPatterns-1)
+	if s.cursorRow < s.viewOffset {
+		s.viewOffset = s.cursorRow
+	} else if s.cursorRow >= s.viewOffset+s.viewRows {
+		s.viewOffset = s.cursorRow - s.viewRows + 1
+	}
+}
+
+// pageArrowLEDs lights the top row in page mode: up/down arrows on cols 1-2
+func (s *SessionDevice) pageArrowLEDs() []LEDState {
+	leds := subPageArrows(s.page(), s.lastPage())
+	for col := 4; col < 8; col++ {
+		leds = append(leds, LEDState{Row: 8, Col: col, Color: [3]uint8{0, 0, 0}, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+// handlePageArrow pages the view from a top row arrow in page mode
+func (s *SessionDevice) handlePageArrow(col int) {
+	switch col {
+	case 1:
+		s.pageBy(-1)
+	case 2:
+		s.pageBy(1)
+	}
+}
+
+// scrollGlyph draws one line of the scrollbar beside the grid (line counts from the
+// top of the view)
+func (s *SessionDevice) scrollGlyph(line int) string {
+	top := s.viewOffset * s.viewRows / NumPatterns
+	bottom := max((s.viewOffset+s.viewRows)*s.viewRows/NumPatterns, top+1)
+	if line >= top && line < bottom {
+		return "█"
+	}
+	return "│"
+}
+
+// pageStrip shows every page: ● the one in view, ○ pages with content, · empty ones
+func (s *SessionDevice) pageStrip(clips [][]ClipInfo) string {
+	var out strings.Builder
+	for p := 0; p <= s.lastPage(); p++ {
+		glyph := "·"
+		if p == s.page() {
+			glyph = "●"
+		} else if pageHasContent(clips, p*s.viewRows, min((p+1)*s.viewRows, NumPatterns)) {
+			glyph = "○"
+		}
+		out.WriteString(glyph)
+	}
+	return out.String()
+}
+
+// pageHasContent reports whether any track has a clip in scenes [from, to)
+func pageHasContent(clips [][]ClipInfo, from, to int) bool {
+	for _, track := range clips {
+		for row := from; row < to; row++ {
+			if track[row].HasContent {
+				return true
+			}
+		}
+	}
+	return false
+}
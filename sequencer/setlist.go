@@ -0,0 +1,662 @@
+package sequencer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// SetListEntry is one song in a set list
+type SetListEntry struct {
+	Project string `json:"project"`
+	Save    string `json:"save,omitempty"`  // save filename ("" = latest)
+	Tempo   int    `json:"tempo,omitempty"` // bpm override (0 = project tempo)
+	Scene   int    `json:"scene,omitempty"` // pattern launched on every track, 1-based (0 = none)
+}
+
+// SetList is an ordered list of projects for a gig (stored outside any project)
+type SetList struct {
+	Name    string         `json:"-"` // from filename
+	Entries []SetListEntry `json:"entries"`
+}
+
+// Set list entry override limits
+const (
+	setListTempoStep = 5
+	setListMinTempo  = 20
+	setListMaxTempo  = 300
+)
+
+// Label describes an entry for lists and tooltips
+func (e SetListEntry) Label() string {
+	label := e.Project
+	if e.Tempo > 0 {
+		label += fmt.Sprintf("  %d bpm", e.Tempo)
+	}
+	if e.Scene > 0 {
+		label += fmt.Sprintf("  scene %d", e.Scene)
+	}
+	return label
+}
+
+// SetListsDir returns the set lists directory path
+func SetListsDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "go-sequence", "setlists"), nil
+}
+
+// ListSetLists returns all set list names
+func ListSetLists() ([]string, error) {
+	dir, err := SetListsDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// LoadSetList reads a set list by name
+func LoadSetList(name string) (*SetList, error) {
+	dir, err := SetListsDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, name+".json"))
+	if err != nil {
+		return nil, err
+	}
+	sl := &SetList{Name: name}
+	if err := json.Unmarshal(data, sl); err != nil {
+		return nil, err
+	}
+	return sl, nil
+}
+
+// SaveSetList writes a set list to its file
+func SaveSetList(sl *SetList) error {
+	dir, err := SetListsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sl, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, sanitizeFilename(sl.Name)+".json"), data, 0644)
+}
+
+// LoadSetListEntry loads an entry's project, then applies its tempo and scene overrides.
+// Mid-set the transport keeps running: the old song's notes are ended, the new one
+// joins at the same place in the bar, and its scene is queued on the next boundary.
+func (m *Manager) LoadSetListEntry(e SetListEntry) error {
+	autoBackup("load")
+	m.mu.Lock()
+	playing := S.Playing
+	tick := S.TimeToTick(time.Now())
+	if playing {
+		m.clearQueues()
+		m.resetMonoNotes()
+	}
+	m.mu.Unlock()
+
+	if err := m.OpenProject(e.Project, e.Save); err != nil {
+		if playing {
+			m.interrupt() // carry on with the song we have
+		}
+		return err
+	}
+	if e.Tempo > 0 {
+		m.SetTempo(e.Tempo)
+	}
+	if playing {
+		m.playFrom(tick)
+	}
+	if e.Scene > 0 {
+		m.LaunchScene(e.Scene - 1)
+	}
+	m.announce("song %s", e.Project)
+	return nil
+}
+
+// setListMode is what the set list device's keys currently do
+type setListMode int
+
+const (
+	setListBrowse  setListMode = iota
+	setListNaming              // typing a new set list name
+	setListPicking             // choosing a project to add
+)
+
+// SetListDevice steps through a set list, one song (project) at a time
+type SetListDevice struct {
+	manager *Manager
+
+	// Cached data
+	names []string // set list files
+	list  *SetList // selected set list (nil = none)
+
+	// Selection state
+	listIdx  int // selected set list
+	entryIdx int // selected song
+	column   int // 0=set lists, 1=songs
+	current  int // song loaded from this list (-1 = none)
+
+	// New set list name / project picker
+	mode        setListMode
+	inputBuffer string
+	projects    []string
+	pickIdx     int
+
+	err string // last load/save error
+}
+
+// NewSetListDevice creates a set list device
+func NewSetListDevice(manager *Manager) *SetListDevice {
+	d := &SetListDevice{manager: manager, current: -1}
+	d.Refresh()
+	return d
+}
+
+// IsInputMode returns true while naming a set list or picking a project
+func (d *SetListDevice) IsInputMode() bool {
+	return d.mode != setListBrowse
+}
+
+// Refresh reloads the set list names and the selected set list
+func (d *SetListDevice) Refresh() {
+	names, _ := ListSetLists()
+	d.names = names
+	if d.listIdx >= len(d.names) {
+		d.listIdx = max(0, len(d.names)-1)
+	}
+
+	d.list = nil
+	if d.listIdx < len(d.names) {
+		sl, err := LoadSetList(d.names[d.listIdx])
+		if err != nil {
+			d.err = err.Error()
+		} else {
+			d.list = sl
+		}
+	}
+	if d.entryIdx >= d.entryCount() {
+		d.entryIdx = max(0, d.entryCount()-1)
+	}
+}
+
+// entryCount returns how many songs the selected set list has
+func (d *SetListDevice) entryCount() int {
+	if d.list == nil {
+		return 0
+	}
+	return len(d.list.Entries)
+}
+
+// selectList switches to another set list, forgetting the loaded song
+func (d *SetListDevice) selectList(idx int) {
+	d.listIdx = idx
+	d.entryIdx = 0
+	d.current = -1
+	d.Refresh()
+}
+
+// save writes the selected set list, keeping the error for the view
+func (d *SetListDevice) save() {
+	if d.list == nil {
+		return
+	}
+	if err := SaveSetList(d.list); err != nil {
+		d.err = err.Error()
+	}
+}
+
+// loadEntry loads a song and keeps the set list focused for the next one
+func (d *SetListDevice) loadEntry(idx int) {
+	if idx < 0 || idx >= d.entryCount() {
+		return
+	}
+	if err := d.manager.LoadSetListEntry(d.list.Entries[idx]); err != nil {
+		d.err = err.Error()
+		return
+	}
+	d.err = ""
+	d.current = idx
+	d.entryIdx = idx
+	d.manager.SetFocused(d)
+}
+
+// Next loads the song after the current one (the first song if none is loaded)
+func (d *SetListDevice) Next() {
+	d.loadEntry(d.current + 1)
+}
+
+// Prev loads the song before the current one
+func (d *SetListDevice) Prev() {
+	if d.current > 0 {
+		d.loadEntry(d.current - 1)
+	}
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (d *SetListDevice) FillUntil(tick int64)                           {}
+func (d *SetListDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (d *SetListDevice) PopNextEvent() *midi.Event                      { return nil }
+func (d *SetListDevice) ClearQueue()                                    {}
+func (d *SetListDevice) QueuePattern(p int, atTick int64)               {}
+func (d *SetListDevice) CurrentPattern() int                            { return 0 }
+func (d *SetListDevice) NextPattern() int                               { return -1 }
+func (d *SetListDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (d *SetListDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (d *SetListDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (d *SetListDevice) HandleMIDI(event midi.Event)                    {}
+func (d *SetListDevice) ToggleRecording()                               {}
+func (d *SetListDevice) TogglePreview()                                 {}
+func (d *SetListDevice) IsRecording() bool                              { return false }
+func (d *SetListDevice) IsPreviewing() bool                             { return false }
+func (d *SetListDevice) HandlePadRelease(row, col int)                  {}
+func (d *SetListDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (d *SetListDevice) View() string {
+	var out strings.Builder
+
+	// Header
+	header := "SET LIST  (none)"
+	if d.list != nil {
+		header = fmt.Sprintf("SET LIST  %s", d.list.Name)
+		if d.current >= 0 {
+			header += fmt.Sprintf("  song %d/%d", d.current+1, len(d.list.Entries))
+		}
+	}
+	out.WriteString(header + "\n\n")
+
+	// Naming takes over
+	if d.mode == setListNaming {
+		out.WriteString("─────────────────────────────────────────────────\n")
+		out.WriteString(fmt.Sprintf("\nNew set list name: %s_\n", d.inputBuffer))
+		out.WriteString("\n[enter] confirm  [esc] cancel\n")
+		out.WriteString("\n─────────────────────────────────────────────────\n")
+		return out.String()
+	}
+
+	// Project picker takes over
+	if d.mode == setListPicking {
+		out.WriteString("Add project\n")
+		out.WriteString("─────────────────────────────────────────────────\n")
+		for i, name := range d.projects {
+			prefix := "  "
+			if i == d.pickIdx {
+				prefix = "> "
+			}
+			out.WriteString(prefix + name + "\n")
+		}
+		if len(d.projects) == 0 {
+			out.WriteString("  (no projects yet)\n")
+		}
+		out.WriteString("\n[enter] add  [esc] cancel\n")
+		return out.String()
+	}
+
+	// Two column layout
+	out.WriteString("Set lists                   Songs\n")
+	out.WriteString("─────────────────────────────────────────────────\n")
+
+	rows := max(1, len(d.names), d.entryCount())
+	for row := 0; row < rows; row++ {
+		// Set lists column
+		if row < len(d.names) {
+			prefix := "  "
+			if row == d.listIdx {
+				prefix = "* "
+				if d.column == 0 {
+					prefix = "> "
+				}
+			}
+			out.WriteString(fmt.Sprintf("%s%-20s", prefix, truncateName(d.names[row], 20)))
+		} else {
+			out.WriteString("                      ")
+		}
+
+		out.WriteString("    ")
+
+		// Songs column
+		if row < d.entryCount() {
+			prefix := "  "
+			if row == d.entryIdx {
+				prefix = "* "
+				if d.column == 1 {
+					prefix = "> "
+				}
+			}
+			playing := " "
+			if row == d.current {
+				playing = "▶"
+			}
+			out.WriteString(fmt.Sprintf("%s%s %2d. %s", prefix, playing, row+1, d.list.Entries[row].Label()))
+		}
+
+		out.WriteString("\n")
+	}
+
+	if len(d.names) == 0 {
+		out.WriteString("  (no set lists yet - c to create)\n")
+	}
+	if d.err != "" {
+		out.WriteString(fmt.Sprintf("\nError: %s\n", d.err))
+	}
+
+	// Key help
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Title: "Browse", Keys: []widgets.KeyBinding{
+			{Key: "h / l", Desc: "switch columns"},
+			{Key: "j / k", Desc: "navigate list"},
+			{Key: "enter", Desc: "load selected song"},
+			{Key: "space", Desc: "next song"},
+			{Key: "b", Desc: "previous song"},
+		}},
+		{Title: "Edit", Keys: []widgets.KeyBinding{
+			{Key: "c", Desc: "new set list"},
+			{Key: "a", Desc: "add project after selected"},
+			{Key: "x", Desc: "remove song"},
+			{Key: "J / K", Desc: "move song down/up"},
+			{Key: "[ / ]", Desc: fmt.Sprintf("tempo override -/+ %d", setListTempoStep)},
+			{Key: "t", Desc: "clear tempo override"},
+			{Key: "< / >", Desc: "scene override -/+"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(d.HelpLayout()))
+	return out.String()
+}
+
+// Set list pad colors
+var (
+	setListSongColor    = [3]uint8{60, 60, 160}
+	setListCurrentColor = [3]uint8{0, 255, 0}
+	setListStepColor    = [3]uint8{255, 140, 0}
+	setListDimColor     = [3]uint8{30, 30, 30}
+)
+
+func (d *SetListDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	layout := d.HelpLayout()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: row, Col: col, Color: layout.Grid[row][col].Color, Channel: midi.ChannelStatic})
+		}
+		leds = append(leds, LEDState{Row: row, Col: 8, Color: layout.RightCol[row].Color, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+func (d *SetListDevice) HandleKey(key string) {
+	switch d.mode {
+	case setListNaming:
+		d.handleNamingKey(key)
+		return
+	case setListPicking:
+		d.handlePickingKey(key)
+		return
+	}
+
+	switch key {
+	case "h", "left":
+		d.column = 0
+	case "l", "right":
+		if d.list != nil {
+			d.column = 1
+		}
+	case "j", "down":
+		if d.column == 0 {
+			if d.listIdx < len(d.names)-1 {
+				d.selectList(d.listIdx + 1)
+			}
+		} else if d.entryIdx < d.entryCount()-1 {
+			d.entryIdx++
+		}
+	case "k", "up":
+		if d.column == 0 {
+			if d.listIdx > 0 {
+				d.selectList(d.listIdx - 1)
+			}
+		} else if d.entryIdx > 0 {
+			d.entryIdx--
+		}
+	case "enter":
+		if d.column == 1 {
+			d.loadEntry(d.entryIdx)
+		} else if d.list != nil {
+			d.column = 1
+		}
+	case " ":
+		d.Next()
+	case "b":
+		d.Prev()
+	case "c":
+		d.mode = setListNaming
+		d.inputBuffer = ""
+	case "a":
+		if d.list != nil {
+			d.projects, _ = ListProjects()
+			d.pickIdx = 0
+			d.mode = setListPicking
+		}
+	default:
+		d.editEntry(key)
+	}
+}
+
+// editEntry handles keys that change the selected song
+func (d *SetListDevice) editEntry(key string) {
+	if d.entryIdx >= d.entryCount() {
+		return
+	}
+	entries := d.list.Entries
+	e := &entries[d.entryIdx]
+
+	switch key {
+	case "x":
+		d.list.Entries = append(entries[:d.entryIdx], entries[d.entryIdx+1:]...)
+		if d.current == d.entryIdx {
+			d.current = -1
+		} else if d.current > d.entryIdx {
+			d.current--
+		}
+		if d.entryIdx >= len(d.list.Entries) {
+			d.entryIdx = max(0, len(d.list.Entries)-1)
+		}
+	case "J":
+		if d.entryIdx < len(entries)-1 {
+			d.swapEntries(d.entryIdx, d.entryIdx+1)
+			d.entryIdx++
+		}
+	case "K":
+		if d.entryIdx > 0 {
+			d.swapEntries(d.entryIdx, d.entryIdx-1)
+			d.entryIdx--
+		}
+	case "[", "]":
+		tempo := e.Tempo
+		if tempo == 0 {
+			tempo = S.Tempo
+		}
+		if key == "[" {
+			tempo -= setListTempoStep
+		} else {
+			tempo += setListTempoStep
+		}
+		e.Tempo = clamp(tempo, setListMinTempo, setListMaxTempo)
+	case "t":
+		e.Tempo = 0
+	case "<":
+		if e.Scene > 0 {
+			e.Scene--
+		}
+	case ">", ".":
+		if e.Scene < NumPatterns {
+			e.Scene++
+		}
+	default:
+		return
+	}
+	d.save()
+}
+
+// swapEntries swaps two songs, keeping the loaded song marker on the same song
+func (d *SetListDevice) swapEntries(i, j int) {
+	entries := d.list.Entries
+	entries[i], entries[j] = entries[j], entries[i]
+	switch d.current {
+	case i:
+		d.current = j
+	case j:
+		d.current = i
+	}
+}
+
+// handleNamingKey edits the new set list name
+func (d *SetListDevice) handleNamingKey(key string) {
+	switch key {
+	case "enter":
+		name := sanitizeFilename(strings.TrimSpace(d.inputBuffer))
+		d.mode = setListBrowse
+		d.inputBuffer = ""
+		if name == "" {
+			return
+		}
+		if err := SaveSetList(&SetList{Name: name, Entries: []SetListEntry{}}); err != nil {
+			d.err = err.Error()
+			return
+		}
+		names, _ := ListSetLists()
+		for i, n := range names {
+			if n == name {
+				d.selectList(i)
+			}
+		}
+	case "esc":
+		d.mode = setListBrowse
+		d.inputBuffer = ""
+	case "backspace":
+		if len(d.inputBuffer) > 0 {
+			d.inputBuffer = d.inputBuffer[:len(d.inputBuffer)-1]
+		}
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 && key != "/" && key != "\\" {
+			d.inputBuffer += key
+		}
+	}
+}
+
+// handlePickingKey chooses a project to add after the selected song
+func (d *SetListDevice) handlePickingKey(key string) {
+	switch key {
+	case "j", "down":
+		if d.pickIdx < len(d.projects)-1 {
+			d.pickIdx++
+		}
+	case "k", "up":
+		if d.pickIdx > 0 {
+			d.pickIdx--
+		}
+	case "enter":
+		if d.pickIdx < len(d.projects) {
+			at := 0
+			if d.entryCount() > 0 {
+				at = d.entryIdx + 1
+			}
+			e := SetListEntry{Project: d.projects[d.pickIdx]}
+			d.list.Entries = append(d.list.Entries[:at], append([]SetListEntry{e}, d.list.Entries[at:]...)...)
+			if d.current >= at {
+				d.current++
+			}
+			d.entryIdx = at
+			d.column = 1
+			d.save()
+		}
+		d.mode = setListBrowse
+	case "esc", "q":
+		d.mode = setListBrowse
+	}
+}
+
+// HandlePad loads the song on a pad; scene buttons step next/previous
+func (d *SetListDevice) HandlePad(row, col int, velocity uint8) {
+	if col == 8 {
+		switch row {
+		case 7:
+			d.Next()
+		case 6:
+			d.Prev()
+		}
+		return
+	}
+	if row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	d.loadEntry((7-row)*8 + col)
+}
+
+// HelpLayout shows one pad per song (top-left first) and the next/previous buttons
+func (d *SetListDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
+
+	for i := 0; i < 8; i++ {
+		l.TopRow[i] = widgets.Pad{Color: setListDimColor}
+		rightCol[i] = widgets.Pad{Color: setListDimColor}
+	}
+	rightCol[7] = widgets.Pad{Color: setListStepColor, Tooltip: "next song"}
+	rightCol[6] = widgets.Pad{Color: setListStepColor, Tooltip: "previous song"}
+
+	for idx := 0; idx < 64; idx++ {
+		pad := widgets.Pad{Color: setListDimColor}
+		if idx < d.entryCount() {
+			pad = widgets.Pad{Color: setListSongColor, Tooltip: fmt.Sprintf("song %d: %s", idx+1, d.list.Entries[idx].Label())}
+			if idx == d.current {
+				pad.Color = setListCurrentColor
+			}
+		}
+		l.Grid[7-idx/8][idx%8] = pad
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: setListSongColor, Name: "Songs", Desc: "tap to load song"},
+		{Color: setListCurrentColor, Name: "Current", Desc: "loaded song"},
+		{Color: setListStepColor, Name: "Step", Desc: "top scene = next song, second = previous"},
+	}
+	return l
+}
@@ -0,0 +1,39 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLoadSetListEntryKeepsPlaying(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+	t.Setenv("HOME", t.TempDir())
+
+	S = NewState()
+	S.Tracks[0].Type, S.Tracks[0].Drum = DeviceTypeDrum, NewDrumState()
+	S.Tracks[0].Drum.Patterns[1].Notes[0].Steps[0] = DrumStepState{Active: true, Velocity: 100}
+	if err := SaveProject("next song"); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager()
+	m.mu.Lock()
+	m.recreateDevicesFromState()
+	m.mu.Unlock()
+	bar := int64(4 * PPQ)
+	m.playFrom(bar + bar/2)
+
+	if err := m.LoadSetListEntry(SetListEntry{Project: "next song", Tempo: 90, Scene: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if !S.Playing || S.Tempo != 90 {
+		t.Fatalf("playing %v at %d bpm after the song change, want playing at 90", S.Playing, S.Tempo)
+	}
+	if tick := S.TimeToTick(time.Now()); tick < bar+bar/2 || tick >= 2*bar {
+		t.Errorf("new song at tick %d, want it to carry on from %d", tick, bar+bar/2)
+	}
+	if next := S.Tracks[0].Drum.Next; next != 1 {
+		t.Errorf("track 1 queued pattern %d, want the entry's scene (1)", next)
+	}
+}
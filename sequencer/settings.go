@@ -2,7 +2,9 @@ package sequencer
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"go-sequence/midi"
 	"go-sequence/widgets"
@@ -15,10 +17,9 @@ const (
 	PopupNone PopupType = iota
 	PopupDeviceType
 	PopupChannel
-	PopupOutput
 	PopupKit
+	PopupProfile
 	PopupConfirm
-	PopupNoteInput
 )
 
 // PopupState holds the state of an open popup
@@ -35,8 +36,8 @@ type SettingsDevice struct {
 	manager *Manager // reference for device access and creation
 
 	// Cursor position
-	cursorRow int // 0-7 for tracks, 8 for note input
-	cursorCol int // 0=device, 1=channel, 2=output
+	cursorRow int // 0-7 for tracks, 8 for note input, 9 LED brightness, 10 LED theme, 11 launch quantization, 12 project rig
+	cursorCol int // 0=device, 1=channel, 2=output, 3=kit, 4=profile, 5=latency, 6=record from, 7=transpose, 8=launch mode, 9=fader CC
 
 	// Popup state
 	popup *PopupState
@@ -47,14 +48,24 @@ type SettingsDevice struct {
 
 	// Flag to signal TUI that note input changed (checked after HandleKey)
 	NoteInputChanged bool
+
+	// Flag to signal TUI that LED brightness or theme changed, to save them in the config
+	LEDSettingsChanged bool
+
+	// Latency test - TUI runs the test for this track when set (checked after HandleKey)
+	LatencyTestTrack int               // -1 = none requested
+	latencyTesting   bool              // a test is in progress
+	latencyResults   map[string]string // port -> last result ("12ms" or error)
 }
 
 // NewSettingsDevice creates a settings device
 func NewSettingsDevice(manager *Manager) *SettingsDevice {
 	return &SettingsDevice{
-		manager:   manager,
-		cursorRow: 0,
-		cursorCol: 0,
+		manager:          manager,
+		cursorRow:        0,
+		cursorCol:        0,
+		LatencyTestTrack: -1,
+		latencyResults:   make(map[string]string),
 	}
 }
 
@@ -64,6 +75,17 @@ func (s *SettingsDevice) SetMIDIPorts(inputs, outputs []string) {
 	s.midiOutputs = outputs
 }
 
+// SetLatencyResult records a finished latency test and applies it to the port's tracks
+func (s *SettingsDevice) SetLatencyResult(port string, latency time.Duration, err error) {
+	s.latencyTesting = false
+	if err != nil {
+		s.latencyResults[port] = err.Error()
+		return
+	}
+	s.latencyResults[port] = fmt.Sprintf("%dms (applied)", latency/time.Millisecond)
+	s.manager.ApplyLatency(port, latency)
+}
+
 // Device interface implementation - queue-based (stubs for non-music device)
 
 func (s *SettingsDevice) FillUntil(tick int64)           {}
@@ -74,6 +96,8 @@ func (s *SettingsDevice) QueuePattern(p int, atTick int64) {}
 func (s *SettingsDevice) CurrentPattern() int            { return 0 }
 func (s *SettingsDevice) NextPattern() int               { return -1 }
 func (s *SettingsDevice) ContentMask() []bool            { return make([]bool, NumPatterns) }
+func (s *SettingsDevice) Density() []float64             { return make([]float64, NumPatterns) }
+func (s *SettingsDevice) PatternBars() []float64            { return make([]float64, NumPatterns) }
 
 func (s *SettingsDevice) HandleMIDI(event midi.Event) {
 	// Could use this for "learn" functionality later
@@ -90,8 +114,8 @@ func (s *SettingsDevice) View() string {
 	out.WriteString("SETTINGS  Track & MIDI Configuration\n\n")
 
 	// Track table header
-	out.WriteString("Track   Device       Channel   Output         Kit\n")
-	out.WriteString("────────────────────────────────────────────────────────────\n")
+	out.WriteString("Track   Device       Channel   Output         Kit           Profile       Latency  Rec from  Transp  Launch    Fader\n")
+	out.WriteString("───────────────────────────────────────────────────────────────────────────────────────────────────────────────────────\n")
 
 	// Track rows
 	for i := 0; i < 8; i++ {
@@ -146,12 +170,106 @@ func (s *SettingsDevice) View() string {
 		if s.cursorRow == i && s.cursorCol == 3 {
 			out.WriteString(fmt.Sprintf("[%-12s]", kitStr))
 		} else {
-			out.WriteString(fmt.Sprintf(" %-12s", kitStr))
+			out.WriteString(fmt.Sprintf(" %-12s ", kitStr))
+		}
+
+		// Output profile cell
+		profileStr := GetProfile(ts.Profile).Name
+		if s.cursorRow == i && s.cursorCol == 4 {
+			out.WriteString(fmt.Sprintf("[%-12s]", profileStr))
+		} else {
+			out.WriteString(fmt.Sprintf(" %-12s ", profileStr))
+		}
+
+		// Latency compensation cell
+		latencyStr := fmt.Sprintf("%dms", ts.LatencyMs)
+		if s.cursorRow == i && s.cursorCol == 5 {
+			out.WriteString(fmt.Sprintf("[%-6s]", latencyStr))
+		} else {
+			out.WriteString(fmt.Sprintf(" %-6s ", latencyStr))
+		}
+
+		// Resample source cell (records another track's output)
+		recordFromStr := "keys"
+		if ts.RecordFrom > 0 {
+			recordFromStr = fmt.Sprintf("T%d out", ts.RecordFrom)
+		}
+		if s.cursorRow == i && s.cursorCol == 6 {
+			out.WriteString(fmt.Sprintf(" [%-6s]", recordFromStr))
+		} else {
+			out.WriteString(fmt.Sprintf("  %-6s", recordFromStr))
+		}
+
+		// Dispatch transpose cell
+		transposeStr := fmt.Sprintf("%+d", ts.Transpose)
+		if ts.Transpose == 0 {
+			transposeStr = "0"
+		}
+		if s.cursorRow == i && s.cursorCol == 7 {
+			out.WriteString(fmt.Sprintf("  [%-4s]", transposeStr))
+		} else {
+			out.WriteString(fmt.Sprintf("   %-4s ", transposeStr))
+		}
+
+		// Session launch mode cell
+		if s.cursorRow == i && s.cursorCol == 8 {
+			out.WriteString(fmt.Sprintf(" [%-7s]", launchModeName(i)))
+		} else {
+			out.WriteString(fmt.Sprintf("  %-7s ", launchModeName(i)))
+		}
+
+		// Controller fader CC cell
+		faderStr := fmt.Sprintf("cc %d", ts.FaderCC())
+		if s.cursorRow == i && s.cursorCol == 9 {
+			out.WriteString(fmt.Sprintf(" [%-6s]", faderStr))
+		} else {
+			out.WriteString(fmt.Sprintf("  %-6s ", faderStr))
 		}
 
 		out.WriteString("\n")
 	}
 
+	// Profile description for the selected track
+	if s.cursorRow < 8 && s.cursorCol == 4 {
+		out.WriteString(fmt.Sprintf("\n  %s: %s\n", GetProfile(S.Tracks[s.cursorRow].Profile).Name, GetProfile(S.Tracks[s.cursorRow].Profile).Description))
+	}
+
+	// Resample hint for the selected track
+	if s.cursorRow < 8 && s.cursorCol == 6 {
+		out.WriteString("\n  Rec from: record another track's output into this one (arm recording on this track)\n")
+	}
+
+	// Transpose hint for the selected track
+	if s.cursorRow < 8 && s.cursorCol == 7 {
+		out.WriteString("\n  Transpose: semitones added to this track's notes on output (patterns are unchanged)\n")
+	}
+
+	// Launch mode hint for the selected track
+	if s.cursorRow < 8 && s.cursorCol == 8 {
+		out.WriteString("\n  Launch: what pressing a clip does in the session - trigger, toggle (press again to stop),\n  retrig (press again to restart at the next bar) or gate (plays while the pad is held)\n")
+	}
+
+	// Fader hint for the selected track
+	if s.cursorRow < 8 && s.cursorCol == 9 {
+		out.WriteString(fmt.Sprintf("\n  Fader: CC a controller's fader (APC) sends on this track - [ ] to change, enter for volume (cc %d)\n", DefaultFaderCC))
+	}
+
+	// Latency test results
+	if s.latencyTesting || len(s.latencyResults) > 0 {
+		out.WriteString("\nLatency (round trip / 2 via note input)\n")
+		if s.latencyTesting {
+			out.WriteString("  testing...\n")
+		}
+		ports := make([]string, 0, len(s.latencyResults))
+		for port := range s.latencyResults {
+			ports = append(ports, port)
+		}
+		sort.Strings(ports)
+		for _, port := range ports {
+			out.WriteString(fmt.Sprintf("  %-30s %s\n", port, s.latencyResults[port]))
+		}
+	}
+
 	// Note Input selection row
 	out.WriteString("\n")
 	out.WriteString("─────────────────────────────────────────────────\n")
@@ -162,16 +280,54 @@ func (s *SettingsDevice) View() string {
 			noteInputStr = noteInputStr[:30]
 		}
 	}
-	if s.cursorRow == 8 {
+	if s.cursorRow == settingsRowNoteInput {
 		out.WriteString(fmt.Sprintf("Note Input:  [%-30s]\n", noteInputStr))
 	} else {
 		out.WriteString(fmt.Sprintf("Note Input:   %-30s\n", noteInputStr))
 	}
 
+	// LED look rows
+	brightnessStr := fmt.Sprintf("%d%%", s.manager.LEDBrightness())
+	themeStr := s.manager.LEDTheme()
+	if themeStr == "" {
+		themeStr = "(device colors)"
+	}
+	if s.cursorRow == settingsRowBrightness {
+		out.WriteString(fmt.Sprintf("LED Bright:  [%-30s]\n", brightnessStr))
+	} else {
+		out.WriteString(fmt.Sprintf("LED Bright:   %-30s\n", brightnessStr))
+	}
+	if s.cursorRow == settingsRowTheme {
+		out.WriteString(fmt.Sprintf("LED Theme:   [%-30s]\n", themeStr))
+	} else {
+		out.WriteString(fmt.Sprintf("LED Theme:    %-30s\n", themeStr))
+	}
+
+	// Project overrides of the config's rig (see overrides.go)
+	launchStr := launchBarsName(s.manager.LaunchBars())
+	if o := S.Overrides; o == nil || o.LaunchBars == nil {
+		launchStr += " (config)"
+	}
+	rigStr := projectRigSummary()
+	if rigStr == "" {
+		rigStr = "(config)"
+	}
+	if s.cursorRow == settingsRowLaunch {
+		out.WriteString(fmt.Sprintf("Launch Quant: [%-30s]\n", launchStr))
+	} else {
+		out.WriteString(fmt.Sprintf("Launch Quant:  %-30s\n", launchStr))
+	}
+	if s.cursorRow == settingsRowRig {
+		out.WriteString(fmt.Sprintf("Project Rig: [%-30s]\n", rigStr))
+		out.WriteString("  enter pins the output port and controller profiles in use to this project (again: back to the config's)\n")
+	} else {
+		out.WriteString(fmt.Sprintf("Project Rig:  %-30s\n", rigStr))
+	}
+
 	// MIDI Inputs section
 	out.WriteString("\nMIDI Inputs")
 	if len(s.midiInputs) == 0 {
-		out.WriteString("  (press r to scan)")
+		out.WriteString("  (scanning... r rescans now)")
 	}
 	out.WriteString("\n")
 	out.WriteString("─────────────────────────────────────────────────\n")
@@ -215,15 +371,18 @@ func (s *SettingsDevice) View() string {
 			{Keys: []widgets.KeyBinding{
 				{Key: "h / l", Desc: "move between columns"},
 				{Key: "j / k", Desc: "move between tracks"},
-				{Key: "enter", Desc: "edit selected cell"},
+				{Key: "enter", Desc: "edit selected cell (latency: run loopback test, launch: next mode; output, rec from and note input open routing)"},
+				{Key: "[ / ]", Desc: "latency -/+ 1ms, transpose -/+ 1, fader cc -/+ 1, LED brightness -/+ 10%, LED theme, launch quantization"},
+				{Key: "{ / }", Desc: "transpose -/+ octave (enter resets)"},
 				{Key: "r", Desc: "rescan MIDI devices"},
+				{Key: "T", Desc: "test the controller's LEDs"},
 			}},
 		}))
 	}
 
 	// Launchpad
 	out.WriteString("\n\n")
-	out.WriteString(s.renderLaunchpadHelp())
+	out.WriteString(widgets.RenderLayout(s.HelpLayout()))
 
 	return out.String()
 }
@@ -243,14 +402,12 @@ func (s *SettingsDevice) renderPopup() string {
 		title = "Device Type"
 	case PopupChannel:
 		title = "MIDI Channel"
-	case PopupOutput:
-		title = "MIDI Output"
 	case PopupKit:
 		title = "Drum Kit"
+	case PopupProfile:
+		title = "Output Profile"
 	case PopupConfirm:
 		title = "Confirm"
-	case PopupNoteInput:
-		title = "Note Input"
 	}
 
 	// Top border
@@ -346,11 +503,11 @@ func (s *SettingsDevice) HandleKey(key string) {
 			s.cursorCol--
 		}
 	case "l", "right":
-		if s.cursorRow < 8 && s.cursorCol < 3 {
+		if s.cursorRow < 8 && s.cursorCol < 9 {
 			s.cursorCol++
 		}
 	case "j", "down":
-		if s.cursorRow < 8 {
+		if s.cursorRow < settingsRowRig {
 			s.cursorRow++
 		}
 	case "k", "up":
@@ -358,28 +515,132 @@ func (s *SettingsDevice) HandleKey(key string) {
 			s.cursorRow--
 		}
 	case "enter", " ":
+		if s.cursorRow == settingsRowBrightness {
+			return
+		}
+		if s.cursorRow == settingsRowTheme {
+			s.cycleLEDTheme(1)
+			return
+		}
+		if s.cursorRow == settingsRowLaunch {
+			s.manager.cycleLaunchBars(1)
+			return
+		}
+		if s.cursorRow == settingsRowRig {
+			s.manager.toggleProjectRig()
+			return
+		}
+		if s.cursorRow < 8 && s.cursorCol == 5 {
+			s.requestLatencyTest()
+			return
+		}
+		if s.cursorRow < 8 && s.cursorCol == 7 {
+			S.Tracks[s.cursorRow].Transpose = 0
+			return
+		}
+		if s.cursorRow < 8 && s.cursorCol == 8 {
+			S.CycleLaunchMode(s.cursorRow)
+			return
+		}
+		if s.cursorRow < 8 && s.cursorCol == 9 {
+			S.Tracks[s.cursorRow].SendCC = 0
+			return
+		}
 		s.openPopupForCurrentCell()
+	case "[":
+		s.nudgeLEDLook(-1)
+		if s.cursorRow < 8 && s.cursorCol == 5 && S.Tracks[s.cursorRow].LatencyMs > 0 {
+			S.Tracks[s.cursorRow].LatencyMs--
+		}
+		if s.cursorRow < 8 && s.cursorCol == 7 {
+			S.NudgeTranspose(s.cursorRow, -1)
+		}
+		if s.cursorRow < 8 && s.cursorCol == 9 {
+			S.NudgeSendCC(s.cursorRow, -1)
+		}
+	case "]":
+		s.nudgeLEDLook(1)
+		if s.cursorRow < 8 && s.cursorCol == 5 && S.Tracks[s.cursorRow].LatencyMs < maxLatencyMs {
+			S.Tracks[s.cursorRow].LatencyMs++
+		}
+		if s.cursorRow < 8 && s.cursorCol == 7 {
+			S.NudgeTranspose(s.cursorRow, 1)
+		}
+		if s.cursorRow < 8 && s.cursorCol == 9 {
+			S.NudgeSendCC(s.cursorRow, 1)
+		}
+	case "T":
+		s.manager.TestLEDs()
+	case "{":
+		if s.cursorRow < 8 && s.cursorCol == 7 {
+			S.NudgeTranspose(s.cursorRow, -transposeOctave)
+		}
+	case "}":
+		if s.cursorRow < 8 && s.cursorCol == 7 {
+			S.NudgeTranspose(s.cursorRow, transposeOctave)
+		}
 	}
 }
 
-func (s *SettingsDevice) openPopupForCurrentCell() {
-	// Note Input row (row 8)
-	if s.cursorRow == 8 {
-		options := []string{"(none)"}
-		options = append(options, s.midiInputs...)
-		selected := 0
-		// Find current port in list
-		for i, port := range s.midiInputs {
-			if port == S.NoteInputPort {
-				selected = i + 1 // +1 because "(none)" is at index 0
-				break
-			}
-		}
-		s.popup = &PopupState{
-			Type:     PopupNoteInput,
-			Options:  options,
-			Selected: selected,
+// Settings rows below the tracks
+const (
+	settingsRowNoteInput  = 8
+	settingsRowBrightness = 9
+	settingsRowTheme      = 10
+	settingsRowLaunch     = 11
+	settingsRowRig        = 12
+)
+
+// nudgeLEDLook changes brightness, theme or launch quantization when the cursor is on
+// their row
+func (s *SettingsDevice) nudgeLEDLook(dir int) {
+	switch s.cursorRow {
+	case settingsRowBrightness:
+		s.manager.SetLEDBrightness(s.manager.LEDBrightness() + dir*ledBrightnessStep)
+		s.LEDSettingsChanged = true
+	case settingsRowTheme:
+		s.cycleLEDTheme(dir)
+	case settingsRowLaunch:
+		s.manager.cycleLaunchBars(dir)
+	}
+}
+
+// cycleLEDTheme steps to the next or previous LED theme
+func (s *SettingsDevice) cycleLEDTheme(dir int) {
+	themes := s.manager.LEDThemes()
+	idx := 0
+	for i, name := range themes {
+		if name == s.manager.LEDTheme() {
+			idx = i
 		}
+	}
+	idx = (idx + dir + len(themes)) % len(themes)
+	s.manager.SetLEDTheme(themes[idx])
+	s.LEDSettingsChanged = true
+}
+
+// maxLatencyMs caps compensation (a track's look-ahead grows by its latency, so any
+// amount is sent on time)
+const maxLatencyMs = 200
+
+// requestLatencyTest asks the TUI to run a loopback test on the cursor track's port
+func (s *SettingsDevice) requestLatencyTest() {
+	if s.latencyTesting {
+		return
+	}
+	port := s.manager.TrackPort(s.cursorRow)
+	if port == "" {
+		s.latencyResults["(no output)"] = "set an output port first"
+		return
+	}
+	s.latencyTesting = true
+	s.LatencyTestTrack = s.cursorRow
+}
+
+func (s *SettingsDevice) openPopupForCurrentCell() {
+	// Note Input row (row 8) - picked in the routing matrix
+	if s.cursorRow == settingsRowNoteInput {
+		s.manager.FocusRoutingAt(routingKeyboardRow, false)
 		return
 	}
 
@@ -406,23 +667,8 @@ func (s *SettingsDevice) openPopupForCurrentCell() {
 		if s.popup.Selected < 0 {
 			s.popup.Selected = 0
 		}
-	case 2: // Output
-		options := []string{"(default)"}
-		options = append(options, s.midiOutputs...)
-		selected := 0
-		// Find current port in list
-		for i, port := range s.midiOutputs {
-			if port == S.Tracks[s.cursorRow].PortName {
-				selected = i + 1 // +1 because "(default)" is at index 0
-				break
-			}
-		}
-		s.popup = &PopupState{
-			Type:       PopupOutput,
-			Options:    options,
-			Selected:   selected,
-			TrackIndex: s.cursorRow,
-		}
+	case 2: // Output - routed in the matrix
+		s.manager.FocusRoutingAt(s.cursorRow, true)
 	case 3: // Kit (only for drum devices)
 		ts := S.Tracks[s.cursorRow]
 		if ts.Type != DeviceTypeDrum {
@@ -444,6 +690,25 @@ func (s *SettingsDevice) openPopupForCurrentCell() {
 			Selected:   selected,
 			TrackIndex: s.cursorRow,
 		}
+	case 4: // Output profile
+		ts := S.Tracks[s.cursorRow]
+		profileNames := ProfileNames()
+		options := make([]string, len(profileNames))
+		selected := 0
+		for i, name := range profileNames {
+			options[i] = GetProfile(name).Name
+			if name == ts.Profile || (ts.Profile == "" && name == DefaultProfile) {
+				selected = i
+			}
+		}
+		s.popup = &PopupState{
+			Type:       PopupProfile,
+			Options:    options,
+			Selected:   selected,
+			TrackIndex: s.cursorRow,
+		}
+	case 6: // Record from (resample another track's output) - routed in the matrix
+		s.manager.FocusRoutingAt(s.cursorRow, false)
 	}
 }
 
@@ -492,6 +757,7 @@ func (s *SettingsDevice) confirmPopupSelection() {
 	case PopupConfirm:
 		if s.popup.Selected == 0 {
 			// User confirmed
+			autoBackup("device-change")
 			s.changeDeviceType(s.popup.TrackIndex, s.popup.PendingType)
 		}
 
@@ -499,14 +765,6 @@ func (s *SettingsDevice) confirmPopupSelection() {
 		ts := S.Tracks[s.popup.TrackIndex]
 		ts.Channel = uint8(s.popup.Selected + 1)
 
-	case PopupOutput:
-		ts := S.Tracks[s.popup.TrackIndex]
-		if s.popup.Selected == 0 {
-			ts.PortName = "" // default
-		} else {
-			ts.PortName = s.midiOutputs[s.popup.Selected-1]
-		}
-
 	case PopupKit:
 		ts := S.Tracks[s.popup.TrackIndex]
 		kitNames := KitNames()
@@ -514,16 +772,13 @@ func (s *SettingsDevice) confirmPopupSelection() {
 			ts.Kit = kitNames[s.popup.Selected]
 		}
 
-	case PopupNoteInput:
-		var portName string
-		if s.popup.Selected == 0 {
-			portName = "" // none
-		} else {
-			portName = s.midiInputs[s.popup.Selected-1]
+	case PopupProfile:
+		ts := S.Tracks[s.popup.TrackIndex]
+		profileNames := ProfileNames()
+		if s.popup.Selected >= 0 && s.popup.Selected < len(profileNames) {
+			ts.Profile = profileNames[s.popup.Selected]
 		}
-		S.NoteInputPort = portName
-		// Signal TUI to connect (TUI checks this flag after HandleKey)
-		s.NoteInputChanged = true
+
 	}
 
 	s.popup = nil
@@ -543,54 +798,47 @@ func (s *SettingsDevice) optionToDeviceType(opt string) DeviceType {
 }
 
 func (s *SettingsDevice) changeDeviceType(trackIdx int, deviceType DeviceType) {
-	var dev Device
-	switch deviceType {
-	case DeviceTypeDrum:
-		dev = s.manager.CreateDrumDevice(trackIdx)
-	case DeviceTypePiano:
-		dev = s.manager.CreatePianoDevice(trackIdx)
-	case DeviceTypeMetropolix:
-		dev = s.manager.CreateMetropolixDevice(trackIdx)
-	case DeviceTypeNone:
-		dev = s.manager.CreateEmptyDevice(trackIdx)
-	}
-	s.manager.SetDevice(trackIdx, dev)
+	s.manager.ChangeDeviceType(trackIdx, deviceType)
 }
 
-func (s *SettingsDevice) HandlePad(row, col int) {
+func (s *SettingsDevice) HandlePadRelease(row, col int) {}
+func (s *SettingsDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (s *SettingsDevice) HandlePad(row, col int, velocity uint8) {
 	// Could use pads to select tracks
 	if col == 0 && row < 8 {
 		s.cursorRow = 7 - row
 	}
 }
 
-func (s *SettingsDevice) renderLaunchpadHelp() string {
+// HelpLayout describes the settings Launchpad page (left column selects tracks, T1 at top)
+func (s *SettingsDevice) HelpLayout() widgets.LaunchpadLayout {
 	trackColor := [3]uint8{100, 100, 200}
 	dimColor := [3]uint8{30, 30, 50}
 
-	var grid [8][8][3]uint8
-	topRow := make([][3]uint8, 8)
+	var l widgets.LaunchpadLayout
 
 	// All dim by default
 	for i := 0; i < 8; i++ {
-		topRow[i] = dimColor
+		l.TopRow[i] = widgets.Pad{Color: dimColor}
 	}
 	for row := 0; row < 8; row++ {
 		for col := 0; col < 8; col++ {
-			grid[row][col] = dimColor
+			l.Grid[row][col] = widgets.Pad{Color: dimColor}
 		}
 	}
 
-	// Left column shows tracks
+	// Left column selects tracks (pad row r = track 8-r, matching HandlePad)
 	for row := 0; row < 8; row++ {
-		if S.Tracks[row].Type != DeviceTypeNone {
-			grid[row][0] = trackColor
+		track := 7 - row
+		l.Grid[row][0].Tooltip = fmt.Sprintf("select track %d", track+1)
+		if S.Tracks[track].Type != DeviceTypeNone {
+			l.Grid[row][0].Color = trackColor
 		}
 	}
 
-	out := widgets.RenderPadRow(topRow) + "\n"
-	out += widgets.RenderPadGrid(grid, nil) + "\n\n"
-	out += widgets.RenderLegendItem(trackColor, "Tracks", "select track to configure")
-
-	return out
+	l.Legend = []widgets.LegendItem{
+		{Color: trackColor, Name: "Tracks", Desc: "select track to configure"},
+	}
+	return l
 }
@@ -0,0 +1,227 @@
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Snapshots - eight quick slots holding what each track plays and its mute/solo (not
+// the pattern data), saved with the project but separate from file saves. Recalling
+// one launches its clips together, stops the tracks it had stopped, and sets the
+// mutes and solos on the spot. From the session press `w` for the slots; a macro pad
+// or keyboard hotkey ("snap") recalls one.
+
+// NumSnapshots is how many snapshot slots a project has
+const NumSnapshots = 8
+
+// maxSnapshotName keeps names short enough for the slot list
+const maxSnapshotName = 16
+
+// Snapshot is a captured performance state
+type Snapshot struct {
+	Slot     int     `json:"slot"`
+	Name     string  `json:"name,omitempty"`
+	Patterns [8]int  `json:"patterns"` // playing pattern per track (-1 = stopped or empty)
+	Muted    [8]bool `json:"muted"`
+	Solo     [8]bool `json:"solo"`
+}
+
+// Snapshot returns the snapshot in a slot (false if the slot is empty)
+func (s *State) Snapshot(slot int) (Snapshot, bool) {
+	for _, snap := range s.Snapshots {
+		if snap.Slot == slot {
+			return snap, true
+		}
+	}
+	return Snapshot{}, false
+}
+
+// setSnapshot stores a snapshot in its slot, replacing what was there
+func (s *State) setSnapshot(snap Snapshot) {
+	s.clearSnapshot(snap.Slot)
+	s.Snapshots = append(s.Snapshots, snap)
+}
+
+// clearSnapshot empties a slot
+func (s *State) clearSnapshot(slot int) {
+	kept := s.Snapshots[:0]
+	for _, snap := range s.Snapshots {
+		if snap.Slot != slot {
+			kept = append(kept, snap)
+		}
+	}
+	s.Snapshots = kept
+}
+
+// snapshotLabel names a slot ("snap 3" when it has no name of its own)
+func snapshotLabel(slot int) string {
+	if snap, ok := S.Snapshot(slot); ok && snap.Name != "" {
+		return snap.Name
+	}
+	return fmt.Sprintf("snap %d", slot+1)
+}
+
+// CaptureSnapshot stores what every track is playing and its mute/solo in a slot
+// (keeping the slot's name)
+func (m *Manager) CaptureSnapshot(slot int) {
+	if slot < 0 || slot >= NumSnapshots {
+		return
+	}
+	snap, _ := S.Snapshot(slot)
+	snap.Slot = slot
+	for i := 0; i < 8; i++ {
+		snap.Patterns[i] = -1
+		if dev := m.GetDevice(i); dev != nil && S.Tracks[i].Type != DeviceTypeNone && !m.ClipStopped(i) {
+			snap.Patterns[i] = dev.CurrentPattern()
+		}
+		snap.Muted[i] = S.Tracks[i].Muted
+		snap.Solo[i] = S.Tracks[i].Solo
+	}
+	S.setSnapshot(snap)
+	m.announce("%s captured", snapshotLabel(slot))
+}
+
+// RecallSnapshot launches a snapshot's clips together, stops the tracks it had stopped
+// and applies its mutes and solos
+func (m *Manager) RecallSnapshot(slot int) {
+	snap, ok := S.Snapshot(slot)
+	if !ok {
+		m.announce("%s is empty", snapshotLabel(slot))
+		return
+	}
+
+	launch := [8]int{-1, -1, -1, -1, -1, -1, -1, -1}
+	for i, p := range snap.Patterns {
+		dev := m.GetDevice(i)
+		if dev == nil || S.Tracks[i].Type == DeviceTypeNone {
+			continue
+		}
+		switch {
+		case p < 0 && !m.ClipStopped(i):
+			m.StopClip(i)
+		case p >= 0 && (p != dev.CurrentPattern() || m.ClipStopped(i)):
+			launch[i] = p
+		}
+	}
+	if launch != [8]int{-1, -1, -1, -1, -1, -1, -1, -1} {
+		m.LaunchClips(launch)
+	}
+	m.setMuteSolo(snap.Muted, snap.Solo)
+	m.announce("%s recalled", snapshotLabel(slot))
+}
+
+// setMuteSolo sets every track's mute and solo, ending notes on tracks that go quiet
+func (m *Manager) setMuteSolo(muted, solo [8]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var audible [8]bool
+	for i := range audible {
+		audible[i] = trackAudible(i)
+	}
+	for i, ts := range S.Tracks {
+		ts.Muted, ts.Solo = muted[i], solo[i]
+	}
+	for i, was := range audible {
+		if was && !trackAudible(i) {
+			m.flushNotes(i)
+		}
+	}
+}
+
+// --- Session snapshot slots ---
+
+// handleSnapshotKey processes keys while the session shows the snapshot slots
+func (s *SessionDevice) handleSnapshotKey(key string) {
+	if s.snapNaming {
+		s.handleSnapshotNameKey(key)
+		return
+	}
+	switch key {
+	case "1", "2", "3", "4", "5", "6", "7", "8":
+		s.snapCursor = int(key[0] - '1')
+		s.manager.RecallSnapshot(s.snapCursor)
+	case "j", "down":
+		s.snapCursor = min(s.snapCursor+1, NumSnapshots-1)
+	case "k", "up":
+		s.snapCursor = max(s.snapCursor-1, 0)
+	case "enter", " ":
+		s.manager.RecallSnapshot(s.snapCursor)
+	case "c":
+		s.manager.CaptureSnapshot(s.snapCursor)
+	case "n":
+		if snap, ok := S.Snapshot(s.snapCursor); ok {
+			s.snapNaming = true
+			s.nameBuffer = snap.Name
+		}
+	case "x":
+		S.clearSnapshot(s.snapCursor)
+		s.manager.announce("snap %d cleared", s.snapCursor+1)
+	case "w", "esc", "q":
+		s.snapMode = false
+	}
+}
+
+// handleSnapshotNameKey processes keys while naming the selected snapshot
+func (s *SessionDevice) handleSnapshotNameKey(key string) {
+	switch key {
+	case "enter":
+		if snap, ok := S.Snapshot(s.snapCursor); ok {
+			snap.Name = strings.TrimSpace(s.nameBuffer)
+			S.setSnapshot(snap)
+		}
+		s.snapNaming = false
+		s.nameBuffer = ""
+	case "esc":
+		s.snapNaming = false
+		s.nameBuffer = ""
+	case "backspace":
+		if len(s.nameBuffer) > 0 {
+			s.nameBuffer = s.nameBuffer[:len(s.nameBuffer)-1]
+		}
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 && len(s.nameBuffer) < maxSnapshotName {
+			s.nameBuffer += key
+		}
+	}
+}
+
+// snapshotView lists the snapshot slots
+func (s *SessionDevice) snapshotView() string {
+	var out strings.Builder
+	out.WriteString("\nSnapshots  1-8 recall  j/k select  c capture  n name  x clear  w/esc back\n")
+	for slot := 0; slot < NumSnapshots; slot++ {
+		prefix := "  "
+		if slot == s.snapCursor {
+			prefix = "> "
+		}
+		snap, ok := S.Snapshot(slot)
+		if !ok {
+			out.WriteString(fmt.Sprintf("%s%d  (empty)\n", prefix, slot+1))
+			continue
+		}
+		name := snapshotLabel(slot)
+		if s.snapNaming && slot == s.snapCursor {
+			name = s.nameBuffer + "_"
+		}
+		var tracks []string
+		for i, p := range snap.Patterns {
+			cell := "-"
+			if p >= 0 {
+				cell = fmt.Sprint(p + 1)
+			}
+			switch {
+			case snap.Muted[i]:
+				cell += "m"
+			case snap.Solo[i]:
+				cell += "s"
+			}
+			tracks = append(tracks, fmt.Sprintf("%-4s", cell))
+		}
+		out.WriteString(fmt.Sprintf("%s%d  %-16s %s\n", prefix, slot+1, name, strings.Join(tracks, "")))
+	}
+	if s.snapNaming {
+		out.WriteString("[enter] confirm (empty = no name)  [esc] cancel\n")
+	}
+	return out.String()
+}
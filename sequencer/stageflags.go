@@ -0,0 +1,95 @@
+package sequencer
+
+import "go-sequence/midi"
+
+// Stage skip and hold - a skipped stage drops out of the cycle (and the faux pattern
+// length); a held stage plays one note tied across all its pulses, ignoring ratchets
+// and gate length. Both are set from the Gate page's second sub-page.
+
+// Gate sub-pages (navigated with the top row up/down arrows)
+const (
+	GateSubLength = 0 // gate length, gate on/off, slide
+	GateSubFlags  = 1 // skip, hold
+)
+
+// skipped reports whether a stage is left out of the cycle. Skips are ignored when
+// every active stage is skipped, so a pattern can't fall silent by accident.
+func (pat *MetropolixPatternState) skipped(stage int) bool {
+	if !pat.Stages[stage].Skip {
+		return false
+	}
+	for i := 0; i < pat.Length; i++ {
+		if !pat.Stages[i].Skip {
+			return true
+		}
+	}
+	return false
+}
+
+// activeStages returns the stages that play, in order
+func (pat *MetropolixPatternState) activeStages() []int {
+	var active []int
+	for i := 0; i < pat.Length; i++ {
+		if !pat.skipped(i) {
+			active = append(active, i)
+		}
+	}
+	return active
+}
+
+// renderStageFlagsPage shows skip (row 1) and hold (row 0) per stage
+func (d *MetropolixDevice) renderStageFlagsPage() []LEDState {
+	pat := &d.state.Patterns[d.state.Editing]
+
+	skipOnColor := [3]uint8{255, 30, 30}
+	skipOffColor := [3]uint8{50, 15, 15}
+	holdOnColor := [3]uint8{255, 200, 0}
+	holdOffColor := [3]uint8{50, 40, 0}
+	offColor := [3]uint8{0, 0, 0}
+
+	var leds []LEDState
+	for col := 0; col < 8; col++ {
+		stage := &pat.Stages[col]
+		for row := 0; row < 8; row++ {
+			color := offColor
+			if col < pat.Length {
+				switch {
+				case row == 1 && stage.Skip:
+					color = skipOnColor
+				case row == 1:
+					color = skipOffColor
+				case row == 0 && stage.Hold:
+					color = holdOnColor
+				case row == 0:
+					color = holdOffColor
+				}
+			}
+			leds = append(leds, LEDState{Row: row, Col: col, Color: color, Channel: midi.ChannelStatic})
+		}
+	}
+	return leds
+}
+
+// subPageArrows lights the top row up/down arrows (cols 1-2) for a page with sub-pages,
+// bright where there's a sub-page to move to
+func subPageArrows(sub, last int) []LEDState {
+	activeColor := [3]uint8{255, 100, 50}
+	dimColor := [3]uint8{50, 30, 20}
+	offColor := [3]uint8{0, 0, 0}
+
+	upColor, downColor := dimColor, dimColor
+	if sub > 0 {
+		upColor = activeColor
+	}
+	if sub < last {
+		downColor = activeColor
+	}
+	leds := []LEDState{
+		{Row: 8, Col: 1, Color: upColor, Channel: midi.ChannelStatic},
+		{Row: 8, Col: 2, Color: downColor, Channel: midi.ChannelStatic},
+	}
+	for _, col := range []int{0, 3} {
+		leds = append(leds, LEDState{Row: 8, Col: col, Color: offColor, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
@@ -0,0 +1,138 @@
+package sequencer
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Stage loop - a performance control that temporarily narrows the cycle to a range of
+// stages (an A/B sub-loop). Hold the loop pad (top row, col 4) and press two stage
+// columns to set it; tap the pad alone to clear it. Changes land at the end of the
+// current cycle, like a pattern switch. The loop isn't saved with the project.
+
+// loopPadCol is the top row pad that sets and clears the stage loop
+const loopPadCol = 4
+
+// cycleStages returns the stages that play in a cycle: the active stages inside the
+// loop, or every active stage when there's no loop (or nothing plays inside it)
+func (d *MetropolixDevice) cycleStages(patternNum int) []int {
+	active := d.state.Patterns[patternNum].activeStages()
+	if !d.looping {
+		return active
+	}
+	var looped []int
+	for _, i := range active {
+		if i >= d.loopStart && i <= d.loopEnd {
+			looped = append(looped, i)
+		}
+	}
+	if len(looped) == 0 {
+		return active
+	}
+	return looped
+}
+
+// stagePlays reports whether a stage is part of the playing pattern's cycle
+func (d *MetropolixDevice) stagePlays(stage int) bool {
+	return slices.Contains(d.cycleStages(d.state.Pattern), stage)
+}
+
+// setLoop loops the stages between a and b (either order)
+func (d *MetropolixDevice) setLoop(a, b int) {
+	cycleTicks := d.fauxPatternTicks(d.state.Pattern)
+	d.loopStart, d.loopEnd = min(a, b), max(a, b)
+	d.looping = true
+	d.requeueLoop(cycleTicks)
+}
+
+// clearLoop returns to the full cycle
+func (d *MetropolixDevice) clearLoop() {
+	d.loopAnchor = -1
+	if !d.looping {
+		return
+	}
+	cycleTicks := d.fauxPatternTicks(d.state.Pattern)
+	d.looping = false
+	d.requeueLoop(cycleTicks)
+}
+
+// markLoop sets a loop point at a stage - the first mark anchors, the second closes
+// the loop. Marking while a loop is active clears it.
+func (d *MetropolixDevice) markLoop(stage int) {
+	switch {
+	case d.looping && d.loopAnchor < 0:
+		d.clearLoop()
+	case d.loopAnchor < 0:
+		d.loopAnchor = stage
+	default:
+		d.setLoop(d.loopAnchor, stage)
+		d.loopAnchor = -1
+	}
+}
+
+// pressLoopPad starts a loop gesture (stage pads pressed while held set the loop)
+func (d *MetropolixDevice) pressLoopPad() {
+	d.loopHeld = true
+	d.loopAnchor = -1
+	d.loopSet = false
+}
+
+// releaseLoopPad ends the gesture - one stage pressed loops that stage, none clears
+func (d *MetropolixDevice) releaseLoopPad() {
+	d.loopHeld = false
+	switch {
+	case d.loopAnchor >= 0:
+		d.setLoop(d.loopAnchor, d.loopAnchor)
+	case !d.loopSet:
+		d.clearLoop()
+	}
+	d.loopAnchor = -1
+}
+
+// loopPadStage handles a stage pad pressed while the loop pad is held
+func (d *MetropolixDevice) loopPadStage(col int) {
+	if d.loopAnchor < 0 {
+		d.loopAnchor = col
+		return
+	}
+	d.setLoop(d.loopAnchor, col)
+	d.loopAnchor = -1
+	d.loopSet = true
+}
+
+// requeueLoop drops queued events past the end of the current cycle so the next cycle
+// is generated with the new loop (the same boundary QueuePattern switches at).
+// cycleTicks is the length of the cycle playing now - read it before changing the loop
+func (d *MetropolixDevice) requeueLoop(cycleTicks int64) {
+	if !S.Playing {
+		return
+	}
+
+	d.queueMu.Lock()
+	boundaryTick := boundaryAfter(S.Tick, d.patternStartTick, cycleTicks)
+	needsNotify := false
+	if d.queuedUntilTick >= boundaryTick {
+		d.queue = cutEvents(d.queue, boundaryTick)
+		d.queuedUntilTick = boundaryTick
+		// The cycle length changes here, so later boundaries count from this one
+		d.patternStartTick = boundaryTick
+		needsNotify = true
+	}
+	d.queueMu.Unlock()
+
+	if needsNotify && d.onQueueChange != nil {
+		d.onQueueChange()
+	}
+}
+
+// loopInfo describes the stage loop for the view
+func (d *MetropolixDevice) loopInfo() string {
+	info := "off"
+	if d.looping {
+		info = fmt.Sprintf("%d-%d", d.loopStart+1, d.loopEnd+1)
+	}
+	if d.loopAnchor >= 0 {
+		info += fmt.Sprintf(" (from %d…)", d.loopAnchor+1)
+	}
+	return info
+}
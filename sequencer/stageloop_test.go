@@ -0,0 +1,41 @@
+package sequencer
+
+import "testing"
+
+func TestRequeueLoopKeepsCycleEnd(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name   string
+		before func(d *MetropolixDevice) // while stopped
+		change func(d *MetropolixDevice)
+	}{
+		{"narrow", func(d *MetropolixDevice) {}, func(d *MetropolixDevice) { d.setLoop(1, 2) }},
+		{"widen", func(d *MetropolixDevice) { d.setLoop(1, 2) }, func(d *MetropolixDevice) { d.clearLoop() }},
+		{"move", func(d *MetropolixDevice) { d.setLoop(0, 0) }, func(d *MetropolixDevice) { d.setLoop(2, 6) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			d := NewMetropolixDevice(NewMetropolixState())
+			tt.before(d)
+			cycle := d.fauxPatternTicks(0)
+
+			S.Playing = true
+			S.Tick = cycle + cycle/3 // a third into the second cycle
+			d.FillUntil(4 * cycle)
+			tt.change(d)
+
+			if d.patternStartTick != 2*cycle || d.queuedUntilTick != 2*cycle {
+				t.Errorf("new loop starts at %d (queued to %d), want the current cycle's end %d",
+					d.patternStartTick, d.queuedUntilTick, 2*cycle)
+			}
+			for _, e := range d.queue {
+				if e.Tick > 2*cycle {
+					t.Fatalf("event at %d survived past the cycle end %d", e.Tick, 2*cycle)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package sequencer
+
+import (
+	"slices"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+)
+
+// Start/Stop out - a track can send MIDI Start and Stop to its port, so a hardware drum
+// machine running its own patterns starts in phase with the clips. Start goes out
+// when the transport starts and again on the boundary every launched clip begins at
+// (restarting the machine's pattern with it); Stop goes out with the transport, and on
+// the bar a stopped clip goes silent. Both are sent early by the track's latency
+// compensation like its notes.
+
+// transportEvent is a Start or Stop waiting for its tick
+type transportEvent struct {
+	tick    int64
+	port    string
+	latency time.Duration
+	start   bool
+}
+
+// ToggleStartStop switches a track's Start/Stop out on or off
+func (m *Manager) ToggleStartStop(trackIdx int) {
+	if trackIdx < 0 || trackIdx >= 8 {
+		return
+	}
+	ts := S.Tracks[trackIdx]
+	ts.StartStop = !ts.StartStop
+	state := "off"
+	if ts.StartStop {
+		state = "on"
+	}
+	m.announce("track %d start/stop out %s", trackIdx+1, state)
+}
+
+// scheduleTransport queues a Start or Stop on a track's port at a tick, replacing
+// anything still pending for the port (the latest launch or stop wins)
+func (m *Manager) scheduleTransport(trackIdx int, tick int64, start bool) {
+	ts := S.Tracks[trackIdx]
+	if !ts.StartStop {
+		return
+	}
+	evt := transportEvent{tick: tick, port: m.TrackPort(trackIdx), latency: ts.Latency(), start: start}
+
+	m.transportMu.Lock()
+	m.transport = slices.DeleteFunc(m.transport, func(e transportEvent) bool { return e.port == evt.port })
+	m.transport = append(m.transport, evt)
+	m.transportMu.Unlock()
+
+	select {
+	case m.transportWake <- struct{}{}:
+	default:
+	}
+}
+
+// startTransport schedules Start for every Start/Stop track that will play when the
+// transport starts at a tick - at once from the top, or at the track's next boundary
+// when joining mid-stream
+func (m *Manager) startTransport(tick int64) {
+	for i, dev := range m.devices {
+		if dev == nil || !S.Tracks[i].StartStop || m.clipStops[i].silentAt(tick) {
+			continue
+		}
+		at := tick
+		if bd, ok := dev.(boundaryDevice); ok && tick > 0 {
+			at = bd.nextBoundary(tick)
+		}
+		m.scheduleTransport(i, at, true)
+	}
+}
+
+// stopTransport drops pending Starts and sends Stop to every Start/Stop track's port
+// now (hold m.mu)
+func (m *Manager) stopTransport() {
+	m.transportMu.Lock()
+	m.transport = nil
+	m.transportMu.Unlock()
+
+	sent := make(map[string]bool)
+	for i := range m.devices {
+		port := m.TrackPort(i)
+		if !S.Tracks[i].StartStop || sent[port] {
+			continue
+		}
+		sent[port] = true
+		if sender := m.getSender(port); sender != nil {
+			sender(gomidi.Stop())
+		}
+	}
+}
+
+// nextTransportTime returns when the earliest pending Start/Stop is due
+func (m *Manager) nextTransportTime() (time.Time, bool) {
+	m.transportMu.Lock()
+	pending := slices.Clone(m.transport)
+	m.transportMu.Unlock()
+	if len(pending) == 0 {
+		return time.Time{}, false
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var next time.Time
+	for i, evt := range pending {
+		at := S.TickToTime(evt.tick).Add(-evt.latency)
+		if i == 0 || at.Before(next) {
+			next = at
+		}
+	}
+	return next, true
+}
+
+// sendDueTransport sends the pending Starts and Stops whose time has come
+func (m *Manager) sendDueTransport() {
+	now := time.Now()
+	m.mu.RLock()
+	m.transportMu.Lock()
+	var due []transportEvent
+	m.transport = slices.DeleteFunc(m.transport, func(evt transportEvent) bool {
+		if S.TickToTime(evt.tick).Add(-evt.latency).After(now) {
+			return false
+		}
+		due = append(due, evt)
+		return true
+	})
+	m.transportMu.Unlock()
+	m.mu.RUnlock()
+
+	for _, evt := range due {
+		sender := m.getSender(evt.port)
+		if sender == nil {
+			continue
+		}
+		if evt.start {
+			sender(gomidi.Start())
+		} else {
+			sender(gomidi.Stop())
+		}
+	}
+}
+
+// waitUntil sleeps until a time, returning early when a Start/Stop is scheduled (so
+// the dispatch loop can look again) and false when the manager shuts down
+func (m *Manager) waitUntil(at time.Time) bool {
+	return m.waitPrecise(at) != waitStopped
+}
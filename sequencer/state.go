@@ -1,6 +1,10 @@
 package sequencer
 
-import "time"
+import (
+	"crypto/sha256"
+	"fmt"
+	"time"
+)
 
 // Timing constants
 const (
@@ -16,26 +20,54 @@ func init() {
 
 // State is the single source of truth for all application state
 type State struct {
-	Tempo         int            `json:"tempo"`
-	Tracks        [8]*TrackState `json:"tracks"`
-	NoteInputPort string         `json:"noteInputPort,omitempty"` // MIDI keyboard input
-	ProjectName   string         `json:"-"`                       // runtime only - current project name
+	Tempo         int               `json:"tempo"`
+	Tracks        [8]*TrackState    `json:"tracks"`
+	NoteInputPort string            `json:"noteInputPort,omitempty"` // MIDI keyboard input
+	ProjectName   string            `json:"-"`                       // runtime only - current project name
+	Thumbnail     []string          `json:"thumbnail,omitempty"`     // session snapshot at save time (see Manager.Save)
+	Macros        []MacroBinding    `json:"macros,omitempty"`        // macro pad bank bindings
+	UserScales    []UserScale       `json:"userScales,omitempty"`    // custom scales, selectable after the built-ins
+	CCMaps        []CCMapping       `json:"ccMaps,omitempty"`        // MIDI learn: incoming CCs mapped to targets
+	InputCurves   []InputCurve      `json:"inputCurves,omitempty"`   // velocity curves per input port (see velcurve.go)
+	Overrides     *ProjectOverrides `json:"overrides,omitempty"`     // rig settings used in place of the config's (see overrides.go)
+	Snapshots     []Snapshot        `json:"snapshots,omitempty"`     // playing clips and mutes in quick slots (see snapshots.go)
+	Exploded      bool              `json:"exploded,omitempty"`      // saves rewrite a tree of small files for version control (see exploded.go)
+
+	// Generative set mode - tracks relaunch random clips every GenerativeBars bars
+	Generative     bool `json:"generative,omitempty"`
+	GenerativeBars int  `json:"generativeBars,omitempty"` // 0 = DefaultGenerativeBars
 
 	// Runtime timing state (not persisted)
 	Playing bool      `json:"-"` // true when playback is active
 	T0      time.Time `json:"-"` // wall-clock reference when play started
 	Tick    int64     `json:"-"` // current global tick position
+
+	// Unsaved changes (not persisted, see dirty.go)
+	Dirty    bool              `json:"-"` // project changed since the last save or load
+	savedSum [sha256.Size]byte // settings fingerprint at the last save or load
 }
 
 // TrackState holds all state for a single track
 type TrackState struct {
-	Name     string     `json:"name"`
-	Channel  uint8      `json:"channel"`
-	Muted    bool       `json:"muted"`
-	Solo     bool       `json:"solo"`
-	PortName string     `json:"portName,omitempty"`
-	Type     DeviceType `json:"type"`
-	Kit      string     `json:"kit,omitempty"` // drum kit mapping ("gm", "rd8", etc.)
+	Name       string     `json:"name"`
+	Channel    uint8      `json:"channel"`
+	Muted      bool       `json:"muted"`
+	Solo       bool       `json:"solo"`
+	PortName   string     `json:"portName,omitempty"`
+	Type       DeviceType `json:"type"`
+	Kit        string     `json:"kit,omitempty"`        // drum kit mapping ("gm", "rd8", etc.)
+	Profile    string     `json:"profile,omitempty"`    // output profile ("midi", "cvocd", etc.)
+	LatencyMs  int        `json:"latencyMs,omitempty"`  // output latency compensation (events sent this much early)
+	Relaunch   int        `json:"relaunch,omitempty"`   // generative mode: percent chance to relaunch a clip per boundary
+	RecordFrom int        `json:"recordFrom,omitempty"` // resample: 1-based track whose output this track records (0 = keyboard only)
+	Transpose  int        `json:"transpose,omitempty"`  // semitones added to notes at dispatch (non-destructive)
+	LaunchMode int        `json:"launchMode,omitempty"` // what pressing a clip does in the session (LaunchTrigger, ...)
+	StartStop  bool       `json:"startStop,omitempty"`  // send MIDI Start/Stop to the port on launches (see startstop.go)
+	Zone       *KeyZone   `json:"zone,omitempty"`       // keyboard split filter (nil = keys while focused, see keyzones.go)
+	SendCC     int        `json:"sendCC,omitempty"`     // CC controller faders send on the track (0 = DefaultFaderCC, see faders.go)
+
+	// Clip names and colors by pattern slot (shown in the session)
+	Clips ClipLabels `json:"clips,omitempty"`
 
 	// Device-specific state (only one populated based on Type)
 	Drum       *DrumState       `json:"drum,omitempty"`
@@ -43,6 +75,11 @@ type TrackState struct {
 	Metropolix *MetropolixState `json:"metropolix,omitempty"`
 }
 
+// Latency returns the track's output latency compensation
+func (ts *TrackState) Latency() time.Duration {
+	return time.Duration(ts.LatencyMs) * time.Millisecond
+}
+
 // DrumState holds all state for a drum device
 type DrumState struct {
 	Patterns [NumPatterns]DrumPatternState `json:"patterns"`
@@ -52,19 +89,31 @@ type DrumState struct {
 	Next              int `json:"next"`
 	Step              int `json:"-"` // runtime only
 
+	// Lane names - user overrides (empty = use kit slot name)
+	LaneNames [16]string `json:"laneNames"`
+
 	// UI
 	SelectedNoteIdx   int `json:"selected"`
 	EditingPatternIdx int `json:"editing"`
 	Cursor            int `json:"cursor"`
 
+	// Humanize
+	HumanizeRange int `json:"humanizeRange"` // max velocity deviation (±)
+
+	// Randomize
+	RandomDensity int `json:"randomDensity"` // generate/mutate density (percent)
+
 	// Recording
-	Recording bool `json:"-"` // runtime only - record input to pattern
-	Preview   bool `json:"-"` // runtime only - MIDI thru
+	Recording     bool `json:"-"`             // runtime only - record input to pattern
+	Preview       bool `json:"-"`             // runtime only - MIDI thru
+	FixedVelocity bool `json:"fixedVelocity"` // record pads at DefaultVelocity instead of pad pressure
 }
 
 // DrumPatternState holds pattern data
 type DrumPatternState struct {
-	Notes [16]DrumNoteState `json:"notes"`
+	Notes   [16]DrumNoteState `json:"notes"`
+	Length  int               `json:"length"`  // master length in steps (0 = longest lane)
+	TimeSig int               `json:"timeSig"` // index into TimeSigs (0 = 4/4)
 }
 
 // DrumNoteState holds a single drum note lane (one of 16 drum sounds)
@@ -73,6 +122,9 @@ type DrumNoteState struct {
 	Length int               `json:"length"`
 }
 
+// DefaultVelocity is used for steps entered without velocity (and fixed-velocity recording)
+const DefaultVelocity = 100
+
 // DrumStepState holds a single step
 type DrumStepState struct {
 	Active   bool  `json:"active"`
@@ -100,19 +152,45 @@ type PianoState struct {
 	ViewRows    int     `json:"viewRows"`
 	EditHoriz   int     `json:"editHoriz"`
 	EditVert    int     `json:"editVert"`
+	Follow      bool    `json:"follow,omitempty"` // keep the playhead centered while playing
+	Ghost       int     `json:"ghost,omitempty"`  // 1-based piano track drawn dimmed underneath (0 = off)
 
 	// Selection
 	SelectedNote int `json:"selectedNote"`
 
+	// Quantize command settings
+	QuantizeGrid     string `json:"quantizeGrid,omitempty"`     // grid name ("" = 1/16)
+	QuantizeStrength int    `json:"quantizeStrength,omitempty"` // percent (0 = 100)
+
+	// Record settings
+	RecordQuantize string `json:"recordQuantize,omitempty"` // grid name or "off" ("" = 1/16)
+	RecordReplace  bool   `json:"recordReplace,omitempty"`  // a take replaces the pattern instead of overdubbing
+
+	// Humanize amounts (both 0 = defaults)
+	HumanizeVelocity int `json:"humanizeVelocity,omitempty"` // max velocity deviation (±)
+	HumanizeTiming   int `json:"humanizeTiming,omitempty"`   // max start shift, percent of a 1/16 step
+
 	// Recording
 	Recording bool `json:"-"` // runtime only - record input to pattern
-	Preview   bool `json:"-"` // runtime only - MIDI thru
+	Preview   bool `json:"-"` // runtime only - audition notes while editing
 }
 
 // PianoPatternState holds pattern data
 type PianoPatternState struct {
-	Notes  []NoteEventState `json:"notes"`
-	Length float64          `json:"length"`
+	Notes   []NoteEventState `json:"notes"`
+	Length  float64          `json:"length"`            // beats, in quarter-beat steps
+	TimeSig int              `json:"timeSig,omitempty"` // index into TimeSigs (0 = 4/4)
+
+	// Scale lock - pitch moves and pad entry snap to the scale (chromatic = off)
+	Scale ScaleType `json:"scale,omitempty"`
+	Root  uint8     `json:"root,omitempty"` // pitch class 0-11 (0 = C)
+
+	// Automation - CC / pitch-bend curves played with the notes
+	Automation []AutomationLane `json:"automation,omitempty"`
+
+	// Loop region - only [LoopStart, LoopEnd) plays (LoopEnd 0 = whole pattern)
+	LoopStart float64 `json:"loopStart,omitempty"`
+	LoopEnd   float64 `json:"loopEnd,omitempty"`
 }
 
 // NoteEventState holds a single note
@@ -121,6 +199,21 @@ type NoteEventState struct {
 	Duration float64 `json:"duration"`
 	Pitch    uint8   `json:"pitch"`
 	Velocity uint8   `json:"velocity"`
+	Channel  uint8   `json:"channel,omitempty"`  // 1-16 output channel override (0 = track channel)
+	Bend     int     `json:"bend,omitempty"`     // semitones the note glides to by its end (see mpe.go)
+	Pressure uint8   `json:"pressure,omitempty"` // aftertouch held through the note (0 = none)
+}
+
+// AutomationLane is a CC (or pitch-bend) curve over a piano pattern
+type AutomationLane struct {
+	CC     int               `json:"cc"` // 0-127, AutomationPitchBend or AutomationPressure
+	Points []AutomationPoint `json:"points"`
+}
+
+// AutomationPoint is a breakpoint - values are interpolated linearly between points
+type AutomationPoint struct {
+	Beat  float64 `json:"beat"`
+	Value int     `json:"value"` // 0-127 (pitch bend: 64 = center)
 }
 
 // PlaybackMode defines how the Metropolix sequences through stages
@@ -169,18 +262,23 @@ type MetropolixState struct {
 	Patterns [NumPatterns]MetropolixPatternState `json:"patterns"`
 
 	// ─────────── UI/Session ───────────
-	Editing      int `json:"editing"`      // Pattern being edited
-	Page         int `json:"page"`         // Launchpad page
-	Selected     int `json:"selected"`     // Selected stage
-	AccumSubPage int `json:"accumSubPage"` // Accum sub-page: 0=value, 1=reset, 2=mode
-	Next         int `json:"next"`         // Queued pattern (-1=none)
+	Editing         int `json:"editing"`                   // Pattern being edited
+	Page            int `json:"page"`                      // Launchpad page
+	Selected        int `json:"selected"`                  // Selected stage
+	AccumSubPage    int `json:"accumSubPage"`              // Accum sub-page: 0=value, 1=reset, 2=mode
+	GateSubPage     int `json:"gateSubPage,omitempty"`     // Gate sub-page: 0=length/gate/slide, 1=skip/hold
+	RatchetSubPage  int `json:"ratchetSubPage,omitempty"`  // Ratchets sub-page: 0=count, 1=probability, 2=shape
+	ProbSubPage     int `json:"probSubPage,omitempty"`     // Probability sub-page: 0=percent, 1=every N cycles
+	SettingsSubPage int `json:"settingsSubPage,omitempty"` // Settings sub-page: 0=settings, 1=scale picker
+	Next            int `json:"next"`                      // Queued pattern (-1=none)
 
 	// ─────────── Playback Position ───────────
-	Pattern     int `json:"pattern"`     // Playing pattern
-	Stage       int `json:"stage"`       // Current stage
-	StageStep   int `json:"stageStep"`   // Tick within stage
-	RatchetStep int `json:"ratchetStep"` // Ratchet counter
-	Direction   int `json:"direction"`   // Pendulum: +1/-1
+	Pattern     int `json:"pattern"`         // Playing pattern
+	Stage       int `json:"stage"`           // Current stage
+	StageStep   int `json:"stageStep"`       // Tick within stage
+	RatchetStep int `json:"ratchetStep"`     // Ratchet counter
+	Direction   int `json:"direction"`       // Pendulum: +1/-1
+	Cycle       int `json:"cycle,omitempty"` // Passes since the pattern started (cycle conditions)
 
 	// ─────────── Note Tracking ───────────
 	ActiveNote  uint8 `json:"activeNote"`  // Held note (0=none)
@@ -197,6 +295,16 @@ type MetropolixState struct {
 	Accum      [8]int `json:"accum"`      // Current offset
 	AccumCount [8]int `json:"accumCount"` // Triggers toward reset
 	AccumDir   [8]int `json:"accumDir"`   // Direction: +1/-1
+
+	// ─────────── Randomness ───────────
+	Seed int64 `json:"seed,omitempty"` // probability/random-mode seed (0 = free-running)
+
+	// ─────────── Root Follow ───────────
+	RootFollow int `json:"rootFollow,omitempty"` // 0=off, 1=next stage, 2=next cycle
+	RootShift  int `json:"rootShift,omitempty"`  // Live transpose from the last followed note
+
+	// ─────────── Feel ───────────
+	GateScale int `json:"gateScale,omitempty"` // Gate length multiplier, percent (0 = 100)
 }
 
 // MetropolixPatternState holds pattern data
@@ -204,26 +312,44 @@ type MetropolixPatternState struct {
 	Stages [8]MetropolixStageState `json:"stages"`
 
 	// Pattern-level settings
-	Length    int          `json:"length"`    // Active stages (1-8)
-	Mode      PlaybackMode `json:"mode"`      // FWD, REV, PEND, RAND
-	Scale     ScaleType    `json:"scale"`     // Chromatic, Major, etc.
-	RootNote  uint8        `json:"rootNote"`  // MIDI note (e.g., 60 = C4)
-	SlideTime int          `json:"slideTime"` // Glide duration (1-8)
+	Length    int          `json:"length"`             // Active stages (1-8)
+	Mode      PlaybackMode `json:"mode"`               // FWD, REV, PEND, RAND
+	Scale     ScaleType    `json:"scale"`              // Chromatic, Major, etc.
+	RootNote  uint8        `json:"rootNote"`           // MIDI note (e.g., 60 = C4)
+	SlideTime int          `json:"slideTime"`          // Glide duration (1-8)
+	Swing     int          `json:"swing,omitempty"`    // Off-beat step delay, percent of a step (0-50)
+	ClockDiv  int          `json:"clockDiv,omitempty"` // Pulse length: 0=1/16, 1=1/8, 2=1/4
+
+	// MOD lanes - per-stage CC values sent with the notes (see metropolixmod.go)
+	Mod [2]MetropolixModLane `json:"mod"`
+}
+
+// MetropolixModLane is a per-stage CC sequence
+type MetropolixModLane struct {
+	CC     int    `json:"cc,omitempty"`   // controller 1-127 (0 = off)
+	Slew   int    `json:"slew,omitempty"` // 0-7: share of the stage spent gliding to the value (0 = jump)
+	Values [8]int `json:"values"`         // 0-127 per stage
 }
 
 // MetropolixStageState holds a single stage's parameters
 type MetropolixStageState struct {
-	Octave      int  `json:"octave"`      // 0-7 (4 = middle C area)
-	Note        int  `json:"note"`        // Scale degree 0-7 (index into scale)
-	Gate        bool `json:"gate"`        // Note on/off
-	PulseCount  int  `json:"pulseCount"`  // Clocks per stage (1-8)
-	Ratchets    int  `json:"ratchets"`    // Subdivisions (1-8)
-	Probability int  `json:"probability"` // 0-100
-	Slide       bool `json:"slide"`       // Glide to next stage
-	GateLength  int  `json:"gateLength"`  // 0-5 index into gateLengthValues (0=trigger, 5=full)
-	Accumulator int  `json:"accumulator"` // Semitones per trigger (-4 to +3)
-	AccumReset  int  `json:"accumReset"`  // Reset after N triggers (0 = never)
-	AccumMode   int  `json:"accumMode"`   // 0=reset, 1=ping-pong, 2=hold at limit
+	Octave      int  `json:"octave"`         // 0-7 (4 = middle C area)
+	Note        int  `json:"note"`           // Scale degree 0-7 (index into scale)
+	Gate        bool `json:"gate"`           // Note on/off
+	PulseCount  int  `json:"pulseCount"`     // Clocks per stage (1-8)
+	Ratchets    int  `json:"ratchets"`       // Subdivisions (1-8)
+	Probability int  `json:"probability"`    // 0-100
+	Slide       bool `json:"slide"`          // Glide to next stage
+	GateLength  int  `json:"gateLength"`     // 0-5 index into gateLengthValues (0=trigger, 5=full)
+	Accumulator int  `json:"accumulator"`    // Semitones per trigger (-4 to +3)
+	AccumReset  int  `json:"accumReset"`     // Reset after N triggers (0 = never)
+	AccumMode   int  `json:"accumMode"`      // 0=reset, 1=ping-pong, 2=hold at limit
+	Skip        bool `json:"skip,omitempty"` // Left out of the cycle
+	Hold        bool `json:"hold,omitempty"` // One note tied across all pulses
+
+	RatchetProb  int `json:"ratchetProb,omitempty"`  // Percent chance each repeat plays (0 = 100)
+	RatchetShape int `json:"ratchetShape,omitempty"` // 0=even, 1=accel, 2=decel
+	Every        int `json:"every,omitempty"`        // Play every Nth pass only (0 = every pass)
 }
 
 // NewState creates a new state with defaults
@@ -253,6 +379,8 @@ func NewDrumState() *DrumState {
 		SelectedNoteIdx:   0,
 		EditingPatternIdx: 0,
 		Cursor:            0,
+		HumanizeRange:     DefaultHumanizeRange,
+		RandomDensity:     DefaultRandomDensity,
 	}
 
 	for i := range d.Patterns {
@@ -364,6 +492,7 @@ func (s *MetropolixState) ResetPlayback() {
 	s.StageStep = 0
 	s.RatchetStep = 0
 	s.Direction = 1
+	s.Cycle = 0
 	s.ActiveNote = 0
 	s.NoteEndStep = -1
 	s.Sliding = false
@@ -382,9 +511,18 @@ func (s *MetropolixState) ResetAccumulators() {
 func (s *MetropolixState) Validate() {
 	// Clamp top-level state
 	s.Editing = clamp(s.Editing, 0, NumPatterns-1)
-	s.Page = clamp(s.Page, 0, 7)
+	s.Page = clamp(s.Page, 0, PageModB)
 	s.Selected = clamp(s.Selected, 0, 7)
-	s.AccumSubPage = clamp(s.AccumSubPage, 0, 2)
+	s.AccumSubPage = clamp(s.AccumSubPage, 0, AccumSubLive)
+	s.GateSubPage = clamp(s.GateSubPage, 0, GateSubFlags)
+	s.RatchetSubPage = clamp(s.RatchetSubPage, 0, RatchetSubShape)
+	s.ProbSubPage = clamp(s.ProbSubPage, 0, ProbSubEvery)
+	s.SettingsSubPage = clamp(s.SettingsSubPage, 0, SettingsSubScales)
+	s.RootFollow = clamp(s.RootFollow, 0, RootFollowPattern)
+	s.RootShift = clamp(s.RootShift, -maxRootShift, maxRootShift)
+	if s.GateScale != 0 {
+		s.GateScale = clamp(s.GateScale, minGateScale, maxGateScale)
+	}
 	s.Pattern = clamp(s.Pattern, 0, NumPatterns-1)
 	s.Stage = clamp(s.Stage, 0, 7)
 	s.Direction = clamp(s.Direction, -1, 1)
@@ -402,9 +540,19 @@ func (s *MetropolixState) Validate() {
 		pat := &s.Patterns[i]
 		pat.Length = clamp(pat.Length, 1, 8)
 		pat.Mode = PlaybackMode(clamp(int(pat.Mode), 0, 3))
-		pat.Scale = ScaleType(clamp(int(pat.Scale), 0, int(ScaleCount)-1))
+		pat.Scale = ScaleType(clamp(int(pat.Scale), 0, numScales()-1))
 		pat.RootNote = uint8(clamp(int(pat.RootNote), 0, 127))
 		pat.SlideTime = clamp(pat.SlideTime, 1, 8)
+		pat.Swing = clamp(pat.Swing, 0, maxSwing)
+		pat.ClockDiv = clamp(pat.ClockDiv, 0, ClockDiv4)
+		for j := range pat.Mod {
+			lane := &pat.Mod[j]
+			lane.CC = clamp(lane.CC, 0, 127)
+			lane.Slew = clamp(lane.Slew, 0, maxModSlew)
+			for k := range lane.Values {
+				lane.Values[k] = clamp(lane.Values[k], 0, 127)
+			}
+		}
 
 		for j := range pat.Stages {
 			stage := &pat.Stages[j]
@@ -417,6 +565,9 @@ func (s *MetropolixState) Validate() {
 			stage.Accumulator = clamp(stage.Accumulator, -4, 3)
 			stage.AccumReset = clamp(stage.AccumReset, 0, 8)
 			stage.AccumMode = clamp(stage.AccumMode, 0, 2)
+			stage.RatchetProb = clamp(stage.RatchetProb, 0, 100)
+			stage.RatchetShape = clamp(stage.RatchetShape, 0, RatchetDecel)
+			stage.Every = clamp(stage.Every, 0, maxEvery)
 		}
 	}
 
@@ -471,8 +622,40 @@ func (s *State) Step() int {
 	return TickToStep(s.Tick) % 16
 }
 
-// MasterLength returns the longest note length in a drum pattern
+// TimeSig is a time signature (e.g. 3/4, 7/8)
+type TimeSig struct {
+	Beats int // beats per bar
+	Unit  int // note value of one beat (4 = quarter, 8 = eighth)
+}
+
+// TimeSigs are the selectable drum and piano pattern time signatures (index 0 is the default)
+var TimeSigs = []TimeSig{{4, 4}, {3, 4}, {5, 4}, {6, 8}, {7, 8}, {12, 8}}
+
+func (t TimeSig) String() string {
+	return fmt.Sprintf("%d/%d", t.Beats, t.Unit)
+}
+
+// BarSteps returns the number of 16th-note steps in one bar
+func (t TimeSig) BarSteps() int {
+	return t.Beats * 16 / t.Unit
+}
+
+// MaxDrumPatternLength is the longest explicit master length in steps
+const MaxDrumPatternLength = 64
+
+// Sig returns the pattern's time signature
+func (p *DrumPatternState) Sig() TimeSig {
+	if p.TimeSig < 0 || p.TimeSig >= len(TimeSigs) {
+		return TimeSigs[0]
+	}
+	return TimeSigs[p.TimeSig]
+}
+
+// MasterLength returns the explicit pattern length, or the longest note length if unset
 func (p *DrumPatternState) MasterLength() int {
+	if p.Length > 0 {
+		return p.Length
+	}
 	max := 1
 	for i := 0; i < 16; i++ {
 		if p.Notes[i].Length > max {
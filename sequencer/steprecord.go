@@ -0,0 +1,70 @@
+package sequencer
+
+import (
+	"math"
+
+	"go-sequence/midi"
+)
+
+// Step record - keyboard notes are written at the step cursor, one edit-grid step
+// long, and the cursor advances once every held key is released (so chords land
+// on one step). Works stopped or playing; space enters a rest.
+
+// toggleStepRecord enters step record at the view center (snapped to the edit grid) or leaves it
+func (p *PianoRollDevice) toggleStepRecord() {
+	p.stepMode = !p.stepMode
+	if !p.stepMode {
+		return
+	}
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	editH := EditHorizSteps[s.EditHoriz]
+	p.stepBeat = math.Floor(s.CenterBeat/editH) * editH
+	if p.stepBeat < 0 || p.stepBeat >= pat.Length {
+		p.stepBeat = 0
+	}
+	p.stepHeld = 0
+}
+
+// moveStep moves the step cursor by whole edit-grid steps, wrapping at the pattern end
+func (p *PianoRollDevice) moveStep(steps int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	p.stepBeat += float64(steps) * EditHorizSteps[s.EditHoriz]
+	if p.stepBeat >= pat.Length-1e-9 {
+		p.stepBeat = 0
+	} else if p.stepBeat < 0 {
+		p.stepBeat = max(0, pat.Length-EditHorizSteps[s.EditHoriz])
+	}
+	s.CenterBeat = p.stepBeat
+}
+
+// stepRecord writes a keyboard note at the step cursor
+func (p *PianoRollDevice) stepRecord(event midi.Event) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+
+	if event.Type == midi.NoteOn && event.Velocity > 0 {
+		defer p.trackEdit()()
+		editH := EditHorizSteps[s.EditHoriz]
+		pat.Notes = append(pat.Notes, NoteEventState{
+			Start:    p.stepBeat,
+			Duration: min(editH, pat.Length-p.stepBeat),
+			Pitch:    p.lockPitch(event.Note),
+			Velocity: event.Velocity,
+		})
+		s.SelectedNote = len(pat.Notes) - 1
+		s.CenterPitch = float64(event.Note)
+		p.stepHeld++
+		return
+	}
+
+	if event.Type == midi.NoteOff || event.Type == midi.NoteOn {
+		if p.stepHeld > 0 {
+			p.stepHeld--
+			if p.stepHeld == 0 {
+				p.moveStep(1)
+			}
+		}
+	}
+}
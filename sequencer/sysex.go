@@ -0,0 +1,594 @@
+package sequencer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+	"go-sequence/widgets"
+)
+
+// SysEx librarian - receives SysEx dumps (patch banks, settings) from a port into .syx
+// files under the project, and sends them back out. Receiving listens on the chosen
+// input until stopped, then asks for a name; sending splits a file into its messages
+// and leaves a gap between them, which slower gear needs to keep up.
+
+// sysexGap is the pause between messages when sending a dump
+const sysexGap = 20 * time.Millisecond
+
+// sysexDirName is the project subdirectory dumps are kept in
+const sysexDirName = "sysex"
+
+var errNoProject = errors.New("save the project first - dumps are stored with it")
+
+// sysexMode is what the librarian's keys currently do
+type sysexMode int
+
+const (
+	sysexBrowse    sysexMode = iota
+	sysexReceiving           // listening for a dump
+	sysexNaming              // typing a name for the received dump
+)
+
+// SysExDir returns the dump directory of the current project
+func SysExDir() (string, error) {
+	if S.ProjectName == "" {
+		return "", errNoProject
+	}
+	dir, err := ProjectDir(S.ProjectName)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, sysexDirName), nil
+}
+
+// ListSysEx returns the project's dump names (without .syx)
+func ListSysEx() ([]string, error) {
+	dir, err := SysExDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".syx") {
+			names = append(names, strings.TrimSuffix(entry.Name(), ".syx"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// splitSysEx splits a dump into its F0 ... F7 messages (bytes outside them are dropped)
+func splitSysEx(data []byte) [][]byte {
+	var msgs [][]byte
+	start := -1
+	for i, b := range data {
+		switch {
+		case b == 0xF0:
+			start = i
+		case b == 0xF7 && start >= 0:
+			msgs = append(msgs, data[start:i+1])
+			start = -1
+		}
+	}
+	return msgs
+}
+
+// SysExDevice is the librarian page
+type SysExDevice struct {
+	manager *Manager
+
+	names   []string // dumps in the project
+	fileIdx int
+	outIdx  int // port picked from the scanned outputs
+	inIdx   int // port picked from the scanned inputs
+
+	mode        sysexMode
+	inputBuffer string
+
+	// Receiving (the listener runs on the driver's goroutine)
+	mu       sync.Mutex
+	stop     func()
+	received []byte
+	messages int
+
+	// Sending (guarded by mu)
+	sending  bool
+	sent     int
+	total    int
+	sendName string
+
+	err string
+}
+
+// NewSysExDevice creates the SysEx librarian
+func NewSysExDevice(manager *Manager) *SysExDevice {
+	d := &SysExDevice{manager: manager}
+	d.Refresh()
+	return d
+}
+
+// IsInputMode returns true while receiving or naming a dump
+func (d *SysExDevice) IsInputMode() bool {
+	return d.mode != sysexBrowse
+}
+
+// Refresh reloads the project's dump list
+func (d *SysExDevice) Refresh() {
+	names, err := ListSysEx()
+	d.names = names
+	d.err = ""
+	if err != nil && !errors.Is(err, errNoProject) {
+		d.err = err.Error()
+	}
+	d.fileIdx = clamp(d.fileIdx, 0, max(0, len(d.names)-1))
+}
+
+// ports returns the scanned MIDI ports (cached by settings on rescan)
+func (d *SysExDevice) ports() (inputs, outputs []string) {
+	if s := d.manager.GetSettings(); s != nil {
+		return s.midiInputs, s.midiOutputs
+	}
+	return nil, nil
+}
+
+// outPort returns the selected output ("" if none)
+func (d *SysExDevice) outPort() string {
+	_, outputs := d.ports()
+	if d.outIdx < len(outputs) {
+		return outputs[d.outIdx]
+	}
+	return ""
+}
+
+// inPort returns the selected input ("" if none)
+func (d *SysExDevice) inPort() string {
+	inputs, _ := d.ports()
+	if d.inIdx < len(inputs) {
+		return inputs[d.inIdx]
+	}
+	return ""
+}
+
+// startReceive listens on the selected input for a dump
+func (d *SysExDevice) startReceive() {
+	if _, err := SysExDir(); err != nil {
+		d.err = err.Error()
+		return
+	}
+	name := d.inPort()
+	if name == "" {
+		d.err = "no input port"
+		return
+	}
+	if !d.manager.MIDIEnabled() {
+		d.err = "MIDI is off (safe mode)"
+		return
+	}
+	for _, port := range gomidi.GetInPorts() {
+		if port.String() != name {
+			continue
+		}
+		stop, err := gomidi.ListenTo(port, d.receive, gomidi.UseSysEx())
+		if err != nil {
+			d.err = err.Error()
+			return
+		}
+		d.mu.Lock()
+		d.stop = stop
+		d.received = nil
+		d.messages = 0
+		d.mu.Unlock()
+		d.err = ""
+		d.mode = sysexReceiving
+		d.manager.announce("receiving SysEx on %s - send the dump, then space", name)
+		return
+	}
+	d.err = fmt.Sprintf("%s not found", name)
+}
+
+// receive collects SysEx messages from the listener
+func (d *SysExDevice) receive(msg gomidi.Message, timestampms int32) {
+	var data []byte
+	if !msg.GetSysEx(&data) {
+		return
+	}
+	d.mu.Lock()
+	d.received = append(d.received, 0xF0)
+	d.received = append(d.received, data...)
+	d.received = append(d.received, 0xF7)
+	d.messages++
+	d.mu.Unlock()
+	d.manager.notifyUpdate()
+}
+
+// stopReceive stops listening - naming the dump if anything came in
+func (d *SysExDevice) stopReceive() {
+	d.mu.Lock()
+	stop := d.stop
+	d.stop = nil
+	d.mu.Unlock()
+	if stop != nil {
+		stop() // outside mu - the listener may be delivering a message
+	}
+	d.mu.Lock()
+	got := len(d.received) > 0
+	d.mu.Unlock()
+
+	d.mode = sysexBrowse
+	if got {
+		d.mode = sysexNaming
+		d.inputBuffer = ""
+	}
+}
+
+// saveReceived writes the received dump under a name
+func (d *SysExDevice) saveReceived(name string) {
+	dir, err := SysExDir()
+	if err == nil {
+		err = os.MkdirAll(dir, 0755)
+	}
+	if err == nil {
+		d.mu.Lock()
+		data := d.received
+		d.mu.Unlock()
+		err = os.WriteFile(filepath.Join(dir, name+".syx"), data, 0644)
+	}
+	if err != nil {
+		d.err = err.Error()
+		return
+	}
+	d.Refresh()
+	for i, n := range d.names {
+		if n == name {
+			d.fileIdx = i
+		}
+	}
+	d.manager.announce("saved SysEx dump %s", name)
+}
+
+// send sends the selected dump out the selected output, a message at a time
+func (d *SysExDevice) send() {
+	d.mu.Lock()
+	sending := d.sending
+	d.mu.Unlock()
+	if sending || d.fileIdx >= len(d.names) {
+		return
+	}
+	port := d.outPort()
+	if port == "" {
+		d.err = "no output port"
+		return
+	}
+	dir, err := SysExDir()
+	if err != nil {
+		d.err = err.Error()
+		return
+	}
+	name := d.names[d.fileIdx]
+	data, err := os.ReadFile(filepath.Join(dir, name+".syx"))
+	if err != nil {
+		d.err = err.Error()
+		return
+	}
+	msgs := splitSysEx(data)
+	if len(msgs) == 0 {
+		d.err = name + " has no SysEx messages"
+		return
+	}
+	sender := d.manager.getSender(port)
+	if sender == nil {
+		d.err = port + " unavailable"
+		return
+	}
+
+	d.err = ""
+	d.mu.Lock()
+	d.sending, d.sent, d.total, d.sendName = true, 0, len(msgs), name
+	d.mu.Unlock()
+	go func() {
+		for i, msg := range msgs {
+			if err := sender(msg); err != nil {
+				d.manager.notify("SysEx send to %s failed: %v", port, err)
+				break
+			}
+			d.mu.Lock()
+			d.sent = i + 1
+			d.mu.Unlock()
+			d.manager.notifyUpdate()
+			time.Sleep(sysexGap)
+		}
+		d.mu.Lock()
+		d.sending = false
+		d.mu.Unlock()
+		d.manager.notifyUpdate()
+	}()
+	d.manager.announce("sending %s to %s", name, port)
+}
+
+// remove deletes the selected dump
+func (d *SysExDevice) remove() {
+	if d.fileIdx >= len(d.names) {
+		return
+	}
+	dir, err := SysExDir()
+	if err == nil {
+		err = os.Remove(filepath.Join(dir, d.names[d.fileIdx]+".syx"))
+	}
+	if err != nil {
+		d.err = err.Error()
+		return
+	}
+	d.Refresh()
+}
+
+// Device interface implementation - queue-based (stubs for non-music device)
+
+func (d *SysExDevice) FillUntil(tick int64)                           {}
+func (d *SysExDevice) PeekNextEvent() *midi.Event                     { return nil }
+func (d *SysExDevice) PopNextEvent() *midi.Event                      { return nil }
+func (d *SysExDevice) ClearQueue()                                    {}
+func (d *SysExDevice) QueuePattern(p int, atTick int64)               {}
+func (d *SysExDevice) CurrentPattern() int                            { return 0 }
+func (d *SysExDevice) NextPattern() int                               { return -1 }
+func (d *SysExDevice) ContentMask() []bool                            { return make([]bool, NumPatterns) }
+func (d *SysExDevice) Density() []float64                             { return make([]float64, NumPatterns) }
+func (d *SysExDevice) PatternBars() []float64                         { return make([]float64, NumPatterns) }
+func (d *SysExDevice) HandleMIDI(event midi.Event)                    {}
+func (d *SysExDevice) ToggleRecording()                               {}
+func (d *SysExDevice) TogglePreview()                                 {}
+func (d *SysExDevice) IsRecording() bool                              { return false }
+func (d *SysExDevice) IsPreviewing() bool                             { return false }
+func (d *SysExDevice) HandlePadRelease(row, col int)                  {}
+func (d *SysExDevice) HandlePadPressure(row, col int, pressure uint8) {}
+
+func (d *SysExDevice) View() string {
+	var out strings.Builder
+
+	project := S.ProjectName
+	if project == "" {
+		project = "(unsaved project)"
+	}
+	out.WriteString(fmt.Sprintf("SYSEX LIBRARIAN  %s\n\n", project))
+
+	in, outPort := d.inPort(), d.outPort()
+	if in == "" {
+		in = "(no inputs found)"
+	}
+	if outPort == "" {
+		outPort = "(no outputs found)"
+	}
+	out.WriteString(fmt.Sprintf("Receive from: %s\n", in))
+	out.WriteString(fmt.Sprintf("Send to:      %s\n\n", outPort))
+
+	switch d.mode {
+	case sysexReceiving:
+		d.mu.Lock()
+		messages, size := d.messages, len(d.received)
+		d.mu.Unlock()
+		out.WriteString("─────────────────────────────────────────────────\n")
+		out.WriteString(fmt.Sprintf("\nReceiving... %d messages, %d bytes\n", messages, size))
+		out.WriteString("\nStart the dump on the device.\n[space/enter] done  [esc] cancel\n")
+		out.WriteString("\n─────────────────────────────────────────────────\n")
+		return out.String()
+	case sysexNaming:
+		d.mu.Lock()
+		messages, size := d.messages, len(d.received)
+		d.mu.Unlock()
+		out.WriteString("─────────────────────────────────────────────────\n")
+		out.WriteString(fmt.Sprintf("\nReceived %d messages, %d bytes\n", messages, size))
+		out.WriteString(fmt.Sprintf("Dump name: %s_\n", d.inputBuffer))
+		out.WriteString("\n[enter] save  [esc] discard\n")
+		out.WriteString("\n─────────────────────────────────────────────────\n")
+		return out.String()
+	}
+
+	out.WriteString("Dumps\n")
+	out.WriteString("─────────────────────────────────────────────────\n")
+	for i, name := range d.names {
+		prefix := "  "
+		if i == d.fileIdx {
+			prefix = "> "
+		}
+		out.WriteString(prefix + name + "\n")
+	}
+	if len(d.names) == 0 {
+		if S.ProjectName == "" {
+			out.WriteString("  (save the project to keep dumps with it)\n")
+		} else {
+			out.WriteString("  (no dumps yet - space to receive one)\n")
+		}
+	}
+	d.mu.Lock()
+	if d.sending {
+		out.WriteString(fmt.Sprintf("\nSending %s... %d/%d messages\n", d.sendName, d.sent, d.total))
+	}
+	d.mu.Unlock()
+	if d.err != "" {
+		out.WriteString(fmt.Sprintf("\nError: %s\n", d.err))
+	}
+
+	out.WriteString("\n")
+	out.WriteString(widgets.RenderKeyHelp([]widgets.KeySection{
+		{Keys: []widgets.KeyBinding{
+			{Key: "j / k", Desc: "select dump"},
+			{Key: "enter", Desc: "send dump to the output"},
+			{Key: "space", Desc: "receive a dump from the input"},
+			{Key: "i / I", Desc: "input port next/prev"},
+			{Key: "o / O", Desc: "output port next/prev"},
+			{Key: "X", Desc: "delete dump"},
+		}},
+	}))
+
+	out.WriteString("\n\n")
+	out.WriteString(widgets.RenderLayout(d.HelpLayout()))
+	return out.String()
+}
+
+// SysEx pad colors
+var (
+	sysexDumpColor    = [3]uint8{0, 120, 160}
+	sysexSendingColor = [3]uint8{0, 255, 0}
+	sysexReceiveColor = [3]uint8{255, 0, 0}
+	sysexDimColor     = [3]uint8{30, 30, 30}
+)
+
+func (d *SysExDevice) RenderLEDs() []LEDState {
+	var leds []LEDState
+	layout := d.HelpLayout()
+	for row := 0; row < 8; row++ {
+		for col := 0; col < 8; col++ {
+			leds = append(leds, LEDState{Row: row, Col: col, Color: layout.Grid[row][col].Color, Channel: midi.ChannelStatic})
+		}
+		leds = append(leds, LEDState{Row: row, Col: 8, Color: layout.RightCol[row].Color, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+func (d *SysExDevice) HandleKey(key string) {
+	switch d.mode {
+	case sysexReceiving:
+		switch key {
+		case " ", "enter":
+			d.stopReceive()
+		case "esc":
+			d.stopReceive()
+			d.mode = sysexBrowse
+		}
+		return
+	case sysexNaming:
+		d.handleNamingKey(key)
+		return
+	}
+
+	inputs, outputs := d.ports()
+	switch key {
+	case "j", "down":
+		if d.fileIdx < len(d.names)-1 {
+			d.fileIdx++
+		}
+	case "k", "up":
+		if d.fileIdx > 0 {
+			d.fileIdx--
+		}
+	case "enter":
+		d.send()
+	case " ":
+		d.startReceive()
+	case "i", "I":
+		if len(inputs) > 0 {
+			delta := 1
+			if key == "I" {
+				delta = len(inputs) - 1
+			}
+			d.inIdx = (d.inIdx + delta) % len(inputs)
+		}
+	case "o", "O":
+		if len(outputs) > 0 {
+			delta := 1
+			if key == "O" {
+				delta = len(outputs) - 1
+			}
+			d.outIdx = (d.outIdx + delta) % len(outputs)
+		}
+	case "X":
+		d.remove()
+	}
+}
+
+// handleNamingKey edits the received dump's name
+func (d *SysExDevice) handleNamingKey(key string) {
+	switch key {
+	case "enter":
+		name := sanitizeFilename(strings.TrimSpace(d.inputBuffer))
+		if name == "" {
+			return
+		}
+		d.mode = sysexBrowse
+		d.inputBuffer = ""
+		d.saveReceived(name)
+	case "esc":
+		d.mode = sysexBrowse
+		d.inputBuffer = ""
+	case "backspace":
+		if len(d.inputBuffer) > 0 {
+			d.inputBuffer = d.inputBuffer[:len(d.inputBuffer)-1]
+		}
+	default:
+		// Only accept printable characters
+		if len(key) == 1 && key[0] >= 32 && key[0] < 127 && key != "/" && key != "\\" {
+			d.inputBuffer += key
+		}
+	}
+}
+
+// HandlePad sends the dump on a pad; the top scene button starts/stops receiving
+func (d *SysExDevice) HandlePad(row, col int, velocity uint8) {
+	if col == 8 {
+		if row == 7 {
+			if d.mode == sysexReceiving {
+				d.stopReceive()
+			} else if d.mode == sysexBrowse {
+				d.startReceive()
+			}
+		}
+		return
+	}
+	if d.mode != sysexBrowse || row < 0 || row > 7 || col < 0 || col > 7 {
+		return
+	}
+	if idx := (7-row)*8 + col; idx < len(d.names) {
+		d.fileIdx = idx
+		d.send()
+	}
+}
+
+// HelpLayout shows one pad per dump (top-left first) and the receive button
+func (d *SysExDevice) HelpLayout() widgets.LaunchpadLayout {
+	var l widgets.LaunchpadLayout
+	rightCol := new([8]widgets.Pad)
+	l.RightCol = rightCol
+
+	for i := 0; i < 8; i++ {
+		l.TopRow[i] = widgets.Pad{Color: sysexDimColor}
+		rightCol[i] = widgets.Pad{Color: sysexDimColor}
+	}
+	rightCol[7] = widgets.Pad{Color: sysexReceiveColor, Tooltip: "receive a dump (again: done)"}
+
+	d.mu.Lock()
+	sending, sendName := d.sending, d.sendName
+	d.mu.Unlock()
+	for idx := 0; idx < 64; idx++ {
+		pad := widgets.Pad{Color: sysexDimColor}
+		if idx < len(d.names) {
+			pad = widgets.Pad{Color: sysexDumpColor, Tooltip: "send " + d.names[idx]}
+			if sending && d.names[idx] == sendName {
+				pad.Color = sysexSendingColor
+			}
+		}
+		l.Grid[7-idx/8][idx%8] = pad
+	}
+
+	l.Legend = []widgets.LegendItem{
+		{Color: sysexDumpColor, Name: "Dumps", Desc: "tap to send to the output"},
+		{Color: sysexSendingColor, Name: "Sending", Desc: "dump going out"},
+		{Color: sysexReceiveColor, Name: "Receive", Desc: "top scene = receive a dump / done"},
+	}
+	return l
+}
@@ -0,0 +1,79 @@
+package sequencer
+
+import (
+	"strconv"
+
+	gomidi "gitlab.com/gomidi/midi/v2"
+
+	"go-sequence/midi"
+)
+
+// Soft MIDI thru - routes from the config that send an input port's notes, CCs, bends
+// and pressure straight to an output port (optionally on another channel), whatever
+// is focused or recording. A keyboard thru'd to its synth is always playable; the
+// tracks' own echo and recording are unaffected.
+
+// ThruRoute sends an input port's channel messages to an output port
+type ThruRoute struct {
+	Input   string // input port ("" = the note-input keyboard)
+	Output  string // output port
+	Channel int    // output channel 1-16 (0 = keep the input's)
+}
+
+// SetThru sets the thru routes
+func (m *Manager) SetThru(routes []ThruRoute) {
+	m.thruMu.Lock()
+	defer m.thruMu.Unlock()
+	m.thru = routes
+}
+
+// ThruRoutes returns the thru routes
+func (m *Manager) ThruRoutes() []ThruRoute {
+	m.thruMu.Lock()
+	defer m.thruMu.Unlock()
+	return m.thru
+}
+
+// SetThruInput starts passing input messages through the thru routes
+func (m *Manager) SetThruInput(events <-chan midi.ThruEvent) {
+	go func() {
+		for evt := range events {
+			m.handleThru(evt)
+		}
+	}()
+}
+
+// handleThru sends one input message down every route from its port
+func (m *Manager) handleThru(evt midi.ThruEvent) {
+	for _, route := range m.ThruRoutes() {
+		input := route.Input
+		if input == "" {
+			input = S.NoteInputPort
+		}
+		if input == "" || input != evt.Port {
+			continue
+		}
+		sender := m.getSender(route.Output)
+		if sender == nil {
+			continue
+		}
+		msg := gomidi.Message(curveMessage(evt.Port, evt.Msg))
+		if route.Channel > 0 {
+			msg = append(gomidi.Message{msg[0]&0xF0 | uint8(route.Channel-1)&0x0F}, msg[1:]...)
+		}
+		sender(msg)
+	}
+}
+
+// label describes a route for the routing view ("Keystep → Minilogue ch3")
+func (r ThruRoute) label() string {
+	input := r.Input
+	if input == "" {
+		input = "keyboard"
+	}
+	s := input + " → " + r.Output
+	if r.Channel > 0 {
+		s += " ch" + strconv.Itoa(r.Channel)
+	}
+	return s
+}
@@ -0,0 +1,147 @@
+package sequencer
+
+// Global top row - in views that don't use the Launchpad's top row themselves, its
+// buttons are global: tempo up/down, focus previous/next (session, tracks 1-8 - the
+// TUI's 0-8 keys), session, play/stop, record and tap tempo. The Manager handles them
+// before the focused device sees the press. The session, Metropolix and routing views
+// keep the top row for their own pads. The two ends (tempo +5 and tap tempo) are also
+// the panic chord, so they act on release, and not at all when the chord fired.
+
+// Top row buttons (cols of row 8, Launchpad X labels in the comments)
+const (
+	topTempoUp   = 0 // ▲
+	topTempoDown = 1 // ▼
+	topFocusPrev = 2 // ◀
+	topFocusNext = 3 // ▶
+	topSession   = 4 // Session
+	topPlayStop  = 5 // Note
+	topRecord    = 6 // Custom
+	topTapTempo  = 7 // Capture MIDI
+)
+
+// topRowTempoStep matches the TUI's +/- keys
+const topRowTempoStep = 5
+
+// topRowTips describes the global buttons (for the status line)
+var topRowTips = [8]string{
+	"tempo +5", "tempo -5", "focus previous", "focus next",
+	"session", "play / stop", "record (while playing)", "tap tempo",
+}
+
+// topRowOwner is a view that uses the top row itself
+type topRowOwner interface {
+	ownsTopRow() bool
+}
+
+func (s *SessionDevice) ownsTopRow() bool    { return true }
+func (d *MetropolixDevice) ownsTopRow() bool { return true }
+func (r *RoutingDevice) ownsTopRow() bool    { return true }
+
+// globalTopRow reports whether the focused view leaves the top row to the Manager
+func (m *Manager) globalTopRow() bool {
+	if owner, ok := m.focused.(topRowOwner); ok && owner.ownsTopRow() {
+		return false
+	}
+	return m.focused != nil
+}
+
+// handleTopRow runs a global top row button (returns false if the press is the
+// device's)
+func (m *Manager) handleTopRow(row, col int) bool {
+	if row != 8 || col < 0 || col >= 8 || !m.globalTopRow() {
+		return false
+	}
+	m.padTipMu.Lock()
+	m.padTip = topRowTips[col]
+	m.padTipMu.Unlock()
+	if col != topTempoUp && col != topTapTempo {
+		m.topRowAction(col)
+	}
+	return true
+}
+
+// releaseTopRow runs a panic chord end's global action on release (returns false if
+// the release is the device's)
+func (m *Manager) releaseTopRow(row, col int) bool {
+	if row != 8 || !m.globalTopRow() {
+		return false
+	}
+	if col == topTempoUp || col == topTapTempo {
+		m.topRowAction(col)
+	}
+	return true
+}
+
+// topRowAction does what a global top row button does
+func (m *Manager) topRowAction(col int) {
+	switch col {
+	case topTempoUp, topTempoDown:
+		_, _, tempo := m.GetState()
+		if col == topTempoUp {
+			m.SetTempo(tempo + topRowTempoStep)
+		} else {
+			m.SetTempo(tempo - topRowTempoStep)
+		}
+	case topFocusPrev:
+		m.focusStep(-1)
+	case topFocusNext:
+		m.focusStep(1)
+	case topSession:
+		m.FocusSession()
+	case topPlayStop:
+		if _, playing, _ := m.GetState(); playing {
+			m.Stop()
+		} else {
+			m.Play()
+		}
+	case topRecord:
+		if _, playing, _ := m.GetState(); playing {
+			m.ToggleRecording()
+		}
+	case topTapTempo:
+		m.TapTempo()
+	}
+	m.notifyUpdate()
+}
+
+// focusStep moves focus along session, tracks 1-8 (other views count as the session)
+func (m *Manager) focusStep(dir int) {
+	pos := m.getFocusedTrackIdx() + 1 // 0 = session
+	pos = (pos + dir + 9) % 9
+	if pos == 0 {
+		m.FocusSession()
+	} else {
+		m.FocusDevice(pos - 1)
+	}
+}
+
+// topRowLEDs lights the global top row (nil when the view owns it)
+func (m *Manager) topRowLEDs() []LEDState {
+	if !m.globalTopRow() {
+		return nil
+	}
+	_, playing, _ := m.GetState()
+	recording := m.focused.IsRecording()
+
+	colors := [8][3]uint8{
+		topTempoUp:   {255, 100, 0},
+		topTempoDown: {255, 100, 0},
+		topFocusPrev: {60, 60, 60},
+		topFocusNext: {60, 60, 60},
+		topSession:   {0, 60, 120},
+		topPlayStop:  {0, 60, 0},
+		topRecord:    {60, 0, 0},
+		topTapTempo:  {180, 180, 0},
+	}
+	if playing {
+		colors[topPlayStop] = [3]uint8{0, 255, 0}
+	}
+	if recording {
+		colors[topRecord] = [3]uint8{255, 0, 0}
+	}
+	leds := make([]LEDState, 0, 8)
+	for col, c := range colors {
+		leds = append(leds, LEDState{Row: 8, Col: col, Color: c})
+	}
+	return leds
+}
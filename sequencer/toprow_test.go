@@ -0,0 +1,36 @@
+package sequencer
+
+import "testing"
+
+func TestTopRowPanicChord(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	tests := []struct {
+		name  string
+		pads  [][2]int // col, 1 = press / 0 = release
+		tempo int      // tempo afterwards (starts at 120)
+	}{
+		{"tempo up on release", [][2]int{{0, 1}, {0, 0}}, 125},
+		{"tempo down on press", [][2]int{{1, 1}}, 115},
+		{"panic chord", [][2]int{{0, 1}, {7, 1}, {0, 0}, {7, 0}}, 120},
+		{"panic chord from the right", [][2]int{{7, 1}, {0, 1}, {7, 0}, {0, 0}}, 120},
+		{"after a panic chord", [][2]int{{0, 1}, {7, 1}, {7, 0}, {0, 0}, {0, 1}, {0, 0}}, 125},
+	}
+	for _, tt := range tests {
+		S = NewState()
+		m := NewManager()
+		m.SetDevice(0, NewEmptyDevice(1))
+		m.FocusDevice(0)
+		for _, pad := range tt.pads {
+			if pad[1] == 1 {
+				m.HandlePad(8, pad[0], 127)
+			} else {
+				m.HandlePadRelease(8, pad[0])
+			}
+		}
+		if S.Tempo != tt.tempo {
+			t.Errorf("%s: tempo %d, want %d", tt.name, S.Tempo, tt.tempo)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package sequencer
+
+import "go-sequence/midi"
+
+// Track buttons - the Launchpad Pro Mk3's row of track buttons under the grid mutes,
+// solos or stops each track in every view. The Mute, Solo and Stop Clip buttons of the
+// bottom row pick which; the Manager handles both rows before the focused device sees
+// a press, so they mean the same thing everywhere.
+
+// trackButtonMode returns what the track buttons do (a midi.ProMode* col)
+func (m *Manager) trackButtonMode() int {
+	m.trackBtnMu.Lock()
+	defer m.trackBtnMu.Unlock()
+	return m.trackBtnMode
+}
+
+// handleButtonRows handles a press on the rows under the grid (returns false for
+// anything else)
+func (m *Manager) handleButtonRows(row, col int) bool {
+	switch row {
+	case midi.RowModeButtons:
+		switch col {
+		case midi.ProModeMute, midi.ProModeSolo, midi.ProModeStopClip:
+			m.trackBtnMu.Lock()
+			m.trackBtnMode = col
+			m.trackBtnMu.Unlock()
+			m.notifyUpdate()
+		}
+		return true
+	case midi.RowTrackButtons:
+		if col < 0 || col >= 8 {
+			return true
+		}
+		switch m.trackButtonMode() {
+		case midi.ProModeSolo:
+			m.ToggleSolo(col)
+		case midi.ProModeStopClip:
+			m.StopClip(col)
+		default:
+			m.ToggleMute(col)
+		}
+		return true
+	}
+	return false
+}
+
+// buttonRowLEDs colors the rows under the grid, when the controller has them
+func (m *Manager) buttonRowLEDs() []LEDState {
+	if m.controller == nil || m.controller.Type() != midi.ControllerLaunchpadPro {
+		return nil
+	}
+	mode := m.trackButtonMode()
+	var leds []LEDState
+	for _, col := range []int{midi.ProModeMute, midi.ProModeSolo, midi.ProModeStopClip} {
+		color := [3]uint8{40, 40, 40}
+		if col == mode {
+			color = [3]uint8{255, 255, 255}
+		}
+		leds = append(leds, LEDState{Row: midi.RowModeButtons, Col: col, Color: color})
+	}
+	var playing [8]bool
+	for track := range playing {
+		playing[track] = m.GetDevice(track) != nil && !m.ClipStopped(track)
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for track := 0; track < 8; track++ {
+		var color [3]uint8
+		switch mode {
+		case midi.ProModeSolo:
+			color = [3]uint8{0, 24, 50}
+			if S.Tracks[track].Solo {
+				color = [3]uint8{0, 120, 255}
+			}
+		case midi.ProModeStopClip:
+			color = [3]uint8{40, 6, 6}
+			if playing[track] {
+				color = [3]uint8{200, 30, 30}
+			}
+		default:
+			color = [3]uint8{50, 36, 0}
+			if S.Tracks[track].Muted {
+				color = [3]uint8{255, 180, 0}
+			}
+		}
+		leds = append(leds, LEDState{Row: midi.RowTrackButtons, Col: track, Color: color})
+	}
+	return leds
+}
+
+// HandlePadPressure routes aftertouch on a held pad to the focused device
+func (m *Manager) HandlePadPressure(row, col int, pressure uint8) {
+	if m.focused == nil || row < 0 {
+		return
+	}
+	m.focused.HandlePadPressure(row, col, pressure)
+	m.notifyUpdate()
+}
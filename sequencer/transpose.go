@@ -0,0 +1,66 @@
+package sequencer
+
+import "go-sequence/midi"
+
+// Transpose - patterns can be transposed destructively (piano roll J/K, ctrl+d and
+// ctrl+u), or a track can carry a non-destructive semitone offset that the Manager adds
+// at dispatch. The offset applies after kit translation, so drum tracks shift their kit
+// notes.
+
+// Transpose limits and steps
+const (
+	maxTranspose    = 48 // semitones either way
+	transposeOctave = 12
+)
+
+// NudgeTranspose changes a track's dispatch transpose by delta semitones
+func (s *State) NudgeTranspose(track, delta int) {
+	if track < 0 || track >= 8 {
+		return
+	}
+	s.Tracks[track].Transpose = clamp(s.Tracks[track].Transpose+delta, -maxTranspose, maxTranspose)
+}
+
+// transposeEvent applies the track's transpose to a note event, returning false if the
+// note falls outside 0-127. Note-offs reuse the shift their note-on was sent with, so
+// changing the offset mid-note can't leave notes hanging (hold m.voiceMu).
+func (m *Manager) transposeEvent(trackIdx int, ts *TrackState, evt *midi.Event) bool {
+	var shift int
+	switch evt.Type {
+	case midi.NoteOn, midi.Trigger:
+		shift = ts.Transpose
+		m.noteShift[trackIdx][evt.Note&0x7f] = int8(shift)
+	case midi.NoteOff:
+		shift = int(m.noteShift[trackIdx][evt.Note&0x7f])
+	default:
+		if !evt.PerNote {
+			return true
+		}
+		shift = int(m.noteShift[trackIdx][evt.Note&0x7f]) // follows its note
+	}
+	note := int(evt.Note) + shift
+	if note < 0 || note > 127 {
+		return false
+	}
+	evt.Note = uint8(note)
+	return true
+}
+
+// transposePattern shifts every note in the editing pattern (no-op if any would leave 0-127).
+// A scale-locked pattern takes its root along so the notes stay in key.
+func (p *PianoRollDevice) transposePattern(semitones int) {
+	s := p.state
+	pat := &s.Patterns[s.Editing]
+	for _, n := range pat.Notes {
+		if pitch := int(n.Pitch) + semitones; pitch < 0 || pitch > 127 {
+			return
+		}
+	}
+	for i := range pat.Notes {
+		pat.Notes[i].Pitch = uint8(int(pat.Notes[i].Pitch) + semitones)
+	}
+	if pat.Scale != ScaleChromatic {
+		pat.Root = uint8(((int(pat.Root)+semitones)%12 + 12) % 12)
+	}
+	s.CenterPitch = max(0, min(127, s.CenterPitch+float64(semitones)))
+}
@@ -0,0 +1,57 @@
+package sequencer
+
+// Undo/redo - each device keeps snapshots of whole patterns taken before edits.
+// Undo swaps the snapshot back in and keeps the replaced pattern for redo.
+
+// undoDepth is how many edits each device remembers
+const undoDepth = 32
+
+// undoEntry is a pattern snapshot
+type undoEntry[T any] struct {
+	pattern int
+	data    T
+}
+
+// undoHistory holds a device's undo and redo stacks
+type undoHistory[T any] struct {
+	undo []undoEntry[T]
+	redo []undoEntry[T]
+}
+
+// push records a pattern snapshot taken before an edit (clears redo, marks unsaved)
+func (h *undoHistory[T]) push(pattern int, data T) {
+	h.undo = pushBounded(h.undo, undoEntry[T]{pattern: pattern, data: data})
+	h.redo = nil
+	S.Dirty = true
+}
+
+// stepBack pops the last snapshot; current returns the pattern's data now, which is kept for redo
+func (h *undoHistory[T]) stepBack(current func(pattern int) T) (undoEntry[T], bool) {
+	return swapSnapshot(&h.undo, &h.redo, current)
+}
+
+// stepForward pops the last undone snapshot, keeping the current data for undo
+func (h *undoHistory[T]) stepForward(current func(pattern int) T) (undoEntry[T], bool) {
+	return swapSnapshot(&h.redo, &h.undo, current)
+}
+
+// swapSnapshot moves the top of from to the caller, pushing the replaced data onto to
+func swapSnapshot[T any](from, to *[]undoEntry[T], current func(pattern int) T) (undoEntry[T], bool) {
+	if len(*from) == 0 {
+		return undoEntry[T]{}, false
+	}
+	e := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = pushBounded(*to, undoEntry[T]{pattern: e.pattern, data: current(e.pattern)})
+	S.Dirty = true
+	return e, true
+}
+
+// pushBounded appends an entry, dropping the oldest past undoDepth
+func pushBounded[T any](stack []undoEntry[T], e undoEntry[T]) []undoEntry[T] {
+	stack = append(stack, e)
+	if len(stack) > undoDepth {
+		stack = stack[1:]
+	}
+	return stack
+}
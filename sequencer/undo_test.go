@@ -0,0 +1,79 @@
+package sequencer
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUndoHistory(t *testing.T) {
+	saved := S
+	defer func() { S = saved }()
+
+	// ops: 'e' edits (snapshot, then bump), 'u' undoes, 'r' redoes
+	tests := []struct {
+		name      string
+		ops       string
+		want      int
+		undo      int
+		redo      int
+		lastApply bool
+	}{
+		{"nothing to undo", "u", 0, 0, 0, false},
+		{"edit then undo", "eu", 0, 0, 1, true},
+		{"undo then redo", "eeur", 2, 2, 0, true},
+		{"nothing to redo", "er", 1, 1, 0, false},
+		{"edit clears redo", "eeue", 2, 2, 0, true},
+		{"undo all", "eeeuuu", 0, 0, 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			S = NewState()
+			var h undoHistory[int]
+			value := 0
+			current := func(int) int { return value }
+			applied := false
+			for _, op := range tt.ops {
+				var e undoEntry[int]
+				switch op {
+				case 'e':
+					h.push(0, value)
+					value++
+					applied = true
+					continue
+				case 'u':
+					e, applied = h.stepBack(current)
+				case 'r':
+					e, applied = h.stepForward(current)
+				}
+				if applied {
+					value = e.data
+				}
+			}
+			if value != tt.want || len(h.undo) != tt.undo || len(h.redo) != tt.redo || applied != tt.lastApply {
+				t.Errorf("value %d undo %d redo %d applied %v, want %d %d %d %v",
+					value, len(h.undo), len(h.redo), applied, tt.want, tt.undo, tt.redo, tt.lastApply)
+			}
+		})
+	}
+}
+
+func TestUndoDepth(t *testing.T) {
+	saved := S
+	S = NewState()
+	defer func() { S = saved }()
+
+	var h undoHistory[int]
+	for i := range undoDepth + 5 {
+		h.push(0, i)
+	}
+	if len(h.undo) != undoDepth {
+		t.Fatalf("%d undo steps, want %d", len(h.undo), undoDepth)
+	}
+	var got []int
+	for _, e := range h.undo[:3] {
+		got = append(got, e.data)
+	}
+	if !slices.Equal(got, []int{5, 6, 7}) {
+		t.Errorf("oldest kept snapshots %v, want [5 6 7]", got)
+	}
+}
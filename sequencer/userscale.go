@@ -0,0 +1,165 @@
+package sequencer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-sequence/midi"
+)
+
+// User scales - project-defined scales selectable after the built-ins (scale types from
+// ScaleCount on). Toggling a pitch class of a built-in scale forks it into a new user
+// scale first, so the built-ins never change.
+
+// UserScale is a project-defined scale
+type UserScale struct {
+	Name  string `json:"name"`
+	Notes []int  `json:"notes"` // pitch classes above the root (0-11), ascending
+}
+
+// Chromatic keyboard pads - white keys on one row, black keys above (cols 0-6)
+var (
+	whiteKeyClasses = []int{0, 2, 4, 5, 7, 9, 11}
+	blackKeyCols    = map[int]int{1: 1, 2: 3, 4: 6, 5: 8, 6: 10} // col -> pitch class
+)
+
+// numScales returns how many scales are selectable (built-ins plus the project's)
+func numScales() int {
+	return int(ScaleCount) + len(S.UserScales)
+}
+
+// userScale returns the user scale for a scale type (nil for built-ins or unknown types)
+func userScale(t ScaleType) *UserScale {
+	i := int(t) - int(ScaleCount)
+	if i < 0 || i >= len(S.UserScales) {
+		return nil
+	}
+	return &S.UserScales[i]
+}
+
+// scaleIntervals returns a scale's intervals from the root (unknown scales are chromatic)
+func scaleIntervals(t ScaleType) []int {
+	if t >= 0 && t < ScaleCount {
+		return scales[t]
+	}
+	if us := userScale(t); us != nil && len(us.Notes) > 0 {
+		return us.Notes
+	}
+	return scales[ScaleChromatic]
+}
+
+// scaleName returns a scale's display name
+func scaleName(t ScaleType) string {
+	if t >= 0 && t < ScaleCount {
+		return scaleNames[t]
+	}
+	if us := userScale(t); us != nil {
+		return us.Name
+	}
+	return scaleNames[ScaleChromatic]
+}
+
+// scaleHasClass reports whether a pitch class (0-11 above the root) is in a scale
+func scaleHasClass(t ScaleType, class int) bool {
+	for _, interval := range scaleIntervals(t) {
+		if interval%12 == class {
+			return true
+		}
+	}
+	return false
+}
+
+// toggleScaleClass adds or removes a pitch class from the scale at *t, forking a
+// built-in into a new user scale first. The last class can't be removed.
+func toggleScaleClass(t *ScaleType, class int) {
+	us := userScale(*t)
+	if us == nil {
+		var notes []int
+		for c := range 12 {
+			if scaleHasClass(*t, c) {
+				notes = append(notes, c)
+			}
+		}
+		S.UserScales = append(S.UserScales, UserScale{Name: fmt.Sprintf("User %d", len(S.UserScales)+1), Notes: notes})
+		*t = ScaleType(numScales() - 1)
+		us = &S.UserScales[len(S.UserScales)-1]
+	}
+
+	if i := slices.Index(us.Notes, class); i >= 0 {
+		if len(us.Notes) > 1 {
+			us.Notes = slices.Delete(us.Notes, i, i+1)
+		}
+		return
+	}
+	us.Notes = append(us.Notes, class)
+	slices.Sort(us.Notes)
+}
+
+// pitchClassName names a pitch's class ("C#")
+func pitchClassName(pitch int) string {
+	notes := []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	return notes[(pitch%12+12)%12]
+}
+
+// scaleRow renders a scale's pitch classes from root for the view, bracketing the cursor
+func scaleRow(t ScaleType, root int, cursor int) string {
+	var parts []string
+	for c := range 12 {
+		name := "·"
+		if scaleHasClass(t, c) {
+			name = pitchClassName(root + c)
+		}
+		if c == cursor {
+			name = "[" + name + "]"
+		}
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, " ")
+}
+
+// renderScaleKeys draws the chromatic keyboard on rows 2 (black keys) and 1 (white keys)
+func renderScaleKeys(t ScaleType) []LEDState {
+	inColor := [3]uint8{0, 255, 100}
+	rootColor := [3]uint8{255, 255, 255}
+	outColor := [3]uint8{20, 40, 30}
+	offColor := [3]uint8{0, 0, 0}
+
+	color := func(class int) [3]uint8 {
+		switch {
+		case !scaleHasClass(t, class):
+			return outColor
+		case class == 0:
+			return rootColor
+		}
+		return inColor
+	}
+
+	var leds []LEDState
+	for col := 0; col < 8; col++ {
+		white, black := offColor, offColor
+		if col < len(whiteKeyClasses) {
+			white = color(whiteKeyClasses[col])
+		}
+		if class, ok := blackKeyCols[col]; ok {
+			black = color(class)
+		}
+		leds = append(leds,
+			LEDState{Row: 2, Col: col, Color: black, Channel: midi.ChannelStatic},
+			LEDState{Row: 1, Col: col, Color: white, Channel: midi.ChannelStatic})
+	}
+	return leds
+}
+
+// scaleKeyClass returns the pitch class of a keyboard pad (-1 if none)
+func scaleKeyClass(row, col int) int {
+	switch {
+	case row == 1 && col < len(whiteKeyClasses):
+		return whiteKeyClasses[col]
+	case row == 2:
+		if class, ok := blackKeyCols[col]; ok {
+			return class
+		}
+	}
+	return -1
+}
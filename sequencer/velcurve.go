@@ -0,0 +1,120 @@
+package sequencer
+
+import (
+	"math"
+
+	"go-sequence/midi"
+)
+
+// Velocity curves - each input port can reshape the velocities it plays, so a hot
+// keybed can be tamed for drum recording (or a stiff one livened up) without touching
+// the keyboard's own settings. The curve applies to the note-input keyboard and to
+// thru routes from the port, before anything is echoed or recorded.
+
+// VelocityCurve reshapes note-on velocities
+type VelocityCurve string
+
+const (
+	CurveLinear VelocityCurve = ""      // as played
+	CurveSoft   VelocityCurve = "soft"  // quieter - hard playing needed for loud notes
+	CurveHard   VelocityCurve = "hard"  // louder - light playing already comes out strong
+	CurveFixed  VelocityCurve = "fixed" // every note at fixedVelocity
+)
+
+// VelocityCurves lists the curves in cycling order
+var VelocityCurves = []VelocityCurve{CurveLinear, CurveSoft, CurveHard, CurveFixed}
+
+// fixedVelocity is the velocity of every note on the fixed curve
+const fixedVelocity = 100
+
+// InputCurve is the velocity curve chosen for an input port
+type InputCurve struct {
+	Port  string        `json:"port"`
+	Curve VelocityCurve `json:"curve"`
+}
+
+// Name labels the curve ("linear" for the default)
+func (c VelocityCurve) Name() string {
+	if c == CurveLinear {
+		return "linear"
+	}
+	return string(c)
+}
+
+// Apply maps a velocity through the curve (0 stays 0 - it's a note-off)
+func (c VelocityCurve) Apply(v uint8) uint8 {
+	if v == 0 {
+		return 0
+	}
+	x := float64(v) / 127
+	switch c {
+	case CurveSoft:
+		x = x * x
+	case CurveHard:
+		x = math.Sqrt(x)
+	case CurveFixed:
+		return fixedVelocity
+	}
+	return uint8(clamp(int(math.Round(x*127)), 1, 127))
+}
+
+// InputCurve returns a port's velocity curve
+func (s *State) InputCurve(port string) VelocityCurve {
+	for _, ic := range s.InputCurves {
+		if ic.Port == port {
+			return ic.Curve
+		}
+	}
+	return CurveLinear
+}
+
+// SetInputCurve sets a port's velocity curve (linear removes the entry)
+func (s *State) SetInputCurve(port string, curve VelocityCurve) {
+	kept := make([]InputCurve, 0, len(s.InputCurves)+1)
+	for _, ic := range s.InputCurves {
+		if ic.Port != port {
+			kept = append(kept, ic)
+		}
+	}
+	if curve != CurveLinear {
+		kept = append(kept, InputCurve{Port: port, Curve: curve})
+	}
+	s.InputCurves = kept
+}
+
+// curveNoteEvent applies the note-input port's curve to a keyboard note-on
+func curveNoteEvent(evt midi.NoteEvent) midi.NoteEvent {
+	if evt.Type == midi.NoteOn {
+		evt.Velocity = S.InputCurve(S.NoteInputPort).Apply(evt.Velocity)
+	}
+	return evt
+}
+
+// curveMessage applies an input port's curve to a note-on message (a copy if changed)
+func curveMessage(port string, msg []byte) []byte {
+	curve := S.InputCurve(port)
+	if curve == CurveLinear || len(msg) < 3 || msg[0]&0xF0 != midi.NoteOn || msg[2] == 0 {
+		return msg
+	}
+	return []byte{msg[0], msg[1], curve.Apply(msg[2])}
+}
+
+// --- Routing matrix editing ---
+
+// cycleCurve steps the velocity curve of the input port under the keyboard line cursor
+func (r *RoutingDevice) cycleCurve(delta int) {
+	inputs, _ := r.ports()
+	if r.cursorRow != routingKeyboardRow || r.cursorCol < 1 || r.cursorCol > len(inputs) {
+		return
+	}
+	port := inputs[r.cursorCol-1]
+	i := 0
+	for j, c := range VelocityCurves {
+		if c == S.InputCurve(port) {
+			i = j
+		}
+	}
+	curve := VelocityCurves[(i+delta+len(VelocityCurves))%len(VelocityCurves)]
+	S.SetInputCurve(port, curve)
+	r.manager.announce("%s velocity: %s", port, curve.Name())
+}
@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"go-sequence/midi"
+	"go-sequence/sequencer"
+)
+
+// Hot-plug - the port lists are polled in the background (like `miditest poll`) and
+// diffed against the last scan. Outputs that vanish lose their sender so they reopen
+// when they come back, a Launchpad that disappears is disconnected and picked up again
+// when new ports show up, and the saved note input reconnects when it reappears.
+
+// hotplugInterval is how often ports are polled
+const hotplugInterval = 2 * time.Second
+
+// HotplugMsg carries the result of one background port scan
+type HotplugMsg struct {
+	inputs  []string
+	outputs []string
+	err     error
+}
+
+// PollPorts scans the MIDI ports after hotplugInterval (skipped in safe mode - a hung
+// CoreMIDI is what safe mode avoids)
+func PollPorts(deviceMgr *midi.DeviceManager) tea.Cmd {
+	return tea.Tick(hotplugInterval, func(time.Time) tea.Msg {
+		if deviceMgr.SafeMode() {
+			return HotplugMsg{err: midi.ErrSafeMode}
+		}
+		inputs, outputs, err := deviceMgr.ScanPorts()
+		if err == nil {
+			deviceMgr.ListenInputs(inputs)
+		}
+		return HotplugMsg{inputs: inputs, outputs: outputs, err: err}
+	})
+}
+
+// handleHotplug reacts to a port scan and schedules the next one
+func (m Model) handleHotplug(msg HotplugMsg) (Model, tea.Cmd) {
+	cmds := []tea.Cmd{PollPorts(m.DeviceMgr)}
+	if msg.err != nil {
+		return m, tea.Batch(cmds...)
+	}
+	first := !m.scanned
+	addedIn, goneIn := diffPorts(m.knownInputs, msg.inputs)
+	addedOut, goneOut := diffPorts(m.knownOutputs, msg.outputs)
+	m.scanned = true
+	m.knownInputs, m.knownOutputs = msg.inputs, msg.outputs
+	if settings := m.Manager.GetSettings(); settings != nil {
+		settings.SetMIDIPorts(msg.inputs, msg.outputs)
+	}
+	if first || len(addedIn)+len(goneIn)+len(addedOut)+len(goneOut) == 0 {
+		return m, tea.Batch(cmds...)
+	}
+
+	m.Manager.ForgetSenders(msg.outputs)
+
+	// Launchpad unplugged - drop it until it comes back
+	if m.controller != nil && slices.Contains(goneIn, m.controller.ID()) {
+		m.DeviceMgr.Disconnect()
+		m.controller = nil
+		m.Manager.SetController(nil)
+	}
+	if m.controller == nil && len(addedIn) > 0 {
+		cmds = append(cmds, RescanDevices(m.DeviceMgr, m.Manager.RigConfig()))
+	}
+
+	// Keyboard back - reconnect the saved note input
+	if port := sequencer.S.NoteInputPort; port != "" && slices.Contains(addedIn, port) {
+		cmds = append(cmds, m.connectNoteInput(port))
+	}
+
+	m.statusMsg = "MIDI devices changed: " + describePortChanges(append(addedIn, addedOut...), append(goneIn, goneOut...))
+	return m, tea.Batch(cmds...)
+}
+
+// diffPorts returns the ports in now that weren't in before, and the ones that went away
+func diffPorts(before, now []string) (added, gone []string) {
+	for _, p := range now {
+		if !slices.Contains(before, p) {
+			added = append(added, p)
+		}
+	}
+	for _, p := range before {
+		if !slices.Contains(now, p) {
+			gone = append(gone, p)
+		}
+	}
+	return added, gone
+}
+
+// describePortChanges summarizes a diff ("+Launchpad X, -IAC Bus 1")
+func describePortChanges(added, gone []string) string {
+	var parts []string
+	for _, p := range slices.Compact(slices.Sorted(slices.Values(added))) {
+		parts = append(parts, "+"+p)
+	}
+	for _, p := range slices.Compact(slices.Sorted(slices.Values(gone))) {
+		parts = append(parts, "-"+p)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -11,6 +12,7 @@ import (
 	"go-sequence/midi"
 	"go-sequence/sequencer"
 	"go-sequence/theme"
+	"go-sequence/widgets"
 )
 
 type Model struct {
@@ -21,23 +23,38 @@ type Model struct {
 	quitting   bool
 	controller midi.Controller
 	statusMsg  string
+
+	confirmQuit bool // quit asked with unsaved changes - waiting on the prompt
+
+	// Hot-plug - ports seen by the last background scan (see hotplug.go)
+	scanned      bool
+	knownInputs  []string
+	knownOutputs []string
 }
 
 type UpdateMsg struct{}
 
 type RescanResultMsg struct {
-	controller  midi.Controller
-	err         error
-	midiInputs  []string
-	midiOutputs []string
+	controller   midi.Controller
+	err          error
+	midiInputs   []string
+	midiOutputs  []string
+	midiRestored bool // safe mode was left - MIDI answered the retry
 }
 
 type NoteInputResultMsg struct {
 	err error
 }
 
+type LatencyResultMsg struct {
+	port    string
+	latency time.Duration
+	err     error
+}
+
 func NewModel(manager *sequencer.Manager, deviceMgr *midi.DeviceManager, cfg *config.Config, th *theme.Theme) Model {
 	controller := deviceMgr.GetController()
+	widgets.Plain = cfg.UI.PlainOutput
 	m := Model{
 		Manager:    manager,
 		DeviceMgr:  deviceMgr,
@@ -57,27 +74,86 @@ func ListenForUpdates(manager *sequencer.Manager) tea.Cmd {
 
 func RescanDevices(deviceMgr *midi.DeviceManager, cfg *config.Config) tea.Cmd {
 	return func() tea.Msg {
+		// Safe mode - retry MIDI before scanning
+		restored := false
+		if deviceMgr.SafeMode() {
+			if err := deviceMgr.RetryMIDI(); err != nil {
+				return RescanResultMsg{err: err}
+			}
+			restored = true
+		}
+
 		// Get port lists first
 		inputs, outputs, _ := deviceMgr.ScanPorts()
 
 		err := deviceMgr.Connect(cfg)
+		deviceMgr.ListenInputs(inputs)
 		if err != nil {
-			return RescanResultMsg{err: err, midiInputs: inputs, midiOutputs: outputs}
+			return RescanResultMsg{err: err, midiInputs: inputs, midiOutputs: outputs, midiRestored: restored}
 		}
-		return RescanResultMsg{controller: deviceMgr.GetController(), midiInputs: inputs, midiOutputs: outputs}
+		return RescanResultMsg{controller: deviceMgr.GetController(), midiInputs: inputs, midiOutputs: outputs, midiRestored: restored}
 	}
 }
 
-func ConnectNoteInput(deviceMgr *midi.DeviceManager, portName string) tea.Cmd {
+// connectNoteInput connects the note input keyboard with the hotkeys its controller
+// profile sets
+func (m Model) connectNoteInput(portName string) tea.Cmd {
+	cfg := m.Manager.RigConfig()
+	m.Manager.SetHotkeys(portName, hotkeysFor(cfg, portName))
+	return ConnectNoteInput(m.DeviceMgr, cfg, portName)
+}
+
+// hotkeysFor converts a keyboard profile's hotkeys (none without a profile)
+func hotkeysFor(cfg *config.Config, portName string) []sequencer.Hotkey {
+	ctrl := cfg.FindController(portName)
+	if ctrl == nil {
+		return nil
+	}
+	var hotkeys []sequencer.Hotkey
+	for _, hk := range ctrl.Hotkeys {
+		h := sequencer.Hotkey{
+			Note:    -1,
+			CC:      -1,
+			Channel: hk.Channel,
+			Macro:   sequencer.MacroBinding{Action: sequencer.MacroAction(hk.Action), Arg: max(hk.Arg-1, 0)},
+		}
+		switch {
+		case hk.Note != nil:
+			h.Note = *hk.Note
+		case hk.CC != nil:
+			h.CC = *hk.CC
+		default:
+			continue
+		}
+		hotkeys = append(hotkeys, h)
+	}
+	return hotkeys
+}
+
+// ConnectNoteInput connects the note input keyboard, on the channel its controller
+// profile picks
+func ConnectNoteInput(deviceMgr *midi.DeviceManager, cfg *config.Config, portName string) tea.Cmd {
+	channel := 0
+	if ctrl := cfg.FindController(portName); ctrl != nil && ctrl.Type == config.ControllerKeyboard {
+		channel = ctrl.InputChannel
+	}
 	return func() tea.Msg {
-		err := deviceMgr.ConnectNoteInput(portName)
+		err := deviceMgr.ConnectNoteInput(portName, channel)
 		return NoteInputResultMsg{err: err}
 	}
 }
 
+func MeasureLatency(manager *sequencer.Manager, trackIdx int) tea.Cmd {
+	return func() tea.Msg {
+		port := manager.TrackPort(trackIdx)
+		latency, err := manager.MeasureLatency(port, sequencer.S.Tracks[trackIdx].Channel)
+		return LatencyResultMsg{port: port, latency: latency, err: err}
+	}
+}
+
 func (m Model) Init() tea.Cmd {
 	var cmds []tea.Cmd
-	cmds = append(cmds, ListenForUpdates(m.Manager))
+	cmds = append(cmds, ListenForUpdates(m.Manager), PollPorts(m.DeviceMgr))
 
 	if m.controller != nil {
 		cmds = append(cmds, m.listenForPads())
@@ -94,28 +170,51 @@ func (m Model) listenForPads() tea.Cmd {
 	}
 	return func() tea.Msg {
 		for pad := range m.controller.PadEvents() {
-			m.Manager.HandlePad(pad.Row, pad.Col)
+			m.Manager.LockDevices()
+			if pad.Pressure {
+				m.Manager.HandlePadPressure(pad.Row, pad.Col, pad.Velocity)
+			} else if pad.Released {
+				m.Manager.HandlePadRelease(pad.Row, pad.Col)
+			} else {
+				m.Manager.HandlePad(pad.Row, pad.Col, pad.Velocity)
+			}
+			m.Manager.UnlockDevices()
 		}
 		return nil
 	}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// The sequencer's devices are ours until Update returns (see sequencer/devicelock.go)
+	m.Manager.LockDevices()
+	defer m.Manager.UnlockDevices()
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		// If save device is in input mode, route all keys there
-		if save := m.Manager.GetSave(); save != nil && save.IsInputMode() {
+		if m.confirmQuit {
+			return m.answerQuit(msg.String())
+		}
+
+		// If the focused device is in input mode (text entry, confirm, preview), route all keys there
+		if im, ok := m.Manager.GetFocused().(interface{ IsInputMode() bool }); ok && im.IsInputMode() {
 			m.Manager.HandleKey(msg.String())
 			return m, nil
 		}
 
 		switch msg.String() {
 		case "Q", "ctrl+c":
-			m.quitting = true
-			m.Manager.Stop()
-			return m, tea.Quit
+			if sequencer.S.Unsaved() {
+				m.confirmQuit = true
+				m.statusMsg = "Unsaved changes - s: save and quit  Q: quit  any other key: cancel"
+				break
+			}
+			return m.quit()
 
 		case "P": // Shift+P - play/stop
+			if m.Manager.IsFollower() {
+				m.statusMsg = "Transport follows sync leader"
+				break
+			}
 			_, playing, _ := m.Manager.GetState()
 			if playing {
 				m.Manager.Stop()
@@ -132,10 +231,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Manager.SetTempo(tempo - 5)
 
 		case "r":
-			// Only allow rescan from settings device
-			if _, ok := m.Manager.GetFocused().(*sequencer.SettingsDevice); ok {
+			// Only allow rescan from the settings, routing and controller devices
+			switch m.Manager.GetFocused().(type) {
+			case *sequencer.SettingsDevice, *sequencer.RoutingDevice, *sequencer.ControllersDevice:
 				m.statusMsg = "Scanning..."
-				return m, RescanDevices(m.DeviceMgr, m.Config)
+				return m, RescanDevices(m.DeviceMgr, m.Manager.RigConfig())
 			}
 			m.Manager.HandleKey(msg.String())
 
@@ -145,29 +245,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.Manager.ToggleRecording()
 			}
 
+		case "!": // Shift+1 - panic (all notes off everywhere)
+			m.Manager.Panic()
+
 		case "p": // preview/thru for focused device
 			m.Manager.TogglePreview()
 
-		case "S": // Shift+S - quick save
-			projectName := sequencer.S.ProjectName
-			if projectName == "" {
-				projectName = "untitled"
-			}
-			if err := sequencer.SaveProject(projectName); err != nil {
-				m.statusMsg = fmt.Sprintf("Save failed: %v", err)
-			} else {
-				m.statusMsg = fmt.Sprintf("Saved to %s", projectName)
-			}
+		case "S", "ctrl+s": // Shift+S - quick save
+			m.quickSave()
 
 		case "D": // Shift+D - save device
 			m.Manager.FocusSave()
 
+		case "M": // Shift+M - macro pads
+			m.Manager.FocusMacro()
+
+		case "L": // Shift+L - set list
+			m.Manager.FocusSetList()
+
+		case "ctrl+e": // export the focused track's editing pattern to a MIDI file
+			m.Manager.ExportFocused()
+
 		case "0":
 			m.Manager.FocusSession()
 
 		case ",":
 			m.Manager.FocusSettings()
 
+		case "ctrl+t":
+			m.Manager.FocusRouting()
+
+		case "`":
+			m.Manager.FocusMonitor()
+
+		case "ctrl+l":
+			m.Manager.FocusLearn()
+
+		case "ctrl+x":
+			m.Manager.FocusSysEx()
+
+		case "ctrl+k":
+			m.Manager.FocusControllerProfiles()
+
+		case "ctrl+g":
+			m.Manager.FocusMixer()
+
 		case "1", "2", "3", "4", "5", "6", "7", "8":
 			idx := int(msg.String()[0] - '1')
 			m.Manager.FocusDevice(idx)
@@ -177,20 +299,58 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Check if settings changed note input
 			if settings := m.Manager.GetSettings(); settings != nil && settings.NoteInputChanged {
 				settings.NoteInputChanged = false
-				return m, ConnectNoteInput(m.DeviceMgr, sequencer.S.NoteInputPort)
+				return m, m.connectNoteInput(sequencer.S.NoteInputPort)
+			}
+			// Check if settings changed the LED look - saved for the next start
+			if settings := m.Manager.GetSettings(); settings != nil && settings.LEDSettingsChanged {
+				settings.LEDSettingsChanged = false
+				m.Config.UI.LEDBrightness = m.Manager.LEDBrightness()
+				m.Config.UI.LEDTheme = m.Manager.LEDTheme()
+				if err := m.Config.Save(); err != nil {
+					m.statusMsg = fmt.Sprintf("Config save failed: %v", err)
+				}
+			}
+			// Check if settings requested a latency test
+			if settings := m.Manager.GetSettings(); settings != nil && settings.LatencyTestTrack >= 0 {
+				trackIdx := settings.LatencyTestTrack
+				settings.LatencyTestTrack = -1
+				m.statusMsg = "Measuring latency..."
+				return m, MeasureLatency(m.Manager, trackIdx)
 			}
 		}
 
 	case UpdateMsg:
+		if status := m.Manager.TakeStatus(); status != "" {
+			m.statusMsg = status
+		}
+		// A loaded project brought its own controller assignments - reconnect with them
+		if m.Manager.TakeRigChange() {
+			m.statusMsg = "Scanning..."
+			return m, tea.Batch(ListenForUpdates(m.Manager), RescanDevices(m.DeviceMgr, m.Manager.RigConfig()))
+		}
 		return m, ListenForUpdates(m.Manager)
 
+	case HotplugMsg:
+		return m.handleHotplug(msg)
+
 	case RescanResultMsg:
 		// Update settings with port info
 		if settings := m.Manager.GetSettings(); settings != nil {
 			settings.SetMIDIPorts(msg.midiInputs, msg.midiOutputs)
 		}
 
-		if msg.err != nil {
+		// Out of safe mode - re-enable output and the saved note input
+		var cmds []tea.Cmd
+		if msg.midiRestored {
+			m.Manager.SetMIDIEnabled(true)
+			if sequencer.S.NoteInputPort != "" {
+				cmds = append(cmds, m.connectNoteInput(sequencer.S.NoteInputPort))
+			}
+		}
+
+		if m.DeviceMgr.SafeMode() {
+			m.statusMsg = fmt.Sprintf("Still in safe mode: %v", msg.err)
+		} else if msg.err != nil {
 			m.statusMsg = fmt.Sprintf("No device: %v", msg.err)
 			m.controller = nil
 			m.Manager.SetController(nil)
@@ -198,7 +358,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.statusMsg = fmt.Sprintf("Connected: %s", msg.controller.ID())
 			m.controller = msg.controller
 			m.Manager.SetController(msg.controller)
-			return m, m.listenForPads()
+			cmds = append(cmds, m.listenForPads())
+		}
+		return m, tea.Batch(cmds...)
+
+	case LatencyResultMsg:
+		if settings := m.Manager.GetSettings(); settings != nil {
+			settings.SetLatencyResult(msg.port, msg.latency, msg.err)
+		}
+		if msg.err != nil {
+			m.statusMsg = fmt.Sprintf("Latency test failed: %v", msg.err)
+		} else {
+			m.statusMsg = fmt.Sprintf("Latency %s: %v", msg.port, msg.latency.Round(time.Millisecond))
 		}
 
 	case NoteInputResultMsg:
@@ -219,13 +390,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// controllerStatus describes the controller connection for the header
+func (m Model) controllerStatus() string {
+	switch {
+	case m.DeviceMgr.SafeMode():
+		return "safe mode - MIDI off, r in settings to retry"
+	case m.controller != nil && m.controller.Type() == midi.ControllerLaunchpadPro:
+		return "Launchpad Pro Mk3"
+	case m.controller != nil && m.controller.Type() == midi.ControllerAPC:
+		return "APC"
+	case m.controller != nil && m.controller.Type() == midi.ControllerGenericGrid:
+		if named, ok := m.controller.(interface{ Name() string }); ok && named.Name() != "" {
+			return named.Name()
+		}
+		return "generic grid"
+	case m.controller != nil:
+		return "Launchpad X"
+	}
+	return "no controller"
+}
+
+// quickSave saves to the current project ("untitled" if there isn't one)
+func (m *Model) quickSave() bool {
+	projectName := sequencer.S.ProjectName
+	if projectName == "" {
+		projectName = "untitled"
+	}
+	if err := m.Manager.Save(projectName); err != nil {
+		m.statusMsg = fmt.Sprintf("Save failed: %v", err)
+		return false
+	}
+	m.statusMsg = fmt.Sprintf("Saved to %s", projectName)
+	return true
+}
+
+// quit stops playback and exits
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	m.quitting = true
+	m.Manager.Stop()
+	return m, tea.Quit
+}
+
+// answerQuit handles the key pressed at the unsaved changes prompt
+func (m Model) answerQuit(key string) (tea.Model, tea.Cmd) {
+	m.confirmQuit = false
+	switch key {
+	case "Q", "y", "ctrl+c":
+		return m.quit()
+	case "s", "S", "ctrl+s":
+		if m.quickSave() {
+			return m.quit()
+		}
+	default:
+		m.statusMsg = "Quit cancelled"
+	}
+	return m, nil
+}
+
 func (m Model) View() string {
 	if m.quitting {
 		return ""
 	}
+	m.Manager.LockDevices()
+	defer m.Manager.UnlockDevices()
 
 	step, playing, tempo := m.Manager.GetState()
 
+	if m.Config.UI.PlainOutput {
+		return m.plainView(step, playing, tempo)
+	}
+
 	// Styles
 	titleStyle := lipgloss.NewStyle().Foreground(m.Theme.Accent()).Bold(true)
 	dimStyle := lipgloss.NewStyle().Foreground(m.Theme.Muted())
@@ -237,15 +471,12 @@ func (m Model) View() string {
 		playState = "PLAY"
 	}
 
-	ctrlStatus := "no controller"
-	if m.controller != nil {
-		ctrlStatus = "Launchpad X"
-	}
+	ctrlStatus := m.controllerStatus()
 
 	// Header block
 	title := titleStyle.Render("go-sequence")
 	status := fmt.Sprintf("  %s  %3d bpm  step %02d  [%s]", playState, tempo, step+1, ctrlStatus)
-	controls := dimStyle.Render("P:play  +/-:tempo  0:session  1-8:device  ,:settings  S:save  D:browser  Q:quit")
+	controls := dimStyle.Render("P:play  +/-:tempo  0:session  1-8:device  ,:settings  S:save  D:browser  M:macros  L:setlist  Q:quit")
 	border := borderStyle.Render("════════════════════════════════════════════════════════════════")
 
 	// Device view (includes grid, key help, and launchpad)
@@ -256,10 +487,18 @@ func (m Model) View() string {
 	out.WriteString("\n")
 	out.WriteString(title)
 	out.WriteString(status)
+	if syncStatus := m.Manager.SyncStatus(); syncStatus != "" {
+		out.WriteString("  ")
+		out.WriteString(dimStyle.Render("sync: " + syncStatus))
+	}
 	if m.statusMsg != "" {
 		out.WriteString("  ")
 		out.WriteString(dimStyle.Render(m.statusMsg))
 	}
+	if tip := m.Manager.PadTooltip(); tip != "" {
+		out.WriteString("  ")
+		out.WriteString(dimStyle.Render("pad: " + tip))
+	}
 	out.WriteString("\n")
 	out.WriteString(controls)
 	out.WriteString("\n")
@@ -269,3 +508,31 @@ func (m Model) View() string {
 
 	return out.String()
 }
+
+// plainView renders screen-reader friendly output: no styling or box drawing,
+// and the latest state change spelled out on its own line
+func (m Model) plainView(step int, playing bool, tempo int) string {
+	playState := "stopped"
+	if playing {
+		playState = "playing"
+	}
+	ctrlStatus := m.controllerStatus()
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("go-sequence. %s, %d bpm, step %d, %s.\n", playState, tempo, step+1, ctrlStatus))
+	if syncStatus := m.Manager.SyncStatus(); syncStatus != "" {
+		out.WriteString("Sync: " + syncStatus + ".\n")
+	}
+	if announcement := m.Manager.Announcement(); announcement != "" {
+		out.WriteString("Now: " + announcement + ".\n")
+	}
+	if tip := m.Manager.PadTooltip(); tip != "" {
+		out.WriteString("Last pad: " + tip + ".\n")
+	}
+	if m.statusMsg != "" {
+		out.WriteString("Status: " + m.statusMsg + ".\n")
+	}
+	out.WriteString("Keys: P play, plus minus tempo, 0 session, 1 to 8 device, comma settings, S save, D browser, M macros, Q quit.\n\n")
+	out.WriteString(plainText(m.Manager.View()))
+	return out.String()
+}
@@ -0,0 +1,36 @@
+package tui
+
+import "strings"
+
+// plainReplacer maps decorative glyphs to plain ASCII (or nothing) so screen
+// readers don't announce box-drawing and shape characters
+var plainReplacer = strings.NewReplacer(
+	// Box drawing - decoration only
+	"─", "", "═", "", "│", " ", "┌", "", "┐", "", "└", "", "┘", "", "├", "", "┤", "",
+	// State glyphs
+	"▶", ">", "▷", ">", "◆", "*", "●", "x", "◉", "X", "○", "o", "□", "_", "·", ".", "■", "#",
+	"░", "1", "▒", "2", "▓", "3", "█", "4",
+	"▁", "1", "▂", "2", "▃", "3", "▄", "4", "▅", "5", "▆", "6", "▇", "7",
+	"±", "+/-",
+)
+
+// plainText strips decoration from rendered output and collapses the blank
+// lines it leaves behind
+func plainText(s string) string {
+	lines := strings.Split(plainReplacer.Replace(s), "\n")
+	var out []string
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " ")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -7,14 +7,24 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// Plain disables colored pad rendering (screen-reader friendly output).
+// Pads and grids render as nothing; legends render as plain text.
+var Plain bool
+
 // RenderPad renders a single colored pad
 func RenderPad(color [3]uint8) string {
+	if Plain {
+		return ""
+	}
 	style := lipgloss.NewStyle().Foreground(lipgloss.Color(rgbToHex(color)))
 	return style.Render("■")
 }
 
 // RenderPadRow renders a row of colored pads with spacing
 func RenderPadRow(colors [][3]uint8) string {
+	if Plain {
+		return ""
+	}
 	var out strings.Builder
 	for i, c := range colors {
 		if i > 0 {
@@ -28,6 +38,9 @@ func RenderPadRow(colors [][3]uint8) string {
 // RenderPadGrid renders an 8x8 grid of pads (row 0 at bottom, row 7 at top)
 // Optional rightCol adds a 9th column (scene buttons)
 func RenderPadGrid(grid [8][8][3]uint8, rightCol *[8][3]uint8) string {
+	if Plain {
+		return ""
+	}
 	var lines []string
 	for row := 7; row >= 0; row-- {
 		var line strings.Builder
@@ -45,9 +58,83 @@ func RenderPadGrid(grid [8][8][3]uint8, rightCol *[8][3]uint8) string {
 
 // RenderLegendItem renders a single legend item: "■ Name - description"
 func RenderLegendItem(color [3]uint8, name, desc string) string {
+	if Plain {
+		return fmt.Sprintf("  %s - %s", name, desc)
+	}
 	return fmt.Sprintf("  %s %s - %s", RenderPad(color), name, desc)
 }
 
+// Pad is one pad in a help layout: its color and what pressing it does
+type Pad struct {
+	Color   [3]uint8
+	Tooltip string // "" = pad does nothing
+}
+
+// LegendItem is one legend line, optionally followed by pre-formatted detail lines
+type LegendItem struct {
+	Color  [3]uint8
+	Name   string
+	Desc   string
+	Detail string
+}
+
+// LaunchpadLayout describes a device's Launchpad page: every pad's color and
+// tooltip, plus the legend shown under the mini Launchpad
+type LaunchpadLayout struct {
+	TopRow   [8]Pad
+	Grid     [8][8]Pad // row 0 at bottom, row 7 at top
+	RightCol *[8]Pad   // scene buttons (nil = not drawn)
+	Legend   []LegendItem
+}
+
+// Tooltip returns what a pad does (row 8 = top row, col 8 = scene buttons)
+func (l *LaunchpadLayout) Tooltip(row, col int) string {
+	switch {
+	case row == 8 && col >= 0 && col < 8:
+		return l.TopRow[col].Tooltip
+	case col == 8 && row >= 0 && row < 8:
+		if l.RightCol != nil {
+			return l.RightCol[row].Tooltip
+		}
+	case row >= 0 && row < 8 && col >= 0 && col < 8:
+		return l.Grid[row][col].Tooltip
+	}
+	return ""
+}
+
+// RenderLayout renders a layout as a mini Launchpad followed by its legend
+func RenderLayout(l LaunchpadLayout) string {
+	topRow := make([][3]uint8, 8)
+	var grid [8][8][3]uint8
+	for col := 0; col < 8; col++ {
+		topRow[col] = l.TopRow[col].Color
+		for row := 0; row < 8; row++ {
+			grid[row][col] = l.Grid[row][col].Color
+		}
+	}
+	var rightCol *[8][3]uint8
+	if l.RightCol != nil {
+		rightCol = new([8][3]uint8)
+		for row := 0; row < 8; row++ {
+			rightCol[row] = l.RightCol[row].Color
+		}
+	}
+
+	var legend []string
+	for _, item := range l.Legend {
+		line := RenderLegendItem(item.Color, item.Name, item.Desc)
+		if item.Detail != "" {
+			line += "\n" + strings.TrimSuffix(item.Detail, "\n")
+		}
+		legend = append(legend, line)
+	}
+
+	out := RenderPadRow(topRow) + "\n"
+	out += RenderPadGrid(grid, rightCol) + "\n\n"
+	out += strings.Join(legend, "\n")
+	return out
+}
+
 // RenderKeyHelp formats key bindings in a friendly way
 func RenderKeyHelp(sections []KeySection) string {
 	var lines []string